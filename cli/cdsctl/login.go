@@ -10,11 +10,13 @@ import (
 	"reflect"
 	"regexp"
 	"runtime"
+	"time"
 
 	"github.com/howeyc/gopass"
 	"github.com/naoina/toml"
 
 	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
 	"github.com/ovh/cds/sdk/keychain"
 )
@@ -42,6 +44,10 @@ var loginCmd = cli.Command{
 			ShortHand: "p",
 			Usage:     "CDS Password",
 			Kind:      reflect.String,
+		}, {
+			Name:  "oidc",
+			Usage: "Login with the OpenID Connect provider of CDS, in a browser",
+			Kind:  reflect.Bool,
 		}, {
 			Name:  "env",
 			Usage: "Display the commands to set up the environment for the cds client",
@@ -56,6 +62,13 @@ func loginRun(v cli.Values) error {
 	password := v.GetString("password")
 	env := v.GetBool("env")
 
+	if v.GetBool("oidc") {
+		if url == "" {
+			return fmt.Errorf("Please set host flag to use --oidc option")
+		}
+		return doLoginOIDC(url, env)
+	}
+
 	if env &&
 		(url == "" || username == "" || password == "") {
 		return fmt.Errorf("Please set flags to use --env option")
@@ -104,6 +117,50 @@ func doLogin(url, username, password string, env bool) error {
 		return fmt.Errorf("login failed")
 	}
 
+	return saveLogin(url, username, token, env, "cds login -H HOST -u USERNAME -p PASSWORD --env")
+}
+
+func doLoginOIDC(url string, env bool) error {
+	conf := cdsclient.Config{
+		Host:    url,
+		Verbose: os.Getenv("CDS_VERBOSE") == "true",
+	}
+
+	client = cdsclient.New(conf)
+	d, err := client.UserLoginOIDCDevice()
+	if err != nil {
+		return err
+	}
+
+	// Instructions are printed on stderr, so that the output can be evaluated with --env
+	if d.VerificationURIComplete != "" {
+		fmt.Fprintf(os.Stderr, "Open %s in your browser and check the code %s\n", d.VerificationURIComplete, d.UserCode)
+	} else {
+		fmt.Fprintf(os.Stderr, "Open %s in your browser and enter the code %s\n", d.VerificationURI, d.UserCode)
+	}
+
+	interval := time.Duration(d.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	deadline := time.Now().Add(time.Duration(d.ExpiresIn) * time.Second)
+	for {
+		time.Sleep(interval)
+		res, err := client.UserLoginOIDCDeviceToken(d.DeviceCode)
+		if sdk.ErrorIs(err, sdk.ErrOIDCAuthorizationPending) {
+			if d.ExpiresIn > 0 && time.Now().After(deadline) {
+				return fmt.Errorf("login expired")
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		return saveLogin(url, res.User.Username, res.Token, env, "cds login -H HOST --oidc --env")
+	}
+}
+
+func saveLogin(url, username, token string, env bool, envCommand string) error {
 	if env && runtime.GOOS == "windows" {
 		fmt.Println("env option is not supported on windows yet")
 		os.Exit(1)
@@ -114,7 +171,7 @@ func doLogin(url, username, password string, env bool) error {
 		fmt.Printf("export CDS_USER=%s\n", username)
 		fmt.Printf("export CDS_TOKEN=%s\n", token)
 		fmt.Println("# Run this command to configure your shell:")
-		fmt.Println(`# eval "$(cds login -H HOST -u USERNAME -p PASSWORD --env)`)
+		fmt.Printf("# eval \"$(%s)\"\n", envCommand)
 		return nil
 	}
 
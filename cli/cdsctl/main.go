@@ -24,6 +24,7 @@ func main() {
 	health := cli.NewGetCommand(healthCmd, healthRun, nil, cli.CommandWithoutExtraFlags)
 	version := cli.NewCommand(versionCmd, versionRun, nil, cli.CommandWithoutExtraFlags)
 	monitoring := cli.NewGetCommand(monitoringCmd, monitoringRun, nil, cli.CommandWithoutExtraFlags)
+	watch := cli.NewCommand(watchCmd, watchRun, nil, cli.CommandWithoutExtraFlags)
 
 	root := cli.NewCommand(mainCmd, mainRun,
 		[]*cobra.Command{
@@ -35,10 +36,12 @@ func main() {
 			pipeline,
 			group,
 			project,
+			quota,
 			worker,
 			workflow,
 			usr,
 			monitoring,
+			watch,
 			health,
 			version,
 		},
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	quotaCmd = cli.Command{
+		Name:  "quota",
+		Short: "Manage CDS quotas of concurrent jobs",
+		Long: `A quota limits the number of concurrent jobs of a group, a project or a worker model.
+
+The jobs of a group are the jobs of the projects in which the group has the read-write-execute permission. A job which exceeds a quota waits in the queue.`,
+	}
+
+	quota = cli.NewCommand(quotaCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(quotaListCmd, quotaListRun, nil),
+			cli.NewGetCommand(quotaShowCmd, quotaShowRun, nil),
+			cli.NewGetCommand(quotaSetCmd, quotaSetRun, nil),
+			cli.NewCommand(quotaDeleteCmd, quotaDeleteRun, nil),
+		})
+)
+
+func isQuotaScope(s string) bool {
+	return sdk.Quota{Scope: s}.IsValid()
+}
+
+var quotaScopeArg = cli.Arg{Name: "scope", IsValid: isQuotaScope}
+
+var quotaListCmd = cli.Command{
+	Name:  "list",
+	Short: "List CDS quotas with their current usage",
+}
+
+func quotaListRun(v cli.Values) (cli.ListResult, error) {
+	qs, err := client.QuotaList()
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(qs), nil
+}
+
+var quotaShowCmd = cli.Command{
+	Name:  "show",
+	Short: "Show a CDS quota with its current usage",
+	Long:  "Scope is group, project or model. Name is the name of the group, the key of the project or the name of the worker model",
+	Args: []cli.Arg{
+		quotaScopeArg,
+		{Name: "name"},
+	},
+}
+
+func quotaShowRun(v cli.Values) (interface{}, error) {
+	q, err := client.QuotaGet(v["scope"], v["name"])
+	if err != nil {
+		return nil, err
+	}
+	return *q, nil
+}
+
+var quotaSetCmd = cli.Command{
+	Name:  "set",
+	Short: "Set the maximum number of concurrent jobs of a group, a project or a worker model",
+	Long:  "Scope is group, project or model. Name is the name of the group, the key of the project or the name of the worker model",
+	Args: []cli.Arg{
+		quotaScopeArg,
+		{Name: "name"},
+		{Name: "max-jobs", IsValid: func(s string) bool {
+			n, err := strconv.ParseInt(s, 10, 64)
+			return err == nil && n >= 0
+		}},
+	},
+}
+
+func quotaSetRun(v cli.Values) (interface{}, error) {
+	max, err := strconv.ParseInt(v["max-jobs"], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	q, err := client.QuotaSet(v["scope"], v["name"], max)
+	if err != nil {
+		return nil, err
+	}
+	return *q, nil
+}
+
+var quotaDeleteCmd = cli.Command{
+	Name:  "delete",
+	Short: "Delete a CDS quota",
+	Args: []cli.Arg{
+		quotaScopeArg,
+		{Name: "name"},
+	},
+}
+
+func quotaDeleteRun(v cli.Values) error {
+	return client.QuotaDelete(v["scope"], v["name"])
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	userAccessTokenCmd = cli.Command{
+		Name:  "accesstoken",
+		Short: "Manage your personal access tokens",
+		Long: `A personal access token lets automation call CDS as you, with restricted permissions. Scopes of a token are:
+
+ - read: read everything you can read
+ - run: read and run workflows and pipelines
+ - admin:<project key>: do everything you can do on a project
+
+Use a token with the environment variables CDS_API and CDS_ACCESS_TOKEN, or with the HTTP header "Authorization: Bearer <token>".`,
+	}
+
+	userAccessToken = cli.NewCommand(userAccessTokenCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(userAccessTokenListCmd, userAccessTokenListRun, nil),
+			cli.NewGetCommand(userAccessTokenCreateCmd, userAccessTokenCreateRun, nil),
+			cli.NewCommand(userAccessTokenRevokeCmd, userAccessTokenRevokeRun, nil),
+		})
+)
+
+var userAccessTokenListCmd = cli.Command{
+	Name:  "list",
+	Short: "List your personal access tokens",
+}
+
+func userAccessTokenListRun(v cli.Values) (cli.ListResult, error) {
+	ts, err := client.UserAccessTokenList(cfg.User)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(ts), nil
+}
+
+var userAccessTokenCreateCmd = cli.Command{
+	Name:  "create",
+	Short: "Create a personal access token, it's displayed only once",
+	Args: []cli.Arg{
+		{Name: "name"},
+		{Name: "scopes", IsValid: func(s string) bool {
+			for _, sc := range strings.Split(s, ",") {
+				if !sdk.IsValidAccessTokenScope(sc) {
+					return false
+				}
+			}
+			return true
+		}},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:    "expire",
+			Usage:   "Number of days before the token expires",
+			Default: "30",
+			Kind:    reflect.String,
+			IsValid: func(s string) bool {
+				n, err := strconv.Atoi(s)
+				return err == nil && n > 0
+			},
+		},
+	},
+}
+
+func userAccessTokenCreateRun(v cli.Values) (interface{}, error) {
+	days, err := strconv.Atoi(v.GetString("expire"))
+	if err != nil {
+		return nil, fmt.Errorf("expire flag have to be a number of days")
+	}
+	t, err := client.UserAccessTokenCreate(cfg.User, sdk.AccessTokenRequest{
+		Name:   v["name"],
+		Scopes: strings.Split(v["scopes"], ","),
+		Expire: time.Now().Add(time.Duration(days) * 24 * time.Hour),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return *t, nil
+}
+
+var userAccessTokenRevokeCmd = cli.Command{
+	Name:  "revoke",
+	Short: "Revoke a personal access token",
+	Args: []cli.Arg{
+		{Name: "id"},
+	},
+}
+
+func userAccessTokenRevokeRun(v cli.Values) error {
+	id, err := strconv.ParseInt(v["id"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("id parameter have to be an integer")
+	}
+	return client.UserAccessTokenDelete(cfg.User, id)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var watchCmd = cli.Command{
+	Name:  "watch",
+	Short: "Watch CDS events",
+	Long:  "Print events of the projects you have access to as soon as they occur",
+	OptionalArgs: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+	},
+}
+
+func watchRun(v cli.Values) error {
+	var projects, workflows []string
+	if v["project-key"] != "" {
+		projects = []string{v["project-key"]}
+	}
+	if v["workflow-name"] != "" {
+		workflows = []string{v["workflow-name"]}
+	}
+
+	chanEvents := make(chan sdk.Event)
+	chanErr := make(chan error, 1)
+	go func() {
+		chanErr <- client.EventsListen(context.Background(), projects, workflows, chanEvents)
+	}()
+
+	for {
+		select {
+		case err := <-chanErr:
+			return err
+		case e := <-chanEvents:
+			fmt.Println(watchEventLine(e))
+		}
+	}
+}
+
+func watchEventLine(e sdk.Event) string {
+	s := fmt.Sprintf("%s %s", e.Timestamp.Format("2006-01-02 15:04:05"), strings.TrimPrefix(e.EventType, "sdk."))
+	for _, k := range []string{"ProjectKey", "WorkflowName", "PipelineName", "NodeName", "Number", "Status"} {
+		if val, ok := e.Payload[k]; ok && val != nil && fmt.Sprintf("%v", val) != "" {
+			s += fmt.Sprintf(" %s=%v", k, val)
+		}
+	}
+	return s
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/ovh/cds/cli"
 	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
 )
 
 var (
@@ -26,7 +27,12 @@ var (
 			cli.NewListCommand(workflowListCmd, workflowListRun, nil),
 			cli.NewGetCommand(workflowShowCmd, workflowShowRun, nil),
 			cli.NewCommand(workflowRunManualCmd, workflowRunManualRun, nil),
+			cli.NewCommand(workflowExportCmd, workflowExportRun, nil),
+			cli.NewCommand(workflowImportCmd, workflowImportRun, nil),
+			cli.NewCommand(workflowLogsCmd, workflowLogsRun, nil),
 			workflowArtifact,
+			workflowTests,
+			cli.NewCommand(workflowCoverageCmd, workflowCoverageRun, nil),
 		})
 )
 
@@ -63,6 +69,107 @@ func workflowShowRun(v cli.Values) (interface{}, error) {
 	return *w, nil
 }
 
+var workflowExportCmd = cli.Command{
+	Name:  "export",
+	Short: "Export a CDS workflow",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow-name"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "format",
+			Usage: "yml or json",
+			IsValid: func(s string) bool {
+				if s != "json" && s != "yml" {
+					return false
+				}
+				return true
+			},
+			Kind:    reflect.String,
+			Default: "yml",
+		},
+	},
+}
+
+func workflowExportRun(v cli.Values) error {
+	btes, err := client.WorkflowExport(v["project-key"], v["workflow-name"], v["format"])
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(btes))
+	return nil
+}
+
+var workflowImportCmd = cli.Command{
+	Name:  "import",
+	Short: "Import a CDS workflow",
+	Long:  "PATH: Path or URL of workflow to import. Pipelines, applications and environments must exist in the project",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "path"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:  "force",
+			Usage: "Use force flag to update your workflow",
+			IsValid: func(s string) bool {
+				if s != "true" && s != "false" {
+					return false
+				}
+				return true
+			},
+			Default: "false",
+			Kind:    reflect.Bool,
+		},
+		{
+			Name:  "dry-run",
+			Usage: "Check the workflow and show the changes without saving them",
+			IsValid: func(s string) bool {
+				if s != "true" && s != "false" {
+					return false
+				}
+				return true
+			},
+			Default: "false",
+			Kind:    reflect.Bool,
+		},
+	},
+}
+
+func workflowImportRun(v cli.Values) error {
+	var btes []byte
+	var format = "yaml"
+
+	if strings.HasSuffix(v["path"], ".json") {
+		format = "json"
+	}
+
+	isURL, _ := regexp.MatchString(`http[s]?:\/\/(.*)`, v["path"])
+	if isURL {
+		var err error
+		btes, _, err = exportentities.ReadURL(v["path"], format)
+		if err != nil {
+			return err
+		}
+	} else {
+		var err error
+		btes, _, err = exportentities.ReadFile(v["path"])
+		if err != nil {
+			return err
+		}
+	}
+
+	msgs, err := client.WorkflowImport(v["project-key"], btes, format, v.GetBool("force"), v.GetBool("dry-run"))
+	if err != nil {
+		return err
+	}
+	for _, m := range msgs {
+		fmt.Println(m)
+	}
+	return nil
+}
+
 var workflowRunManualCmd = cli.Command{
 	Name:  "run",
 	Short: "Run a CDS workflow",
@@ -88,6 +195,18 @@ var workflowRunManualCmd = cli.Command{
 			Usage: "Node Name to relaunch; Flag run-number is mandatory",
 			Kind:  reflect.String,
 		},
+		{
+			Name:  "priority",
+			Usage: "Priority of the jobs in the queue, overrides the priority of the workflow nodes",
+			IsValid: func(s string) bool {
+				if s == "" {
+					return true
+				}
+				_, err := strconv.ParseInt(s, 10, 64)
+				return err == nil
+			},
+			Kind: reflect.String,
+		},
 	},
 }
 
@@ -97,6 +216,14 @@ func workflowRunManualRun(v cli.Values) error {
 		manual.Payload = v["payload"]
 	}
 
+	if v.GetString("priority") != "" {
+		priority, err := strconv.ParseInt(v.GetString("priority"), 10, 64)
+		if err != nil {
+			return fmt.Errorf("priority invalid: not a integer")
+		}
+		manual.Priority = &priority
+	}
+
 	var runNumber, fromNodeID int64
 
 	if v.GetString("run-number") != "" {
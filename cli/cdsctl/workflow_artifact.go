@@ -2,6 +2,7 @@ package main
 
 import (
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -92,9 +93,10 @@ func workflowArtifactDownloadRun(v cli.Values) error {
 		if errop != nil {
 			return errop
 		}
-		//Compute md5sum
+		//Compute md5sum and sha256sum, the artifact may have been downloaded directly from the objectstore
 		hash := md5.New()
-		if _, errcopy := io.Copy(hash, fileForMD5); errcopy != nil {
+		hashSHA256 := sha256.New()
+		if _, errcopy := io.Copy(io.MultiWriter(hash, hashSHA256), fileForMD5); errcopy != nil {
 			return errcopy
 		}
 		hashInBytes := hash.Sum(nil)[:16]
@@ -103,6 +105,9 @@ func workflowArtifactDownloadRun(v cli.Values) error {
 		if md5sumStr != a.MD5sum {
 			return fmt.Errorf("Invalid md5sum \ndownloaded file:%s\n%s:%s", md5sumStr, f.Name(), a.MD5sum)
 		}
+		if sha256sumStr := hex.EncodeToString(hashSHA256.Sum(nil)); a.SHA256sum != "" && sha256sumStr != a.SHA256sum {
+			return fmt.Errorf("Invalid sha256sum \ndownloaded file:%s\n%s:%s", sha256sumStr, f.Name(), a.SHA256sum)
+		}
 
 		fmt.Printf("File %s created, checksum OK\n", f.Name())
 		ok = true
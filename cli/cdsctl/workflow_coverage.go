@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ovh/cds/cli"
+)
+
+var workflowCoverageCmd = cli.Command{
+	Name:  "coverage",
+	Short: "Show the coverage of a Workflow Run compared to the default branch",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow"},
+		{Name: "number"},
+	},
+}
+
+func workflowCoverageRun(v cli.Values) error {
+	number, err := strconv.ParseInt(v["number"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("number parameter have to be an integer")
+	}
+
+	coverages, err := client.WorkflowRunCoverage(v["project-key"], v["workflow"], number)
+	if err != nil {
+		return err
+	}
+	if len(coverages) == 0 {
+		fmt.Printf("No coverage on run #%d\n", number)
+		return nil
+	}
+
+	for _, c := range coverages {
+		s := c.Report.Summary
+		fmt.Printf("%s #%d.%d on branch %s: %.2f%% of lines covered (%d/%d)\n", c.NodeName, c.Number, c.SubNumber, c.Branch, s.Percent, s.LinesCovered, s.LinesTotal)
+		if c.Delta == nil {
+			fmt.Printf("  No coverage on %s to compare with\n", c.DefaultBranch)
+			continue
+		}
+		fmt.Printf("  %+.2f%% compared to %s #%d\n", c.Delta.Percent, c.DefaultBranch, c.ReferenceNumber)
+		for _, f := range c.Delta.Files {
+			delta := fmt.Sprintf("%+.2f%%", f.Delta)
+			if f.New {
+				delta = "new"
+			}
+			fmt.Printf("  %s\t%.2f%%\t%s\n", f.Path, f.Percent, delta)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+
+	tm "github.com/buger/goterm"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var workflowLogsCmd = cli.Command{
+	Name:  "logs",
+	Short: "Show the logs of a Workflow Run",
+	Long:  "Print the logs of the steps of a Workflow Run. With --follow, the logs of a running Workflow Run are printed until it ends. With --download, the logs are saved in a tar.gz archive",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow"},
+		{Name: "number"},
+	},
+	Flags: []cli.Flag{
+		{
+			Name:      "follow",
+			ShortHand: "f",
+			Usage:     "Stream the logs until the Workflow Run ends",
+			Default:   "false",
+			Kind:      reflect.Bool,
+		},
+		{
+			Name:  "download",
+			Usage: "Download the logs of all the steps in this tar.gz file",
+			Kind:  reflect.String,
+		},
+	},
+}
+
+// Delays between two connections to the logs stream of a workflow run, doubled at each connection without logs
+const (
+	workflowLogsMinBackoff = time.Second
+	workflowLogsMaxBackoff = 30 * time.Second
+)
+
+func workflowLogsRun(v cli.Values) error {
+	number, err := strconv.ParseInt(v["number"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("number parameter have to be an integer")
+	}
+
+	if file := v.GetString("download"); file != "" {
+		f, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+		if err := client.WorkflowRunLogsDownload(v["project-key"], v["workflow"], number, f); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Printf("Logs of %s #%d saved in %s\n", v["workflow"], number, file)
+		return nil
+	}
+
+	follow := v.GetBool("follow")
+	p := newWorkflowLogsPrinter()
+	backoff := workflowLogsMinBackoff
+	for {
+		chanLogs := make(chan sdk.WorkflowRunLog)
+		chanErr := make(chan error, 1)
+		go func() {
+			chanErr <- client.WorkflowRunLogsStream(context.Background(), v["project-key"], v["workflow"], number, follow, chanLogs)
+		}()
+
+		var errStream error
+	read:
+		for {
+			select {
+			case errStream = <-chanErr:
+				break read
+			case l := <-chanLogs:
+				p.print(l)
+				// The stream works, reconnect quickly if it is closed
+				backoff = workflowLogsMinBackoff
+			}
+		}
+
+		// The stream may be closed by a proxy or a timeout while the run is still building
+		wr, err := client.WorkflowRunGet(v["project-key"], v["workflow"], number)
+		if err != nil {
+			return err
+		}
+		if !follow || sdk.StatusIsTerminated(wr.Status) {
+			if errStream != nil {
+				return errStream
+			}
+			icon, _ := statusShort(wr.Status)
+			fmt.Printf("\nWorkflow %s #%d.%d: %s %s\n", v["workflow"], wr.Number, wr.LastSubNumber, statusColor(wr.Status, wr.Status), icon)
+			return nil
+		}
+
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > workflowLogsMaxBackoff {
+			backoff = workflowLogsMaxBackoff
+		}
+	}
+}
+
+// workflowLogsPrinter prints the parts of the logs of a workflow run, with a header before the logs of each step.
+// The parts already printed are skipped, so that a stream can be resumed
+type workflowLogsPrinter struct {
+	currentStep string
+	offsets     map[string]int
+	statuses    map[string]string
+}
+
+func newWorkflowLogsPrinter() *workflowLogsPrinter {
+	return &workflowLogsPrinter{
+		offsets:  map[string]int{},
+		statuses: map[string]string{},
+	}
+}
+
+func (p *workflowLogsPrinter) print(l sdk.WorkflowRunLog) {
+	k := l.StepKey()
+
+	value := l.Value
+	if skip := p.offsets[k] - l.Offset; skip > 0 {
+		if skip >= len(value) {
+			value = ""
+		} else {
+			value = value[skip:]
+		}
+	}
+
+	if value != "" {
+		if p.currentStep != k {
+			p.currentStep = k
+			fmt.Println(tm.Bold(fmt.Sprintf("==> %s", l.Title())))
+		}
+		fmt.Print(value)
+		if value[len(value)-1] != '\n' {
+			fmt.Println()
+		}
+		p.offsets[k] = l.Offset + len(l.Value)
+	}
+
+	// The status of each attempt of a requeued job is printed
+	sk := fmt.Sprintf("%s/%d", k, l.Retry)
+	if l.Status != p.statuses[sk] {
+		p.statuses[sk] = l.Status
+		if sdk.StatusIsTerminated(l.Status) {
+			icon, _ := statusShort(l.Status)
+			fmt.Printf("%s %s %s\n", icon, l.Title(), statusColor(l.Status, l.Status))
+		}
+	}
+}
+
+// statusColor colors a text given a status: red for failures, green for success
+func statusColor(status, s string) string {
+	switch status {
+	case sdk.StatusFail.String(), sdk.StatusStopped.String():
+		return tm.Color(tm.Bold(s), tm.RED)
+	case sdk.StatusSuccess.String():
+		return tm.Color(tm.Bold(s), tm.GREEN)
+	}
+	return tm.Bold(s)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/cli"
+	"github.com/ovh/cds/sdk"
+)
+
+var (
+	workflowTestsCmd = cli.Command{
+		Name:  "tests",
+		Short: "Show the history of the tests of a Workflow",
+	}
+
+	workflowTests = cli.NewCommand(workflowTestsCmd, nil,
+		[]*cobra.Command{
+			cli.NewListCommand(workflowTestsFlakyCmd, workflowTestsFlakyRun, nil),
+			cli.NewCommand(workflowTestsHistoryCmd, workflowTestsHistoryRun, nil),
+			cli.NewCommand(workflowTestsCompareCmd, workflowTestsCompareRun, nil),
+		})
+)
+
+var workflowTestsLimitFlag = cli.Flag{
+	Name:    "limit",
+	Usage:   "Maximum number of results",
+	Default: "50",
+	Kind:    reflect.String,
+	IsValid: func(s string) bool {
+		n, err := strconv.Atoi(s)
+		return err == nil && n > 0
+	},
+}
+
+var workflowTestsFlakyCmd = cli.Command{
+	Name:  "flaky",
+	Short: "List the tests which succeeded and failed on the same commit",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow"},
+	},
+	Flags: []cli.Flag{workflowTestsLimitFlag},
+}
+
+func workflowTestsFlakyRun(v cli.Values) (cli.ListResult, error) {
+	limit, _ := strconv.Atoi(v.GetString("limit"))
+	tests, err := client.WorkflowFlakyTestCases(v["project-key"], v["workflow"], limit)
+	if err != nil {
+		return nil, err
+	}
+	return cli.AsListResult(tests), nil
+}
+
+var workflowTestsHistoryCmd = cli.Command{
+	Name:  "history",
+	Short: "Show the results of a test in the last runs of a Workflow",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow"},
+		{Name: "node"},
+		{Name: "testsuite"},
+		{Name: "name"},
+	},
+	Flags: []cli.Flag{workflowTestsLimitFlag},
+}
+
+func workflowTestsHistoryRun(v cli.Values) error {
+	limit, _ := strconv.Atoi(v.GetString("limit"))
+	h, err := client.WorkflowTestCaseHistory(v["project-key"], v["workflow"], v["node"], v["testsuite"], v["name"], limit)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s %s/%s: %d failure(s) in %d run(s)\n", h.NodeName, h.TestSuite, h.Name, h.Failures, h.Total)
+	if h.Flaky {
+		fmt.Printf("Flaky on %d commit(s): %v\n", len(h.FlakyHashes), h.FlakyHashes)
+	}
+	fmt.Printf("Average duration: %.3fs, trend: %+.1f%%\n", h.AverageDuration, h.DurationTrend)
+	for _, r := range h.Runs {
+		fmt.Printf("#%d.%d\t%s\t%.3fs\t%s\t%s\n", r.Number, r.SubNumber, r.Status, r.Duration, r.Branch, r.Hash)
+	}
+	return nil
+}
+
+var workflowTestsCompareCmd = cli.Command{
+	Name:  "compare",
+	Short: "Compare the tests of a Workflow Run with the previous run on the same branch",
+	Args: []cli.Arg{
+		{Name: "project-key"},
+		{Name: "workflow"},
+		{Name: "number"},
+	},
+}
+
+func workflowTestsCompareRun(v cli.Values) error {
+	number, err := strconv.ParseInt(v["number"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("number parameter have to be an integer")
+	}
+
+	c, err := client.WorkflowRunTestsComparison(v["project-key"], v["workflow"], number)
+	if err != nil {
+		return err
+	}
+
+	if c.PreviousNumber == 0 {
+		fmt.Printf("No previous run with tests on branch %s\n", c.Branch)
+	} else {
+		fmt.Printf("#%d compared to #%d on branch %s\n", c.Number, c.PreviousNumber, c.Branch)
+	}
+	printTestCases := func(title string, tests []sdk.WorkflowNodeRunTestCase) {
+		fmt.Printf("%s: %d\n", title, len(tests))
+		for _, t := range tests {
+			fmt.Printf("  %s %s/%s\n", t.NodeName, t.TestSuite, t.Name)
+		}
+	}
+	printTestCases("New failures", c.NewFailures)
+	printTestCases("Still failing", c.StillFailing)
+	printTestCases("Fixed", c.Fixed)
+	printTestCases("New tests", c.NewTests)
+	return nil
+}
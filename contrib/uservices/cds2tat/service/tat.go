@@ -38,6 +38,13 @@ func process(event sdk.Event) error {
 		} else {
 			return processEventJob(&e)
 		}
+	} else if event.EventType == fmt.Sprintf("%T", sdk.EventWorkflowNodeRun{}) {
+		var e sdk.EventWorkflowNodeRun
+		if err := mapstructure.Decode(event.Payload, &e); err != nil {
+			log.Errorf("Error during consumption EventWorkflowNodeRun: %s", err)
+		} else {
+			return processEventWorkflowNodeRun(&e)
+		}
 	}
 	return nil
 }
@@ -66,6 +73,18 @@ func processEventJob(e *sdk.EventJob) error {
 	return processMsg(eventType, cdsProject, cdsApp, cdsPipeline, cdsEnvironment, version, branch, e.Status)
 }
 
+func processEventWorkflowNodeRun(e *sdk.EventWorkflowNodeRun) error {
+	eventType := "workflowNodeRun"
+	cdsProject := e.ProjectKey
+	cdsApp := e.ApplicationName
+	cdsPipeline := e.PipelineName
+	cdsEnvironment := e.EnvironmentName
+	version := e.Number
+	branch := e.BranchName
+
+	return processMsg(eventType, cdsProject, cdsApp, cdsPipeline, cdsEnvironment, version, branch, e.Status)
+}
+
 func processMsg(eventType, cdsProject, cdsApp, cdsPipeline, cdsEnvironment string, version int64, branch string, cdsStatus sdk.Status) error {
 
 	text := fmt.Sprintf("#cds #type:%s #project:%s #app:%s #pipeline:%s #environment:%s #version:%d #branch:%s",
@@ -111,6 +111,9 @@ func init() {
 	flags.String("admin-conference", "", "CDS Admin conference cds@conference.jabber.yourdomain.net")
 	viper.BindPFlag("admin_conference", flags.Lookup("admin-conference"))
 
+	flags.String("workflow-conference", "", "Conference receiving the final status of the workflow node runs, ex: cds-workflows@conference.jabber.yourdomain.net")
+	viper.BindPFlag("workflow_conference", flags.Lookup("workflow-conference"))
+
 	flags.Bool("xmpp-debug", false, "XMPP Debug")
 	viper.BindPFlag("xmpp_debug", flags.Lookup("xmpp-debug"))
 
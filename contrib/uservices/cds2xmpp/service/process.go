@@ -30,23 +30,41 @@ func do() {
 }
 
 func process(event sdk.Event) error {
-	var eventNotif sdk.EventNotif
 	log.Debugf("process> receive: type:%s", event.EventType)
 
-	// skip all event != eventNotif
-	if event.EventType != fmt.Sprintf("%T", sdk.EventNotif{}) {
+	switch event.EventType {
+	case fmt.Sprintf("%T", sdk.EventNotif{}):
+		var eventNotif sdk.EventNotif
+		if err := mapstructure.Decode(event.Payload, &eventNotif); err != nil {
+			log.Warnf("process> Error during consumption. type:%s err:%s", event.EventType, err)
+			return nil
+		}
+		log.Debugf("process> event:%+v", event)
+		send(eventNotif.Recipients, eventNotif.Subject+" "+eventNotif.Body)
+	case fmt.Sprintf("%T", sdk.EventWorkflowNodeRun{}):
+		conference := viper.GetString("workflow_conference")
+		if conference == "" {
+			return nil
+		}
+		var e sdk.EventWorkflowNodeRun
+		if err := mapstructure.Decode(event.Payload, &e); err != nil {
+			log.Warnf("process> Error during consumption. type:%s err:%s", event.EventType, err)
+			return nil
+		}
+		// only final statuses are sent, to avoid flooding the conference
+		if e.Status != sdk.StatusSuccess && e.Status != sdk.StatusFail && e.Status != sdk.StatusStopped {
+			return nil
+		}
+		log.Debugf("process> event:%+v", event)
+		send([]string{conference}, fmt.Sprintf("CDS %s/%s #%d.%d %s: %s", e.ProjectKey, e.WorkflowName, e.Number, e.SubNumber, e.NodeName, e.Status.String()))
+	default:
 		log.Debugf("process> receive: type:%s - skipped", event.EventType)
-		return nil
-	}
-
-	if err := mapstructure.Decode(event.Payload, &eventNotif); err != nil {
-		log.Warnf("process> Error during consumption. type:%s err:%s", event.EventType, err)
-		return nil
 	}
+	return nil
+}
 
-	log.Debugf("process> event:%+v", event)
-
-	for _, destination := range eventNotif.Recipients {
+func send(recipients []string, text string) {
+	for _, destination := range recipients {
 		fullDestination := destination
 		if !strings.Contains(destination, "@") {
 			fullDestination += "@" + viper.GetString("xmpp_default_hostname")
@@ -76,10 +94,8 @@ func process(event sdk.Event) error {
 		cdsbot.chats <- xmpp.Chat{
 			Remote: fullDestination,
 			Type:   typeXMPP,
-			Text:   eventNotif.Subject + " " + eventNotif.Body,
+			Text:   text,
 		}
 		cdsbot.nbXMPPSent++
 	}
-
-	return nil
 }
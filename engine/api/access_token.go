@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/accesstoken"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (api *API) getAccessTokensHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		username := mux.Vars(r)["username"]
+
+		u, errU := user.LoadUserWithoutAuth(api.mustDB(), username)
+		if errU != nil {
+			return sdk.WrapError(sdk.ErrNotFound, "getAccessTokensHandler> Cannot load user %s: %s", username, errU)
+		}
+
+		ts, errL := accesstoken.LoadAllByUser(api.mustDB(), u.ID)
+		if errL != nil {
+			return sdk.WrapError(errL, "getAccessTokensHandler> Cannot load tokens of %s", username)
+		}
+		return WriteJSON(w, r, ts, http.StatusOK)
+	}
+}
+
+func (api *API) postAccessTokenHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		username := mux.Vars(r)["username"]
+
+		// Even an administrator cannot create a token for another user
+		if username != getUser(ctx).Username {
+			return sdk.WrapError(sdk.ErrForbidden, "postAccessTokenHandler> %s cannot create a token for %s", getUser(ctx).Username, username)
+		}
+
+		var req sdk.AccessTokenRequest
+		if err := UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+
+		t, errN := accesstoken.New(getUser(ctx).ID, req.Name, req.Scopes, req.Expire)
+		if errN != nil {
+			return errN
+		}
+		if err := accesstoken.Insert(api.mustDB(), t); err != nil {
+			return sdk.WrapError(err, "postAccessTokenHandler> Cannot insert token")
+		}
+		log.Info("postAccessTokenHandler> Access token %s created by %s with scopes %v", t.Name, username, t.Scopes)
+
+		// The token is only returned once
+		return WriteJSON(w, r, t, http.StatusCreated)
+	}
+}
+
+func (api *API) deleteAccessTokenHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		username := vars["username"]
+		id, errID := strconv.ParseInt(vars["id"], 10, 64)
+		if errID != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "deleteAccessTokenHandler> Invalid id %s", vars["id"])
+		}
+
+		u, errU := user.LoadUserWithoutAuth(api.mustDB(), username)
+		if errU != nil {
+			return sdk.WrapError(sdk.ErrNotFound, "deleteAccessTokenHandler> Cannot load user %s: %s", username, errU)
+		}
+
+		t, errL := accesstoken.LoadByID(api.mustDB(), u.ID, id)
+		if errL != nil {
+			return sdk.WrapError(errL, "deleteAccessTokenHandler> Cannot load token %d of %s", id, username)
+		}
+		if err := accesstoken.Delete(api.mustDB(), t); err != nil {
+			return sdk.WrapError(err, "deleteAccessTokenHandler> Cannot delete token %s", t.Name)
+		}
+		log.Info("deleteAccessTokenHandler> Access token %s of %s revoked by %s", t.Name, username, getUser(ctx).Username)
+		return nil
+	}
+}
@@ -0,0 +1,120 @@
+package accesstoken
+
+import (
+	"crypto/sha512"
+	"database/sql"
+	"encoding/hex"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/token"
+	"github.com/ovh/cds/sdk"
+)
+
+// lastUsedPrecision avoids to update a token each time it's used
+const lastUsedPrecision = time.Minute
+
+// Hash returns the hash of a token, only the hash is stored in database
+func Hash(t string) string {
+	h := sha512.Sum512([]byte(t))
+	return hex.EncodeToString(h[:])
+}
+
+// New generates a token for a user, it's not inserted
+func New(userID int64, name string, scopes []string, expire time.Time) (*sdk.AccessToken, error) {
+	if name == "" || len(scopes) == 0 {
+		return nil, sdk.WrapError(sdk.ErrWrongRequest, "accesstoken.New> Name and scopes are mandatory")
+	}
+	for _, s := range scopes {
+		if !sdk.IsValidAccessTokenScope(s) {
+			return nil, sdk.WrapError(sdk.ErrInvalidAccessTokenScope, "accesstoken.New> Invalid scope %s", s)
+		}
+	}
+	now := time.Now()
+	if !expire.After(now) {
+		return nil, sdk.WrapError(sdk.ErrWrongRequest, "accesstoken.New> Expiration must be in the future")
+	}
+
+	value, err := token.GenerateToken()
+	if err != nil {
+		return nil, sdk.WrapError(err, "accesstoken.New> Unable to generate token")
+	}
+	value = sdk.AccessTokenPrefix + value
+
+	return &sdk.AccessToken{
+		UserID:  userID,
+		Name:    name,
+		Scopes:  scopes,
+		Hash:    Hash(value),
+		Created: now,
+		Expire:  expire,
+		Token:   value,
+	}, nil
+}
+
+// Insert a token
+func Insert(db gorp.SqlExecutor, t *sdk.AccessToken) error {
+	dbt := dbAccessToken(*t)
+	if err := db.Insert(&dbt); err != nil {
+		return sdk.WrapError(err, "accesstoken.Insert> Unable to insert token %s", t.Name)
+	}
+	t.ID = dbt.ID
+	return nil
+}
+
+// Delete a token
+func Delete(db gorp.SqlExecutor, t *sdk.AccessToken) error {
+	dbt := dbAccessToken(*t)
+	if _, err := db.Delete(&dbt); err != nil {
+		return sdk.WrapError(err, "accesstoken.Delete> Unable to delete token %s", t.Name)
+	}
+	return nil
+}
+
+// UpdateLastUsed sets the last time the token has been used
+func UpdateLastUsed(db gorp.SqlExecutor, t *sdk.AccessToken, now time.Time) error {
+	if t.LastUsed != nil && now.Sub(*t.LastUsed) < lastUsedPrecision {
+		return nil
+	}
+	if _, err := db.Exec("UPDATE access_token SET last_used = $1 WHERE id = $2", now, t.ID); err != nil {
+		return sdk.WrapError(err, "accesstoken.UpdateLastUsed> Unable to update token %s", t.Name)
+	}
+	t.LastUsed = &now
+	return nil
+}
+
+// LoadByToken loads a token given its value
+func LoadByToken(db gorp.SqlExecutor, value string) (*sdk.AccessToken, error) {
+	return loadOne(db, "SELECT * FROM access_token WHERE hash = $1", Hash(value))
+}
+
+// LoadByID loads a token of a user
+func LoadByID(db gorp.SqlExecutor, userID, id int64) (*sdk.AccessToken, error) {
+	return loadOne(db, "SELECT * FROM access_token WHERE user_id = $1 AND id = $2", userID, id)
+}
+
+// LoadAllByUser loads the tokens of a user
+func LoadAllByUser(db gorp.SqlExecutor, userID int64) ([]sdk.AccessToken, error) {
+	var dbts []dbAccessToken
+	if _, err := db.Select(&dbts, "SELECT * FROM access_token WHERE user_id = $1 ORDER BY name", userID); err != nil {
+		return nil, sdk.WrapError(err, "accesstoken.LoadAllByUser> Unable to load tokens")
+	}
+	ts := make([]sdk.AccessToken, len(dbts))
+	for i := range dbts {
+		ts[i] = sdk.AccessToken(dbts[i])
+	}
+	return ts, nil
+}
+
+func loadOne(db gorp.SqlExecutor, query string, args ...interface{}) (*sdk.AccessToken, error) {
+	var dbt dbAccessToken
+	if err := db.SelectOne(&dbt, query, args...); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrNotFound
+		}
+		return nil, sdk.WrapError(err, "accesstoken.loadOne> Unable to load token")
+	}
+	t := sdk.AccessToken(dbt)
+	return &t, nil
+}
@@ -0,0 +1,33 @@
+package accesstoken
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestNew(t *testing.T) {
+	expire := time.Now().Add(24 * time.Hour)
+	tok, err := New(1, "ci", []string{"read", "admin:PROJ"}, expire)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(tok.Token, sdk.AccessTokenPrefix))
+	assert.Equal(t, Hash(tok.Token), tok.Hash)
+	assert.NotEqual(t, tok.Token, tok.Hash)
+
+	other, err := New(1, "ci", []string{"read"}, expire)
+	assert.NoError(t, err)
+	assert.NotEqual(t, tok.Token, other.Token)
+
+	_, err = New(1, "ci", []string{"write"}, expire)
+	assert.Error(t, err)
+	_, err = New(1, "ci", nil, expire)
+	assert.Error(t, err)
+	_, err = New(1, "", []string{"read"}, expire)
+	assert.Error(t, err)
+	_, err = New(1, "ci", []string{"read"}, time.Now().Add(-time.Hour))
+	assert.Error(t, err)
+}
@@ -0,0 +1,43 @@
+package accesstoken
+
+import (
+	"encoding/json"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+type dbAccessToken sdk.AccessToken
+
+// PostInsert is a DB Hook on PostInsert to store scopes JSON in DB
+func (t *dbAccessToken) PostInsert(s gorp.SqlExecutor) error {
+	scopes, err := json.Marshal(t.Scopes)
+	if err != nil {
+		return sdk.WrapError(err, "PostInsert> Unable to marshal scopes")
+	}
+	if _, err := s.Exec("UPDATE access_token SET scopes = $1 WHERE id = $2", scopes, t.ID); err != nil {
+		return sdk.WrapError(err, "PostInsert> Unable to update scopes")
+	}
+	return nil
+}
+
+// PostGet is a DB Hook on Select to get scopes JSON column
+func (t *dbAccessToken) PostGet(s gorp.SqlExecutor) error {
+	var scopes []byte
+	if err := s.QueryRow("SELECT scopes FROM access_token WHERE id = $1", t.ID).Scan(&scopes); err != nil {
+		return sdk.WrapError(err, "PostGet> Unable to load scopes")
+	}
+	if len(scopes) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(scopes, &t.Scopes); err != nil {
+		return sdk.WrapError(err, "PostGet> Unable to unmarshal scopes")
+	}
+	return nil
+}
+
+func init() {
+	gorpmapping.Register(gorpmapping.New(dbAccessToken{}, "access_token", true, "id"))
+}
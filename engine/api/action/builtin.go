@@ -182,6 +182,81 @@ Tag the current branch and push it.`
 		return err
 	}
 
+	// ----------------------------------- Cache Push -----------------------
+	cachePush := sdk.NewAction(sdk.CachePushAction)
+	cachePush.Type = sdk.BuiltinAction
+	cachePush.Description = `CDS Builtin Action.
+Archive files and directories in a cache of the project, which can be restored by the next jobs with the CachePull action.`
+	cachePush.Parameter(sdk.Parameter{
+		Name:        "key",
+		Description: "Key of the cache, for example deps-{{.git.hash}}. It can contain only alphanumerical characters, dots, dashes and underscores.",
+		Type:        sdk.StringParameter,
+	})
+	cachePush.Parameter(sdk.Parameter{
+		Name:        "path",
+		Description: "Files and directories to archive, one per line.",
+		Type:        sdk.TextParameter,
+	})
+	if err := checkBuiltinAction(db, cachePush); err != nil {
+		return err
+	}
+
+	// ----------------------------------- Cache Pull -----------------------
+	cachePull := sdk.NewAction(sdk.CachePullAction)
+	cachePull.Type = sdk.BuiltinAction
+	cachePull.Description = `CDS Builtin Action.
+Restore a cache of the project pushed with the CachePush action. The step succeeds if no cache is found.`
+	cachePull.Parameter(sdk.Parameter{
+		Name:        "key",
+		Description: "Key of the cache to restore.",
+		Type:        sdk.StringParameter,
+	})
+	cachePull.Parameter(sdk.Parameter{
+		Name:        "fallback",
+		Description: "Fallback keys, one per line, tried in order if no cache matches the key. The most recent cache whose key starts with a fallback key is restored.",
+		Type:        sdk.TextParameter,
+	})
+	cachePull.Parameter(sdk.Parameter{
+		Name:        "path",
+		Description: "Directory where the cache is extracted.",
+		Value:       "{{.cds.workspace}}",
+		Type:        sdk.StringParameter,
+	})
+	if err := checkBuiltinAction(db, cachePull); err != nil {
+		return err
+	}
+
+	// ----------------------------------- Coverage -----------------------
+	coverage := sdk.NewAction(sdk.CoverageAction)
+	coverage.Type = sdk.BuiltinAction
+	coverage.Description = `CDS Builtin Action.
+Parse Cobertura, LCOV or Go coverprofile files and store the coverage on the workflow node run. It can be compared to the last run on the default branch, and sent as commit status and pull request comment.`
+	coverage.Parameter(sdk.Parameter{
+		Name:        "path",
+		Description: "Path of the coverage files. Glob patterns are supported.",
+		Type:        sdk.StringParameter,
+	})
+	coverage.Parameter(sdk.Parameter{
+		Name:        "format",
+		Description: "Format of the coverage files: cobertura, lcov or go.",
+		Value:       "cobertura;lcov;go",
+		Type:        sdk.ListParameter,
+	})
+	coverage.Parameter(sdk.Parameter{
+		Name:        "minimum",
+		Description: "Minimum percentage of covered lines. If set, the step fails below this value.",
+		Type:        sdk.StringParameter,
+	})
+	coverage.Parameter(sdk.Parameter{
+		Name:        "vcs_status",
+		Description: "Send the coverage as commit status and pull request comment through the VCS µservice.",
+		Value:       "false",
+		Type:        sdk.BooleanParameter,
+	})
+	if err := checkBuiltinAction(db, coverage); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -10,11 +10,11 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-func insertEdge(db gorp.SqlExecutor, parentID, childID int64, execOrder int, optional, alwaysExecuted, enabled bool) (int64, error) {
-	query := `INSERT INTO action_edge (parent_id, child_id, exec_order, optional, always_executed, enabled) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+func insertEdge(db gorp.SqlExecutor, parentID, childID int64, execOrder int, optional, alwaysExecuted, enabled bool, retry, retryDelay, timeout int) (int64, error) {
+	query := `INSERT INTO action_edge (parent_id, child_id, exec_order, optional, always_executed, enabled, retry, retry_delay, timeout) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) RETURNING id`
 
 	var id int64
-	err := db.QueryRow(query, parentID, childID, execOrder, optional, alwaysExecuted, enabled).Scan(&id)
+	err := db.QueryRow(query, parentID, childID, execOrder, optional, alwaysExecuted, enabled, retry, retryDelay, timeout).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -27,7 +27,7 @@ func insertActionChild(db gorp.SqlExecutor, actionID int64, child sdk.Action, ex
 		return fmt.Errorf("insertActionChild: child action has no id")
 	}
 
-	id, err := insertEdge(db, actionID, child.ID, execOrder, child.Optional, child.AlwaysExecuted, child.Enabled)
+	id, err := insertEdge(db, actionID, child.ID, execOrder, child.Optional, child.AlwaysExecuted, child.Enabled, child.Retry, child.RetryDelay, child.Timeout)
 	if err != nil {
 		return err
 	}
@@ -67,7 +67,7 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 	var children []sdk.Action
 	var edgeIDs []int64
 	var childrenIDs []int64
-	query := `SELECT id, child_id, exec_order, optional, always_executed, enabled FROM action_edge WHERE parent_id = $1 ORDER BY exec_order ASC`
+	query := `SELECT id, child_id, exec_order, optional, always_executed, enabled, retry, retry_delay, timeout FROM action_edge WHERE parent_id = $1 ORDER BY exec_order ASC`
 
 	rows, err := db.Query(query, actionID)
 	if err != nil {
@@ -76,14 +76,17 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 	defer rows.Close()
 
 	var edgeID, childID int64
-	var execOrder int
+	var execOrder, retry, retryDelay, timeout int
 	var optional, alwaysExecuted, enabled bool
 	var mapOptional = make(map[int64]bool)
 	var mapAlwaysExecuted = make(map[int64]bool)
 	var mapEnabled = make(map[int64]bool)
+	var mapRetry = make(map[int64]int)
+	var mapRetryDelay = make(map[int64]int)
+	var mapTimeout = make(map[int64]int)
 
 	for rows.Next() {
-		err = rows.Scan(&edgeID, &childID, &execOrder, &optional, &alwaysExecuted, &enabled)
+		err = rows.Scan(&edgeID, &childID, &execOrder, &optional, &alwaysExecuted, &enabled, &retry, &retryDelay, &timeout)
 		if err != nil {
 			return nil, err
 		}
@@ -92,6 +95,9 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 		mapOptional[edgeID] = optional
 		mapAlwaysExecuted[edgeID] = alwaysExecuted
 		mapEnabled[edgeID] = enabled
+		mapRetry[edgeID] = retry
+		mapRetryDelay[edgeID] = retryDelay
+		mapTimeout[edgeID] = timeout
 	}
 	rows.Close()
 
@@ -118,6 +124,11 @@ func loadActionChildren(db gorp.SqlExecutor, actionID int64) ([]sdk.Action, erro
 		children[i].AlwaysExecuted = mapAlwaysExecuted[edgeIDs[i]]
 		// Get enable flag
 		children[i].Enabled = mapEnabled[edgeIDs[i]]
+		// Get retry policy
+		children[i].Retry = mapRetry[edgeIDs[i]]
+		children[i].RetryDelay = mapRetryDelay[edgeIDs[i]]
+		// Get timeout
+		children[i].Timeout = mapTimeout[edgeIDs[i]]
 	}
 
 	return children, nil
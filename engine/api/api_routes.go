@@ -18,9 +18,20 @@ func (api *API) InitRouter() {
 		&sync.Mutex{},
 	}
 	api.lastUpdateBroker.Init(api.Router.Background, api.DBConnectionFactory.GetDBMap, api.Cache)
+	api.eventsBroker = &eventsBroker{
+		make(map[string]*eventsBrokerSubscribe),
+		make(chan *eventsBrokerSubscribe),
+		make(chan string),
+		&sync.Mutex{},
+	}
+	api.eventsBroker.Init(api.Router.Background, api.DBConnectionFactory.GetDBMap, api.Cache)
 
 	r := api.Router
 	r.Handle("/login", r.POST(api.loginUserHandler, Auth(false)))
+	r.Handle("/login/oidc", r.GET(api.getLoginOIDCHandler, Auth(false)))
+	r.Handle("/login/oidc/callback", r.POST(api.postLoginOIDCCallbackHandler, Auth(false)))
+	r.Handle("/login/oidc/device", r.POST(api.postLoginOIDCDeviceHandler, Auth(false)))
+	r.Handle("/login/oidc/device/token", r.POST(api.postLoginOIDCDeviceTokenHandler, Auth(false)))
 
 	// Action
 	r.Handle("/action", r.GET(api.getActionsHandler))
@@ -108,6 +119,9 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/application/{permApplicationName}/branches", r.GET(api.getApplicationBranchHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/remotes", r.GET(api.getApplicationRemoteHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/version", r.GET(api.getApplicationBranchVersionHandler))
+	r.Handle("/project/{key}/application/{permApplicationName}/vcs/branches", r.GET(api.getApplicationVCSBranchesHandler, AllowServices(true)))
+	r.Handle("/project/{key}/application/{permApplicationName}/vcs/commits/{hash}", r.GET(api.getApplicationVCSCommitHandler, AllowServices(true)))
+	r.Handle("/project/{key}/application/{permApplicationName}/vcs/tags", r.GET(api.getApplicationVCSTagsHandler, AllowServices(true)))
 	r.Handle("/project/{key}/application/{permApplicationName}/clone", r.POST(api.cloneApplicationHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/group", r.POST(api.addGroupInApplicationHandler), r.PUT(api.updateGroupsInApplicationHandler, DEPRECATED))
 	r.Handle("/project/{key}/application/{permApplicationName}/group/{group}", r.PUT(api.updateGroupRoleOnApplicationHandler), r.DELETE(api.deleteGroupFromApplicationHandler))
@@ -169,17 +183,25 @@ func (api *API) InitRouter() {
 	r.Handle("/workflow/artifact/{hash}", r.GET(api.downloadworkflowArtifactDirectHandler, Auth(false)))
 
 	r.Handle("/project/{permProjectKey}/workflows", r.POST(api.postWorkflowHandler), r.GET(api.getWorkflowsHandler))
+	r.Handle("/project/{permProjectKey}/import/workflows", r.POST(api.importWorkflowHandler))
+	r.Handle("/project/{key}/export/workflows/{permWorkflowName}", r.GET(api.exportWorkflowHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}", r.GET(api.getWorkflowHandler), r.PUT(api.putWorkflowHandler), r.DELETE(api.deleteWorkflowHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/groups", r.POST(api.postWorkflowGroupHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/groups/{groupName}", r.PUT(api.putWorkflowGroupHandler), r.DELETE(api.deleteWorkflowGroupHandler))
 	// Workflows run
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs", r.GET(api.getWorkflowRunsHandler), r.POSTEXECUTE(api.postWorkflowRunHandler, AllowServices(true)))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/tests/flaky", r.GET(api.getWorkflowFlakyTestCasesHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/tests/history", r.GET(api.getWorkflowTestCaseHistoryHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/latest", r.GET(api.getLatestWorkflowRunHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/tags", r.GET(api.getWorkflowRunTagsHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}", r.GET(api.getWorkflowRunHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/stop", r.POST(api.stopWorkflowRunHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/resync", r.POST(api.resyncWorkflowRunPipelinesHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/artifacts", r.GET(api.getWorkflowRunArtifactsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/logs", r.GET(api.getWorkflowRunLogsHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/logs/download", r.GET(api.getWorkflowRunLogsDownloadHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/tests/compare", r.GET(api.getWorkflowRunTestsComparisonHandler))
+	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/coverage", r.GET(api.getWorkflowRunCoverageHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}", r.GET(api.getWorkflowNodeRunHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeRunID}/stop", r.POST(api.stopWorkflowNodeRunHandler))
 	r.Handle("/project/{key}/workflows/{permWorkflowName}/runs/{number}/nodes/{nodeID}/history", r.GET(api.getWorkflowNodeRunHistoryHandler))
@@ -225,6 +247,7 @@ func (api *API) InitRouter() {
 	r.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/{buildNumber}/artifact/{tag}", r.POSTEXECUTE(api.uploadArtifactHandler))
 	r.Handle("/project/{key}/application/{permApplicationName}/pipeline/{permPipelineKey}/artifact/download/{id}", r.GET(api.downloadArtifactHandler))
 	r.Handle("/artifact/{hash}", r.GET(api.downloadArtifactDirectHandler, Auth(false)))
+	r.Handle("/objectstore/{path}/{name}", r.GET(api.getObjectstoreSignedHandler, Auth(false)))
 
 	// Hooks
 	r.Handle("/project/{key}/application/{permApplicationName}/hook", r.GET(api.getApplicationHooksHandler))
@@ -245,6 +268,10 @@ func (api *API) InitRouter() {
 	r.Handle("/build/{id}/log", r.POST(api.addBuildLogHandler))
 	r.Handle("/build/{id}/step", r.POST(api.updateStepStatusHandler))
 
+	// Quotas of the queue
+	r.Handle("/quota", r.GET(api.getQuotasHandler))
+	r.Handle("/quota/{scope}/{name}", r.GET(api.getQuotaHandler), r.PUT(api.putQuotaHandler, NeedAdmin(true)), r.DELETE(api.deleteQuotaHandler, NeedAdmin(true)))
+
 	//Workflow queue
 	r.Handle("/queue/workflows", r.GET(api.getWorkflowJobQueueHandler))
 	r.Handle("/queue/workflows/requirements/errors", r.POST(api.postWorkflowJobRequirementsErrorHandler, NeedWorker()))
@@ -255,9 +282,13 @@ func (api *API) InitRouter() {
 	r.Handle("/queue/workflows/{permID}/result", r.POSTEXECUTE(api.postWorkflowJobResultHandler, NeedWorker()))
 	r.Handle("/queue/workflows/{permID}/log", r.POSTEXECUTE(api.postWorkflowJobLogsHandler, NeedWorker()))
 	r.Handle("/queue/workflows/{permID}/test", r.POSTEXECUTE(api.postWorkflowJobTestsResultsHandler, NeedWorker()))
+	r.Handle("/queue/workflows/{permID}/coverage", r.POSTEXECUTE(api.postWorkflowJobCoverageHandler, NeedWorker()))
 	r.Handle("/queue/workflows/{permID}/variable", r.POSTEXECUTE(api.postWorkflowJobVariableHandler, NeedWorker()))
 	r.Handle("/queue/workflows/{permID}/step", r.POSTEXECUTE(api.postWorkflowJobStepStatusHandler, NeedWorker()))
+	r.Handle("/queue/workflows/{permID}/step/spawn/infos", r.POSTEXECUTE(api.postWorkflowJobStepSpawnInfosHandler, NeedWorker()))
 	r.Handle("/queue/workflows/{permID}/artifact/{tag}", r.POSTEXECUTE(api.postWorkflowJobArtifactHandler, NeedWorker()))
+	r.Handle("/queue/workflows/{permID}/cache", r.GET(api.getWorkflowJobCacheHandler, NeedWorker(), NeedJobTaken()))
+	r.Handle("/queue/workflows/{permID}/cache/{key}", r.GET(api.getWorkflowJobCacheDownloadHandler, NeedWorker(), NeedJobTaken()), r.POSTEXECUTE(api.postWorkflowJobCacheHandler, NeedWorker()))
 
 	r.Handle("/variable/type", r.GET(api.getVariableTypeHandler))
 	r.Handle("/parameter/type", r.GET(api.getParameterTypeHandler))
@@ -305,6 +336,8 @@ func (api *API) InitRouter() {
 	r.Handle("/user/import", r.POST(api.importUsersHandler, NeedAdmin(true)))
 	r.Handle("/user/{username}", r.GET(api.getUserHandler, NeedUsernameOrAdmin(true)), r.PUT(api.updateUserHandler, NeedUsernameOrAdmin(true)), r.DELETE(api.deleteUserHandler, NeedUsernameOrAdmin(true)))
 	r.Handle("/user/{username}/groups", r.GET(api.getUserGroupsHandler, NeedUsernameOrAdmin(true)))
+	r.Handle("/user/{username}/accesstoken", r.GET(api.getAccessTokensHandler, NeedUsernameOrAdmin(true)), r.POST(api.postAccessTokenHandler, NeedUsernameOrAdmin(true)))
+	r.Handle("/user/{username}/accesstoken/{id}", r.DELETE(api.deleteAccessTokenHandler, NeedUsernameOrAdmin(true)))
 	r.Handle("/user/{username}/confirm/{token}", r.GET(api.confirmUserHandler, Auth(false)))
 	r.Handle("/user/{username}/reset", r.POST(api.resetUserHandler, Auth(false)))
 	r.Handle("/auth/mode", r.GET(api.authModeHandler, Auth(false)))
@@ -333,6 +366,7 @@ func (api *API) InitRouter() {
 
 	// SSE
 	r.Handle("/mon/lastupdates/events", r.GET(api.lastUpdateBroker.ServeHTTP))
+	r.Handle("/events", r.GET(api.eventsBroker.ServeHTTP))
 
 	// Engine µServices
 	r.Handle("/services/register", r.POST(api.postServiceRegisterHandler, Auth(false)))
@@ -21,6 +21,7 @@ import (
 	"github.com/ovh/cds/engine/api/repositoriesmanager"
 	"github.com/ovh/cds/engine/api/sanity"
 	"github.com/ovh/cds/engine/api/scheduler"
+	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/trigger"
 	"github.com/ovh/cds/engine/api/workflowv0"
 	"github.com/ovh/cds/sdk"
@@ -352,6 +353,98 @@ func (api *API) getApplicationBranchHandler() Handler {
 	}
 }
 
+func (api *API) getApplicationVCSBranchesHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars["key"]
+		applicationName := vars["permApplicationName"]
+
+		client, app, err := api.getApplicationVCSClient(ctx, projectKey, applicationName)
+		if err != nil {
+			return sdk.WrapError(err, "getApplicationVCSBranchesHandler")
+		}
+
+		branches, err := client.Branches(app.RepositoryFullname)
+		if err != nil {
+			return sdk.WrapError(err, "getApplicationVCSBranchesHandler> Cannot get branches from repository %s", app.RepositoryFullname)
+		}
+
+		return WriteJSON(w, r, branches, http.StatusOK)
+	}
+}
+
+func (api *API) getApplicationVCSCommitHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars["key"]
+		applicationName := vars["permApplicationName"]
+		hash := vars["hash"]
+
+		client, app, err := api.getApplicationVCSClient(ctx, projectKey, applicationName)
+		if err != nil {
+			return sdk.WrapError(err, "getApplicationVCSCommitHandler")
+		}
+
+		commit, err := client.Commit(app.RepositoryFullname, hash)
+		if err != nil {
+			return sdk.WrapError(err, "getApplicationVCSCommitHandler> Cannot get commit %s from repository %s", hash, app.RepositoryFullname)
+		}
+
+		return WriteJSON(w, r, commit, http.StatusOK)
+	}
+}
+
+func (api *API) getApplicationVCSTagsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		projectKey := vars["key"]
+		applicationName := vars["permApplicationName"]
+
+		client, app, err := api.getApplicationVCSClient(ctx, projectKey, applicationName)
+		if err != nil {
+			return sdk.WrapError(err, "getApplicationVCSTagsHandler")
+		}
+
+		tags, err := client.Tags(app.RepositoryFullname)
+		if err != nil {
+			return sdk.WrapError(err, "getApplicationVCSTagsHandler> Cannot get tags from repository %s", app.RepositoryFullname)
+		}
+
+		return WriteJSON(w, r, tags, http.StatusOK)
+	}
+}
+
+// getApplicationVCSClient loads the application and returns a client of the VCS µservices for its repositories manager
+func (api *API) getApplicationVCSClient(ctx context.Context, projectKey, applicationName string) (*repositoriesmanager.VCSServiceClient, *sdk.Application, error) {
+	app, err := application.LoadByName(api.mustDB(), api.Cache, projectKey, applicationName, getApplicationUser(ctx), application.LoadOptions.Default)
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "getApplicationVCSClient> Cannot load application %s for project %s from db", applicationName, projectKey)
+	}
+
+	if app.RepositoryFullname == "" || app.RepositoriesManager == nil {
+		return nil, nil, sdk.WrapError(sdk.ErrNoReposManager, "getApplicationVCSClient> Application %s is not attached to a repository", applicationName)
+	}
+
+	srvs, err := services.NewRepository(api.mustDB, api.Cache).FindByType("vcs")
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "getApplicationVCSClient> Unable to load vcs services")
+	}
+
+	client, err := repositoriesmanager.NewVCSServiceClient(api.mustDB(), srvs, projectKey, app.RepositoriesManager.Name)
+	if err != nil {
+		return nil, nil, sdk.WrapError(err, "getApplicationVCSClient> Cannot get vcs client of %s on %s", projectKey, app.RepositoriesManager.Name)
+	}
+	return client, app, nil
+}
+
+// getApplicationUser returns the user used to load applications, services are allowed to load all applications
+func getApplicationUser(ctx context.Context) *sdk.User {
+	if getService(ctx) != nil {
+		return nil
+	}
+	return getUser(ctx)
+}
+
 func (api *API) getApplicationRemoteHandler() Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		vars := mux.Vars(r)
@@ -5,14 +5,18 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"mime"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/go-gorp/gorp"
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/application"
 	"github.com/ovh/cds/engine/api/artifact"
 	"github.com/ovh/cds/engine/api/environment"
+	"github.com/ovh/cds/engine/api/objectstore"
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/sdk"
@@ -145,6 +149,10 @@ func (api *API) downloadArtifactHandler() Handler {
 
 		log.Debug("downloadArtifactHandler: Serving %+v", art)
 
+		if redirectArtifact(w, r, art) {
+			return nil
+		}
+
 		w.Header().Add("Content-Type", "application/octet-stream")
 		w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", art.Name))
 
@@ -264,6 +272,10 @@ func (api *API) downloadArtifactDirectHandler() Handler {
 			return sdk.WrapError(err, "downloadArtifactDirectHandler> Could not load artifact with hash %s", hash)
 		}
 
+		if redirectArtifact(w, r, art) {
+			return nil
+		}
+
 		w.Header().Add("Content-Type", "application/octet-stream")
 		w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", art.Name))
 
@@ -275,6 +287,47 @@ func (api *API) downloadArtifactDirectHandler() Handler {
 	}
 }
 
+func (api *API) getObjectstoreSignedHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		q := r.URL.Query()
+
+		// The filename is signed with the path of the object
+		filename := q.Get("filename")
+		f, err := objectstore.FetchSigned(vars["path"], vars["name"], filename, q.Get("expires"), q.Get("signature"))
+		if err != nil {
+			log.Warning("getObjectstoreSignedHandler> Cannot fetch %s/%s: %s", vars["path"], vars["name"], err)
+			return sdk.ErrForbidden
+		}
+		defer f.Close()
+
+		if filename == "" {
+			filename = vars["name"]
+		}
+		w.Header().Add("Content-Type", "application/octet-stream")
+		w.Header().Add("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+
+		if err := objectstore.StreamFile(w, f); err != nil {
+			return sdk.WrapError(err, "getObjectstoreSignedHandler> Cannot stream object")
+		}
+		return nil
+	}
+}
+
+// redirectArtifact redirects the client to a signed url of the artifact, if the objectstore provides one
+func redirectArtifact(w http.ResponseWriter, r *http.Request, o objectstore.Object) bool {
+	u, err := artifact.TemporaryURL(o)
+	if err != nil {
+		log.Warning("redirectArtifact> Cannot get temporary url for %s: %s", o.GetName(), err)
+		return false
+	}
+	if u == "" {
+		return false
+	}
+	http.Redirect(w, r, u, http.StatusTemporaryRedirect)
+	return true
+}
+
 func generateHash() (string, error) {
 	size := 128
 	bs := make([]byte, size)
@@ -287,3 +340,26 @@ func generateHash() (string, error) {
 	log.Debug("generateHash> new generated id: %s", token)
 	return string(token), nil
 }
+
+// artifactBlobPurgeRoutine deletes the blobs which are not referenced anymore every hour
+func artifactBlobPurgeRoutine(c context.Context, DBFunc func() *gorp.DbMap) {
+	tick := time.NewTicker(time.Hour).C
+
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting artifactBlobPurgeRoutine: %v", c.Err())
+			}
+			return
+		case <-tick:
+			db := DBFunc()
+			if db == nil {
+				continue
+			}
+			if err := artifact.PurgeBlobs(db); err != nil {
+				log.Warning("artifactBlobPurgeRoutine> %s", err)
+			}
+		}
+	}
+}
@@ -18,7 +18,7 @@ func LoadArtifactByHash(db gorp.SqlExecutor, hash string) (*sdk.Artifact, error)
 	art := &sdk.Artifact{}
 	query := `SELECT artifact.id, artifact.name, artifact.tag, 
 		  pipeline.name, project.projectKey, application.name, environment.name,
-		  artifact.size, artifact.perm, artifact.md5sum, artifact.object_path
+		  artifact.size, artifact.perm, artifact.md5sum, artifact.sha256sum, artifact.object_path
 		  FROM artifact
 		  JOIN pipeline ON artifact.pipeline_id = pipeline.id
 		  JOIN project ON pipeline.project_id = project.id
@@ -26,15 +26,18 @@ func LoadArtifactByHash(db gorp.SqlExecutor, hash string) (*sdk.Artifact, error)
 		  JOIN environment ON environment.id = artifact.environment_id
 		  WHERE download_hash = $1`
 
-	var md5sum, objectpath sql.NullString
+	var md5sum, sha256sum, objectpath sql.NullString
 	var size, perm sql.NullInt64
-	err := db.QueryRow(query, hash).Scan(&art.ID, &art.Name, &art.Tag, &art.Pipeline, &art.Project, &art.Application, &art.Environment, &size, &perm, &md5sum, &objectpath)
+	err := db.QueryRow(query, hash).Scan(&art.ID, &art.Name, &art.Tag, &art.Pipeline, &art.Project, &art.Application, &art.Environment, &size, &perm, &md5sum, &sha256sum, &objectpath)
 	if err != nil {
 		return nil, err
 	}
 	if md5sum.Valid {
 		art.MD5sum = md5sum.String
 	}
+	if sha256sum.Valid {
+		art.SHA256sum = sha256sum.String
+	}
 	if objectpath.Valid {
 		art.ObjectPath = objectpath.String
 	}
@@ -49,7 +52,7 @@ func LoadArtifactByHash(db gorp.SqlExecutor, hash string) (*sdk.Artifact, error)
 
 // LoadArtifactsByBuildNumber Load artifact by pipeline ID and buildNUmber
 func LoadArtifactsByBuildNumber(db gorp.SqlExecutor, pipelineID int64, applicationID int64, buildNumber int64, environmentID int64) ([]sdk.Artifact, error) {
-	query := `SELECT id, name, tag, download_hash, size, perm, md5sum, object_path
+	query := `SELECT id, name, tag, download_hash, size, perm, md5sum, sha256sum, object_path
 	          FROM "artifact"
 	          WHERE build_number = $1 AND pipeline_id = $2 AND application_id = $3 AND environment_id = $4
 	          ORDER BY name`
@@ -63,15 +66,18 @@ func LoadArtifactsByBuildNumber(db gorp.SqlExecutor, pipelineID int64, applicati
 	arts := []sdk.Artifact{}
 	for rows.Next() {
 		art := sdk.Artifact{}
-		var md5sum, objectpath sql.NullString
+		var md5sum, sha256sum, objectpath sql.NullString
 		var size, perm sql.NullInt64
-		err = rows.Scan(&art.ID, &art.Name, &art.Tag, &art.DownloadHash, &size, &perm, &md5sum, &objectpath)
+		err = rows.Scan(&art.ID, &art.Name, &art.Tag, &art.DownloadHash, &size, &perm, &md5sum, &sha256sum, &objectpath)
 		if err != nil {
 			return nil, err
 		}
 		if md5sum.Valid {
 			art.MD5sum = md5sum.String
 		}
+		if sha256sum.Valid {
+			art.SHA256sum = sha256sum.String
+		}
 		if objectpath.Valid {
 			art.ObjectPath = objectpath.String
 		}
@@ -88,7 +94,7 @@ func LoadArtifactsByBuildNumber(db gorp.SqlExecutor, pipelineID int64, applicati
 
 // LoadArtifacts Load artifact by pipeline ID
 func LoadArtifacts(db gorp.SqlExecutor, pipelineID int64, applicationID int64, environmentID int64, tag string) ([]sdk.Artifact, error) {
-	query := `SELECT id, name, download_hash, size, perm, md5sum, object_path
+	query := `SELECT id, name, download_hash, size, perm, md5sum, sha256sum, object_path
 		FROM "artifact" 
 		WHERE tag = $1 
 		AND pipeline_id = $2 
@@ -103,15 +109,18 @@ func LoadArtifacts(db gorp.SqlExecutor, pipelineID int64, applicationID int64, e
 	var arts []sdk.Artifact
 	for rows.Next() {
 		art := sdk.Artifact{}
-		var md5sum, objectpath sql.NullString
+		var md5sum, sha256sum, objectpath sql.NullString
 		var size, perm sql.NullInt64
-		err = rows.Scan(&art.ID, &art.Name, &art.DownloadHash, &size, &perm, &md5sum, &objectpath)
+		err = rows.Scan(&art.ID, &art.Name, &art.DownloadHash, &size, &perm, &md5sum, &sha256sum, &objectpath)
 		if err != nil {
 			return nil, err
 		}
 		if md5sum.Valid {
 			art.MD5sum = md5sum.String
 		}
+		if sha256sum.Valid {
+			art.SHA256sum = sha256sum.String
+		}
 		if objectpath.Valid {
 			art.ObjectPath = objectpath.String
 		}
@@ -130,7 +139,7 @@ func LoadArtifacts(db gorp.SqlExecutor, pipelineID int64, applicationID int64, e
 // LoadArtifact Load artifact by ID
 func LoadArtifact(db gorp.SqlExecutor, id int64) (*sdk.Artifact, error) {
 	query := `SELECT 
-			artifact.name, artifact.tag, artifact.download_hash, artifact.size, artifact.perm, artifact.md5sum, artifact.object_path, 
+			artifact.name, artifact.tag, artifact.download_hash, artifact.size, artifact.perm, artifact.md5sum, artifact.sha256sum, artifact.object_path, 
 			pipeline.name, project.projectKey, application.name, environment.name FROM artifact
 			JOIN pipeline ON artifact.pipeline_id = pipeline.id
 			JOIN project ON pipeline.project_id = project.id
@@ -139,13 +148,16 @@ func LoadArtifact(db gorp.SqlExecutor, id int64) (*sdk.Artifact, error) {
 			WHERE artifact.id = $1`
 
 	s := &sdk.Artifact{}
-	var md5sum, objectpath sql.NullString
+	var md5sum, sha256sum, objectpath sql.NullString
 	var size, perm sql.NullInt64
-	err := db.QueryRow(query, id).Scan(&s.Name, &s.Tag, &s.DownloadHash, &size, &perm, &md5sum, &objectpath,
+	err := db.QueryRow(query, id).Scan(&s.Name, &s.Tag, &s.DownloadHash, &size, &perm, &md5sum, &sha256sum, &objectpath,
 		&s.Pipeline, &s.Project, &s.Application, &s.Environment)
 	if md5sum.Valid {
 		s.MD5sum = md5sum.String
 	}
+	if sha256sum.Valid {
+		s.SHA256sum = sha256sum.String
+	}
 	if objectpath.Valid {
 		s.ObjectPath = objectpath.String
 	}
@@ -160,7 +172,7 @@ func LoadArtifact(db gorp.SqlExecutor, id int64) (*sdk.Artifact, error) {
 
 // DeleteArtifactsByApplicationID Delete all artifact related to given application
 func DeleteArtifactsByApplicationID(db gorp.SqlExecutor, id int64) error {
-	query := `SELECT artifact.name, artifact.tag, pipeline.name, project.projectKey, application.name, environment.name FROM artifact
+	query := `SELECT artifact.id, artifact.name, artifact.tag, artifact.sha256sum, pipeline.name, project.projectKey, application.name, environment.name FROM artifact
 						JOIN pipeline ON artifact.pipeline_id = pipeline.id
 						JOIN project ON pipeline.project_id = project.id
 						JOIN application ON application.id = artifact.application_id
@@ -174,20 +186,24 @@ func DeleteArtifactsByApplicationID(db gorp.SqlExecutor, id int64) error {
 	}
 	for rows.Next() {
 		s := sdk.Artifact{}
-		if err := rows.Scan(&s.Name, &s.Tag, &s.Pipeline, &s.Project, &s.Application, &s.Environment); err != nil {
+		var sha256sum sql.NullString
+		if err := rows.Scan(&s.ID, &s.Name, &s.Tag, &sha256sum, &s.Pipeline, &s.Project, &s.Application, &s.Environment); err != nil {
 			rows.Close()
 			return sdk.WrapError(err, "DeleteArtifactsByApplicationID> Cannot select artifact")
 		}
+		if sha256sum.Valid {
+			s.SHA256sum = sha256sum.String
+		}
 		arts = append(arts, s)
 	}
 	rows.Close()
 
 	for _, a := range arts {
-		if err := objectstore.DeleteArtifact(&a); err != nil && !strings.Contains(err.Error(), "404") {
+		if err := deleteArtifactObject(db, &a); err != nil {
 			return sdk.WrapError(err, "DeleteArtifact> Cannot delete artifact in store")
 		}
 		query = `DELETE FROM artifact WHERE id = $1`
-		if _, err := db.Exec(query, id); err != nil {
+		if _, err := db.Exec(query, a.ID); err != nil {
 			return sdk.WrapError(err, "DeleteArtifact> Cannot delete artifact in DB")
 		}
 	}
@@ -199,7 +215,7 @@ func DeleteArtifactsByApplicationID(db gorp.SqlExecutor, id int64) error {
 // finally remove artifact from database if actual delete is performed
 func DeleteArtifact(db gorp.SqlExecutor, id int64) error {
 
-	query := `SELECT artifact.name, artifact.tag, pipeline.name, project.projectKey, application.name, environment.name FROM artifact
+	query := `SELECT artifact.name, artifact.tag, artifact.sha256sum, pipeline.name, project.projectKey, application.name, environment.name FROM artifact
 						JOIN pipeline ON artifact.pipeline_id = pipeline.id
 						JOIN project ON pipeline.project_id = project.id
 						JOIN application ON application.id = artifact.application_id
@@ -207,11 +223,15 @@ func DeleteArtifact(db gorp.SqlExecutor, id int64) error {
 						WHERE artifact.id = $1 FOR UPDATE`
 
 	s := sdk.Artifact{}
-	if err := db.QueryRow(query, id).Scan(&s.Name, &s.Tag, &s.Pipeline, &s.Project, &s.Application, &s.Environment); err != nil {
+	var sha256sum sql.NullString
+	if err := db.QueryRow(query, id).Scan(&s.Name, &s.Tag, &sha256sum, &s.Pipeline, &s.Project, &s.Application, &s.Environment); err != nil {
 		return sdk.WrapError(err, "DeleteArtifact> Cannot select artifact")
 	}
+	if sha256sum.Valid {
+		s.SHA256sum = sha256sum.String
+	}
 
-	if err := objectstore.DeleteArtifact(&s); err != nil && !strings.Contains(err.Error(), "404") {
+	if err := deleteArtifactObject(db, &s); err != nil {
 		return sdk.WrapError(err, "DeleteArtifact> Cannot delete artifact in store")
 	}
 
@@ -223,33 +243,75 @@ func DeleteArtifact(db gorp.SqlExecutor, id int64) error {
 	return nil
 }
 
+// deleteArtifactObject releases the blob of the artifact, or deletes the artifact object if it has been stored
+// before content addressed storage
+func deleteArtifactObject(db gorp.SqlExecutor, a *sdk.Artifact) error {
+	if a.SHA256sum != "" {
+		return ReleaseBlob(db, a.SHA256sum)
+	}
+	if err := objectstore.DeleteArtifact(a); err != nil && !strings.Contains(err.Error(), "404") {
+		return err
+	}
+	return nil
+}
+
 func insertArtifact(db gorp.SqlExecutor, pipelineID, applicationID int64, environmentID int64, art sdk.Artifact) error {
-	query := `DELETE FROM "artifact" WHERE name = $1 AND tag = $2 AND pipeline_id = $3 AND application_id = $4 AND environment_id = $5`
-	_, err := db.Exec(query, art.Name, art.Tag, pipelineID, applicationID, environmentID)
+	// An artifact with the same name and tag is replaced, release its blob
+	query := `DELETE FROM "artifact" WHERE name = $1 AND tag = $2 AND pipeline_id = $3 AND application_id = $4 AND environment_id = $5 RETURNING sha256sum`
+	rows, err := db.Query(query, art.Name, art.Tag, pipelineID, applicationID, environmentID)
 	if err != nil {
 		return err
 	}
+	replaced := []string{}
+	for rows.Next() {
+		var sha256sum sql.NullString
+		if err := rows.Scan(&sha256sum); err != nil {
+			rows.Close()
+			return err
+		}
+		if sha256sum.Valid && sha256sum.String != "" {
+			replaced = append(replaced, sha256sum.String)
+		}
+	}
+	rows.Close()
+
+	for _, sha256sum := range replaced {
+		if err := ReleaseBlob(db, sha256sum); err != nil {
+			return sdk.WrapError(err, "insertArtifact> Unable to release replaced artifact")
+		}
+	}
 
 	query = `INSERT INTO "artifact" 
-			(name, tag, pipeline_id, application_id, build_number, environment_id, download_hash, size, perm, md5sum, object_path) 
+			(name, tag, pipeline_id, application_id, build_number, environment_id, download_hash, size, perm, md5sum, sha256sum, object_path) 
 			VALUES 
-			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
-	_, err = db.Exec(query, art.Name, art.Tag, pipelineID, applicationID, art.BuildNumber, environmentID, art.DownloadHash, art.Size, art.Perm, art.MD5sum, art.ObjectPath)
+			($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+	_, err = db.Exec(query, art.Name, art.Tag, pipelineID, applicationID, art.BuildNumber, environmentID, art.DownloadHash, art.Size, art.Perm, art.MD5sum, art.SHA256sum, art.ObjectPath)
 	if err != nil {
 		return sdk.WrapError(err, "insertArtifact> Unable to insert artifact")
 	}
 	return nil
 }
 
-// SaveWorkflowFile Insert file in db and write it in data directory
-func SaveWorkflowFile(art *sdk.WorkflowNodeRunArtifact, content io.ReadCloser) error {
-	objectPath, err := objectstore.StoreArtifact(art, content)
+// SaveWorkflowFile stores the content of the artifact as a blob in the objectstore.
+// The blob must be released with ReleaseBlob, then deleted with DeleteBlob, if the artifact is not inserted
+func SaveWorkflowFile(db *gorp.DbMap, art *sdk.WorkflowNodeRunArtifact, content io.ReadCloser) error {
+	defer content.Close()
+
+	tx, errB := db.Begin()
+	if errB != nil {
+		return sdk.WrapError(errB, "SaveWorkflowFile> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	sha256sum, objectPath, err := StoreBlob(tx, content)
 	if err != nil {
 		return sdk.WrapError(err, "SaveWorkflowFile> Cannot store artifact")
 	}
 	log.Debug("objectpath=%s\n", objectPath)
+	art.SHA256sum = sha256sum
 	art.ObjectPath = objectPath
-	return nil
+
+	return tx.Commit()
 }
 
 // SaveFile Insert file in db and write it in data directory
@@ -260,11 +322,12 @@ func SaveFile(db *gorp.DbMap, p *sdk.Pipeline, a *sdk.Application, art sdk.Artif
 	}
 	defer tx.Rollback()
 
-	objectPath, errO := objectstore.StoreArtifact(&art, content)
+	sha256sum, objectPath, errO := StoreBlob(tx, content)
 	if errO != nil {
 		return sdk.WrapError(errO, "SaveFile>Cannot store artifact")
 	}
 	log.Debug("objectpath=%s\n", objectPath)
+	art.SHA256sum = sha256sum
 	art.ObjectPath = objectPath
 	if err := insertArtifact(tx, p.ID, a.ID, e.ID, art); err != nil {
 		return sdk.WrapError(err, "SaveFile> Cannot insert artifact in DB")
@@ -273,9 +336,17 @@ func SaveFile(db *gorp.DbMap, p *sdk.Pipeline, a *sdk.Application, art sdk.Artif
 	return tx.Commit()
 }
 
-// StreamFile Stream artifact
+// StreamFile Stream artifact. Artifacts stored as blobs are checked against their sha256 digest
 func StreamFile(w io.Writer, o objectstore.Object) error {
-	f, err := objectstore.FetchArtifact(o)
+	sha256sum := blobSHA256sum(o)
+
+	var f io.ReadCloser
+	var err error
+	if sha256sum != "" {
+		f, err = objectstore.FetchBlob(sha256sum)
+	} else {
+		f, err = objectstore.FetchArtifact(o)
+	}
 	if err != nil {
 		return fmt.Errorf("cannot fetch artifact: %s", err)
 	}
@@ -285,3 +356,22 @@ func StreamFile(w io.Writer, o objectstore.Object) error {
 	}
 	return f.Close()
 }
+
+// TemporaryURL returns a signed url to download the artifact directly from the objectstore,
+// or an empty string if the objectstore doesn't provide it
+func TemporaryURL(o objectstore.Object) (string, error) {
+	if sha256sum := blobSHA256sum(o); sha256sum != "" {
+		return objectstore.TemporaryURL(&objectstore.Blob{SHA256: sha256sum}, o.GetName())
+	}
+	return objectstore.TemporaryURL(o, o.GetName())
+}
+
+func blobSHA256sum(o objectstore.Object) string {
+	switch a := o.(type) {
+	case *sdk.Artifact:
+		return a.SHA256sum
+	case *sdk.WorkflowNodeRunArtifact:
+		return a.SHA256sum
+	}
+	return ""
+}
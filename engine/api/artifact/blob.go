@@ -0,0 +1,140 @@
+package artifact
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// StoreBlob stores the content in the objectstore, addressed by its sha256 digest.
+// If the same content has already been stored, its reference count is incremented and nothing is uploaded.
+// It returns the sha256 digest and the object path of the blob
+func StoreBlob(db gorp.SqlExecutor, content io.Reader) (string, string, error) {
+	tmp, errT := ioutil.TempFile("", "cds-blob")
+	if errT != nil {
+		return "", "", sdk.WrapError(errT, "StoreBlob> Cannot create temporary file")
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), content); err != nil {
+		return "", "", sdk.WrapError(err, "StoreBlob> Cannot read content")
+	}
+	sha256sum := hex.EncodeToString(h.Sum(nil))
+
+	// The row stays locked until the end of the transaction, so concurrent uploads of the same content wait for this one
+	query := `INSERT INTO artifact_blob (sha256sum, object_path, ref_count)
+		VALUES ($1, '', 1)
+		ON CONFLICT (sha256sum) DO UPDATE SET ref_count = artifact_blob.ref_count + 1
+		RETURNING ref_count, object_path`
+	var refCount int64
+	var objectPath string
+	if err := db.QueryRow(query, sha256sum).Scan(&refCount, &objectPath); err != nil {
+		return "", "", sdk.WrapError(err, "StoreBlob> Cannot reference blob %s", sha256sum)
+	}
+
+	if refCount > 1 {
+		log.Debug("StoreBlob> Blob %s already stored (%d references)", sha256sum, refCount)
+		return sha256sum, objectPath, nil
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return "", "", sdk.WrapError(err, "StoreBlob> Cannot rewind temporary file")
+	}
+
+	objectPath, errS := objectstore.StoreArtifact(&objectstore.Blob{SHA256: sha256sum}, ioutil.NopCloser(tmp))
+	if errS != nil {
+		return "", "", sdk.WrapError(errS, "StoreBlob> Cannot store blob %s", sha256sum)
+	}
+
+	if _, err := db.Exec(`UPDATE artifact_blob SET object_path = $2 WHERE sha256sum = $1`, sha256sum, objectPath); err != nil {
+		return "", "", sdk.WrapError(err, "StoreBlob> Cannot update blob %s", sha256sum)
+	}
+
+	return sha256sum, objectPath, nil
+}
+
+// ReleaseBlob decrements the reference count of a blob. The blob is not deleted from the objectstore here,
+// it has to be deleted with DeleteBlob or PurgeBlobs once the transaction is committed
+func ReleaseBlob(db gorp.SqlExecutor, sha256sum string) error {
+	if sha256sum == "" {
+		return nil
+	}
+
+	var refCount int64
+	query := `UPDATE artifact_blob SET ref_count = ref_count - 1 WHERE sha256sum = $1 RETURNING ref_count`
+	if err := db.QueryRow(query, sha256sum).Scan(&refCount); err != nil {
+		if err == sql.ErrNoRows {
+			log.Warning("ReleaseBlob> Blob %s is not referenced", sha256sum)
+			return nil
+		}
+		return sdk.WrapError(err, "ReleaseBlob> Cannot release blob %s", sha256sum)
+	}
+
+	return nil
+}
+
+// DeleteBlob deletes a blob from the objectstore and the database if it is not referenced anymore.
+// The row is locked while the blob is deleted, so a concurrent StoreBlob of the same content waits and uploads it again
+func DeleteBlob(db *gorp.DbMap, sha256sum string) error {
+	if sha256sum == "" {
+		return nil
+	}
+
+	tx, errB := db.Begin()
+	if errB != nil {
+		return sdk.WrapError(errB, "DeleteBlob> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	var refCount int64
+	query := `SELECT ref_count FROM artifact_blob WHERE sha256sum = $1 FOR UPDATE SKIP LOCKED`
+	if err := tx.QueryRow(query, sha256sum).Scan(&refCount); err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		return sdk.WrapError(err, "DeleteBlob> Cannot lock blob %s", sha256sum)
+	}
+
+	if refCount > 0 {
+		return nil
+	}
+
+	if err := objectstore.DeleteArtifact(&objectstore.Blob{SHA256: sha256sum}); err != nil && !strings.Contains(err.Error(), "404") {
+		return sdk.WrapError(err, "DeleteBlob> Cannot delete blob %s in store", sha256sum)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM artifact_blob WHERE sha256sum = $1`, sha256sum); err != nil {
+		return sdk.WrapError(err, "DeleteBlob> Cannot delete blob %s in DB", sha256sum)
+	}
+
+	return tx.Commit()
+}
+
+// PurgeBlobs deletes all the blobs which are not referenced anymore
+func PurgeBlobs(db *gorp.DbMap) error {
+	var blobs []string
+	if _, err := db.Select(&blobs, `SELECT sha256sum FROM artifact_blob WHERE ref_count <= 0`); err != nil {
+		return sdk.WrapError(err, "PurgeBlobs> Cannot load unreferenced blobs")
+	}
+
+	for _, sha256sum := range blobs {
+		if err := DeleteBlob(db, sha256sum); err != nil {
+			log.Warning("PurgeBlobs> %s", err)
+			continue
+		}
+		log.Debug("PurgeBlobs> Blob %s deleted", sha256sum)
+	}
+	return nil
+}
@@ -6,13 +6,17 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-gorp/gorp"
 
+	"github.com/ovh/cds/engine/api/accesstoken"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/hatchery"
 	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/engine/api/sessionstore"
+	"github.com/ovh/cds/engine/api/user"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/cdsclient"
@@ -26,6 +30,7 @@ const (
 	ContextHatchery
 	ContextWorker
 	ContextService
+	ContextAccessToken
 )
 
 //Driver is an interface to all auth method (local, ldap and beyond...)
@@ -50,6 +55,10 @@ func GetDriver(c context.Context, mode string, options interface{}, storeOptions
 		d = &LDAPClient{
 			dbFunc: DBFunc,
 		}
+	case "oidc":
+		d = &OIDCClient{
+			dbFunc: DBFunc,
+		}
 	default:
 		d = &LocalClient{
 			dbFunc: DBFunc,
@@ -166,6 +175,39 @@ func CheckServiceAuth(ctx context.Context, db *gorp.DbMap, store cache.Store, he
 	return ctx, nil
 }
 
+// GetAccessToken returns the personal access token sent as a bearer token, or an empty string
+func GetAccessToken(headers http.Header) string {
+	h := headers.Get("Authorization")
+	if !strings.HasPrefix(h, "Bearer "+sdk.AccessTokenPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(h, "Bearer ")
+}
+
+// CheckAccessTokenAuth checks personal access token authentication
+func CheckAccessTokenAuth(ctx context.Context, db gorp.SqlExecutor, headers http.Header) (context.Context, error) {
+	t, err := accesstoken.LoadByToken(db, GetAccessToken(headers))
+	if err != nil {
+		return ctx, fmt.Errorf("invalid access token: %s", err)
+	}
+	now := time.Now()
+	if t.IsExpired(now) {
+		return ctx, fmt.Errorf("access token %s expired at %s", t.Name, t.Expire)
+	}
+
+	u, err := user.LoadUserWithoutAuthByID(db, t.UserID)
+	if err != nil {
+		return ctx, fmt.Errorf("cannot load user of access token %s: %s", t.Name, err)
+	}
+	if err := accesstoken.UpdateLastUsed(db, t, now); err != nil {
+		log.Warning("CheckAccessTokenAuth> %s", err)
+	}
+
+	ctx = context.WithValue(ctx, ContextUser, u)
+	ctx = context.WithValue(ctx, ContextAccessToken, t)
+	return ctx, nil
+}
+
 // CheckHatcheryAuth checks hatchery authentication
 func CheckHatcheryAuth(ctx context.Context, db *gorp.DbMap, headers http.Header) (context.Context, error) {
 	uid, err := base64.StdEncoding.DecodeString(headers.Get(sdk.AuthHeader))
@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	_ "crypto/sha256" // register SHA-256
+	_ "crypto/sha512" // register SHA-384 and SHA-512
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// oidcClockSkew is the tolerance on the dates of an ID token
+const oidcClockSkew = time.Minute
+
+// oidcAlgorithms are the signature algorithms accepted for ID tokens
+var oidcAlgorithms = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+}
+
+// jsonWebKey is a public key published by an OpenID Connect provider
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// jsonWebKeySet is the content of the jwks_uri of an OpenID Connect provider
+type jsonWebKeySet struct {
+	Keys []jsonWebKey `json:"keys"`
+}
+
+// rsaPublicKey decodes a RSA key
+func (k jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus of key %s: %s", k.Kid, err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent of key %s: %s", k.Kid, err)
+	}
+	exp := new(big.Int).SetBytes(e)
+	if !exp.IsInt64() || exp.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("invalid exponent of key %s", k.Kid)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}, nil
+}
+
+// jwtHeader is the header of a JSON Web Token
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// OIDCClaims are the claims of an ID token
+type OIDCClaims map[string]interface{}
+
+// String returns the value of a claim, or an empty string if it's not a string
+func (c OIDCClaims) String(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// Strings returns the values of a claim which is either a string or an array of strings
+func (c OIDCClaims) Strings(name string) []string {
+	switch v := c[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		res := make([]string, 0, len(v))
+		for _, i := range v {
+			if s, ok := i.(string); ok {
+				res = append(res, s)
+			}
+		}
+		return res
+	}
+	return nil
+}
+
+// Time returns the value of a date claim
+func (c OIDCClaims) Time(name string) (time.Time, bool) {
+	n, ok := c[name].(json.Number)
+	if !ok {
+		return time.Time{}, false
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(f), 0), true
+}
+
+// parseJWT decodes a JSON Web Token in compact serialization, without checking its signature
+func parseJWT(raw string) (jwtHeader, OIDCClaims, []byte, error) {
+	var h jwtHeader
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return h, nil, nil, fmt.Errorf("malformed token")
+	}
+
+	bh, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return h, nil, nil, fmt.Errorf("malformed token header: %s", err)
+	}
+	if err := json.Unmarshal(bh, &h); err != nil {
+		return h, nil, nil, fmt.Errorf("malformed token header: %s", err)
+	}
+
+	bc, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return h, nil, nil, fmt.Errorf("malformed token claims: %s", err)
+	}
+	claims := OIDCClaims{}
+	dec := json.NewDecoder(bytes.NewReader(bc))
+	dec.UseNumber()
+	if err := dec.Decode(&claims); err != nil {
+		return h, nil, nil, fmt.Errorf("malformed token claims: %s", err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return h, nil, nil, fmt.Errorf("malformed token signature: %s", err)
+	}
+	return h, claims, sig, nil
+}
+
+// verifyJWTSignature checks the signature of a JSON Web Token with a RSA key
+func verifyJWTSignature(raw string, h jwtHeader, sig []byte, key *rsa.PublicKey) error {
+	hash, ok := oidcAlgorithms[h.Alg]
+	if !ok {
+		return fmt.Errorf("unsupported signature algorithm %s", h.Alg)
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(raw[:strings.LastIndex(raw, ".")]))
+	if err := rsa.VerifyPKCS1v15(key, hash, hasher.Sum(nil), sig); err != nil {
+		return fmt.Errorf("invalid token signature")
+	}
+	return nil
+}
+
+// verifyIDTokenClaims checks the issuer, the audience, the dates and the nonce of an ID token
+func verifyIDTokenClaims(claims OIDCClaims, issuer, clientID, nonce string, now time.Time) error {
+	if iss := claims.String("iss"); iss != issuer {
+		return fmt.Errorf("invalid token issuer %s", iss)
+	}
+
+	aud := claims.Strings("aud")
+	var found bool
+	for _, a := range aud {
+		if a == clientID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("token not issued for client %s", clientID)
+	}
+	if azp := claims.String("azp"); len(aud) > 1 && azp != "" && azp != clientID {
+		return fmt.Errorf("token authorized for another party %s", azp)
+	}
+
+	exp, ok := claims.Time("exp")
+	if !ok {
+		return fmt.Errorf("token without expiration")
+	}
+	if now.After(exp.Add(oidcClockSkew)) {
+		return fmt.Errorf("token expired")
+	}
+	if iat, ok := claims.Time("iat"); ok && iat.After(now.Add(oidcClockSkew)) {
+		return fmt.Errorf("token issued in the future")
+	}
+
+	if nonce != "" && claims.String("nonce") != nonce {
+		return fmt.Errorf("invalid token nonce")
+	}
+	return nil
+}
@@ -0,0 +1,450 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/sessionstore"
+	"github.com/ovh/cds/engine/api/user"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+//OIDCConfig handles all config to connect to an OpenID Connect provider
+type OIDCConfig struct {
+	Issuer           string
+	ClientID         string
+	ClientSecret     string
+	RedirectURL      string
+	Scopes           []string
+	UsernameClaim    string
+	FullnameClaim    string
+	GroupsClaim      string
+	AutoCreateGroups bool
+}
+
+// oidcProvider is the configuration published by an OpenID Connect provider on its discovery endpoint
+type oidcProvider struct {
+	Issuer                      string `json:"issuer"`
+	AuthorizationEndpoint       string `json:"authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	JWKSURI                     string `json:"jwks_uri"`
+}
+
+// oidcTokenResponse is the response of the token endpoint, with an error if the request is refused
+type oidcTokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+//OIDCClient is an auth driver which authenticates users on an OpenID Connect provider.
+//Sessions are handled like local sessions, local users can still log in with their password
+type OIDCClient struct {
+	store  sessionstore.Store
+	conf   OIDCConfig
+	local  *LocalClient
+	dbFunc func() *gorp.DbMap
+	client *http.Client
+
+	provider  oidcProvider
+	keysMutex sync.RWMutex
+	keys      map[string]*rsa.PublicKey
+}
+
+//Open discovers the OpenID Connect provider and loads its keys
+func (c *OIDCClient) Open(options interface{}, store sessionstore.Store) error {
+	log.Info("Auth> Connecting to session store")
+	c.store = store
+	//OIDC Client needs a local client to check sessions and local users
+	c.local = &LocalClient{
+		dbFunc: c.dbFunc,
+	}
+	c.local.Open(options, store)
+
+	conf, ok := options.(OIDCConfig)
+	if !ok {
+		return fmt.Errorf("invalid OpenID Connect configuration")
+	}
+	if conf.Issuer == "" || conf.ClientID == "" {
+		return fmt.Errorf("OpenID Connect issuer and client id are mandatory")
+	}
+	if len(conf.Scopes) == 0 {
+		conf.Scopes = []string{"openid", "profile", "email"}
+	}
+	if conf.UsernameClaim == "" {
+		conf.UsernameClaim = "preferred_username"
+	}
+	if conf.FullnameClaim == "" {
+		conf.FullnameClaim = "name"
+	}
+	c.conf = conf
+	if c.client == nil {
+		c.client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	log.Info("Auth> Discovering OpenID Connect provider %s", conf.Issuer)
+	discovery := strings.TrimSuffix(conf.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := c.getJSON(discovery, &c.provider); err != nil {
+		return sdk.WrapError(err, "OIDC> Cannot discover provider")
+	}
+	if c.provider.Issuer != conf.Issuer {
+		return fmt.Errorf("OpenID Connect provider issuer %s does not match %s", c.provider.Issuer, conf.Issuer)
+	}
+	return c.loadKeys()
+}
+
+//Store returns store
+func (c *OIDCClient) Store() sessionstore.Store {
+	return c.store
+}
+
+//CheckAuth checks the session, users logged with OpenID Connect are stored in database
+func (c *OIDCClient) CheckAuth(ctx context.Context, w http.ResponseWriter, req *http.Request) (context.Context, error) {
+	return c.local.CheckAuth(ctx, w, req)
+}
+
+//Authentify checks username and password of local users, OpenID Connect users log in on the provider
+func (c *OIDCClient) Authentify(username, password string) (bool, error) {
+	return c.local.Authentify(username, password)
+}
+
+//AuthCodeURL returns the URL of the provider where the user logs in, the provider redirects the user on the redirect URL with a code
+func (c *OIDCClient) AuthCodeURL(state, nonce string) string {
+	v := url.Values{}
+	v.Set("response_type", "code")
+	v.Set("client_id", c.conf.ClientID)
+	v.Set("redirect_uri", c.conf.RedirectURL)
+	v.Set("scope", strings.Join(c.conf.Scopes, " "))
+	v.Set("state", state)
+	v.Set("nonce", nonce)
+
+	sep := "?"
+	if strings.Contains(c.provider.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return c.provider.AuthorizationEndpoint + sep + v.Encode()
+}
+
+//Exchange exchanges the code of the authorization code flow against an ID token and returns its claims
+func (c *OIDCClient) Exchange(code, nonce string) (OIDCClaims, error) {
+	v := url.Values{}
+	v.Set("grant_type", "authorization_code")
+	v.Set("code", code)
+	v.Set("redirect_uri", c.conf.RedirectURL)
+
+	tok, err := c.postForm(c.provider.TokenEndpoint, v)
+	if err != nil {
+		return nil, err
+	}
+	if tok.Error != "" {
+		return nil, fmt.Errorf("code refused by provider: %s %s", tok.Error, tok.ErrorDescription)
+	}
+	return c.VerifyIDToken(tok.IDToken, nonce)
+}
+
+//DeviceAuthorization starts the device flow
+func (c *OIDCClient) DeviceAuthorization() (*sdk.OIDCDeviceAuthorization, error) {
+	if c.provider.DeviceAuthorizationEndpoint == "" {
+		return nil, fmt.Errorf("device flow is not supported by provider %s", c.conf.Issuer)
+	}
+
+	v := url.Values{}
+	v.Set("scope", strings.Join(c.conf.Scopes, " "))
+
+	req, err := c.newFormRequest(c.provider.DeviceAuthorizationEndpoint, v)
+	if err != nil {
+		return nil, err
+	}
+	var res sdk.OIDCDeviceAuthorization
+	if err := c.doJSON(req, &res, http.StatusOK); err != nil {
+		return nil, err
+	}
+	if res.DeviceCode == "" {
+		return nil, fmt.Errorf("no device code returned by provider")
+	}
+	return &res, nil
+}
+
+//DeviceToken polls the token of the device flow. sdk.ErrOIDCAuthorizationPending is returned while the user has not logged in
+func (c *OIDCClient) DeviceToken(deviceCode string) (OIDCClaims, error) {
+	v := url.Values{}
+	v.Set("grant_type", "urn:ietf:params:oauth:grant-type:device_code")
+	v.Set("device_code", deviceCode)
+
+	tok, err := c.postForm(c.provider.TokenEndpoint, v)
+	if err != nil {
+		return nil, err
+	}
+	switch tok.Error {
+	case "":
+	case "authorization_pending", "slow_down":
+		return nil, sdk.ErrOIDCAuthorizationPending
+	default:
+		return nil, fmt.Errorf("device code refused by provider: %s %s", tok.Error, tok.ErrorDescription)
+	}
+	return c.VerifyIDToken(tok.IDToken, "")
+}
+
+//VerifyIDToken checks the signature and the claims of an ID token. The nonce is not checked if empty
+func (c *OIDCClient) VerifyIDToken(raw, nonce string) (OIDCClaims, error) {
+	h, claims, sig, err := parseJWT(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := c.key(h.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyJWTSignature(raw, h, sig, key); err != nil {
+		return nil, err
+	}
+	if err := verifyIDTokenClaims(claims, c.provider.Issuer, c.conf.ClientID, nonce, time.Now()); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+//InsertOrUpdateUser creates or refreshes the user from the claims of an ID token, and adds the user in the groups of the groups claim
+func (c *OIDCClient) InsertOrUpdateUser(db gorp.SqlExecutor, claims OIDCClaims) (*sdk.User, error) {
+	nu, err := c.userFromClaims(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := user.LoadUserAndAuth(db, nu.Username)
+	switch {
+	case err == sql.ErrNoRows:
+		a := &sdk.Auth{
+			EmailVerified: true,
+		}
+		if err := user.InsertUser(db, nu, a); err != nil {
+			return nil, sdk.WrapError(err, "OIDC> Error inserting user %s", nu.Username)
+		}
+		nu.Auth = *a
+		u = nu
+	case err != nil:
+		return nil, sdk.WrapError(err, "OIDC> Cannot load user %s", nu.Username)
+	case u.Origin != nu.Origin:
+		//Users of other origins are not managed by the provider, they can't log in with it
+		return nil, sdk.WrapError(sdk.ErrInvalidUser, "OIDC> User %s has origin %s", u.Username, u.Origin)
+	default:
+		u.Fullname = nu.Fullname
+		u.Email = nu.Email
+		if err := user.UpdateUser(db, *u); err != nil {
+			return nil, sdk.WrapError(err, "OIDC> Unable to update user %s", u.Username)
+		}
+	}
+
+	if err := c.joinGroups(db, u, c.groupsFromClaims(claims)); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// userFromClaims computes the user from the claims of an ID token
+func (c *OIDCClient) userFromClaims(claims OIDCClaims) (*sdk.User, error) {
+	username := claims.String(c.conf.UsernameClaim)
+	if !regexp.MustCompile(sdk.NamePattern).MatchString(username) {
+		return nil, sdk.WrapError(sdk.ErrInvalidUser, "OIDC> Invalid username '%s' in claim %s", username, c.conf.UsernameClaim)
+	}
+
+	u := &sdk.User{
+		Username: username,
+		Fullname: claims.String(c.conf.FullnameClaim),
+		Email:    claims.String("email"),
+		Origin:   "oidc",
+	}
+	if u.Fullname == "" {
+		u.Fullname = username
+	}
+	return u, nil
+}
+
+// groupsFromClaims returns the names of the groups of the user. Leading slashes of group paths are removed.
+// The shared infrastructure group gives access to all the projects, it can't be joined from the claims
+func (c *OIDCClient) groupsFromClaims(claims OIDCClaims) []string {
+	if c.conf.GroupsClaim == "" {
+		return nil
+	}
+	var groups []string
+	for _, g := range claims.Strings(c.conf.GroupsClaim) {
+		g = strings.TrimLeft(g, "/")
+		if g == group.SharedInfraGroupName {
+			log.Warning("OIDC> Ignoring group %s of the %s claim", g, c.conf.GroupsClaim)
+			continue
+		}
+		if g != "" {
+			groups = append(groups, g)
+		}
+	}
+	return groups
+}
+
+// joinGroups adds the user in the groups, missing groups are created if AutoCreateGroups is set
+func (c *OIDCClient) joinGroups(db gorp.SqlExecutor, u *sdk.User, groups []string) error {
+	for _, name := range groups {
+		g, err := group.LoadGroup(db, name)
+		if err == sdk.ErrGroupNotFound {
+			if !c.conf.AutoCreateGroups {
+				log.Debug("OIDC> Group %s of user %s not found", name, u.Username)
+				continue
+			}
+			id, _, errA := group.AddGroup(db, &sdk.Group{Name: name})
+			if errA != nil {
+				log.Warning("OIDC> Cannot create group %s for user %s: %s", name, u.Username, errA)
+				continue
+			}
+			log.Info("OIDC> Group %s created for user %s", name, u.Username)
+			g = &sdk.Group{ID: id, Name: name}
+		} else if err != nil {
+			return sdk.WrapError(err, "OIDC> Cannot load group %s", name)
+		}
+
+		ok, err := group.CheckUserInGroup(db, g.ID, u.ID)
+		if err != nil {
+			return sdk.WrapError(err, "OIDC> Cannot check user %s in group %s", u.Username, name)
+		}
+		if ok {
+			continue
+		}
+		if err := group.InsertUserInGroup(db, g.ID, u.ID, false); err != nil {
+			return sdk.WrapError(err, "OIDC> Cannot add user %s in group %s", u.Username, name)
+		}
+	}
+	return nil
+}
+
+// key returns the key of the provider with the given id. Keys are reloaded once if the id is unknown, to handle key rotation
+func (c *OIDCClient) key(kid string) (*rsa.PublicKey, error) {
+	c.keysMutex.RLock()
+	k, ok := c.findKey(kid)
+	c.keysMutex.RUnlock()
+	if ok {
+		return k, nil
+	}
+
+	if err := c.loadKeys(); err != nil {
+		return nil, err
+	}
+	c.keysMutex.RLock()
+	defer c.keysMutex.RUnlock()
+	if k, ok := c.findKey(kid); ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown token key %s", kid)
+}
+
+// findKey must be called with the keys lock. A token without key id is accepted if the provider has a single key
+func (c *OIDCClient) findKey(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(c.keys) == 1 {
+		for _, k := range c.keys {
+			return k, true
+		}
+	}
+	k, ok := c.keys[kid]
+	return k, ok
+}
+
+// loadKeys loads the signing keys of the provider
+func (c *OIDCClient) loadKeys() error {
+	var set jsonWebKeySet
+	if err := c.getJSON(c.provider.JWKSURI, &set); err != nil {
+		return sdk.WrapError(err, "OIDC> Cannot load provider keys")
+	}
+
+	keys := make(map[string]*rsa.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		pub, err := k.rsaPublicKey()
+		if err != nil {
+			log.Warning("OIDC> %s", err)
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+
+	c.keysMutex.Lock()
+	c.keys = keys
+	c.keysMutex.Unlock()
+	return nil
+}
+
+// postForm sends a request authenticated with the client credentials to the token endpoint
+func (c *OIDCClient) postForm(endpoint string, v url.Values) (*oidcTokenResponse, error) {
+	req, err := c.newFormRequest(endpoint, v)
+	if err != nil {
+		return nil, err
+	}
+	var tok oidcTokenResponse
+	// Errors of the token endpoint are returned with a 400 status
+	if err := c.doJSON(req, &tok, http.StatusOK, http.StatusBadRequest, http.StatusUnauthorized); err != nil {
+		return nil, err
+	}
+	if tok.Error == "" && tok.IDToken == "" {
+		return nil, fmt.Errorf("no ID token returned by provider")
+	}
+	return &tok, nil
+}
+
+func (c *OIDCClient) newFormRequest(endpoint string, v url.Values) (*http.Request, error) {
+	v.Set("client_id", c.conf.ClientID)
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(v.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if c.conf.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(c.conf.ClientID), url.QueryEscape(c.conf.ClientSecret))
+	}
+	return req, nil
+}
+
+func (c *OIDCClient) getJSON(u string, i interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	return c.doJSON(req, i, http.StatusOK)
+}
+
+func (c *OIDCClient) doJSON(req *http.Request, i interface{}, statuses ...int) error {
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range statuses {
+		if resp.StatusCode == s {
+			if err := json.Unmarshal(body, i); err != nil {
+				return fmt.Errorf("invalid response from %s: %s", req.URL, err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %s: HTTP %d", req.Method, req.URL, resp.StatusCode)
+}
@@ -0,0 +1,248 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// fakeIssuer is a minimal OpenID Connect provider signing ID tokens with a RSA key
+type fakeIssuer struct {
+	*httptest.Server
+	t          *testing.T
+	key        *rsa.PrivateKey
+	kid        string
+	claims     map[string]interface{}
+	devicePoll int
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	f := &fakeIssuer{t: t, key: key, kid: "key1"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                        f.URL,
+			"authorization_endpoint":        f.URL + "/auth",
+			"token_endpoint":                f.URL + "/token",
+			"device_authorization_endpoint": f.URL + "/device",
+			"jwks_uri":                      f.URL + "/certs",
+		})
+	})
+	mux.HandleFunc("/certs", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(jsonWebKeySet{Keys: []jsonWebKey{{
+			Kid: f.kid,
+			Kty: "RSA",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(f.key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(f.key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(sdk.OIDCDeviceAuthorization{
+			DeviceCode:      "device-code",
+			UserCode:        "ABCD-EFGH",
+			VerificationURI: f.URL + "/device/verify",
+			ExpiresIn:       600,
+			Interval:        5,
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		if id != "cds" || secret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+		r.ParseForm()
+		switch r.Form.Get("grant_type") {
+		case "authorization_code":
+			if r.Form.Get("code") != "good-code" || r.Form.Get("redirect_uri") != "https://cds.local/account/oidc" {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+				return
+			}
+		case "urn:ietf:params:oauth:grant-type:device_code":
+			f.devicePoll++
+			if f.devicePoll == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": "authorization_pending"})
+				return
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": f.sign(f.claims)})
+	})
+	f.Server = httptest.NewServer(mux)
+	return f
+}
+
+func (f *fakeIssuer) sign(claims map[string]interface{}) string {
+	h, _ := json.Marshal(map[string]string{"alg": "RS256", "kid": f.kid})
+	c, _ := json.Marshal(claims)
+	payload := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	sum := sha256.Sum256([]byte(payload))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, f.key, crypto.SHA256, sum[:])
+	assert.NoError(f.t, err)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func (f *fakeIssuer) validClaims() map[string]interface{} {
+	return map[string]interface{}{
+		"iss":                f.URL,
+		"aud":                "cds",
+		"sub":                "1234",
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"iat":                time.Now().Unix(),
+		"preferred_username": "john.doe",
+		"name":               "John Doe",
+		"email":              "john.doe@cds.local",
+		"groups":             []string{"/team-a", "team-b"},
+	}
+}
+
+func newTestOIDCClient(t *testing.T, f *fakeIssuer) *OIDCClient {
+	c := &OIDCClient{}
+	err := c.Open(OIDCConfig{
+		Issuer:       f.URL,
+		ClientID:     "cds",
+		ClientSecret: "secret",
+		RedirectURL:  "https://cds.local/account/oidc",
+		GroupsClaim:  "groups",
+	}, nil)
+	assert.NoError(t, err)
+	return c
+}
+
+func TestOIDCClientAuthCodeURL(t *testing.T) {
+	f := newFakeIssuer(t)
+	defer f.Close()
+	c := newTestOIDCClient(t, f)
+
+	u, err := url.Parse(c.AuthCodeURL("the-state", "the-nonce"))
+	assert.NoError(t, err)
+	assert.Equal(t, f.URL+"/auth", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, "code", u.Query().Get("response_type"))
+	assert.Equal(t, "cds", u.Query().Get("client_id"))
+	assert.Equal(t, "https://cds.local/account/oidc", u.Query().Get("redirect_uri"))
+	assert.Equal(t, "openid profile email", u.Query().Get("scope"))
+	assert.Equal(t, "the-state", u.Query().Get("state"))
+	assert.Equal(t, "the-nonce", u.Query().Get("nonce"))
+}
+
+func TestOIDCClientExchange(t *testing.T) {
+	f := newFakeIssuer(t)
+	defer f.Close()
+	c := newTestOIDCClient(t, f)
+
+	f.claims = f.validClaims()
+	f.claims["nonce"] = "the-nonce"
+
+	claims, err := c.Exchange("good-code", "the-nonce")
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe", claims.String("preferred_username"))
+
+	_, err = c.Exchange("good-code", "another-nonce")
+	assert.Error(t, err)
+	_, err = c.Exchange("bad-code", "the-nonce")
+	assert.Error(t, err)
+}
+
+func TestOIDCClientDeviceFlow(t *testing.T) {
+	f := newFakeIssuer(t)
+	defer f.Close()
+	c := newTestOIDCClient(t, f)
+	f.claims = f.validClaims()
+
+	d, err := c.DeviceAuthorization()
+	assert.NoError(t, err)
+	assert.Equal(t, "device-code", d.DeviceCode)
+	assert.Equal(t, "ABCD-EFGH", d.UserCode)
+
+	_, err = c.DeviceToken(d.DeviceCode)
+	assert.Equal(t, sdk.ErrOIDCAuthorizationPending, err)
+
+	claims, err := c.DeviceToken(d.DeviceCode)
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe", claims.String("preferred_username"))
+}
+
+func TestOIDCClientVerifyIDToken(t *testing.T) {
+	f := newFakeIssuer(t)
+	defer f.Close()
+	c := newTestOIDCClient(t, f)
+
+	_, err := c.VerifyIDToken(f.sign(f.validClaims()), "")
+	assert.NoError(t, err)
+
+	invalid := map[string]func(map[string]interface{}){
+		"issuer":   func(c map[string]interface{}) { c["iss"] = "https://evil.local" },
+		"audience": func(c map[string]interface{}) { c["aud"] = []string{"other"} },
+		"party":    func(c map[string]interface{}) { c["aud"] = []string{"cds", "other"}; c["azp"] = "other" },
+		"expired":  func(c map[string]interface{}) { c["exp"] = time.Now().Add(-time.Hour).Unix() },
+		"no exp":   func(c map[string]interface{}) { delete(c, "exp") },
+		"future":   func(c map[string]interface{}) { c["iat"] = time.Now().Add(time.Hour).Unix() },
+	}
+	for name, modify := range invalid {
+		claims := f.validClaims()
+		modify(claims)
+		_, err := c.VerifyIDToken(f.sign(claims), "")
+		assert.Error(t, err, name)
+	}
+
+	// Signed by another key
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	key := f.key
+	f.key = other
+	token := f.sign(f.validClaims())
+	f.key = key
+	_, err = c.VerifyIDToken(token, "")
+	assert.Error(t, err)
+
+	// Keys are reloaded when the provider rotates its key
+	f.key = other
+	f.kid = "key2"
+	_, err = c.VerifyIDToken(f.sign(f.validClaims()), "")
+	assert.NoError(t, err)
+}
+
+func TestOIDCClientClaimsMapping(t *testing.T) {
+	f := newFakeIssuer(t)
+	defer f.Close()
+	c := newTestOIDCClient(t, f)
+
+	claims, err := c.VerifyIDToken(f.sign(f.validClaims()), "")
+	assert.NoError(t, err)
+
+	u, err := c.userFromClaims(claims)
+	assert.NoError(t, err)
+	assert.Equal(t, "john.doe", u.Username)
+	assert.Equal(t, "John Doe", u.Fullname)
+	assert.Equal(t, "john.doe@cds.local", u.Email)
+	assert.Equal(t, "oidc", u.Origin)
+	assert.Equal(t, []string{"team-a", "team-b"}, c.groupsFromClaims(claims))
+
+	_, err = c.userFromClaims(OIDCClaims{"preferred_username": "john doe"})
+	assert.Error(t, err)
+
+	// The shared infrastructure group is never joined from the claims
+	assert.Equal(t, []string{"team-a"}, c.groupsFromClaims(OIDCClaims{"groups": []interface{}{"/shared.infra", "shared.infra", "team-a"}}))
+
+	c.conf.GroupsClaim = ""
+	assert.Nil(t, c.groupsFromClaims(claims))
+}
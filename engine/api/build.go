@@ -19,6 +19,7 @@ import (
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/pipeline"
 	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/quota"
 	"github.com/ovh/cds/engine/api/stats"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/sdk"
@@ -344,12 +345,24 @@ func (api *API) takePipelineBuildJobHandler() Handler {
 			return sdk.WrapError(sdk.ErrWrongRequest, "takePipelineBuildJobHandler> worker %s is not available to for build (status = %s)", caller.Name, caller.Status)
 		}
 
+		projectID, errP := quota.ProjectIDByPipelineBuildJob(api.mustDB(), id)
+		if errP != nil {
+			return sdk.WrapError(errP, "takePipelineBuildJobHandler> Cannot load project")
+		}
+
 		tx, errBegin := api.mustDB().Begin()
 		if errBegin != nil {
 			return sdk.WrapError(errBegin, "takePipelineBuildJobHandler> Cannot start transaction")
 		}
 		defer tx.Rollback()
 
+		if err := api.checkJobQuota(tx, fmt.Sprintf("pipeline-%d", id), projectID, caller.ModelID, func(infos []sdk.SpawnInfo) error {
+			_, err := pipeline.AddSpawnInfosPipelineBuildJob(api.mustDB(), id, infos)
+			return err
+		}); err != nil {
+			return sdk.WrapError(err, "takePipelineBuildJobHandler> Cannot take job %d", id)
+		}
+
 		workerModel := caller.Name
 		if caller.ModelID != 0 {
 			wm, errModel := worker.LoadWorkerModelByID(api.mustDB(), caller.ModelID)
@@ -407,6 +420,19 @@ func (api *API) bookPipelineBuildJobHandler() Handler {
 			return sdk.WrapError(errc, "bookPipelineBuildJobHandler> invalid id")
 		}
 
+		projectID, errP := quota.ProjectIDByPipelineBuildJob(api.mustDB(), id)
+		if errP != nil {
+			return sdk.WrapError(errP, "bookPipelineBuildJobHandler> Cannot load project")
+		}
+
+		// The booking is not counted in the quotas: it only avoids to spawn a worker for a job which can't be taken
+		if err := api.checkJobQuota(api.mustDB(), fmt.Sprintf("pipeline-%d", id), projectID, 0, func(infos []sdk.SpawnInfo) error {
+			_, err := pipeline.AddSpawnInfosPipelineBuildJob(api.mustDB(), id, infos)
+			return err
+		}); err != nil {
+			return sdk.WrapError(err, "bookPipelineBuildJobHandler> Cannot book job %d", id)
+		}
+
 		if _, err := pipeline.BookPipelineBuildJob(id, getHatchery(ctx)); err != nil {
 			return sdk.WrapError(err, "bookPipelineBuildJobHandler> job already booked")
 		}
@@ -32,7 +32,7 @@ type Store interface {
 	Subscribe(queueName string) PubSub
 	GetMessageFromSubscription(c context.Context, pb PubSub) (string, error)
 	Status() string
-	SetAdd(rootKey string, memberKey string, member interface{})
+	SetAdd(rootKey string, memberKey string, member interface{}) error
 	SetRemove(rootKey string, memberKey string, member interface{})
 	SetCard(key string) int
 	SetScan(key string, members ...interface{}) error
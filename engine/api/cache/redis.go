@@ -281,13 +281,25 @@ func (s *RedisStore) Status() string {
 	return "KO (redis"
 }
 
-// SetAdd add a member (identified by a key) in the cached set
-func (s *RedisStore) SetAdd(rootKey string, memberKey string, member interface{}) {
-	s.Client.ZAdd(rootKey, redis.Z{
+// SetAdd add a member (identified by a key) in the cached set. The member is saved before being added in the set
+func (s *RedisStore) SetAdd(rootKey string, memberKey string, member interface{}) error {
+	if s.Client == nil {
+		return fmt.Errorf("redis> cannot get redis client")
+	}
+	b, err := json.Marshal(member)
+	if err != nil {
+		return fmt.Errorf("redis> Error encoding %s: %v", Key(rootKey, memberKey), err)
+	}
+	if err := s.Client.Set(Key(rootKey, memberKey), string(b), 0).Err(); err != nil {
+		return fmt.Errorf("redis> Error caching %s: %v", Key(rootKey, memberKey), err)
+	}
+	if err := s.Client.ZAdd(rootKey, redis.Z{
 		Member: memberKey,
 		Score:  float64(time.Now().UnixNano()),
-	})
-	s.SetWithTTL(Key(rootKey, memberKey), member, -1)
+	}).Err(); err != nil {
+		return fmt.Errorf("redis> Error adding %s in set %s: %v", memberKey, rootKey, err)
+	}
+	return nil
 }
 
 // SetRemove removes a member from a set
@@ -0,0 +1,161 @@
+package event
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/streadway/amqp"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const amqpConfirmTimeout = 10 * time.Second
+
+// AMQPClient embeddes the AMQP connection
+type AMQPClient struct {
+	options  AMQPConfig
+	mutex    sync.Mutex
+	conn     *amqp.Connection
+	channel  *amqp.Channel
+	confirms chan amqp.Confirmation
+}
+
+// AMQPConfig handles all config to connect to an AMQP broker (RabbitMQ...)
+type AMQPConfig struct {
+	SinkConfig
+	Enabled    bool
+	URL        string
+	Exchange   string
+	RoutingKey string
+}
+
+// initialize returns broker, isInit and err if
+func (c *AMQPClient) initialize(options interface{}) (Broker, error) {
+	conf, ok := options.(AMQPConfig)
+	if !ok {
+		return nil, fmt.Errorf("Invalid AMQP Initialization")
+	}
+
+	if conf.URL == "" || conf.Exchange == "" {
+		return nil, fmt.Errorf("initAMQP> Invalid AMQP Configuration")
+	}
+	c.options = conf
+
+	//The connection is opened again on the first event if the broker is not available
+	if err := c.connect(); err != nil {
+		log.Warning("initAMQP> Error with init amqp: %s", err)
+	}
+
+	return c, nil
+}
+
+// connect opens the connection and a channel in confirm mode, so that each message is acknowledged by the broker
+func (c *AMQPClient) connect() error {
+	conn, err := amqp.Dial(c.options.URL)
+	if err != nil {
+		return err
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	if err := channel.ExchangeDeclarePassive(c.options.Exchange, "topic", true, false, false, false, nil); err != nil {
+		conn.Close()
+		return fmt.Errorf("exchange %s is not available: %s", c.options.Exchange, err)
+	}
+
+	if err := channel.Confirm(false); err != nil {
+		conn.Close()
+		return err
+	}
+
+	log.Debug("initAMQP> AMQP used on exchange:%s", c.options.Exchange)
+	c.conn = conn
+	c.channel = channel
+	c.confirms = channel.NotifyPublish(make(chan amqp.Confirmation, 1))
+	return nil
+}
+
+// reset closes the connection, it will be opened again on next event
+func (c *AMQPClient) reset() {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	c.conn = nil
+	c.channel = nil
+	c.confirms = nil
+}
+
+// close closes connection
+func (c *AMQPClient) close() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.reset()
+}
+
+// sendEvent publishes the event on the exchange, routed by the event type if no routing key is configured
+func (c *AMQPClient) sendEvent(event *sdk.Event) error {
+	data, errm := json.Marshal(event)
+	if errm != nil {
+		return errm
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.channel == nil {
+		if err := c.connect(); err != nil {
+			return err
+		}
+	}
+
+	routingKey := c.options.RoutingKey
+	if routingKey == "" {
+		routingKey = event.EventType
+	}
+
+	msg := amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Timestamp:    event.Timestamp,
+		Type:         event.EventType,
+		Body:         data,
+	}
+	if err := c.channel.Publish(c.options.Exchange, routingKey, false, false, msg); err != nil {
+		c.reset()
+		return err
+	}
+
+	select {
+	case confirm, ok := <-c.confirms:
+		if !ok {
+			c.reset()
+			return fmt.Errorf("amqp channel closed")
+		}
+		if !confirm.Ack {
+			return fmt.Errorf("event %s not acknowledged by amqp broker", event.EventType)
+		}
+	case <-time.After(amqpConfirmTimeout):
+		c.reset()
+		return fmt.Errorf("timeout while waiting amqp broker acknowledgment")
+	}
+
+	log.Debug("Event %+v sent to exchange %s with routing key %s", event, c.options.Exchange, routingKey)
+	return nil
+}
+
+// status returns the state of the connection
+func (c *AMQPClient) status() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.channel == nil {
+		return "AMQP KO"
+	}
+	return "AMQP OK"
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/docker/docker/pkg/namesgenerator"
 
@@ -13,7 +14,7 @@ import (
 
 var hostname, cdsname string
 var kafkaBroker Broker
-var brokers []Broker
+var sinks []*sink
 
 // Broker event typed
 type Broker interface {
@@ -23,17 +24,35 @@ type Broker interface {
 	close()
 }
 
+// Config handles the configuration of all the event brokers
+type Config struct {
+	Kafka   KafkaConfig
+	AMQP    AMQPConfig
+	NATS    NATSConfig
+	Webhook WebhookConfig
+}
+
 func getBroker(t string, option interface{}) (Broker, error) {
 	switch t {
 	case "kafka":
 		k := &KafkaClient{}
 		return k.initialize(option)
+	case "amqp":
+		a := &AMQPClient{}
+		return a.initialize(option)
+	case "nats":
+		n := &NATSClient{}
+		return n.initialize(option)
+	case "webhook":
+		w := &WebhookClient{}
+		return w.initialize(option)
 	}
 	return nil, fmt.Errorf("Invalid Broker Type %s", t)
 }
 
-// Initialize initializes event system
-func Initialize(k KafkaConfig) error {
+// Initialize initializes event system. A broker with an invalid configuration is skipped and reported in the returned error,
+// the other brokers are registered. Brokers which are not reachable are connected again on the next event
+func Initialize(c Config) error {
 	var err error
 	hostname, err = os.Hostname()
 	if err != nil {
@@ -41,20 +60,47 @@ func Initialize(k KafkaConfig) error {
 	}
 	cdsname = namesgenerator.GetRandomName(0)
 
-	brokers = []Broker{}
-	if k.Enabled {
-		var errk error
-		kafkaBroker, errk = getBroker("kafka", k)
-		if errk != nil {
-			return errk
+	sinks = []*sink{}
+	var failed []string
+	addSink := func(t string, option interface{}, conf SinkConfig) {
+		b, err := getBroker(t, option)
+		if err != nil {
+			log.Error("event.Initialize> Unable to initialize %s broker: %s", t, err)
+			failed = append(failed, t)
+			return
+		}
+		if t == "kafka" {
+			kafkaBroker = b
 		}
-		brokers = append(brokers, kafkaBroker)
+		sinks = append(sinks, newSink(t, b, conf))
+	}
+
+	if c.Kafka.Enabled {
+		addSink("kafka", c.Kafka, c.Kafka.SinkConfig)
+	}
+	if c.AMQP.Enabled {
+		addSink("amqp", c.AMQP, c.AMQP.SinkConfig)
+	}
+	if c.NATS.Enabled {
+		addSink("nats", c.NATS, c.NATS.SinkConfig)
+	}
+	if c.Webhook.Enabled {
+		addSink("webhook", c.Webhook, c.Webhook.SinkConfig)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("invalid configuration of broker(s) %s, the other brokers are started", strings.Join(failed, ", "))
 	}
 	return nil
 }
 
-// DequeueEvent runs in a goroutine and dequeue event from cache
+// DequeueEvent runs in a goroutine and dequeue event from cache.
+// Each event is dispatched in the queue of every broker whose filter matches it
 func DequeueEvent(c context.Context) {
+	for _, s := range sinks {
+		go s.run(c)
+	}
+
 	for {
 		e := sdk.Event{}
 		Cache.DequeueWithContext(c, "events", &e)
@@ -63,9 +109,9 @@ func DequeueEvent(c context.Context) {
 			return
 		}
 
-		for _, b := range brokers {
-			if err := b.sendEvent(&e); err != nil {
-				log.Warning("Error while sending message: %s", err)
+		for _, s := range sinks {
+			if s.filter.Match(&e) {
+				Cache.Enqueue(s.queueName(), e)
 			}
 		}
 	}
@@ -83,16 +129,16 @@ func GetCDSName() string {
 
 // Close closes event system
 func Close() {
-	for _, b := range brokers {
-		b.close()
+	for _, s := range sinks {
+		s.broker.close()
 	}
 }
 
 // Status returns Event status
 func Status() string {
 	o := ""
-	for _, b := range brokers {
-		o += b.status() + " "
+	for _, s := range sinks {
+		o += s.broker.status() + " "
 	}
 
 	if o == "" {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/Shopify/sarama"
 
@@ -16,11 +17,13 @@ var producer sarama.SyncProducer
 // KafkaClient enbeddes the Kafka connecion
 type KafkaClient struct {
 	options  KafkaConfig
+	mutex    sync.Mutex
 	producer sarama.SyncProducer
 }
 
 // KafkaConfig handles all config to connect to Kafka
 type KafkaConfig struct {
+	SinkConfig
 	Enabled         bool
 	BrokerAddresses string
 	User            string
@@ -43,8 +46,9 @@ func (c *KafkaClient) initialize(options interface{}) (Broker, error) {
 	}
 	c.options = conf
 
+	//The producer is created again on the first event if the brokers are not available
 	if err := c.initProducer(); err != nil {
-		return nil, fmt.Errorf("initKafka> Error with init sarama:%s (newSyncPoducer on %s user:%s)", err.Error(), conf.BrokerAddresses, conf.User)
+		log.Warning("%s", err)
 	}
 
 	return c, nil
@@ -52,6 +56,8 @@ func (c *KafkaClient) initialize(options interface{}) (Broker, error) {
 
 // close closes producer
 func (c *KafkaClient) close() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 	if c.producer != nil {
 		if err := c.producer.Close(); err != nil {
 			log.Warning("closeKafka> Error while closing kafka producer:%s", err.Error())
@@ -86,6 +92,15 @@ func (c *KafkaClient) sendEvent(event *sdk.Event) error {
 		return errm
 	}
 
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.producer == nil {
+		if err := c.initProducer(); err != nil {
+			return err
+		}
+	}
+
 	msg := &sarama.ProducerMessage{Topic: c.options.Topic, Value: sarama.ByteEncoder(data)}
 	partition, offset, errs := c.producer.SendMessage(msg)
 	if errs != nil {
@@ -95,7 +110,12 @@ func (c *KafkaClient) sendEvent(event *sdk.Event) error {
 	return nil
 }
 
-// status: here, if c is initialized, Kafka is ok
+// status: Kafka is ok if the producer is created
 func (c *KafkaClient) status() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.producer == nil {
+		return "Kafka KO"
+	}
 	return "Kafka OK"
 }
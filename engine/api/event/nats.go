@@ -0,0 +1,221 @@
+package event
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const natsTimeout = 10 * time.Second
+
+// NATSClient embeddes the NATS connection. It implements the publishing part of the NATS client protocol
+type NATSClient struct {
+	options NATSConfig
+	mutex   sync.Mutex
+	conn    net.Conn
+	reader  *bufio.Reader
+}
+
+// NATSConfig handles all config to connect to a NATS server
+type NATSConfig struct {
+	SinkConfig
+	Enabled bool
+	URL     string
+	Subject string
+}
+
+type natsInfo struct {
+	TLSRequired bool `json:"tls_required"`
+}
+
+type natsConnect struct {
+	Verbose  bool   `json:"verbose"`
+	Pedantic bool   `json:"pedantic"`
+	Name     string `json:"name"`
+	Lang     string `json:"lang"`
+	Version  string `json:"version"`
+	User     string `json:"user,omitempty"`
+	Pass     string `json:"pass,omitempty"`
+	Token    string `json:"auth_token,omitempty"`
+}
+
+// initialize returns broker, isInit and err if
+func (c *NATSClient) initialize(options interface{}) (Broker, error) {
+	conf, ok := options.(NATSConfig)
+	if !ok {
+		return nil, fmt.Errorf("Invalid NATS Initialization")
+	}
+
+	if conf.URL == "" || conf.Subject == "" {
+		return nil, fmt.Errorf("initNATS> Invalid NATS Configuration")
+	}
+	c.options = conf
+
+	//The connection is opened again on the first event if the server is not available
+	if err := c.connect(); err != nil {
+		log.Warning("initNATS> Error with init nats: %s", err)
+	}
+
+	return c, nil
+}
+
+// connect opens the connection: the server sends INFO, then the client sends CONNECT
+func (c *NATSClient) connect() error {
+	u, err := url.Parse(c.options.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url %s: %s", c.options.URL, err)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "4222")
+	}
+
+	conn, err := net.DialTimeout("tcp", host, natsTimeout)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(natsTimeout))
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if !strings.HasPrefix(line, "INFO ") {
+		conn.Close()
+		return fmt.Errorf("unexpected message from nats server: %s", strings.TrimSpace(line))
+	}
+
+	var info natsInfo
+	if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "INFO ")), &info); err != nil {
+		conn.Close()
+		return fmt.Errorf("cannot read nats server info: %s", err)
+	}
+
+	if info.TLSRequired || u.Scheme == "tls" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return err
+		}
+		conn = tlsConn
+		reader = bufio.NewReader(conn)
+	}
+
+	connect := natsConnect{Name: "cds-" + cdsname, Lang: "go", Version: sdk.VERSION}
+	if u.User != nil {
+		if pass, ok := u.User.Password(); ok {
+			connect.User = u.User.Username()
+			connect.Pass = pass
+		} else {
+			connect.Token = u.User.Username()
+		}
+	}
+	btes, _ := json.Marshal(connect)
+
+	c.conn = conn
+	c.reader = reader
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\n", btes); err != nil {
+		c.reset()
+		return err
+	}
+	// The server answers PONG only once the connection is accepted
+	if err := c.ping(); err != nil {
+		c.reset()
+		return err
+	}
+
+	log.Debug("initNATS> NATS used at %s on subject:%s", u.Host, c.options.Subject)
+	return nil
+}
+
+// ping sends a PING and waits for the PONG, so that all previous messages have been processed by the server
+func (c *NATSClient) ping() error {
+	c.conn.SetDeadline(time.Now().Add(natsTimeout))
+	if _, err := c.conn.Write([]byte("PING\r\n")); err != nil {
+		return err
+	}
+	for {
+		line, err := c.reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := c.conn.Write([]byte("PONG\r\n")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("nats error: %s", strings.TrimSpace(strings.TrimPrefix(line, "-ERR")))
+		}
+	}
+}
+
+// reset closes the connection, it will be opened again on next event
+func (c *NATSClient) reset() {
+	if c.conn != nil {
+		c.conn.Close()
+	}
+	c.conn = nil
+	c.reader = nil
+}
+
+// close closes connection
+func (c *NATSClient) close() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.reset()
+}
+
+// sendEvent publishes the event on the subject
+func (c *NATSClient) sendEvent(event *sdk.Event) error {
+	data, errm := json.Marshal(event)
+	if errm != nil {
+		return errm
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.conn == nil {
+		if err := c.connect(); err != nil {
+			return err
+		}
+	}
+
+	c.conn.SetDeadline(time.Now().Add(natsTimeout))
+	if _, err := fmt.Fprintf(c.conn, "PUB %s %d\r\n%s\r\n", c.options.Subject, len(data), data); err != nil {
+		c.reset()
+		return err
+	}
+	if err := c.ping(); err != nil {
+		c.reset()
+		return err
+	}
+
+	log.Debug("Event %+v sent to subject %s", event, c.options.Subject)
+	return nil
+}
+
+// status returns the state of the connection
+func (c *NATSClient) status() string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.conn == nil {
+		return "NATS KO"
+	}
+	return "NATS OK"
+}
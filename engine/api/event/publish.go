@@ -1,6 +1,7 @@
 package event
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -14,6 +15,9 @@ import (
 
 var Cache cache.Store
 
+// PubSubChannel is the cache channel where all the events are published
+const PubSubChannel = "events_pubsub"
+
 // Publish sends a event to a queue
 //func Publish(event sdk.Event, eventType string) {
 func Publish(payload interface{}) {
@@ -28,11 +32,95 @@ func Publish(payload interface{}) {
 	Cache.Enqueue("events", event)
 	// send to cache for cds repositories manager
 	Cache.Enqueue("events_repositoriesmanager", event)
+
+	// send to cache pub/sub for clients listening the events stream
+	if b, err := json.Marshal(event); err == nil {
+		Cache.Publish(PubSubChannel, string(b))
+	}
+}
+
+// PublishWorkflowRun sends a workflow run event
+func PublishWorkflowRun(wr *sdk.WorkflowRun) {
+	e := sdk.EventWorkflowRun{
+		ID:            wr.ID,
+		Number:        wr.Number,
+		LastSubNumber: wr.LastSubNumber,
+		Status:        sdk.StatusFromString(wr.Status),
+		Start:         wr.Start.Unix(),
+		LastModified:  wr.LastModified.Unix(),
+		ProjectKey:    wr.Workflow.ProjectKey,
+		WorkflowName:  wr.Workflow.Name,
+	}
+	for _, t := range wr.Tags {
+		switch t.Tag {
+		case "git.branch":
+			e.BranchName = t.Value
+		case "git.hash":
+			e.Hash = t.Value
+		}
+	}
+
+	Publish(e)
 }
 
-// PublishJobRun sends an event
+// PublishWorkflowNodeRun sends a workflow node run event
+func PublishWorkflowNodeRun(wr *sdk.WorkflowRun, n *sdk.WorkflowNodeRun) {
+	e := sdk.EventWorkflowNodeRun{
+		ID:              n.ID,
+		WorkflowRunID:   n.WorkflowRunID,
+		WorkflowNodeID:  n.WorkflowNodeID,
+		Number:          n.Number,
+		SubNumber:       n.SubNumber,
+		Status:          sdk.StatusFromString(n.Status),
+		Start:           n.Start.Unix(),
+		Done:            n.Done.Unix(),
+		ProjectKey:      sdk.ParameterValue(n.BuildParameters, "cds.project"),
+		WorkflowName:    sdk.ParameterValue(n.BuildParameters, "cds.workflow"),
+		PipelineName:    sdk.ParameterValue(n.BuildParameters, "cds.pipeline"),
+		ApplicationName: sdk.ParameterValue(n.BuildParameters, "cds.application"),
+		EnvironmentName: sdk.ParameterValue(n.BuildParameters, "cds.environment"),
+		BranchName:      sdk.ParameterValue(n.BuildParameters, "git.branch"),
+		Hash:            sdk.ParameterValue(n.BuildParameters, "git.hash"),
+	}
+
+	if wr != nil {
+		if node := wr.Workflow.GetNode(n.WorkflowNodeID); node != nil {
+			e.NodeName = node.Name
+			if node.Context != nil && node.Context.Application != nil {
+				e.RepositoryFullname = node.Context.Application.RepositoryFullname
+				if node.Context.Application.RepositoriesManager != nil {
+					e.RepositoryManagerName = node.Context.Application.RepositoriesManager.Name
+				}
+			}
+		}
+	}
+
+	Publish(e)
+}
+
+// PublishJobRun sends a workflow node job run event
 func PublishJobRun(n *sdk.WorkflowNodeRun, j *sdk.WorkflowNodeJobRun) {
-	//TODO PublishJobRun sends an event
+	e := sdk.EventWorkflowNodeJobRun{
+		ID:                j.ID,
+		WorkflowNodeRunID: j.WorkflowNodeRunID,
+		Number:            n.Number,
+		SubNumber:         n.SubNumber,
+		JobName:           j.Job.Action.Name,
+		Status:            sdk.StatusFromString(j.Status),
+		Queued:            j.Queued.Unix(),
+		Start:             j.Start.Unix(),
+		Done:              j.Done.Unix(),
+		ModelName:         j.Model,
+		ProjectKey:        sdk.ParameterValue(n.BuildParameters, "cds.project"),
+		WorkflowName:      sdk.ParameterValue(n.BuildParameters, "cds.workflow"),
+		PipelineName:      sdk.ParameterValue(n.BuildParameters, "cds.pipeline"),
+		ApplicationName:   sdk.ParameterValue(n.BuildParameters, "cds.application"),
+		EnvironmentName:   sdk.ParameterValue(n.BuildParameters, "cds.environment"),
+		BranchName:        sdk.ParameterValue(n.BuildParameters, "git.branch"),
+		Hash:              sdk.ParameterValue(n.BuildParameters, "git.hash"),
+	}
+
+	Publish(e)
 }
 
 // PublishActionBuild sends a actionBuild event
@@ -0,0 +1,184 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	defaultRetryDelay = time.Second
+	maxRetryDelay     = 5 * time.Minute
+)
+
+// SinkConfig handles the configuration shared by all the event brokers
+type SinkConfig struct {
+	Filter     Filter
+	MaxRetries int
+	RetryDelay time.Duration
+}
+
+// Filter selects the events sent to a broker. An empty list matches everything
+type Filter struct {
+	ProjectKeys []string
+	EventTypes  []string
+	Status      []string
+}
+
+// NewFilter returns a filter from comma separated lists of project keys, event types and status
+func NewFilter(projectKeys, eventTypes, status string) Filter {
+	return Filter{
+		ProjectKeys: splitList(projectKeys),
+		EventTypes:  splitList(eventTypes),
+		Status:      splitList(status),
+	}
+}
+
+func splitList(s string) []string {
+	res := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+// Match returns true if the event has to be sent
+func (f Filter) Match(e *sdk.Event) bool {
+	if len(f.EventTypes) > 0 {
+		eventType := strings.TrimPrefix(e.EventType, "sdk.")
+		if !containsFold(f.EventTypes, eventType) && !containsFold(f.EventTypes, e.EventType) {
+			return false
+		}
+	}
+	if len(f.ProjectKeys) > 0 && !containsFold(f.ProjectKeys, payloadValue(e, "ProjectKey")) {
+		return false
+	}
+	if len(f.Status) > 0 && !containsFold(f.Status, payloadValue(e, "Status")) {
+		return false
+	}
+	return true
+}
+
+func payloadValue(e *sdk.Event, key string) string {
+	v, ok := e.Payload[key]
+	if !ok || v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+func containsFold(list []string, s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// DeadLetter is an event which has not been sent to a broker after all the retries
+type DeadLetter struct {
+	Sink      string    `json:"sink"`
+	Error     string    `json:"error"`
+	Timestamp time.Time `json:"timestamp"`
+	Event     sdk.Event `json:"event"`
+}
+
+// sink wraps a broker with its own queue, filter and retry policy.
+// A slow or unavailable broker doesn't delay the events sent to the others
+type sink struct {
+	name       string
+	broker     Broker
+	filter     Filter
+	maxRetries int
+	retryDelay time.Duration
+}
+
+func newSink(name string, b Broker, conf SinkConfig) *sink {
+	s := &sink{
+		name:       name,
+		broker:     b,
+		filter:     conf.Filter,
+		maxRetries: conf.MaxRetries,
+		retryDelay: conf.RetryDelay,
+	}
+	if s.maxRetries < 0 {
+		s.maxRetries = 0
+	}
+	if s.retryDelay <= 0 {
+		s.retryDelay = defaultRetryDelay
+	}
+	return s
+}
+
+func (s *sink) queueName() string {
+	return "events_" + s.name
+}
+
+// DeadLetterQueueName returns the name of the queue where undelivered events of a broker are kept
+func DeadLetterQueueName(name string) string {
+	return "events_" + name + "_dlq"
+}
+
+// run dequeues the events of the sink and sends them
+func (s *sink) run(c context.Context) {
+	for {
+		e := sdk.Event{}
+		Cache.DequeueWithContext(c, s.queueName(), &e)
+		if err := c.Err(); err != nil {
+			log.Error("Exiting event.sink.run %s: %v", s.name, err)
+			return
+		}
+
+		if err := s.send(c, &e); err != nil {
+			if c.Err() != nil {
+				// Keep the event for the next start
+				Cache.Enqueue(s.queueName(), e)
+				return
+			}
+			log.Error("event.sink> Unable to send event %s to %s after %d attempts, moved to dead letter queue: %s", e.EventType, s.name, e.Attempts, err)
+			Cache.Enqueue(DeadLetterQueueName(s.name), DeadLetter{
+				Sink:      s.name,
+				Error:     err.Error(),
+				Timestamp: time.Now(),
+				Event:     e,
+			})
+		}
+	}
+}
+
+// send sends the event to the broker, and retries with an exponential backoff on failure
+func (s *sink) send(c context.Context, e *sdk.Event) error {
+	delay := s.retryDelay
+	for {
+		e.Attempts++
+		err := s.broker.sendEvent(e)
+		if err == nil {
+			return nil
+		}
+		if e.Attempts > s.maxRetries {
+			return err
+		}
+		log.Warning("event.sink> Error while sending event to %s (attempt %d), retrying in %s: %s", s.name, e.Attempts, delay, err)
+
+		select {
+		case <-time.After(delay):
+		case <-c.Done():
+			return c.Err()
+		}
+
+		delay *= 2
+		if delay > maxRetryDelay {
+			delay = maxRetryDelay
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/fatih/structs"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func newTestEvent(payload interface{}) *sdk.Event {
+	return &sdk.Event{
+		EventType: fmt.Sprintf("%T", payload),
+		Payload:   structs.Map(payload),
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	e := newTestEvent(sdk.EventPipelineBuild{ProjectKey: "PROJ", Status: sdk.StatusFail})
+
+	assert.True(t, NewFilter("", "", "").Match(e))
+	assert.True(t, NewFilter("PROJ, OTHER", "", "").Match(e))
+	assert.False(t, NewFilter("OTHER", "", "").Match(e))
+	assert.True(t, NewFilter("", "EventPipelineBuild", "").Match(e))
+	assert.True(t, NewFilter("", "sdk.EventPipelineBuild", "").Match(e))
+	assert.False(t, NewFilter("", "EventJob", "").Match(e))
+	assert.True(t, NewFilter("proj", "EventPipelineBuild", "Fail").Match(e))
+	assert.False(t, NewFilter("PROJ", "", "Success").Match(e))
+
+	// Events without project are filtered out as soon as a project filter is set
+	assert.False(t, NewFilter("PROJ", "", "").Match(newTestEvent(sdk.EventEngine{Message: "started"})))
+}
+
+type failingBroker struct {
+	failures int
+	calls    int
+}
+
+func (b *failingBroker) initialize(options interface{}) (Broker, error) { return b, nil }
+func (b *failingBroker) close()                                         {}
+func (b *failingBroker) status() string                                 { return "OK" }
+func (b *failingBroker) sendEvent(event *sdk.Event) error {
+	b.calls++
+	if b.calls <= b.failures {
+		return fmt.Errorf("failure %d", b.calls)
+	}
+	return nil
+}
+
+func TestSinkSendRetries(t *testing.T) {
+	b := &failingBroker{failures: 2}
+	s := newSink("test", b, SinkConfig{MaxRetries: 3, RetryDelay: time.Millisecond})
+	e := newTestEvent(sdk.EventEngine{Message: "started"})
+	assert.NoError(t, s.send(context.Background(), e))
+	assert.Equal(t, 3, b.calls)
+	assert.Equal(t, 3, e.Attempts)
+
+	b = &failingBroker{failures: 10}
+	s = newSink("test", b, SinkConfig{MaxRetries: 3, RetryDelay: time.Millisecond})
+	e = newTestEvent(sdk.EventEngine{Message: "started"})
+	assert.EqualError(t, s.send(context.Background(), e), "failure 4")
+	assert.Equal(t, 4, b.calls)
+}
+
+func TestWebhookSendEvent(t *testing.T) {
+	var received sdk.Event
+	status := http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Header.Get(WebhookSignatureHeader) != WebhookSignature("secret", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "sdk.EventEngine", r.Header.Get(WebhookEventTypeHeader))
+		json.Unmarshal(body, &received)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	b, err := getBroker("webhook", WebhookConfig{URL: srv.URL, Secret: "secret"})
+	assert.NoError(t, err)
+	assert.NoError(t, b.sendEvent(newTestEvent(sdk.EventEngine{Message: "started"})))
+	assert.Equal(t, "started", received.Payload["Message"])
+
+	status = http.StatusInternalServerError
+	assert.Error(t, b.sendEvent(newTestEvent(sdk.EventEngine{Message: "started"})))
+
+	b, err = getBroker("webhook", WebhookConfig{URL: srv.URL, Secret: "wrong"})
+	assert.NoError(t, err)
+	assert.Error(t, b.sendEvent(newTestEvent(sdk.EventEngine{Message: "started"})))
+}
+
+func TestInitializeUnavailableBroker(t *testing.T) {
+	defer Close()
+
+	err := Initialize(Config{
+		NATS:    NATSConfig{Enabled: true, URL: "nats://127.0.0.1:1", Subject: "cds"},
+		AMQP:    AMQPConfig{Enabled: true},
+		Webhook: WebhookConfig{Enabled: true, URL: "http://127.0.0.1:1", Secret: "secret"},
+	})
+	assert.EqualError(t, err, "invalid configuration of broker(s) amqp, the other brokers are started")
+	assert.Len(t, sinks, 2)
+	assert.Equal(t, "NATS KO", sinks[0].broker.status())
+}
@@ -0,0 +1,107 @@
+package event
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	// WebhookSignatureHeader contains the HMAC-SHA256 signature of the body, computed with the webhook secret
+	WebhookSignatureHeader = "X-CDS-Signature"
+	// WebhookEventTypeHeader contains the type of the event
+	WebhookEventTypeHeader = "X-CDS-Event-Type"
+
+	defaultWebhookTimeout = 10 * time.Second
+)
+
+// WebhookClient sends events to an HTTP endpoint
+type WebhookClient struct {
+	options WebhookConfig
+	client  *http.Client
+}
+
+// WebhookConfig handles all config to send events to an HTTP endpoint
+type WebhookConfig struct {
+	SinkConfig
+	Enabled bool
+	URL     string
+	Secret  string
+	Timeout time.Duration
+}
+
+// initialize returns broker, isInit and err if
+func (c *WebhookClient) initialize(options interface{}) (Broker, error) {
+	conf, ok := options.(WebhookConfig)
+	if !ok {
+		return nil, fmt.Errorf("Invalid Webhook Initialization")
+	}
+
+	if conf.URL == "" || conf.Secret == "" {
+		return nil, fmt.Errorf("initWebhook> Invalid Webhook Configuration")
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = defaultWebhookTimeout
+	}
+	c.options = conf
+	c.client = &http.Client{Timeout: conf.Timeout}
+
+	log.Debug("initWebhook> Webhook used at %s", conf.URL)
+	return c, nil
+}
+
+// close does nothing, there is no connection to close
+func (c *WebhookClient) close() {}
+
+// WebhookSignature returns the signature of a webhook body: "sha256=" followed by the hex encoded HMAC-SHA256
+func WebhookSignature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// sendEvent posts the event, any response other than 2xx is an error
+func (c *WebhookClient) sendEvent(event *sdk.Event) error {
+	data, errm := json.Marshal(event)
+	if errm != nil {
+		return errm
+	}
+
+	req, err := http.NewRequest("POST", c.options.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "CDS/"+sdk.VERSION)
+	req.Header.Set(WebhookEventTypeHeader, event.EventType)
+	req.Header.Set(WebhookSignatureHeader, WebhookSignature(c.options.Secret, data))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %s", c.options.URL, resp.Status)
+	}
+
+	log.Debug("Event %+v sent to webhook %s", event, c.options.URL)
+	return nil
+}
+
+// status: here, if c is initialized, webhook is ok
+func (c *WebhookClient) status() string {
+	return "Webhook OK"
+}
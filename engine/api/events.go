@@ -0,0 +1,229 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/engine/api/sessionstore"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// eventsBrokerSubscribe is the information needed to subscribe to the events stream
+type eventsBrokerSubscribe struct {
+	UUID      string
+	User      *sdk.User
+	Projects  []string
+	Workflows []string
+	Queue     chan string
+}
+
+// eventsBroker keeps connected clients of the events stream
+type eventsBroker struct {
+	clients    map[string]*eventsBrokerSubscribe
+	newClients chan *eventsBrokerSubscribe
+	messages   chan string
+	mutex      *sync.Mutex
+}
+
+//Init the eventsBroker
+func (b *eventsBroker) Init(c context.Context, DBFunc func() *gorp.DbMap, store cache.Store) {
+	// Start cache Subscription
+	go eventsSubscribe(c, b.messages, store)
+
+	// Start processing events
+	go b.Start(c, DBFunc, store)
+}
+
+// eventsSubscribe reads the events published in the cache. Unlike CacheSubscribe, which reads a message
+// every 250ms, the next message is read as soon as the previous one has been sent to the broker
+func eventsSubscribe(c context.Context, messages chan<- string, store cache.Store) {
+	pubSub := store.Subscribe(event.PubSubChannel)
+	for {
+		msg, err := store.GetMessageFromSubscription(c, pubSub)
+		if c.Err() != nil {
+			log.Error("eventsSubscribe> Exiting: %v", c.Err())
+			return
+		}
+		if err != nil {
+			log.Warning("eventsSubscribe> Cannot get message %s: %s", msg, err)
+			time.Sleep(5 * time.Second)
+			continue
+		}
+
+		select {
+		case messages <- msg:
+		case <-c.Done():
+		}
+	}
+}
+
+// Start the broker
+func (b *eventsBroker) Start(c context.Context, DBFunc func() *gorp.DbMap, store cache.Store) {
+	for {
+		select {
+		case <-c.Done():
+			b.mutex.Lock()
+			for c := range b.clients {
+				delete(b.clients, c)
+			}
+			b.mutex.Unlock()
+			if c.Err() != nil {
+				log.Error("eventsBroker.Start> Exiting: %v", c.Err())
+				return
+			}
+		case s := <-b.newClients:
+			b.mutex.Lock()
+			b.clients[s.UUID] = s
+			b.mutex.Unlock()
+		case msg := <-b.messages:
+			var e sdk.Event
+			if err := json.Unmarshal([]byte(msg), &e); err != nil {
+				log.Warning("eventsBroker.Start> Cannot unmarshal message: %s", msg)
+				continue
+			}
+
+			b.mutex.Lock()
+			for _, i := range b.clients {
+				if !i.match(&e) {
+					continue
+				}
+
+				if err := loadUserPermissions(DBFunc(), store, i.User); err != nil {
+					log.Warning("eventsBroker.Start> Cannot load user permission: %s", err)
+					continue
+				}
+				if !eventReadPermission(i.User, &e) {
+					continue
+				}
+
+				// Never block the broker on a slow client
+				select {
+				case i.Queue <- msg:
+				default:
+					log.Warning("eventsBroker.Start> Client %s is too slow, event dropped", i.User.Username)
+				}
+			}
+			b.mutex.Unlock()
+		}
+	}
+}
+
+func eventPayloadValue(e *sdk.Event, key string) string {
+	v, ok := e.Payload[key]
+	if !ok || v == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// match checks the filters of the client
+func (s *eventsBrokerSubscribe) match(e *sdk.Event) bool {
+	if len(s.Projects) > 0 && !isInStringArray(eventPayloadValue(e, "ProjectKey"), s.Projects) {
+		return false
+	}
+	if len(s.Workflows) > 0 && !isInStringArray(eventPayloadValue(e, "WorkflowName"), s.Workflows) {
+		return false
+	}
+	return true
+}
+
+func isInStringArray(elt string, array []string) bool {
+	for _, item := range array {
+		if item == elt {
+			return true
+		}
+	}
+	return false
+}
+
+// eventReadPermission returns true if the user can read the project of the event.
+// Events which are not related to a project are only sent to administrators
+func eventReadPermission(u *sdk.User, e *sdk.Event) bool {
+	if u.Admin {
+		return true
+	}
+	key := eventPayloadValue(e, "ProjectKey")
+	if key == "" {
+		return false
+	}
+	for _, g := range u.Groups {
+		for _, pg := range g.ProjectGroups {
+			if pg.Project.Key == key {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (b *eventsBroker) ServeHTTP() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		// Make sure that the writer supports flushing.
+		f, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "Streaming unsupported!", http.StatusInternalServerError)
+			return nil
+		}
+
+		uuid, errS := sessionstore.NewSessionKey()
+		if errS != nil {
+			return sdk.WrapError(errS, "eventsBroker.Serve> Cannot generate UUID")
+		}
+
+		if err := r.ParseForm(); err != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "eventsBroker.Serve> Cannot parse query: %s", err)
+		}
+
+		client := &eventsBrokerSubscribe{
+			UUID:      string(uuid),
+			User:      getUser(ctx),
+			Projects:  r.Form["project"],
+			Workflows: r.Form["workflow"],
+			Queue:     make(chan string, 100),
+		}
+
+		// Add this client to the map of those that should receive events
+		b.newClients <- client
+		defer func() {
+			b.mutex.Lock()
+			delete(b.clients, client.UUID)
+			b.mutex.Unlock()
+		}()
+
+		// Set the headers related to event streaming.
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		f.Flush()
+
+		// Comments keep the connection opened through proxies
+		keepAlive := time.NewTicker(30 * time.Second)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case <-w.(http.CloseNotifier).CloseNotify():
+				return nil
+			case <-ctx.Done():
+				return nil
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+				f.Flush()
+			case msg := <-client.Queue:
+				fmt.Fprintf(w, "data: %s\n\n", msg)
+				f.Flush()
+			}
+		}
+	}
+}
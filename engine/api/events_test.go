@@ -0,0 +1,30 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/fatih/structs"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_eventsBrokerFilterAndPermission(t *testing.T) {
+	e := &sdk.Event{
+		EventType: "sdk.EventWorkflowRun",
+		Payload:   structs.Map(sdk.EventWorkflowRun{ProjectKey: "PROJ", WorkflowName: "wf"}),
+	}
+
+	assert.True(t, (&eventsBrokerSubscribe{}).match(e))
+	assert.True(t, (&eventsBrokerSubscribe{Projects: []string{"PROJ"}, Workflows: []string{"wf"}}).match(e))
+	assert.False(t, (&eventsBrokerSubscribe{Projects: []string{"OTHER"}}).match(e))
+	assert.False(t, (&eventsBrokerSubscribe{Workflows: []string{"other"}}).match(e))
+
+	u := &sdk.User{Groups: []sdk.Group{{ProjectGroups: []sdk.ProjectGroup{{Project: sdk.Project{Key: "PROJ"}}}}}}
+	assert.True(t, eventReadPermission(u, e))
+	assert.False(t, eventReadPermission(&sdk.User{}, e))
+	assert.True(t, eventReadPermission(&sdk.User{Admin: true}, e))
+
+	engine := &sdk.Event{Payload: structs.Map(sdk.EventEngine{Message: "started"})}
+	assert.False(t, eventReadPermission(u, engine))
+}
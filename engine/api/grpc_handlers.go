@@ -129,15 +129,16 @@ func (h *grpcHandlers) SendResult(c context.Context, res *sdk.Result) (*empty.Em
 		return new(empty.Empty), sdk.WrapError(errP, "postWorkflowJobResultHandler> Cannot load project")
 	}
 
+	events := &workflow.RunEvents{}
 	//Add spawn infos
-	if _, err := workflow.AddSpawnInfosNodeJobRun(tx, h.store, p, job.ID, infos); err != nil {
+	if _, err := workflow.AddSpawnInfosNodeJobRun(tx, h.store, events, p, job.ID, infos); err != nil {
 		log.Error("addQueueResultHandler> Cannot save spawn info job %d: %s", job.ID, err)
 		return nil, err
 	}
 
 	// Update action status
 	log.Debug("postWorkflowJobResultHandler> Updating %d to %s in queue", workerID, res.Status)
-	if err := workflow.UpdateNodeJobRunStatus(tx, h.store, p, job, sdk.Status(res.Status)); err != nil {
+	if err := workflow.UpdateNodeJobRunStatus(tx, h.store, events, p, job, sdk.Status(res.Status)); err != nil {
 		return new(empty.Empty), sdk.WrapError(err, "postWorkflowJobResultHandler> Cannot update %d status", workerID)
 	}
 
@@ -145,6 +146,7 @@ func (h *grpcHandlers) SendResult(c context.Context, res *sdk.Result) (*empty.Em
 	if err := tx.Commit(); err != nil {
 		return new(empty.Empty), sdk.WrapError(err, "postWorkflowJobResultHandler> Cannot commit tx")
 	}
+	events.Publish()
 
 	return new(empty.Empty), nil
 }
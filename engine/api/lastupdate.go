@@ -34,15 +34,15 @@ type lastUpdateBroker struct {
 //Init the lastUpdateBroker
 func (b *lastUpdateBroker) Init(c context.Context, DBFunc func() *gorp.DbMap, store cache.Store) {
 	// Start cache Subscription
-	go CacheSubscribe(c, b.messages, store)
+	go CacheSubscribe(c, b.messages, store, "lastUpdates")
 
 	// Start processing events
 	go b.Start(c, DBFunc, store)
 }
 
 // CacheSubscribe subscribe to a channel and push received message in a channel
-func CacheSubscribe(c context.Context, cacheMsgChan chan<- string, store cache.Store) {
-	pubSub := store.Subscribe("lastUpdates")
+func CacheSubscribe(c context.Context, cacheMsgChan chan<- string, store cache.Store, channel string) {
+	pubSub := store.Subscribe(channel)
 	tick := time.NewTicker(250 * time.Millisecond)
 	defer tick.Stop()
 	for {
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/ovh/cds/engine/api/auth"
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/sessionstore"
+	"github.com/ovh/cds/engine/api/token"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	// oidcStateTTL is the delay in seconds for the user to log in on the OpenID Connect provider
+	oidcStateTTL = 600
+	// oidcStateCookie binds the state to the browser which has started the login
+	oidcStateCookie = "cds_oidc_state"
+)
+
+func (api *API) oidcDriver() (*auth.OIDCClient, error) {
+	d, ok := api.Router.AuthDriver.(*auth.OIDCClient)
+	if !ok {
+		return nil, sdk.ErrOIDCNotEnabled
+	}
+	return d, nil
+}
+
+// getLoginOIDCHandler returns the URL of the OpenID Connect provider where the UI redirects the user
+func (api *API) getLoginOIDCHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		d, err := api.oidcDriver()
+		if err != nil {
+			return err
+		}
+
+		state, errS := token.GenerateToken()
+		if errS != nil {
+			return sdk.WrapError(errS, "getLoginOIDCHandler> Cannot generate state")
+		}
+		nonce, errN := token.GenerateToken()
+		if errN != nil {
+			return sdk.WrapError(errN, "getLoginOIDCHandler> Cannot generate nonce")
+		}
+		// The nonce is kept until the provider redirects the user with the state
+		api.Cache.SetWithTTL(cache.Key("oidc", "state", state), nonce, oidcStateTTL)
+		// Without path, the cookie is sent back on the callback route, whatever the prefix of the API in the UI
+		http.SetCookie(w, &http.Cookie{
+			Name:     oidcStateCookie,
+			Value:    state,
+			MaxAge:   oidcStateTTL,
+			HttpOnly: true,
+			Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+		})
+
+		return WriteJSON(w, r, sdk.OIDCLoginResponse{URL: d.AuthCodeURL(state, nonce)}, http.StatusOK)
+	}
+}
+
+// postLoginOIDCCallbackHandler exchanges the code given by the OpenID Connect provider and opens a session
+func (api *API) postLoginOIDCCallbackHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		d, err := api.oidcDriver()
+		if err != nil {
+			return err
+		}
+
+		var req sdk.OIDCCallbackRequest
+		if err := UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+
+		// The state must come from the browser which has started the login, not only from the provider
+		cookie, errC := r.Cookie(oidcStateCookie)
+		if errC != nil || req.State == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(req.State)) != 1 {
+			return sdk.WrapError(sdk.ErrOIDCInvalidState, "postLoginOIDCCallbackHandler> State is not bound to this browser")
+		}
+		http.SetCookie(w, &http.Cookie{Name: oidcStateCookie, MaxAge: -1, HttpOnly: true})
+
+		var nonce string
+		k := cache.Key("oidc", "state", req.State)
+		if !api.Cache.Get(k, &nonce) || nonce == "" {
+			return sdk.ErrOIDCInvalidState
+		}
+		api.Cache.Delete(k)
+
+		claims, errE := d.Exchange(req.Code, nonce)
+		if errE != nil {
+			return sdk.WrapError(sdk.ErrInvalidUser, "postLoginOIDCCallbackHandler> Login failed: %s", errE)
+		}
+		return api.loginOIDCUser(d, claims, false, w, r)
+	}
+}
+
+// postLoginOIDCDeviceHandler starts the device flow for the CLI
+func (api *API) postLoginOIDCDeviceHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		d, err := api.oidcDriver()
+		if err != nil {
+			return err
+		}
+
+		res, errD := d.DeviceAuthorization()
+		if errD != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "postLoginOIDCDeviceHandler> Cannot start device flow: %s", errD)
+		}
+		return WriteJSON(w, r, res, http.StatusOK)
+	}
+}
+
+// postLoginOIDCDeviceTokenHandler is polled by the CLI until the user has logged in on the OpenID Connect provider
+func (api *API) postLoginOIDCDeviceTokenHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		d, err := api.oidcDriver()
+		if err != nil {
+			return err
+		}
+
+		var req sdk.OIDCDeviceTokenRequest
+		if err := UnmarshalBody(r, &req); err != nil {
+			return err
+		}
+		if req.DeviceCode == "" {
+			return sdk.ErrWrongRequest
+		}
+
+		claims, errT := d.DeviceToken(req.DeviceCode)
+		if errT == sdk.ErrOIDCAuthorizationPending {
+			return errT
+		}
+		if errT != nil {
+			return sdk.WrapError(sdk.ErrInvalidUser, "postLoginOIDCDeviceTokenHandler> Login failed: %s", errT)
+		}
+		return api.loginOIDCUser(d, claims, true, w, r)
+	}
+}
+
+// loginOIDCUser creates or refreshes the user from the claims of its ID token and opens a new session.
+// A persistent session is opened for the CLI
+func (api *API) loginOIDCUser(d *auth.OIDCClient, claims auth.OIDCClaims, persistent bool, w http.ResponseWriter, r *http.Request) error {
+	tx, errb := api.mustDB().Begin()
+	if errb != nil {
+		return sdk.WrapError(errb, "loginOIDCUser> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	u, erru := d.InsertOrUpdateUser(tx, claims)
+	if erru != nil {
+		return sdk.WrapError(erru, "loginOIDCUser> Cannot get user")
+	}
+	if err := group.CheckUserInDefaultGroup(tx, u.ID); err != nil {
+		log.Warning("Auth> Error while check user in default group:%s\n", err)
+	}
+
+	var sessionKey sessionstore.SessionKey
+	var errs error
+	if persistent {
+		sessionKey, errs = auth.NewPersistentSession(tx, d, u)
+	} else {
+		sessionKey, errs = auth.NewSession(d, u)
+	}
+	if errs != nil {
+		return sdk.WrapError(errs, "loginOIDCUser> Error while creating new session")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "loginOIDCUser> Cannot commit transaction")
+	}
+
+	log.Info("Auth> User %s logged in with OpenID Connect", u.Username)
+	w.Header().Set(sdk.SessionTokenHeader, string(sessionKey))
+	response := sdk.UserAPIResponse{
+		User:  *u,
+		Token: string(sessionKey),
+	}
+	response.User.Auth = sdk.Auth{}
+	return WriteJSON(w, r, response, http.StatusOK)
+}
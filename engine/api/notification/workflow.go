@@ -0,0 +1,282 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	defaultWorkflowSubject = "{{.cds.project}}/{{.cds.workflow}}#{{.cds.version}} {{.cds.node}}: {{.cds.status}}"
+	defaultWorkflowBody    = "Project: {{.cds.project}}\nWorkflow: {{.cds.workflow}}#{{.cds.version}}\nPipeline: {{.cds.pipeline}}\nStatus: {{.cds.status}}\nDetails: {{.cds.buildURL}}"
+)
+
+// workflowNotificationClient posts the workflow notifications. It connects only to the allowed addresses, so that
+// the users who configure notifications cannot reach the internal services of the API
+var workflowNotificationClient = &http.Client{
+	Timeout:   10 * time.Second,
+	Transport: &http.Transport{DialContext: dialWorkflowNotification},
+}
+
+// deniedNetworks are the private, loopback and link-local networks, where notifications are not posted
+var deniedNetworks = parseNetworks("0.0.0.0/8,10.0.0.0/8,100.64.0.0/10,127.0.0.0/8,169.254.0.0/16,172.16.0.0/12,192.168.0.0/16,::/128,::1/128,fc00::/7,fe80::/10")
+
+// allowedNetworks are the denied networks where notifications are posted anyway
+var allowedNetworks []*net.IPNet
+
+// SetAllowedNetworks sets the comma separated list of private networks where workflow notifications can be posted
+func SetAllowedNetworks(networks string) error {
+	nets, err := parseNetworksE(networks)
+	if err != nil {
+		return err
+	}
+	allowedNetworks = nets
+	return nil
+}
+
+func parseNetworksE(networks string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, s := range strings.Split(networks, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %s: %v", s, err)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+func parseNetworks(networks string) []*net.IPNet {
+	nets, err := parseNetworksE(networks)
+	if err != nil {
+		panic(err)
+	}
+	return nets
+}
+
+// isAllowedNotificationIP returns false for private, loopback, link-local and multicast addresses, unless their network is allowed
+func isAllowedNotificationIP(ip net.IP) bool {
+	for _, n := range allowedNetworks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	if ip.IsMulticast() {
+		return false
+	}
+	for _, n := range deniedNetworks {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// dialWorkflowNotification resolves the host of a notification and connects to it only if all its addresses are allowed.
+// The connection is made on the checked address, so that the host cannot be resolved again to another address
+func dialWorkflowNotification(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	for _, ip := range ips {
+		if !isAllowedNotificationIP(ip.IP) {
+			return nil, fmt.Errorf("notifications to %s are not allowed: %s is a private address", host, ip.IP)
+		}
+	}
+
+	var d net.Dialer
+	for _, ip := range ips {
+		var conn net.Conn
+		conn, err = d.DialContext(ctx, network, net.JoinHostPort(ip.IP.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+	}
+	if err == nil {
+		err = fmt.Errorf("no address found for %s", host)
+	}
+	return nil, err
+}
+
+// slackMessage is the payload of slack and mattermost incoming webhooks
+type slackMessage struct {
+	Text     string `json:"text"`
+	Channel  string `json:"channel,omitempty"`
+	Username string `json:"username,omitempty"`
+}
+
+// teamsMessageCard is the payload of Microsoft Teams incoming webhooks
+type teamsMessageCard struct {
+	Type            string               `json:"@type"`
+	Context         string               `json:"@context"`
+	Summary         string               `json:"summary"`
+	ThemeColor      string               `json:"themeColor"`
+	Title           string               `json:"title"`
+	Text            string               `json:"text"`
+	PotentialAction []teamsOpenURIAction `json:"potentialAction,omitempty"`
+}
+
+type teamsOpenURIAction struct {
+	Type    string           `json:"@type"`
+	Name    string           `json:"name"`
+	Targets []teamsURITarget `json:"targets"`
+}
+
+type teamsURITarget struct {
+	OS  string `json:"os"`
+	URI string `json:"uri"`
+}
+
+// webhookMessage is the payload of generic webhooks
+type webhookMessage struct {
+	Subject      string `json:"subject"`
+	Body         string `json:"body"`
+	Status       string `json:"status"`
+	ProjectKey   string `json:"project_key"`
+	WorkflowName string `json:"workflow_name"`
+	NodeName     string `json:"node_name"`
+	PipelineName string `json:"pipeline_name"`
+	Number       int64  `json:"number"`
+	SubNumber    int64  `json:"subnumber"`
+	URL          string `json:"url"`
+}
+
+// SendWorkflowNodeRunNotifications sends the notifications of a node when its run is over.
+// The previous status is the status of the previous run of the node, empty for the first run.
+func SendWorkflowNodeRunNotifications(node *sdk.WorkflowNode, nr *sdk.WorkflowNodeRun, previousStatus string) {
+	params := workflowNodeRunParams(node, nr)
+	for _, n := range node.Notifications {
+		if !n.ShouldNotify(nr.Status, previousStatus) {
+			continue
+		}
+		if err := sendWorkflowNodeNotification(n, nr, params); err != nil {
+			log.Warning("notification.SendWorkflowNodeRunNotifications> Unable to send %s notification of node run %d: %v", n.Type, nr.ID, err)
+		}
+	}
+}
+
+// workflowNodeRunParams returns the variables of the templates: the build parameters of the node run, its status, the node name and the UI url of the node run
+func workflowNodeRunParams(node *sdk.WorkflowNode, nr *sdk.WorkflowNodeRun) map[string]string {
+	params := sdk.ParametersToMap(nr.BuildParameters)
+	params["cds.status"] = nr.Status
+	params["cds.node"] = node.Name
+	params["cds.buildURL"] = fmt.Sprintf("%s/project/%s/workflow/%s/run/%d/node/%d?name=%s", uiURL,
+		params["cds.project"], params["cds.workflow"], nr.Number, nr.ID, url.QueryEscape(params["cds.pipeline"]))
+	return params
+}
+
+// interpolate applies the variables on a template, the raw template is kept if it is invalid
+func interpolate(tmpl, defaultTmpl string, params map[string]string) string {
+	if tmpl == "" {
+		tmpl = defaultTmpl
+	}
+	s, err := sdk.Interpolate(tmpl, params)
+	if err != nil {
+		log.Warning("notification.interpolate> Unable to interpolate %s: %v", tmpl, err)
+		return tmpl
+	}
+	return s
+}
+
+// workflowNotificationPayload returns the body to post for the type of the notification
+func workflowNotificationPayload(n sdk.WorkflowNodeNotification, nr *sdk.WorkflowNodeRun, params map[string]string) (interface{}, error) {
+	subject := interpolate(n.Settings.Template.Subject, defaultWorkflowSubject, params)
+	body := interpolate(n.Settings.Template.Body, defaultWorkflowBody, params)
+
+	switch n.Type {
+	case sdk.SlackUserNotification, sdk.MattermostUserNotification:
+		return slackMessage{
+			Text:     strings.TrimSpace(subject + "\n" + body),
+			Channel:  n.Settings.Channel,
+			Username: "CDS",
+		}, nil
+	case sdk.TeamsUserNotification:
+		var color string
+		switch nr.Status {
+		case sdk.StatusSuccess.String():
+			color = "21BA45"
+		case sdk.StatusFail.String():
+			color = "DB2828"
+		default:
+			color = "767676"
+		}
+		return teamsMessageCard{
+			Type:       "MessageCard",
+			Context:    "https://schema.org/extensions",
+			Summary:    subject,
+			ThemeColor: color,
+			Title:      subject,
+			Text:       strings.Replace(body, "\n", "\n\n", -1),
+			PotentialAction: []teamsOpenURIAction{{
+				Type:    "OpenUri",
+				Name:    "Open in CDS",
+				Targets: []teamsURITarget{{OS: "default", URI: params["cds.buildURL"]}},
+			}},
+		}, nil
+	case sdk.WebhookUserNotification:
+		return webhookMessage{
+			Subject:      subject,
+			Body:         body,
+			Status:       nr.Status,
+			ProjectKey:   params["cds.project"],
+			WorkflowName: params["cds.workflow"],
+			NodeName:     params["cds.node"],
+			PipelineName: params["cds.pipeline"],
+			Number:       nr.Number,
+			SubNumber:    nr.SubNumber,
+			URL:          params["cds.buildURL"],
+		}, nil
+	}
+	return nil, sdk.ErrNotSupportedUserNotification
+}
+
+// sendWorkflowNodeNotification posts the notification, any response other than 2xx is an error
+func sendWorkflowNodeNotification(n sdk.WorkflowNodeNotification, nr *sdk.WorkflowNodeRun, params map[string]string) error {
+	payload, err := workflowNotificationPayload(n, nr, params)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", n.Settings.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "CDS/"+sdk.VERSION)
+
+	resp, err := workflowNotificationClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s notification returned %s", n.Type, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package notification
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func testWorkflowNodeRun() (*sdk.WorkflowNode, *sdk.WorkflowNodeRun) {
+	node := &sdk.WorkflowNode{Name: "deploy-prod"}
+	nr := &sdk.WorkflowNodeRun{
+		ID:        42,
+		Number:    12,
+		SubNumber: 1,
+		Status:    sdk.StatusFail.String(),
+		BuildParameters: []sdk.Parameter{
+			{Name: "cds.project", Type: sdk.StringParameter, Value: "PROJ"},
+			{Name: "cds.workflow", Type: sdk.StringParameter, Value: "my-workflow"},
+			{Name: "cds.pipeline", Type: sdk.StringParameter, Value: "deploy"},
+			{Name: "cds.version", Type: sdk.StringParameter, Value: "12"},
+			{Name: "git.branch", Type: sdk.StringParameter, Value: "master"},
+		},
+	}
+	return node, nr
+}
+
+// testReceiver records the JSON body posted to an incoming webhook, its loopback address is allowed
+func testReceiver(t *testing.T, status int, body *map[string]interface{}) *httptest.Server {
+	assert.NoError(t, SetAllowedNetworks("127.0.0.0/8"))
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(body))
+		w.WriteHeader(status)
+	}))
+}
+
+func TestSendWorkflowNodeNotificationSlack(t *testing.T) {
+	Init("https://cds-api.local", "https://cds.local")
+	node, nr := testWorkflowNodeRun()
+
+	var body map[string]interface{}
+	s := testReceiver(t, http.StatusOK, &body)
+	defer s.Close()
+
+	n := sdk.WorkflowNodeNotification{
+		Type: sdk.MattermostUserNotification,
+		Settings: sdk.WorkflowNodeNotificationSettings{
+			When:     sdk.UserNotificationAlways,
+			URL:      s.URL,
+			Channel:  "deploy",
+			Template: sdk.UserNotificationTemplate{Subject: "{{.cds.node}} on {{.git.branch}}: {{.cds.status}}", Body: "{{.cds.buildURL}}"},
+		},
+	}
+	assert.NoError(t, sendWorkflowNodeNotification(n, nr, workflowNodeRunParams(node, nr)))
+	assert.Equal(t, "deploy-prod on master: Fail\nhttps://cds.local/project/PROJ/workflow/my-workflow/run/12/node/42?name=deploy", body["text"])
+	assert.Equal(t, "deploy", body["channel"])
+	assert.Equal(t, "CDS", body["username"])
+}
+
+func TestSendWorkflowNodeNotificationTeams(t *testing.T) {
+	Init("https://cds-api.local", "https://cds.local")
+	node, nr := testWorkflowNodeRun()
+
+	var body map[string]interface{}
+	s := testReceiver(t, http.StatusOK, &body)
+	defer s.Close()
+
+	n := sdk.WorkflowNodeNotification{
+		Type:     sdk.TeamsUserNotification,
+		Settings: sdk.WorkflowNodeNotificationSettings{When: sdk.UserNotificationAlways, URL: s.URL},
+	}
+	assert.NoError(t, sendWorkflowNodeNotification(n, nr, workflowNodeRunParams(node, nr)))
+	assert.Equal(t, "MessageCard", body["@type"])
+	assert.Equal(t, "PROJ/my-workflow#12 deploy-prod: Fail", body["title"])
+	assert.Equal(t, "DB2828", body["themeColor"])
+	assert.Contains(t, body["text"], "Pipeline: deploy\n\nStatus: Fail")
+}
+
+func TestSendWorkflowNodeNotificationWebhook(t *testing.T) {
+	Init("https://cds-api.local", "https://cds.local")
+	node, nr := testWorkflowNodeRun()
+
+	var body map[string]interface{}
+	s := testReceiver(t, http.StatusOK, &body)
+	defer s.Close()
+
+	n := sdk.WorkflowNodeNotification{
+		Type:     sdk.WebhookUserNotification,
+		Settings: sdk.WorkflowNodeNotificationSettings{When: sdk.UserNotificationAlways, URL: s.URL},
+	}
+	assert.NoError(t, sendWorkflowNodeNotification(n, nr, workflowNodeRunParams(node, nr)))
+	assert.Equal(t, "PROJ/my-workflow#12 deploy-prod: Fail", body["subject"])
+	assert.Equal(t, "Fail", body["status"])
+	assert.Equal(t, "PROJ", body["project_key"])
+	assert.Equal(t, "my-workflow", body["workflow_name"])
+	assert.Equal(t, "deploy-prod", body["node_name"])
+	assert.Equal(t, float64(12), body["number"])
+	assert.Equal(t, float64(1), body["subnumber"])
+}
+
+func TestSendWorkflowNodeNotificationError(t *testing.T) {
+	node, nr := testWorkflowNodeRun()
+
+	var body map[string]interface{}
+	s := testReceiver(t, http.StatusNotFound, &body)
+	defer s.Close()
+
+	n := sdk.WorkflowNodeNotification{
+		Type:     sdk.SlackUserNotification,
+		Settings: sdk.WorkflowNodeNotificationSettings{When: sdk.UserNotificationAlways, URL: s.URL},
+	}
+	assert.Error(t, sendWorkflowNodeNotification(n, nr, workflowNodeRunParams(node, nr)))
+
+	n.Type = sdk.EmailUserNotification
+	assert.Equal(t, sdk.ErrNotSupportedUserNotification, sendWorkflowNodeNotification(n, nr, workflowNodeRunParams(node, nr)))
+}
+
+func TestSendWorkflowNodeNotificationPrivateAddress(t *testing.T) {
+	node, nr := testWorkflowNodeRun()
+
+	var body map[string]interface{}
+	s := testReceiver(t, http.StatusOK, &body)
+	defer s.Close()
+	assert.NoError(t, SetAllowedNetworks(""))
+	defer SetAllowedNetworks("127.0.0.0/8")
+
+	n := sdk.WorkflowNodeNotification{
+		Type:     sdk.WebhookUserNotification,
+		Settings: sdk.WorkflowNodeNotificationSettings{When: sdk.UserNotificationAlways, URL: s.URL},
+	}
+	assert.Error(t, sendWorkflowNodeNotification(n, nr, workflowNodeRunParams(node, nr)))
+	assert.Nil(t, body)
+
+	assert.Error(t, SetAllowedNetworks("10.0.0.0"))
+}
+
+func TestIsAllowedNotificationIP(t *testing.T) {
+	assert.NoError(t, SetAllowedNetworks("10.1.0.0/16"))
+	defer SetAllowedNetworks("")
+
+	for ip, allowed := range map[string]bool{
+		"93.184.216.34":   true,
+		"2001:db8::1":     true,
+		"10.1.2.3":        true,
+		"10.2.2.3":        false,
+		"127.0.0.1":       false,
+		"169.254.169.254": false,
+		"172.16.0.1":      false,
+		"192.168.1.1":     false,
+		"0.0.0.0":         false,
+		"::1":             false,
+		"fd00::1":         false,
+		"224.0.0.1":       false,
+	} {
+		assert.Equal(t, allowed, isAllowedNotificationIP(net.ParseIP(ip)), ip)
+	}
+}
@@ -0,0 +1,64 @@
+package objectstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// Blob is a content addressed object, identified by the sha256 digest of its content.
+// A blob is stored only once, whatever the number of artifacts referencing it
+type Blob struct {
+	SHA256 string
+}
+
+//GetName returns the name the blob
+func (b *Blob) GetName() string {
+	return b.SHA256
+}
+
+//GetPath returns the path of the blob, blobs are spread in containers by the first bytes of their digest
+func (b *Blob) GetPath() string {
+	if len(b.SHA256) < 2 {
+		return "blobs"
+	}
+	return "blobs-" + b.SHA256[:2]
+}
+
+// FetchBlob fetch a blob with default objectstore driver. The digest of the content is checked while reading it
+func FetchBlob(sha256sum string) (io.ReadCloser, error) {
+	f, err := FetchArtifact(&Blob{SHA256: sha256sum})
+	if err != nil {
+		return nil, err
+	}
+	return NewVerifiedReader(f, sha256sum), nil
+}
+
+// NewVerifiedReader returns a reader which computes the sha256 digest of the data read and returns an error
+// instead of io.EOF if it doesn't match the expected one
+func NewVerifiedReader(r io.ReadCloser, sha256sum string) io.ReadCloser {
+	return &verifiedReader{r: r, expected: sha256sum, h: sha256.New()}
+}
+
+type verifiedReader struct {
+	r        io.ReadCloser
+	expected string
+	h        hash.Hash
+}
+
+func (v *verifiedReader) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	v.h.Write(p[:n])
+	if err == io.EOF {
+		if sum := hex.EncodeToString(v.h.Sum(nil)); sum != v.expected {
+			return n, fmt.Errorf("corrupted blob %s: computed sha256 is %s", v.expected, sum)
+		}
+	}
+	return n, err
+}
+
+func (v *verifiedReader) Close() error {
+	return v.r.Close()
+}
@@ -0,0 +1,52 @@
+package objectstore
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewVerifiedReader(t *testing.T) {
+	content := []byte("Hello World")
+	h := sha256.Sum256(content)
+	sum := hex.EncodeToString(h[:])
+
+	r := NewVerifiedReader(ioutil.NopCloser(bytes.NewReader(content)), sum)
+	b, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, content, b)
+
+	r = NewVerifiedReader(ioutil.NopCloser(bytes.NewReader([]byte("Hello Corrupted World"))), sum)
+	_, err = ioutil.ReadAll(r)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "corrupted blob")
+}
+
+func TestBlobStoreAndFetch(t *testing.T) {
+	basedir, err := ioutil.TempDir("", "cds-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(basedir)
+	storage, err = NewFilesystemStore(basedir, "", "")
+	assert.NoError(t, err)
+	defer func() { storage = nil }()
+
+	content := []byte("Hello World")
+	h := sha256.Sum256(content)
+	b := &Blob{SHA256: hex.EncodeToString(h[:])}
+	assert.Equal(t, "blobs-a5", b.GetPath())
+
+	_, err = StoreArtifact(b, ioutil.NopCloser(bytes.NewReader(content)))
+	assert.NoError(t, err)
+
+	r, err := FetchBlob(b.SHA256)
+	assert.NoError(t, err)
+	res, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, content, res)
+	r.Close()
+}
@@ -1,27 +1,36 @@
 package objectstore
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"path"
+	"strconv"
+	"time"
 
 	"github.com/ovh/cds/sdk/log"
 )
 
 // FilesystemStore implements ObjectStore interface with filesystem driver
 type FilesystemStore struct {
-	basedir string
+	basedir    string
+	baseURL    string
+	signingKey []byte
 }
 
-// NewFilesystemStore creates a new ObjectStore with filesystem driver
-func NewFilesystemStore(basedir string) (*FilesystemStore, error) {
+// NewFilesystemStore creates a new ObjectStore with filesystem driver.
+// baseURL and signingKey are used to generate temporary urls, they can be empty
+func NewFilesystemStore(basedir, baseURL, signingKey string) (*FilesystemStore, error) {
 	log.Info("Objectstore> Initialize Filesystem driver on directory: %s", basedir)
 	if basedir == "" {
 		return nil, fmt.Errorf("artifact storage is filesystem, but --artifact-basedir is not provided")
 	}
 
-	fss := &FilesystemStore{basedir: basedir}
+	fss := &FilesystemStore{basedir: basedir, baseURL: baseURL, signingKey: []byte(signingKey)}
 	return fss, nil
 }
 
@@ -61,3 +70,49 @@ func (fss *FilesystemStore) Delete(o Object) error {
 	dst := path.Join(fss.basedir, o.GetPath(), o.GetName())
 	return os.RemoveAll(dst)
 }
+
+// TemporaryURL returns an url on the API signed with HMAC, valid for ttl. The filename is part of the signature
+func (fss *FilesystemStore) TemporaryURL(o Object, filename string, ttl time.Duration) (string, error) {
+	if fss.baseURL == "" || len(fss.signingKey) == 0 {
+		return "", nil
+	}
+
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	q := url.Values{
+		"expires":   []string{expires},
+		"signature": []string{fss.signature(o.GetPath(), o.GetName(), filename, expires)},
+	}
+	if filename != "" {
+		q.Set("filename", filename)
+	}
+
+	return fmt.Sprintf("%s/objectstore/%s/%s?%s", fss.baseURL, url.PathEscape(o.GetPath()), url.PathEscape(o.GetName()), q.Encode()), nil
+}
+
+// FetchSigned checks the signature of a temporary url, then lookup on disk for data
+func (fss *FilesystemStore) FetchSigned(objectPath, name, filename, expires, signature string) (io.ReadCloser, error) {
+	if len(fss.signingKey) == 0 {
+		return nil, fmt.Errorf("temporary urls are not enabled")
+	}
+
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expiration")
+	}
+	if time.Now().Unix() > exp {
+		return nil, fmt.Errorf("url has expired")
+	}
+
+	expected := fss.signature(objectPath, name, filename, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return nil, fmt.Errorf("invalid signature")
+	}
+
+	return fss.Fetch(&object{path: objectPath, name: name})
+}
+
+func (fss *FilesystemStore) signature(objectPath, name, filename, expires string) string {
+	h := hmac.New(sha256.New, fss.signingKey)
+	fmt.Fprintf(h, "%s/%s\n%s\n%s", objectPath, name, filename, expires)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,53 @@
+package objectstore
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilesystemStoreTemporaryURL(t *testing.T) {
+	basedir, err := ioutil.TempDir("", "cds-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(basedir)
+
+	fss, err := NewFilesystemStore(basedir, "http://localhost:8081", "0123456789abcdef0123456789abcdef")
+	assert.NoError(t, err)
+
+	o := testObject{name: "file.txt", path: "proj-app"}
+	_, err = fss.Store(o, ioutil.NopCloser(bytes.NewBufferString("Hello World")))
+	assert.NoError(t, err)
+
+	s, err := fss.TemporaryURL(o, "my-file.txt", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(s, "http://localhost:8081/objectstore/proj-app/file.txt?"))
+
+	u, err := url.Parse(s)
+	assert.NoError(t, err)
+	q := u.Query()
+	assert.Equal(t, "my-file.txt", q.Get("filename"))
+
+	r, err := fss.FetchSigned("proj-app", "file.txt", q.Get("filename"), q.Get("expires"), q.Get("signature"))
+	assert.NoError(t, err)
+	content, _ := ioutil.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "Hello World", string(content))
+
+	_, err = fss.FetchSigned("proj-app", "other.txt", q.Get("filename"), q.Get("expires"), q.Get("signature"))
+	assert.Error(t, err)
+
+	_, err = fss.FetchSigned("proj-app", "file.txt", "other.txt", q.Get("expires"), q.Get("signature"))
+	assert.Error(t, err)
+
+	expired, err := fss.TemporaryURL(o, "", -time.Minute)
+	assert.NoError(t, err)
+	u, _ = url.Parse(expired)
+	_, err = fss.FetchSigned("proj-app", "file.txt", "", u.Query().Get("expires"), u.Query().Get("signature"))
+	assert.Error(t, err)
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/ovh/cds/sdk"
 )
 
 var storage Driver
+var temporaryURLTTL time.Duration
 
 //Status is for status handler
 func Status() string {
@@ -43,6 +45,28 @@ func DeleteArtifact(o Object) error {
 	return fmt.Errorf("store not initialized")
 }
 
+// TemporaryURL returns a signed url to fetch the object directly from the storage backend. The url expires after
+// the configured TTL. It returns an empty string if temporary urls are disabled or not supported by the driver
+func TemporaryURL(o Object, filename string) (string, error) {
+	if storage == nil {
+		return "", fmt.Errorf("store not initialized")
+	}
+	d, ok := storage.(DriverWithTemporaryURL)
+	if !ok || temporaryURLTTL <= 0 {
+		return "", nil
+	}
+	return d.TemporaryURL(o, filename, temporaryURLTTL)
+}
+
+// FetchSigned fetch an object from a temporary url generated by the filesystem driver
+func FetchSigned(objectPath, name, filename, expires, signature string) (io.ReadCloser, error) {
+	fss, ok := storage.(*FilesystemStore)
+	if !ok {
+		return nil, fmt.Errorf("signed urls are only served for filesystem store")
+	}
+	return fss.FetchSigned(objectPath, name, filename, expires, signature)
+}
+
 //StorePlugin call Store on the common driver
 func StorePlugin(art sdk.ActionPlugin, data io.ReadCloser) (string, error) {
 	if storage != nil {
@@ -94,6 +118,7 @@ func DeleteTemplateExtension(tmpl sdk.TemplateExtension) error {
 // Driver allows artifact to be stored and retrieve the same way to any backend
 // - Openstack / Swift
 // - Filesystem
+// - S3 compatible (AWS S3, Minio, Ceph RGW)
 type Driver interface {
 	Status() string
 	Store(o Object, data io.ReadCloser) (string, error)
@@ -101,6 +126,16 @@ type Driver interface {
 	Delete(o Object) error
 }
 
+// DriverWithTemporaryURL is implemented by drivers able to generate signed, expiring urls to fetch objects
+// without streaming them through the API
+// - Openstack / Swift: TempURL
+// - S3: presigned url
+// - Filesystem: HMAC signed API route
+type DriverWithTemporaryURL interface {
+	Driver
+	TemporaryURL(o Object, filename string, ttl time.Duration) (string, error)
+}
+
 // Initialize setup wanted ObjectStore driver
 func Initialize(c context.Context, cfg Config) error {
 	var err error
@@ -108,6 +143,7 @@ func Initialize(c context.Context, cfg Config) error {
 	if err != nil {
 		return err
 	}
+	temporaryURLTTL = cfg.TemporaryURLTTL
 	return nil
 }
 
@@ -119,20 +155,23 @@ const (
 	Openstack Kind = iota
 	Filesystem
 	Swift
+	S3
 )
 
 //TODO Use github.com/graymeta/stow
 
 // Config represents all the configuration for all objecstore drivers
 type Config struct {
-	Kind    Kind
-	Options ConfigOptions
+	Kind            Kind
+	Options         ConfigOptions
+	TemporaryURLTTL time.Duration
 }
 
 // ConfigOptions is used by Config
 type ConfigOptions struct {
 	Openstack  ConfigOptionsOpenstack
 	Filesystem ConfigOptionsFilesystem
+	S3         ConfigOptionsS3
 }
 
 // ConfigOptionsOpenstack is used by ConfigOptions
@@ -143,11 +182,26 @@ type ConfigOptionsOpenstack struct {
 	Tenant          string
 	Region          string
 	ContainerPrefix string
+	TempURLKey      string
 }
 
 // ConfigOptionsFilesystem is used by ConfigOptions
 type ConfigOptionsFilesystem struct {
-	Basedir string
+	Basedir    string
+	BaseURL    string
+	SigningKey string
+}
+
+// ConfigOptionsS3 is used by ConfigOptions
+type ConfigOptionsS3 struct {
+	Endpoint        string
+	Region          string
+	BucketName      string
+	Prefix          string
+	AccessKeyID     string
+	SecretAccessKey string
+	ForcePathStyle  bool
+	PartSize        int64
 }
 
 // New initialise a new ArtifactStorage
@@ -159,9 +213,21 @@ func New(c context.Context, cfg Config) (Driver, error) {
 			cfg.Options.Openstack.Password,
 			cfg.Options.Openstack.Tenant,
 			cfg.Options.Openstack.Region,
-			cfg.Options.Openstack.ContainerPrefix)
+			cfg.Options.Openstack.ContainerPrefix,
+			cfg.Options.Openstack.TempURLKey)
 	case Filesystem:
-		return NewFilesystemStore(cfg.Options.Filesystem.Basedir)
+		return NewFilesystemStore(cfg.Options.Filesystem.Basedir,
+			cfg.Options.Filesystem.BaseURL,
+			cfg.Options.Filesystem.SigningKey)
+	case S3:
+		return NewS3Store(cfg.Options.S3.Endpoint,
+			cfg.Options.S3.Region,
+			cfg.Options.S3.BucketName,
+			cfg.Options.S3.Prefix,
+			cfg.Options.S3.AccessKeyID,
+			cfg.Options.S3.SecretAccessKey,
+			cfg.Options.S3.ForcePathStyle,
+			cfg.Options.S3.PartSize)
 	default:
 		return nil, fmt.Errorf("Invalid flag --artifact-mode")
 	}
@@ -2,10 +2,15 @@ package objectstore
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/url"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/ovh/cds/sdk/log"
 )
@@ -20,10 +25,11 @@ type OpenstackStore struct {
 	token           *Token
 	endpoint        string
 	containerprefix string
+	tempURLKey      string
 }
 
 // NewOpenstackStore create a new ObjectStore with openstack driver and check configuration
-func NewOpenstackStore(c context.Context, address, user, password, tenant, region, containerprefix string) (*OpenstackStore, error) {
+func NewOpenstackStore(c context.Context, address, user, password, tenant, region, containerprefix, tempURLKey string) (*OpenstackStore, error) {
 	log.Info("Objectstore> Initialize Swift(Openstack) driver on address: %s, tenant: %s, region: %s, prefix: %s", address, tenant, region, containerprefix)
 	if address == "" {
 		return nil, fmt.Errorf("artifact storage is openstack, but flag --artifact_address is not provided")
@@ -48,6 +54,7 @@ func NewOpenstackStore(c context.Context, address, user, password, tenant, regio
 		tenant:          tenant,
 		region:          region,
 		containerprefix: containerprefix,
+		tempURLKey:      tempURLKey,
 	}
 
 	var err error
@@ -57,10 +64,39 @@ func NewOpenstackStore(c context.Context, address, user, password, tenant, regio
 	}
 	go ops.refreshTokenRoutine(c)
 
+	if tempURLKey != "" {
+		if err := ops.initTempURLKey(); err != nil {
+			return nil, fmt.Errorf("cannot set temp url key on swift account: %s", err)
+		}
+	}
+
 	log.Debug("NewOpenstackStore> Got token %dchar at %s\n", len(ops.token.ID), ops.endpoint)
 	return ops, nil
 }
 
+// initTempURLKey sets the temp url key on the swift account if it is not one of its keys yet.
+// The keys of the account may be used by other applications: a key is only set on a free slot, so that
+// their temporary urls are never invalidated. Temporary urls are disabled if both slots are used.
+func (ops *OpenstackStore) initTempURLKey() error {
+	key1, key2, err := getTempURLKeys(ops.token.ID, ops.endpoint)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case key1 == ops.tempURLKey, key2 == ops.tempURLKey:
+		return nil
+	case key1 == "":
+		return setTempURLKey(ops.token.ID, ops.endpoint, "X-Account-Meta-Temp-URL-Key", ops.tempURLKey)
+	case key2 == "":
+		return setTempURLKey(ops.token.ID, ops.endpoint, "X-Account-Meta-Temp-URL-Key-2", ops.tempURLKey)
+	}
+
+	log.Warning("NewOpenstackStore> Both temp url keys of the swift account are already used, temporary urls are disabled")
+	ops.tempURLKey = ""
+	return nil
+}
+
 //Status return Openstack storage status
 func (ops *OpenstackStore) Status() string {
 	if err := account(ops.token.ID, ops.endpoint); err != nil {
@@ -116,6 +152,39 @@ func (ops *OpenstackStore) Fetch(o Object) (io.ReadCloser, error) {
 	return data, nil
 }
 
+// TemporaryURL returns a Swift TempURL, valid for ttl
+func (ops *OpenstackStore) TemporaryURL(o Object, filename string, ttl time.Duration) (string, error) {
+	if ops.tempURLKey == "" {
+		return "", nil
+	}
+
+	endpoint, err := url.Parse(ops.endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	container := ops.containerprefix + o.GetPath()
+	object := o.GetName()
+	objectPath := endpoint.Path + "/" + container + "/" + object
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+
+	h := hmac.New(sha1.New, []byte(ops.tempURLKey))
+	fmt.Fprintf(h, "GET\n%s\n%s", expires, objectPath)
+
+	q := url.Values{
+		"temp_url_sig":     []string{hex.EncodeToString(h.Sum(nil))},
+		"temp_url_expires": []string{expires},
+	}
+	if filename != "" {
+		q.Set("filename", filename)
+	}
+
+	u := *endpoint
+	u.Path = objectPath
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
 func (ops *OpenstackStore) escape(container, object string) (string, string) {
 	container = url.QueryEscape(container)
 	container = strings.Replace(container, "/", "-", -1)
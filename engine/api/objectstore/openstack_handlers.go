@@ -113,6 +113,59 @@ func account(token string, url string) error {
 	return nil
 }
 
+// getTempURLKeys returns the two temp url keys of the swift account
+func getTempURLKeys(token string, url string) (string, string, error) {
+	req, err := http.NewRequest("HEAD", url, nil)
+	if err != nil {
+		return "", "", err
+	}
+
+	req.Header.Set("X-Auth-Token", token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		rbody, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return "", "", fmt.Errorf("cannot read body")
+		}
+		return "", "", unmarshalOpenstackError(rbody, resp.Status)
+	}
+
+	return resp.Header.Get("X-Account-Meta-Temp-URL-Key"), resp.Header.Get("X-Account-Meta-Temp-URL-Key-2"), nil
+}
+
+// setTempURLKey sets a temp url key of the swift account, header is X-Account-Meta-Temp-URL-Key or X-Account-Meta-Temp-URL-Key-2
+func setTempURLKey(token string, url string, header string, key string) error {
+	req, err := http.NewRequest("POST", url, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("X-Auth-Token", token)
+	req.Header.Set(header, key)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		rbody, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("cannot read body")
+		}
+		return unmarshalOpenstackError(rbody, resp.Status)
+	}
+
+	return nil
+}
+
 func deleteObject(token string, url string, account string, objectname string) error {
 	uri := fmt.Sprintf("%s/%s/%s", url, account, objectname)
 	req, err := http.NewRequest("DELETE", uri, nil)
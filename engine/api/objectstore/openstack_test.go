@@ -0,0 +1,51 @@
+package objectstore
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenstackInitTempURLKey(t *testing.T) {
+	var key1, key2 string
+	var nbPost int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case "HEAD":
+			w.Header().Set("X-Account-Meta-Temp-URL-Key", key1)
+			w.Header().Set("X-Account-Meta-Temp-URL-Key-2", key2)
+		case "POST":
+			nbPost++
+			if k := r.Header.Get("X-Account-Meta-Temp-URL-Key"); k != "" {
+				key1 = k
+			}
+			if k := r.Header.Get("X-Account-Meta-Temp-URL-Key-2"); k != "" {
+				key2 = k
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	ops := &OpenstackStore{token: &Token{ID: "token"}, endpoint: srv.URL, tempURLKey: "cds"}
+
+	// the key of another application is kept
+	key1 = "other"
+	assert.NoError(t, ops.initTempURLKey())
+	assert.Equal(t, "other", key1)
+	assert.Equal(t, "cds", key2)
+	assert.Equal(t, 1, nbPost)
+
+	// the key is not set again at the next startup
+	assert.NoError(t, ops.initTempURLKey())
+	assert.Equal(t, 1, nbPost)
+	assert.Equal(t, "cds", ops.tempURLKey)
+
+	// temporary urls are disabled when both keys are used
+	key2 = "another"
+	assert.NoError(t, ops.initTempURLKey())
+	assert.Equal(t, 1, nbPost)
+	assert.Equal(t, "", ops.tempURLKey)
+}
@@ -0,0 +1,176 @@
+package objectstore
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	s3DefaultPartSize = 64 * 1024 * 1024
+	s3MinPartSize     = 5 * 1024 * 1024
+)
+
+// S3Store implements ObjectStore interface with a S3 compatible implementation (AWS S3, Minio, Ceph RGW...)
+type S3Store struct {
+	endpoint        *url.URL
+	region          string
+	bucket          string
+	prefix          string
+	accessKeyID     string
+	secretAccessKey string
+	forcePathStyle  bool
+	partSize        int64
+}
+
+// NewS3Store create a new ObjectStore with S3 driver and check configuration
+func NewS3Store(endpoint, region, bucket, prefix, accessKeyID, secretAccessKey string, forcePathStyle bool, partSize int64) (*S3Store, error) {
+	log.Info("Objectstore> Initialize S3 driver on endpoint: %s, region: %s, bucket: %s, prefix: %s", endpoint, region, bucket, prefix)
+	if bucket == "" {
+		return nil, fmt.Errorf("artifact storage is s3, but bucket name is not provided")
+	}
+
+	if region == "" {
+		return nil, fmt.Errorf("artifact storage is s3, but region is not provided")
+	}
+
+	if accessKeyID == "" || secretAccessKey == "" {
+		return nil, fmt.Errorf("artifact storage is s3, but credentials are not provided")
+	}
+
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("artifact storage is s3, but endpoint %s is invalid: %s", endpoint, err)
+	}
+
+	if partSize == 0 {
+		partSize = s3DefaultPartSize
+	}
+	if partSize < s3MinPartSize {
+		return nil, fmt.Errorf("artifact storage is s3, but part size must be at least %d bytes", s3MinPartSize)
+	}
+
+	s3 := &S3Store{
+		endpoint:        u,
+		region:          region,
+		bucket:          bucket,
+		prefix:          prefix,
+		accessKeyID:     accessKeyID,
+		secretAccessKey: secretAccessKey,
+		forcePathStyle:  forcePathStyle,
+		partSize:        partSize,
+	}
+
+	return s3, nil
+}
+
+// Status return S3 storage status
+func (s3 *S3Store) Status() string {
+	if err := s3.headBucket(); err != nil {
+		return "S3 KO (" + err.Error() + ")"
+	}
+	return "S3 OK"
+}
+
+// Store stores in the S3 bucket. Data bigger than the part size is sent with a multipart upload
+func (s3 *S3Store) Store(o Object, data io.ReadCloser) (string, error) {
+	defer data.Close()
+	key := s3.key(o)
+
+	log.Debug("S3Store> Storing /%s/%s\n", s3.bucket, key)
+
+	first, err := readPart(data, s3.partSize)
+	if err != nil {
+		return "", err
+	}
+
+	// Small objects are sent in one request
+	if int64(len(first)) < s3.partSize {
+		if err := s3.putObject(key, first); err != nil {
+			log.Warning("S3Store.Store> Cannot put object: %s\n", err)
+			return "", err
+		}
+		return s3.bucket + "/" + key, nil
+	}
+
+	if err := s3.multipartUpload(key, first, data); err != nil {
+		log.Warning("S3Store.Store> Cannot upload object: %s\n", err)
+		return "", err
+	}
+
+	return s3.bucket + "/" + key, nil
+}
+
+// Fetch lookup on S3 to fetch data
+func (s3 *S3Store) Fetch(o Object) (io.ReadCloser, error) {
+	key := s3.key(o)
+	log.Debug("S3Store> Fetching /%s/%s\n", s3.bucket, key)
+	return s3.getObject(key)
+}
+
+// Delete should delete on S3
+func (s3 *S3Store) Delete(o Object) error {
+	return s3.deleteObject(s3.key(o))
+}
+
+// TemporaryURL returns a presigned url, valid for ttl
+func (s3 *S3Store) TemporaryURL(o Object, filename string, ttl time.Duration) (string, error) {
+	query := url.Values{}
+	if filename != "" {
+		query.Set("response-content-disposition", fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	}
+	return s3.presign(s3.key(o), query, ttl, time.Now().UTC()), nil
+}
+
+func (s3 *S3Store) key(o Object) string {
+	return strings.TrimPrefix(path.Join(s3.prefix, o.GetPath(), o.GetName()), "/")
+}
+
+func (s3 *S3Store) multipartUpload(key string, first []byte, data io.Reader) error {
+	uploadID, err := s3.initiateMultipartUpload(key)
+	if err != nil {
+		return err
+	}
+
+	parts := []s3CompletedPart{}
+	part := first
+	for n := 1; len(part) > 0; n++ {
+		etag, err := s3.uploadPart(key, uploadID, n, part)
+		if err != nil {
+			s3.abortMultipartUpload(key, uploadID)
+			return err
+		}
+		parts = append(parts, s3CompletedPart{PartNumber: n, ETag: etag})
+
+		part, err = readPart(data, s3.partSize)
+		if err != nil {
+			s3.abortMultipartUpload(key, uploadID)
+			return err
+		}
+	}
+
+	if err := s3.completeMultipartUpload(key, uploadID, parts); err != nil {
+		s3.abortMultipartUpload(key, uploadID)
+		return err
+	}
+	return nil
+}
+
+// readPart reads at most size bytes from r
+func readPart(r io.Reader, size int64) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if _, err := io.CopyN(buf, r, size); err != nil && err != io.EOF {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
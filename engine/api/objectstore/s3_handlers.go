@@ -0,0 +1,329 @@
+package objectstore
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk/log"
+)
+
+//////////// S3 HANDLERS //////////
+
+const (
+	s3Algorithm   = "AWS4-HMAC-SHA256"
+	s3Service     = "s3"
+	s3DateFormat  = "20060102T150405Z"
+	s3ShortFormat = "20060102"
+)
+
+type s3InitiateMultipartUploadResult struct {
+	XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+	Bucket   string   `xml:"Bucket"`
+	Key      string   `xml:"Key"`
+	UploadID string   `xml:"UploadId"`
+}
+
+type s3CompletedPart struct {
+	PartNumber int    `xml:"PartNumber"`
+	ETag       string `xml:"ETag"`
+}
+
+type s3CompleteMultipartUpload struct {
+	XMLName xml.Name          `xml:"CompleteMultipartUpload"`
+	Parts   []s3CompletedPart `xml:"Part"`
+}
+
+/*<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>*/
+type s3Error struct {
+	XMLName xml.Name `xml:"Error"`
+	Code    string   `xml:"Code"`
+	Message string   `xml:"Message"`
+}
+
+func (s3 *S3Store) headBucket() error {
+	resp, err := s3.do("HEAD", "", nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s3 *S3Store) putObject(key string, data []byte) error {
+	resp, err := s3.do("PUT", key, nil, data)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s3 *S3Store) getObject(key string) (io.ReadCloser, error) {
+	resp, err := s3.do("GET", key, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s3 *S3Store) deleteObject(key string) error {
+	resp, err := s3.do("DELETE", key, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s3 *S3Store) initiateMultipartUpload(key string) (string, error) {
+	resp, err := s3.do("POST", key, url.Values{"uploads": []string{""}}, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var res s3InitiateMultipartUploadResult
+	if err := xml.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return "", fmt.Errorf("cannot read multipart upload initialization: %s", err)
+	}
+	if res.UploadID == "" {
+		return "", fmt.Errorf("no upload id returned for %s", key)
+	}
+	return res.UploadID, nil
+}
+
+func (s3 *S3Store) uploadPart(key, uploadID string, partNumber int, data []byte) (string, error) {
+	query := url.Values{
+		"partNumber": []string{strconv.Itoa(partNumber)},
+		"uploadId":   []string{uploadID},
+	}
+	resp, err := s3.do("PUT", key, query, data)
+	if err != nil {
+		return "", err
+	}
+	resp.Body.Close()
+	return resp.Header.Get("ETag"), nil
+}
+
+func (s3 *S3Store) completeMultipartUpload(key, uploadID string, parts []s3CompletedPart) error {
+	body, err := xml.Marshal(s3CompleteMultipartUpload{Parts: parts})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s3.do("POST", key, url.Values{"uploadId": []string{uploadID}}, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// S3 can answer 200 OK with an error in the body
+	rbody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("cannot read body")
+	}
+	if bytes.Contains(rbody, []byte("<Error>")) {
+		return unmarshalS3Error(rbody, resp.Status)
+	}
+	return nil
+}
+
+func (s3 *S3Store) abortMultipartUpload(key, uploadID string) {
+	resp, err := s3.do("DELETE", key, url.Values{"uploadId": []string{uploadID}}, nil)
+	if err != nil {
+		log.Warning("S3Store.abortMultipartUpload> Cannot abort upload %s: %s\n", uploadID, err)
+		return
+	}
+	resp.Body.Close()
+}
+
+// objectURL returns the URL of an object, with path-style or virtual-hosted style addressing
+func (s3 *S3Store) objectURL(key string, query url.Values) *url.URL {
+	u := *s3.endpoint
+	p := strings.TrimSuffix(u.Path, "/")
+	if s3.forcePathStyle {
+		p += "/" + s3.bucket
+	} else {
+		u.Host = s3.bucket + "." + u.Host
+	}
+	if key != "" {
+		p += "/" + key
+	}
+	if p == "" {
+		p = "/"
+	}
+	u.Path = p
+	u.RawPath = s3URIEncode(p, false)
+	u.RawQuery = s3CanonicalQuery(query)
+	return &u
+}
+
+func (s3 *S3Store) do(method, key string, query url.Values, body []byte) (*http.Response, error) {
+	u := s3.objectURL(key, query)
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, u.String(), r)
+	if err != nil {
+		return nil, err
+	}
+
+	s3.sign(req, u, body, time.Now().UTC())
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= 400 {
+		defer resp.Body.Close()
+		rbody, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read body")
+		}
+		return nil, fmt.Errorf("%s (%s)", u.Path, unmarshalS3Error(rbody, resp.Status))
+	}
+
+	return resp, nil
+}
+
+// sign adds AWS Signature Version 4 headers on the request
+func (s3 *S3Store) sign(req *http.Request, u *url.URL, body []byte, t time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := t.Format(s3DateFormat)
+	scope := strings.Join([]string{t.Format(s3ShortFormat), s3.region, s3Service, "aws4_request"}, "/")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + u.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		u.RawPath,
+		u.RawQuery,
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	signature := s3.signature(t, scope, canonicalRequest)
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, s3.accessKeyID, scope, signedHeaders, signature))
+}
+
+// presign returns an url signed with AWS Signature Version 4 in query string, valid for ttl
+func (s3 *S3Store) presign(key string, query url.Values, ttl time.Duration, t time.Time) string {
+	scope := strings.Join([]string{t.Format(s3ShortFormat), s3.region, s3Service, "aws4_request"}, "/")
+
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("X-Amz-Algorithm", s3Algorithm)
+	query.Set("X-Amz-Credential", s3.accessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", t.Format(s3DateFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(ttl.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	u := s3.objectURL(key, query)
+	canonicalRequest := strings.Join([]string{
+		"GET",
+		u.RawPath,
+		u.RawQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	u.RawQuery += "&X-Amz-Signature=" + s3.signature(t, scope, canonicalRequest)
+	return u.String()
+}
+
+func (s3 *S3Store) signature(t time.Time, scope, canonicalRequest string) string {
+	stringToSign := strings.Join([]string{
+		s3Algorithm,
+		t.Format(s3DateFormat),
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+	return hex.EncodeToString(hmacSHA256(s3.signingKey(t), stringToSign))
+}
+
+func (s3 *S3Store) signingKey(t time.Time) []byte {
+	k := hmacSHA256([]byte("AWS4"+s3.secretAccessKey), t.Format(s3ShortFormat))
+	k = hmacSHA256(k, s3.region)
+	k = hmacSHA256(k, s3Service)
+	return hmacSHA256(k, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+// s3URIEncode encodes a string as specified by AWS: every byte except unreserved characters is escaped
+func s3URIEncode(s string, encodeSlash bool) string {
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			buf.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			buf.WriteByte(c)
+		default:
+			fmt.Fprintf(&buf, "%%%02X", c)
+		}
+	}
+	return buf.String()
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	params := []string{}
+	for _, k := range keys {
+		vals := query[k]
+		sort.Strings(vals)
+		for _, v := range vals {
+			params = append(params, s3URIEncode(k, true)+"="+s3URIEncode(v, true))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+func unmarshalS3Error(data []byte, status string) error {
+	s3err := s3Error{}
+	if err := xml.Unmarshal(data, &s3err); err != nil || s3err.Code == "" {
+		return fmt.Errorf("%s", status)
+	}
+	return fmt.Errorf("%s: %s: %s", status, s3err.Code, s3err.Message)
+}
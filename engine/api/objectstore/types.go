@@ -5,3 +5,17 @@ type Object interface {
 	GetName() string
 	GetPath() string
 }
+
+// object is a generic Object, used when the object is only known by its path and name
+type object struct {
+	path string
+	name string
+}
+
+func (o *object) GetName() string {
+	return o.name
+}
+
+func (o *object) GetPath() string {
+	return o.path
+}
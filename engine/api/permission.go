@@ -83,7 +83,11 @@ func (api *API) authMiddleware(ctx context.Context, w http.ResponseWriter, req *
 			}
 		default:
 			var err error
-			ctx, err = api.Router.AuthDriver.CheckAuth(ctx, w, req)
+			if auth.GetAccessToken(headers) != "" {
+				ctx, err = auth.CheckAccessTokenAuth(ctx, api.mustDB(), headers)
+			} else {
+				ctx, err = api.Router.AuthDriver.CheckAuth(ctx, w, req)
+			}
 			if err != nil {
 				return ctx, sdk.WrapError(sdk.ErrUnauthorized, "Router> Authorization denied on %s %s for %s agent %s : %s", req.Method, req.URL, req.RemoteAddr, getAgent(req), err)
 			}
@@ -162,6 +166,10 @@ func (api *API) authMiddleware(ctx context.Context, w http.ResponseWriter, req *
 		return ctx, nil
 	}
 
+	if t := getAccessToken(ctx); t != nil && !checkAccessTokenScopes(t, req.Method, rc.Options["isExecution"] == "true", mux.Vars(req)) {
+		return ctx, sdk.WrapError(sdk.ErrAccessTokenScopeForbidden, "Router> Access token %s of %s not authorized on %s %s", t.Name, getUser(ctx).Username, req.Method, req.URL)
+	}
+
 	if getUser(ctx).Admin {
 		return ctx, nil
 	}
@@ -202,7 +210,7 @@ func (api *API) checkWorkerPermission(ctx context.Context, db gorp.SqlExecutor,
 	}
 
 	//IF it is POSTEXECUTE, it means that the job is must be taken by the worker
-	if rc.Options["isExecution"] == "true" {
+	if rc.Options["isExecution"] == "true" || rc.Options["needJobTaken"] == "true" {
 		node, err := workflow.LoadNodeJobRun(db, api.Cache, id)
 		if err != nil {
 			log.Error("checkWorkerPermission> Unable to load job %d", id)
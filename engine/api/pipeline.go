@@ -524,6 +524,7 @@ func (api *API) updatePipelineHandler() Handler {
 
 		pipelineDB.Name = p.Name
 		pipelineDB.Type = p.Type
+		pipelineDB.Priority = p.Priority
 
 		if err := pipeline.UpdatePipeline(tx, pipelineDB); err != nil {
 			return sdk.WrapError(err, "updatePipelineHandler> cannot update pipeline %s", name)
@@ -58,11 +58,11 @@ func LoadPipeline(db gorp.SqlExecutor, projectKey, name string, deep bool) (*sdk
 	var p sdk.Pipeline
 
 	var lastModified time.Time
-	query := `SELECT pipeline.id, pipeline.name, pipeline.project_id, pipeline.type, pipeline.last_modified FROM pipeline
+	query := `SELECT pipeline.id, pipeline.name, pipeline.project_id, pipeline.type, pipeline.last_modified, pipeline.priority FROM pipeline
 	 		JOIN project on pipeline.project_id = project.id
 	 		WHERE pipeline.name = $1 AND project.projectKey = $2`
 
-	err := db.QueryRow(query, name, projectKey).Scan(&p.ID, &p.Name, &p.ProjectID, &p.Type, &lastModified)
+	err := db.QueryRow(query, name, projectKey).Scan(&p.ID, &p.Name, &p.ProjectID, &p.Type, &lastModified, &p.Priority)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, sdk.ErrPipelineNotFound
@@ -85,11 +85,11 @@ func LoadPipeline(db gorp.SqlExecutor, projectKey, name string, deep bool) (*sdk
 func LoadPipelineByID(db gorp.SqlExecutor, pipelineID int64, deep bool) (*sdk.Pipeline, error) {
 	var lastModified time.Time
 	var p sdk.Pipeline
-	query := `SELECT pipeline.name, pipeline.type, project.projectKey, pipeline.last_modified FROM pipeline
+	query := `SELECT pipeline.name, pipeline.type, project.projectKey, pipeline.last_modified, pipeline.priority FROM pipeline
 	JOIN project on pipeline.project_id = project.id
 	WHERE pipeline.id = $1`
 
-	err := db.QueryRow(query, pipelineID).Scan(&p.Name, &p.Type, &p.ProjectKey, &lastModified)
+	err := db.QueryRow(query, pipelineID).Scan(&p.Name, &p.Type, &p.ProjectKey, &lastModified, &p.Priority)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, sdk.ErrPipelineNotFound
@@ -346,14 +346,14 @@ func UpdatePipeline(db gorp.SqlExecutor, p *sdk.Pipeline) error {
 	}
 
 	//Update pipeline
-	query := `UPDATE pipeline SET name=$1, type=$2 WHERE id=$3`
-	_, err := db.Exec(query, p.Name, string(p.Type), p.ID)
+	query := `UPDATE pipeline SET name=$1, type=$2, priority=$3 WHERE id=$4`
+	_, err := db.Exec(query, p.Name, string(p.Type), p.Priority, p.ID)
 	return err
 }
 
 // InsertPipeline inserts pipeline informations in database
 func InsertPipeline(db gorp.SqlExecutor, proj *sdk.Project, p *sdk.Pipeline, u *sdk.User) error {
-	query := `INSERT INTO pipeline (name, project_id, type, last_modified) VALUES ($1,$2,$3, current_timestamp) RETURNING id`
+	query := `INSERT INTO pipeline (name, project_id, type, priority, last_modified) VALUES ($1,$2,$3,$4, current_timestamp) RETURNING id`
 
 	rx := regexp.MustCompile(sdk.NamePattern)
 	if !rx.MatchString(p.Name) {
@@ -368,7 +368,7 @@ func InsertPipeline(db gorp.SqlExecutor, proj *sdk.Project, p *sdk.Pipeline, u *
 		return sdk.WrapError(sdk.ErrInvalidProject, "InsertPipeline>")
 	}
 
-	if err := db.QueryRow(query, p.Name, p.ProjectID, string(p.Type)).Scan(&p.ID); err != nil {
+	if err := db.QueryRow(query, p.Name, p.ProjectID, string(p.Type), p.Priority).Scan(&p.ID); err != nil {
 		return err
 	}
 
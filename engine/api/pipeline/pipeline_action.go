@@ -1,6 +1,7 @@
 package pipeline
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
@@ -103,8 +104,12 @@ func InsertJob(db gorp.SqlExecutor, job *sdk.Job, stageID int64, pip *sdk.Pipeli
 	job.PipelineStageID = stage.ID
 
 	// Create pipeline action
-	query := `INSERT INTO pipeline_action (pipeline_stage_id, action_id, enabled) VALUES ($1, $2, $3) RETURNING id`
-	if err := db.QueryRow(query, job.PipelineStageID, job.Action.ID, job.Enabled).Scan(&job.PipelineActionID); err != nil {
+	matrix, errM := json.Marshal(job.Matrix)
+	if errM != nil {
+		return errM
+	}
+	query := `INSERT INTO pipeline_action (pipeline_stage_id, action_id, enabled, retry, retry_delay, timeout, matrix) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`
+	if err := db.QueryRow(query, job.PipelineStageID, job.Action.ID, job.Enabled, job.Retry, job.RetryDelay, job.Timeout, matrix).Scan(&job.PipelineActionID); err != nil {
 		return err
 	}
 	return nil
@@ -121,8 +126,12 @@ func UpdateJob(db gorp.SqlExecutor, job *sdk.Job, userID int64) error {
 		return sdk.ErrForbidden
 	}
 
-	query := `UPDATE pipeline_action set action_id=$1, pipeline_stage_id=$2, enabled=$4  WHERE id=$3`
-	_, err = db.Exec(query, job.Action.ID, job.PipelineStageID, job.PipelineActionID, job.Enabled)
+	matrix, err := json.Marshal(job.Matrix)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE pipeline_action set action_id=$1, pipeline_stage_id=$2, enabled=$4, retry=$5, retry_delay=$6, timeout=$7, matrix=$8  WHERE id=$3`
+	_, err = db.Exec(query, job.Action.ID, job.PipelineStageID, job.PipelineActionID, job.Enabled, job.Retry, job.RetryDelay, job.Timeout, matrix)
 	if err != nil {
 		return err
 	}
@@ -137,9 +146,13 @@ func DeleteJob(db gorp.SqlExecutor, job sdk.Job, userID int64) error {
 
 // UpdatePipelineAction Update an action in a pipeline
 func UpdatePipelineAction(db gorp.SqlExecutor, job sdk.Job) error {
-	query := `UPDATE pipeline_action set action_id=$1, pipeline_stage_id=$2, enabled=$4  WHERE id=$3`
+	matrix, err := json.Marshal(job.Matrix)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE pipeline_action set action_id=$1, pipeline_stage_id=$2, enabled=$4, retry=$5, retry_delay=$6, timeout=$7, matrix=$8  WHERE id=$3`
 
-	_, err := db.Exec(query, job.Action.ID, job.PipelineStageID, job.PipelineActionID, job.Enabled)
+	_, err = db.Exec(query, job.Action.ID, job.PipelineStageID, job.PipelineActionID, job.Enabled, job.Retry, job.RetryDelay, job.Timeout, matrix)
 	if err != nil {
 		return err
 	}
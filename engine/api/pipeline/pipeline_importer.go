@@ -25,6 +25,13 @@ func ImportUpdate(db gorp.SqlExecutor, proj *sdk.Project, pip *sdk.Pipeline, msg
 
 	pip.ID = oldPipeline.ID
 
+	if pip.Priority != oldPipeline.Priority {
+		oldPipeline.Priority = pip.Priority
+		if err := UpdatePipeline(db, oldPipeline); err != nil {
+			return sdk.WrapError(err, "ImportUpdate> Unable to update pipeline %s priority", pip.Name)
+		}
+	}
+
 	if pip.GroupPermission != nil {
 		//Browse all new persmission to know if we had to insert of update
 		for _, gp := range pip.GroupPermission {
@@ -155,7 +155,8 @@ func LoadPipelineStage(db gorp.SqlExecutor, p *sdk.Pipeline, args ...FuncArg) er
 	SELECT  pipeline_stage_R.id as stage_id, pipeline_stage_R.pipeline_id, pipeline_stage_R.name, pipeline_stage_R.last_modified,
 			pipeline_stage_R.build_order, pipeline_stage_R.enabled, pipeline_stage_R.parameter,
 			pipeline_stage_R.expected_value, pipeline_action_R.id as pipeline_action_id, pipeline_action_R.action_id, pipeline_action_R.action_last_modified,
-			pipeline_action_R.action_args, pipeline_action_R.action_enabled
+			pipeline_action_R.action_args, pipeline_action_R.action_enabled,
+			pipeline_action_R.action_retry, pipeline_action_R.action_retry_delay, pipeline_action_R.action_timeout, pipeline_action_R.action_matrix
 	FROM (
 		SELECT  pipeline_stage.id, pipeline_stage.pipeline_id,
 				pipeline_stage.name, pipeline_stage.last_modified ,pipeline_stage.build_order,
@@ -168,6 +169,8 @@ func LoadPipelineStage(db gorp.SqlExecutor, p *sdk.Pipeline, args ...FuncArg) er
 	LEFT OUTER JOIN (
 		SELECT  pipeline_action.id, action.id as action_id, action.name as action_name, action.last_modified as action_last_modified,
 				pipeline_action.args as action_args, pipeline_action.enabled as action_enabled,
+				pipeline_action.retry as action_retry, pipeline_action.retry_delay as action_retry_delay,
+				pipeline_action.timeout as action_timeout, pipeline_action.matrix as action_matrix,
 				pipeline_action.pipeline_stage_id
 		FROM action
 		JOIN pipeline_action ON pipeline_action.action_id = action.id
@@ -189,9 +192,9 @@ func LoadPipelineStage(db gorp.SqlExecutor, p *sdk.Pipeline, args ...FuncArg) er
 	for rows.Next() {
 		var stageID, pipelineID int64
 		var stageBuildOrder int
-		var pipelineActionID, actionID sql.NullInt64
+		var pipelineActionID, actionID, actionRetry, actionRetryDelay, actionTimeout sql.NullInt64
 		var stageName string
-		var stagePrerequisiteParameter, stagePrerequisiteExpectedValue, actionArgs sql.NullString
+		var stagePrerequisiteParameter, stagePrerequisiteExpectedValue, actionArgs, actionMatrix sql.NullString
 		var stageEnabled, actionEnabled sql.NullBool
 		var stageLastModified, actionLastModified pq.NullTime
 
@@ -199,7 +202,7 @@ func LoadPipelineStage(db gorp.SqlExecutor, p *sdk.Pipeline, args ...FuncArg) er
 			&stageID, &pipelineID, &stageName, &stageLastModified,
 			&stageBuildOrder, &stageEnabled, &stagePrerequisiteParameter,
 			&stagePrerequisiteExpectedValue, &pipelineActionID, &actionID, &actionLastModified,
-			&actionArgs, &actionEnabled)
+			&actionArgs, &actionEnabled, &actionRetry, &actionRetryDelay, &actionTimeout, &actionMatrix)
 		if err != nil {
 			return err
 		}
@@ -247,10 +250,18 @@ func LoadPipelineStage(db gorp.SqlExecutor, p *sdk.Pipeline, args ...FuncArg) er
 					PipelineActionID: pipelineActionID.Int64,
 					LastModified:     actionLastModified.Time.Unix(),
 					Enabled:          actionEnabled.Bool,
+					Retry:            int(actionRetry.Int64),
+					RetryDelay:       int(actionRetryDelay.Int64),
+					Timeout:          int(actionTimeout.Int64),
 					Action: sdk.Action{
 						ID: actionID.Int64,
 					},
 				}
+				if actionMatrix.Valid {
+					if err := json.Unmarshal([]byte(actionMatrix.String), &j.Matrix); err != nil {
+						return sdk.WrapError(err, "LoadPipelineStage> Unable to unmarshal matrix of job %d", j.PipelineActionID)
+					}
+				}
 				mapAllActions[pipelineActionID.Int64] = j
 				mapActionsStages[stageID] = append(mapActionsStages[stageID], *j)
 
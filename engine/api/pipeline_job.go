@@ -31,6 +31,10 @@ func (api *API) addJobToStageHandler() Handler {
 			return err
 		}
 
+		if err := job.Matrix.Check(); err != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "addJobToStageHandler> Invalid matrix: %s", err)
+		}
+
 		pip, errl := pipeline.LoadPipeline(api.mustDB(), projectKey, pipelineName, true)
 		if errl != nil {
 			return sdk.WrapError(sdk.ErrPipelineNotFound, "addJobToStageHandler> Cannot load pipeline %s for project %s: %s", pipelineName, projectKey, errl)
@@ -123,6 +127,10 @@ func (api *API) updateJobHandler() Handler {
 			return err
 		}
 
+		if err := job.Matrix.Check(); err != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "updateJobHandler> Invalid matrix: %s", err)
+		}
+
 		if jobID != job.PipelineActionID {
 			return sdk.WrapError(sdk.ErrInvalidID, "updateJobHandler>Pipeline action does not match")
 		}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/quota"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (api *API) getQuotasHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		qs, err := quota.LoadAll(api.mustDB())
+		if err != nil {
+			return sdk.WrapError(err, "getQuotasHandler> Cannot load quotas")
+		}
+		return WriteJSON(w, r, qs, http.StatusOK)
+	}
+}
+
+func (api *API) getQuotaHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		targetID, err := quota.TargetID(api.mustDB(), vars["scope"], vars["name"])
+		if err != nil {
+			return sdk.WrapError(err, "getQuotaHandler> Cannot load %s %s", vars["scope"], vars["name"])
+		}
+
+		q, err := quota.Load(api.mustDB(), vars["scope"], targetID)
+		if err != nil {
+			return sdk.WrapError(err, "getQuotaHandler> Cannot load quota of %s %s", vars["scope"], vars["name"])
+		}
+		return WriteJSON(w, r, q, http.StatusOK)
+	}
+}
+
+func (api *API) putQuotaHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+
+		var q sdk.Quota
+		if err := UnmarshalBody(r, &q); err != nil {
+			return sdk.WrapError(err, "putQuotaHandler> Cannot unmarshal body")
+		}
+		q.Scope = vars["scope"]
+		q.Name = vars["name"]
+		if !q.IsValid() {
+			return sdk.WrapError(sdk.ErrInvalidQuota, "putQuotaHandler> Invalid quota %s %s", q.Scope, q.Name)
+		}
+
+		targetID, err := quota.TargetID(api.mustDB(), q.Scope, q.Name)
+		if err != nil {
+			return sdk.WrapError(err, "putQuotaHandler> Cannot load %s %s", q.Scope, q.Name)
+		}
+		q.TargetID = targetID
+
+		old, err := quota.Load(api.mustDB(), q.Scope, q.TargetID)
+		switch {
+		case err == sdk.ErrNotFound:
+			err = quota.Insert(api.mustDB(), &q)
+		case err == nil:
+			q.ID = old.ID
+			err = quota.Update(api.mustDB(), &q)
+		}
+		if err != nil {
+			return sdk.WrapError(err, "putQuotaHandler> Cannot save quota of %s %s", q.Scope, q.Name)
+		}
+
+		res, err := quota.Load(api.mustDB(), q.Scope, q.TargetID)
+		if err != nil {
+			return sdk.WrapError(err, "putQuotaHandler> Cannot load quota of %s %s", q.Scope, q.Name)
+		}
+		return WriteJSON(w, r, res, http.StatusOK)
+	}
+}
+
+func (api *API) deleteQuotaHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		targetID, err := quota.TargetID(api.mustDB(), vars["scope"], vars["name"])
+		if err != nil {
+			return sdk.WrapError(err, "deleteQuotaHandler> Cannot load %s %s", vars["scope"], vars["name"])
+		}
+
+		q, err := quota.Load(api.mustDB(), vars["scope"], targetID)
+		if err != nil {
+			return sdk.WrapError(err, "deleteQuotaHandler> Cannot load quota of %s %s", vars["scope"], vars["name"])
+		}
+
+		if err := quota.Delete(api.mustDB(), q); err != nil {
+			return sdk.WrapError(err, "deleteQuotaHandler> Cannot delete quota of %s %s", vars["scope"], vars["name"])
+		}
+		return WriteJSON(w, r, nil, http.StatusOK)
+	}
+}
+
+// checkJobQuota returns sdk.ErrQuotaExceeded if a quota forbids to start the job. In this case, a spawn info is
+// added to the job with addSpawnInfos, at most once a minute. When taking a job, db must be the transaction which
+// takes it: the quotas are locked until its end
+func (api *API) checkJobQuota(db gorp.SqlExecutor, jobKey string, projectID, modelID int64, addSpawnInfos func([]sdk.SpawnInfo) error) error {
+	q, err := quota.Check(db, projectID, modelID)
+	if err != nil {
+		return sdk.WrapError(err, "checkJobQuota> Cannot check quotas")
+	}
+	if q == nil {
+		return nil
+	}
+
+	k := cache.Key("quota", "job", jobKey)
+	var notified bool
+	if !api.Cache.Get(k, &notified) {
+		api.Cache.SetWithTTL(k, true, 60)
+		infos := []sdk.SpawnInfo{{
+			RemoteTime: time.Now(),
+			Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobWaitingQuota.ID, Args: []interface{}{q.Scope, q.Name, fmt.Sprintf("%d", q.Current), fmt.Sprintf("%d", q.MaxJobs)}},
+		}}
+		if err := addSpawnInfos(infos); err != nil {
+			log.Warning("checkJobQuota> Cannot add spawn infos on job %s: %s", jobKey, err)
+		}
+	}
+
+	return sdk.WrapError(sdk.ErrQuotaExceeded, "checkJobQuota> job %s: quota of %s %s reached (%d/%d)", jobKey, q.Scope, q.Name, q.Current, q.MaxJobs)
+}
@@ -0,0 +1,36 @@
+package quota
+
+import (
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/sdk"
+)
+
+// Check returns the first quota exceeded by a new job of the project run by a worker of the worker model:
+// the quotas of the project, of the groups which can run its workflows and of the worker model.
+// A zero model id is ignored. It returns nil if no quota is exceeded.
+// The quotas are locked until the end of the transaction, so that concurrent jobs are counted one after the other
+func Check(db gorp.SqlExecutor, projectID, modelID int64) (*sdk.Quota, error) {
+	query := `SELECT id FROM quota
+	WHERE (scope = $1 AND target_id = $2)
+	OR (scope = $3 AND target_id IN (SELECT group_id FROM project_group WHERE project_id = $2 AND role >= $4))
+	OR (scope = $5 AND target_id = $6)
+	ORDER BY id
+	FOR UPDATE`
+	var ids []int64
+	if _, err := db.Select(&ids, query, sdk.QuotaScopeProject, projectID, sdk.QuotaScopeGroup, permission.PermissionReadWriteExecute, sdk.QuotaScopeModel, modelID); err != nil {
+		return nil, sdk.WrapError(err, "quota.Check> Unable to lock quotas of project %d", projectID)
+	}
+
+	for _, id := range ids {
+		qs, err := load(db, selectQuery+" WHERE quota.id = $1", id)
+		if err != nil {
+			return nil, sdk.WrapError(err, "quota.Check> Unable to load quota %d", id)
+		}
+		if len(qs) == 1 && qs[0].Exceeded() {
+			return &qs[0], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,180 @@
+package quota
+
+import (
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/sdk"
+)
+
+// Insert a quota
+func Insert(db gorp.SqlExecutor, q *sdk.Quota) error {
+	dbq := dbQuota(*q)
+	if err := db.Insert(&dbq); err != nil {
+		return sdk.WrapError(err, "quota.Insert> Unable to insert quota %s %s", q.Scope, q.Name)
+	}
+	q.ID = dbq.ID
+	return nil
+}
+
+// Update a quota
+func Update(db gorp.SqlExecutor, q *sdk.Quota) error {
+	dbq := dbQuota(*q)
+	if _, err := db.Update(&dbq); err != nil {
+		return sdk.WrapError(err, "quota.Update> Unable to update quota %s %s", q.Scope, q.Name)
+	}
+	return nil
+}
+
+// Delete a quota
+func Delete(db gorp.SqlExecutor, q *sdk.Quota) error {
+	dbq := dbQuota(*q)
+	if _, err := db.Delete(&dbq); err != nil {
+		return sdk.WrapError(err, "quota.Delete> Unable to delete quota %s %s", q.Scope, q.Name)
+	}
+	return nil
+}
+
+const selectQuery = `
+	SELECT quota.id, quota.scope, quota.target_id, quota.max_jobs, COALESCE("group".name, project.projectkey, worker_model.name, '')
+	FROM quota
+	LEFT JOIN "group" ON quota.scope = 'group' AND "group".id = quota.target_id
+	LEFT JOIN project ON quota.scope = 'project' AND project.id = quota.target_id
+	LEFT JOIN worker_model ON quota.scope = 'model' AND worker_model.id = quota.target_id`
+
+// LoadAll loads all the quotas with their current usage
+func LoadAll(db gorp.SqlExecutor) ([]sdk.Quota, error) {
+	return load(db, selectQuery+" ORDER BY quota.scope, 5")
+}
+
+// Load loads the quota of a group, a project or a worker model with its current usage
+func Load(db gorp.SqlExecutor, scope string, targetID int64) (*sdk.Quota, error) {
+	qs, err := load(db, selectQuery+" WHERE quota.scope = $1 AND quota.target_id = $2", scope, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if len(qs) == 0 {
+		return nil, sdk.ErrNotFound
+	}
+	return &qs[0], nil
+}
+
+func load(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.Quota, error) {
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, sdk.WrapError(err, "quota.load> Unable to load quotas")
+	}
+	defer rows.Close()
+
+	qs := []sdk.Quota{}
+	for rows.Next() {
+		var q sdk.Quota
+		if err := rows.Scan(&q.ID, &q.Scope, &q.TargetID, &q.MaxJobs, &q.Name); err != nil {
+			return nil, sdk.WrapError(err, "quota.load> Unable to scan quota")
+		}
+		qs = append(qs, q)
+	}
+	rows.Close()
+
+	for i := range qs {
+		current, err := Usage(db, qs[i].Scope, qs[i].TargetID)
+		if err != nil {
+			return nil, err
+		}
+		qs[i].Current = current
+	}
+	return qs, nil
+}
+
+// TargetID returns the id of the group, the project or the worker model with the given name
+func TargetID(db gorp.SqlExecutor, scope, name string) (int64, error) {
+	var query string
+	switch scope {
+	case sdk.QuotaScopeGroup:
+		query = `SELECT id FROM "group" WHERE name = $1`
+	case sdk.QuotaScopeProject:
+		query = `SELECT id FROM project WHERE projectkey = $1`
+	case sdk.QuotaScopeModel:
+		query = `SELECT id FROM worker_model WHERE name = $1`
+	default:
+		return 0, sdk.ErrInvalidQuota
+	}
+
+	var id int64
+	if err := db.QueryRow(query, name).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, sdk.WrapError(sdk.ErrNotFound, "quota.TargetID> %s %s not found", scope, name)
+		}
+		return 0, sdk.WrapError(err, "quota.TargetID> Unable to load %s %s", scope, name)
+	}
+	return id, nil
+}
+
+// Usage returns the number of jobs currently running for a group, a project or a worker model
+func Usage(db gorp.SqlExecutor, scope string, targetID int64) (int64, error) {
+	var queries []string
+	switch scope {
+	case sdk.QuotaScopeGroup:
+		// The jobs of the projects in which the group can run workflows
+		queries = []string{
+			`SELECT COUNT(1) FROM workflow_node_run_job
+			JOIN workflow_node_run ON workflow_node_run.id = workflow_node_run_job.workflow_node_run_id
+			JOIN workflow_run ON workflow_run.id = workflow_node_run.workflow_run_id
+			WHERE workflow_run.project_id IN (SELECT project_id FROM project_group WHERE group_id = $1 AND role >= $3)
+			AND workflow_node_run_job.status = $2`,
+			`SELECT COUNT(1) FROM pipeline_build_job
+			JOIN pipeline_build ON pipeline_build.id = pipeline_build_job.pipeline_build_id
+			JOIN pipeline ON pipeline.id = pipeline_build.pipeline_id
+			WHERE pipeline.project_id IN (SELECT project_id FROM project_group WHERE group_id = $1 AND role >= $3)
+			AND pipeline_build_job.status = $2`,
+		}
+	case sdk.QuotaScopeProject:
+		queries = []string{
+			`SELECT COUNT(1) FROM workflow_node_run_job
+			JOIN workflow_node_run ON workflow_node_run.id = workflow_node_run_job.workflow_node_run_id
+			JOIN workflow_run ON workflow_run.id = workflow_node_run.workflow_run_id
+			WHERE workflow_run.project_id = $1 AND workflow_node_run_job.status = $2`,
+			`SELECT COUNT(1) FROM pipeline_build_job
+			JOIN pipeline_build ON pipeline_build.id = pipeline_build_job.pipeline_build_id
+			JOIN pipeline ON pipeline.id = pipeline_build.pipeline_id
+			WHERE pipeline.project_id = $1 AND pipeline_build_job.status = $2`,
+		}
+	case sdk.QuotaScopeModel:
+		queries = []string{
+			`SELECT COUNT(1) FROM workflow_node_run_job WHERE model = (SELECT name FROM worker_model WHERE id = $1) AND status = $2`,
+			`SELECT COUNT(1) FROM pipeline_build_job WHERE model = (SELECT name FROM worker_model WHERE id = $1) AND status = $2`,
+		}
+	default:
+		return 0, sdk.ErrInvalidQuota
+	}
+
+	args := []interface{}{targetID, sdk.StatusBuilding.String()}
+	if scope == sdk.QuotaScopeGroup {
+		args = append(args, permission.PermissionReadWriteExecute)
+	}
+
+	var total int64
+	for _, query := range queries {
+		n, err := db.SelectInt(query, args...)
+		if err != nil {
+			return 0, sdk.WrapError(err, "quota.Usage> Unable to count jobs of %s %d", scope, targetID)
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// ProjectIDByPipelineBuildJob returns the id of the project of a pipeline build job
+func ProjectIDByPipelineBuildJob(db gorp.SqlExecutor, pbJobID int64) (int64, error) {
+	query := `SELECT pipeline.project_id FROM pipeline_build_job
+		JOIN pipeline_build ON pipeline_build.id = pipeline_build_job.pipeline_build_id
+		JOIN pipeline ON pipeline.id = pipeline_build.pipeline_id
+		WHERE pipeline_build_job.id = $1`
+	id, err := db.SelectInt(query, pbJobID)
+	if err != nil {
+		return 0, sdk.WrapError(err, "quota.ProjectIDByPipelineBuildJob> Unable to load project of job %d", pbJobID)
+	}
+	return id, nil
+}
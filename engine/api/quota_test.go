@@ -0,0 +1,82 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_quotaHandlers(t *testing.T) {
+	api, db, router := newTestAPI(t)
+	ctx := test_runWorkflow(t, api, router, db)
+	test_getWorkflowJob(t, api, router, &ctx)
+	assert.NotNil(t, ctx.job)
+
+	//Set a quota on the project
+	vars := map[string]string{
+		"scope": sdk.QuotaScopeProject,
+		"name":  ctx.project.Key,
+	}
+	uri := router.GetRoute("PUT", api.putQuotaHandler, vars)
+	test.NotEmpty(t, uri)
+	req := assets.NewAuthentifiedRequest(t, ctx.user, ctx.password, "PUT", uri, sdk.Quota{MaxJobs: 0})
+	rec := httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, 200, rec.Code)
+
+	//Check the quota
+	uri = router.GetRoute("GET", api.getQuotaHandler, vars)
+	test.NotEmpty(t, uri)
+	req = assets.NewAuthentifiedRequest(t, ctx.user, ctx.password, "GET", uri, nil)
+	rec = httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, 200, rec.Code)
+
+	var q sdk.Quota
+	test.NoError(t, json.Unmarshal(rec.Body.Bytes(), &q))
+	assert.Equal(t, sdk.QuotaScopeProject, q.Scope)
+	assert.Equal(t, ctx.project.Key, q.Name)
+	assert.Equal(t, ctx.project.ID, q.TargetID)
+	assert.Equal(t, int64(0), q.MaxJobs)
+
+	//The job cannot be booked
+	test_registerHatchery(t, api, router, &ctx)
+	uri = router.GetRoute("POST", api.postBookWorkflowJobHandler, map[string]string{
+		"key":              ctx.project.Key,
+		"permWorkflowName": ctx.workflow.Name,
+		"id":               fmt.Sprintf("%d", ctx.job.ID),
+	})
+	test.NotEmpty(t, uri)
+	req = assets.NewAuthentifiedRequestFromHatchery(t, ctx.hatchery, "POST", uri, nil)
+	rec = httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, 429, rec.Code)
+
+	//Invalid quota
+	uri = router.GetRoute("PUT", api.putQuotaHandler, vars)
+	req = assets.NewAuthentifiedRequest(t, ctx.user, ctx.password, "PUT", uri, sdk.Quota{MaxJobs: -1})
+	rec = httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, 400, rec.Code)
+
+	//Delete the quota
+	uri = router.GetRoute("DELETE", api.deleteQuotaHandler, vars)
+	test.NotEmpty(t, uri)
+	req = assets.NewAuthentifiedRequest(t, ctx.user, ctx.password, "DELETE", uri, nil)
+	rec = httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, 200, rec.Code)
+
+	uri = router.GetRoute("GET", api.getQuotaHandler, vars)
+	req = assets.NewAuthentifiedRequest(t, ctx.user, ctx.password, "GET", uri, nil)
+	rec = httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, 404, rec.Code)
+}
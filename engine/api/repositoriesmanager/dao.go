@@ -227,6 +227,15 @@ func AuthorizedClient(db gorp.SqlExecutor, projectKey, rmName string, store cach
 		return nil, err
 	}
 
+	accessToken, accessTokenSecret, err := loadAccessTokens(db, projectKey, rmName)
+	if err != nil {
+		return nil, err
+	}
+	return rm.Consumer.GetAuthorized(accessToken, accessTokenSecret)
+}
+
+//loadAccessTokens returns the tokens granted to the project at the end of the oauth process
+func loadAccessTokens(db gorp.SqlExecutor, projectKey, rmName string) (string, string, error) {
 	var data string
 	query := `SELECT 	repositories_manager_project.data
 			FROM 	repositories_manager_project
@@ -236,20 +245,20 @@ func AuthorizedClient(db gorp.SqlExecutor, projectKey, rmName string, store cach
 			AND		repositories_manager.name = $2`
 
 	if err := db.QueryRow(query, projectKey, rmName).Scan(&data); err != nil {
-		return nil, err
+		return "", "", err
 	}
 
 	var clientData map[string]interface{}
 	if err := json.Unmarshal([]byte(data), &clientData); err != nil {
-		return nil, err
+		return "", "", err
 	}
 
-	if len(clientData) > 0 && clientData["access_token"] != nil && clientData["access_token_secret"] != nil {
-		return rm.Consumer.GetAuthorized(clientData["access_token"].(string), clientData["access_token_secret"].(string))
+	accessToken, okToken := clientData["access_token"].(string)
+	accessTokenSecret, okSecret := clientData["access_token_secret"].(string)
+	if !okToken || !okSecret {
+		return "", "", sdk.ErrNoReposManagerClientAuth
 	}
-
-	return nil, sdk.ErrNoReposManagerClientAuth
-
+	return accessToken, accessTokenSecret, nil
 }
 
 //InsertForApplication associates a repositories manager with an application
@@ -3,11 +3,15 @@ package repositoriesmanager
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"time"
 
+	"github.com/fatih/structs"
 	"github.com/go-gorp/gorp"
 	"github.com/mitchellh/mapstructure"
 
 	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/services"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -29,6 +33,13 @@ func ReceiveEvents(c context.Context, DBFunc func() *gorp.DbMap, store cache.Sto
 
 		db := DBFunc()
 		if db != nil {
+			if e.EventType == fmt.Sprintf("%T", sdk.EventWorkflowNodeRun{}) {
+				if err := processWorkflowNodeRunEvent(DBFunc, e, store); err != nil {
+					log.Error("ReceiveEvents> err while processing error=%s : %v", err, e)
+					retryEvent(&e, err, store)
+				}
+				continue
+			}
 			if err := processEvent(db, e, store); err != nil {
 				log.Error("ReceiveEvents> err while processing error=%s : %v", err, e)
 				retryEvent(&e, err, store)
@@ -79,3 +90,68 @@ func processEvent(db gorp.SqlExecutor, event sdk.Event, store cache.Store) error
 
 	return nil
 }
+
+//processWorkflowNodeRunEvent sets the status of the commit of a workflow node run through the VCS µservices
+func processWorkflowNodeRunEvent(DBFunc func() *gorp.DbMap, event sdk.Event, store cache.Store) error {
+	var eventnr sdk.EventWorkflowNodeRun
+	if err := mapstructure.Decode(event.Payload, &eventnr); err != nil {
+		return sdk.WrapError(err, "processWorkflowNodeRunEvent> Error during consumption")
+	}
+
+	cs, ok := workflowNodeRunCommitStatus(eventnr, options.UIBaseURL)
+	if !ok {
+		return nil
+	}
+
+	srvs, err := services.NewRepository(DBFunc, store).FindByType("vcs")
+	if err != nil {
+		return sdk.WrapError(err, "processWorkflowNodeRunEvent> Unable to load vcs services")
+	}
+	if len(srvs) == 0 {
+		log.Debug("processWorkflowNodeRunEvent> no vcs service available, status of %s not sent", cs.Hash)
+		return nil
+	}
+
+	client, err := NewVCSServiceClient(DBFunc(), srvs, eventnr.ProjectKey, eventnr.RepositoryManagerName)
+	if err != nil {
+		return sdk.WrapError(err, "processWorkflowNodeRunEvent> Unable to get vcs client of %s on %s", eventnr.ProjectKey, eventnr.RepositoryManagerName)
+	}
+
+	e := sdk.Event{
+		Timestamp: time.Now(),
+		EventType: fmt.Sprintf("%T", cs),
+		Payload:   structs.Map(cs),
+	}
+	if err := client.SetStatus(cs.RepositoryFullname, e); err != nil {
+		return sdk.WrapError(err, "processWorkflowNodeRunEvent> Unable to set status on %s", cs.RepositoryFullname)
+	}
+	return nil
+}
+
+//workflowNodeRunCommitStatus returns the commit status of a workflow node run event, false if no status has to be sent
+func workflowNodeRunCommitStatus(e sdk.EventWorkflowNodeRun, uiURL string) (sdk.EventCommitStatus, bool) {
+	if e.RepositoryManagerName == "" || e.RepositoryFullname == "" || e.Hash == "" {
+		return sdk.EventCommitStatus{}, false
+	}
+
+	//We only manage waiting, building and final statuses
+	status := e.Status
+	switch status {
+	case sdk.StatusWaiting, sdk.StatusBuilding, sdk.StatusSuccess, sdk.StatusFail:
+	case sdk.StatusStopped:
+		status = sdk.StatusFail
+	default:
+		return sdk.EventCommitStatus{}, false
+	}
+
+	return sdk.EventCommitStatus{
+		RepositoryFullname: e.RepositoryFullname,
+		Hash:               e.Hash,
+		BranchName:         e.BranchName,
+		Context:            fmt.Sprintf("continuous-delivery/CDS/%s/%s", e.WorkflowName, e.NodeName),
+		Status:             status,
+		Description:        fmt.Sprintf("Workflow %s #%d.%d %s: %s", e.WorkflowName, e.Number, e.SubNumber, e.NodeName, e.Status.String()),
+		URL: fmt.Sprintf("%s/project/%s/workflow/%s/run/%d/node/%d?name=%s", uiURL,
+			e.ProjectKey, e.WorkflowName, e.Number, e.ID, url.QueryEscape(e.PipelineName)),
+	}, true
+}
@@ -0,0 +1,43 @@
+package repositoriesmanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_workflowNodeRunCommitStatus(t *testing.T) {
+	e := sdk.EventWorkflowNodeRun{
+		ID:                    42,
+		Number:                3,
+		SubNumber:             1,
+		Status:                sdk.StatusStopped,
+		ProjectKey:            "KEY",
+		WorkflowName:          "wf",
+		NodeName:              "build",
+		PipelineName:          "build pip",
+		BranchName:            "feat",
+		Hash:                  "abcdef",
+		RepositoryManagerName: "github",
+		RepositoryFullname:    "ovh/cds",
+	}
+
+	cs, ok := workflowNodeRunCommitStatus(e, "http://ui")
+	assert.True(t, ok)
+	assert.Equal(t, sdk.StatusFail, cs.Status)
+	assert.Equal(t, "ovh/cds", cs.RepositoryFullname)
+	assert.Equal(t, "abcdef", cs.Hash)
+	assert.Equal(t, "continuous-delivery/CDS/wf/build", cs.Context)
+	assert.Equal(t, "http://ui/project/KEY/workflow/wf/run/3/node/42?name=build+pip", cs.URL)
+
+	e.Status = sdk.StatusSkipped
+	_, ok = workflowNodeRunCommitStatus(e, "http://ui")
+	assert.False(t, ok)
+
+	e.Status = sdk.StatusSuccess
+	e.RepositoryFullname = ""
+	_, ok = workflowNodeRunCommitStatus(e, "http://ui")
+	assert.False(t, ok)
+}
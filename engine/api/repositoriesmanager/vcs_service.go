@@ -0,0 +1,111 @@
+package repositoriesmanager
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/sdk"
+)
+
+// VCSServiceClient performs requests on the VCS µservices with the tokens granted to a project on a repositories manager.
+// The VCS server of the µservice must have the name of the repositories manager.
+type VCSServiceClient struct {
+	srvs              []sdk.Service
+	name              string
+	accessToken       string
+	accessTokenSecret string
+}
+
+// NewVCSServiceClient returns a client of the VCS µservices for a project and a repositories manager
+func NewVCSServiceClient(db gorp.SqlExecutor, srvs []sdk.Service, projectKey, rmName string) (*VCSServiceClient, error) {
+	if len(srvs) == 0 {
+		return nil, fmt.Errorf("No vcs service available")
+	}
+	accessToken, accessTokenSecret, err := loadAccessTokens(db, projectKey, rmName)
+	if err != nil {
+		return nil, sdk.WrapError(err, "NewVCSServiceClient> Unable to load tokens of %s on %s", projectKey, rmName)
+	}
+	return &VCSServiceClient{
+		srvs:              srvs,
+		name:              rmName,
+		accessToken:       accessToken,
+		accessTokenSecret: accessTokenSecret,
+	}, nil
+}
+
+// doJSONRequest performs the request on the first VCS µservice which answers
+func (c *VCSServiceClient) doJSONRequest(method, path string, in, out interface{}) error {
+	mods := []sdk.RequestModifier{
+		sdk.SetHeader(sdk.HeaderXAccessToken, base64.StdEncoding.EncodeToString([]byte(c.accessToken))),
+		sdk.SetHeader(sdk.HeaderXAccessTokenSecret, base64.StdEncoding.EncodeToString([]byte(c.accessTokenSecret))),
+	}
+
+	var err error
+	for i := range c.srvs {
+		var code int
+		code, err = services.DoJSONRequest(&c.srvs[i], method, path, in, out, mods...)
+		if err == nil && code >= 400 {
+			err = fmt.Errorf("HTTP %d", code)
+		}
+		if err == nil {
+			return nil
+		}
+	}
+	return sdk.WrapError(err, "VCSServiceClient> %s %s failed", method, path)
+}
+
+// Branches returns the branches of a repository
+func (c *VCSServiceClient) Branches(repo string) ([]sdk.VCSBranch, error) {
+	branches := []sdk.VCSBranch{}
+	path := fmt.Sprintf("/vcs/%s/repos/%s/branches", c.name, repo)
+	if err := c.doJSONRequest(http.MethodGet, path, nil, &branches); err != nil {
+		return nil, err
+	}
+	return branches, nil
+}
+
+// Commit returns a commit of a repository
+func (c *VCSServiceClient) Commit(repo, hash string) (sdk.VCSCommit, error) {
+	commit := sdk.VCSCommit{}
+	path := fmt.Sprintf("/vcs/%s/repos/%s/commits/%s", c.name, repo, hash)
+	if err := c.doJSONRequest(http.MethodGet, path, nil, &commit); err != nil {
+		return commit, err
+	}
+	return commit, nil
+}
+
+// Tags returns the tags of a repository
+func (c *VCSServiceClient) Tags(repo string) ([]sdk.VCSTag, error) {
+	tags := []sdk.VCSTag{}
+	path := fmt.Sprintf("/vcs/%s/repos/%s/tags", c.name, repo)
+	if err := c.doJSONRequest(http.MethodGet, path, nil, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// PullRequests returns the pull requests of a repository
+func (c *VCSServiceClient) PullRequests(repo string) ([]sdk.VCSPullRequest, error) {
+	prs := []sdk.VCSPullRequest{}
+	path := fmt.Sprintf("/vcs/%s/repos/%s/pullrequests", c.name, repo)
+	if err := c.doJSONRequest(http.MethodGet, path, nil, &prs); err != nil {
+		return nil, err
+	}
+	return prs, nil
+}
+
+// PullRequestComment adds a comment on a pull request, or updates the comment containing its marker
+func (c *VCSServiceClient) PullRequestComment(repo string, id int, comment sdk.VCSPullRequestComment) error {
+	path := fmt.Sprintf("/vcs/%s/repos/%s/pullrequests/%d/comments", c.name, repo, id)
+	return c.doJSONRequest(http.MethodPost, path, comment, nil)
+}
+
+// SetStatus sets the status of a commit of a repository from an event
+func (c *VCSServiceClient) SetStatus(repo string, event sdk.Event) error {
+	path := fmt.Sprintf("/vcs/%s/repos/%s/status", c.name, repo)
+	return c.doJSONRequest(http.MethodPost, path, event, nil)
+}
@@ -341,6 +341,14 @@ func NeedWorker() HandlerConfigParam {
 	return f
 }
 
+// NeedJobTaken set the route for the worker which has taken the job {permID} only, as the execution routes
+func NeedJobTaken() HandlerConfigParam {
+	f := func(rc *HandlerConfig) {
+		rc.Options["needJobTaken"] = "true"
+	}
+	return f
+}
+
 // AllowServices allows CDS service to use this route
 func AllowServices(s bool) HandlerConfigParam {
 	f := func(rc *HandlerConfig) {
@@ -2,6 +2,7 @@ package api
 
 import (
 	"fmt"
+	"net/http"
 	"strconv"
 
 	"github.com/go-gorp/gorp"
@@ -90,3 +91,29 @@ func loadGroupPermissions(db gorp.SqlExecutor, store cache.Store, groupID int64)
 	}
 	return group, nil
 }
+
+// checkAccessTokenScopes checks that a request is allowed by the scopes of a personal access token.
+// The permissions of the user are checked afterwards
+func checkAccessTokenScopes(t *sdk.AccessToken, method string, isExecution bool, routeVar map[string]string) bool {
+	for _, s := range t.Scopes {
+		switch s {
+		case sdk.AccessTokenScopeRead:
+			if method == http.MethodGet {
+				return true
+			}
+		case sdk.AccessTokenScopeRun:
+			if method == http.MethodGet || (method == http.MethodPost && isExecution) {
+				return true
+			}
+		default:
+			key, ok := sdk.AccessTokenScopeProject(s)
+			if !ok {
+				continue
+			}
+			if routeVar["key"] == key || routeVar["permProjectKey"] == key {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_checkAccessTokenScopes(t *testing.T) {
+	tests := []struct {
+		name        string
+		scopes      []string
+		method      string
+		isExecution bool
+		vars        map[string]string
+		want        bool
+	}{
+		{name: "read allows GET", scopes: []string{"read"}, method: "GET", want: true},
+		{name: "read refuses POST", scopes: []string{"read"}, method: "POST", want: false},
+		{name: "read refuses run", scopes: []string{"read"}, method: "POST", isExecution: true, want: false},
+		{name: "run allows GET", scopes: []string{"run"}, method: "GET", want: true},
+		{name: "run allows run", scopes: []string{"run"}, method: "POST", isExecution: true, want: true},
+		{name: "run refuses update", scopes: []string{"run"}, method: "PUT", want: false},
+		{name: "admin allows update on its project", scopes: []string{"admin:PROJ"}, method: "PUT", vars: map[string]string{"key": "PROJ"}, want: true},
+		{name: "admin allows delete on its project", scopes: []string{"admin:PROJ"}, method: "DELETE", vars: map[string]string{"permProjectKey": "PROJ"}, want: true},
+		{name: "admin refuses another project", scopes: []string{"admin:PROJ"}, method: "GET", vars: map[string]string{"key": "OTHER"}, want: false},
+		{name: "admin refuses routes without project", scopes: []string{"admin:PROJ"}, method: "GET", want: false},
+		{name: "scopes are cumulative", scopes: []string{"read", "admin:PROJ"}, method: "GET", vars: map[string]string{"key": "OTHER"}, want: true},
+		{name: "no scope", method: "GET", want: false},
+	}
+	for _, tt := range tests {
+		tok := &sdk.AccessToken{Scopes: tt.scopes}
+		assert.Equal(t, tt.want, checkAccessTokenScopes(tok, tt.method, tt.isExecution, tt.vars), tt.name)
+	}
+}
@@ -88,6 +88,10 @@ func (api *API) statusHandler() Handler {
 		output = append(output, fmt.Sprintf("LastUpdate Connected: %d", len(api.lastUpdateBroker.clients)))
 		log.Debug("Status> LastUpdate ConnectedUser> %d", len(api.lastUpdateBroker.clients))
 
+		// Check Events Stream Connected User
+		output = append(output, fmt.Sprintf("Events Stream Connected: %d", len(api.eventsBroker.clients)))
+		log.Debug("Status> Events Stream ConnectedUser> %d", len(api.eventsBroker.clients))
+
 		// Check Worker Model Error
 		wmStatus := worker.Status(api.mustDB())
 		output = append(output, fmt.Sprintf("Worker Model Errors: %s", wmStatus))
@@ -157,10 +157,13 @@ func (api *API) getUsersHandler() Handler {
 // AddUser creates a new user and generate verification email
 func (api *API) addUserHandler() Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-		//returns forbidden if LDAP mode is activated
+		//returns forbidden if LDAP or OIDC mode is activated
 		if _, ldap := api.Router.AuthDriver.(*auth.LDAPClient); ldap {
 			return sdk.ErrForbidden
 		}
+		if _, oidc := api.Router.AuthDriver.(*auth.OIDCClient); oidc {
+			return sdk.ErrForbidden
+		}
 
 		createUserRequest := sdk.UserAPIRequest{}
 		if err := UnmarshalBody(r, &createUserRequest); err != nil {
@@ -225,10 +228,13 @@ func (api *API) addUserHandler() Handler {
 
 func (api *API) resetUserHandler() Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-		//returns forbidden if LDAP mode is activated
+		//returns forbidden if LDAP or OIDC mode is activated
 		if _, ldap := api.Router.AuthDriver.(*auth.LDAPClient); ldap {
 			return sdk.ErrForbidden
 		}
+		if _, oidc := api.Router.AuthDriver.(*auth.OIDCClient); oidc {
+			return sdk.ErrForbidden
+		}
 
 		// Get username in URL
 		vars := mux.Vars(r)
@@ -270,6 +276,9 @@ func (api *API) authModeHandler() Handler {
 		if _, ldap := api.Router.AuthDriver.(*auth.LDAPClient); ldap {
 			mode = "ldap"
 		}
+		if _, oidc := api.Router.AuthDriver.(*auth.OIDCClient); oidc {
+			mode = "oidc"
+		}
 		res := map[string]string{
 			"auth_mode": mode,
 		}
@@ -280,10 +289,13 @@ func (api *API) authModeHandler() Handler {
 // ConfirmUser verify token send via email and mark user as verified
 func (api *API) confirmUserHandler() Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-		//returns forbidden if LDAP mode is activated
+		//returns forbidden if LDAP or OIDC mode is activated
 		if _, ldap := api.Router.AuthDriver.(*auth.LDAPClient); ldap {
 			return sdk.ErrForbidden
 		}
+		if _, oidc := api.Router.AuthDriver.(*auth.OIDCClient); oidc {
+			return sdk.ErrForbidden
+		}
 
 		// Get user name in URL
 		vars := mux.Vars(r)
@@ -120,7 +120,7 @@ func (api *API) refreshWorkerHandler() Handler {
 
 func (api *API) unregisterWorkerHandler() Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
-		if err := worker.DeleteWorker(api.mustDB(), getWorker(ctx).ID); err != nil {
+		if err := worker.DeleteWorker(api.mustDB(), api.Cache, getWorker(ctx).ID); err != nil {
 			return sdk.WrapError(err, "unregisterWorkerHandler> cannot delete worker %s", getWorker(ctx).ID)
 		}
 		return nil
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/sdk/log"
 )
 
@@ -12,7 +14,7 @@ import (
 var WorkerHeartbeatTimeout = 600.0
 
 // CheckHeartbeat runs in a goroutine and check last beat from all workers
-func CheckHeartbeat(c context.Context, DBFunc func() *gorp.DbMap) {
+func CheckHeartbeat(c context.Context, DBFunc func() *gorp.DbMap, store cache.Store) {
 	tick := time.NewTicker(10 * time.Second).C
 
 	for {
@@ -32,7 +34,7 @@ func CheckHeartbeat(c context.Context, DBFunc func() *gorp.DbMap) {
 
 				for i := range w {
 					log.Debug("WorkerHeartbeat> Delete worker %s[%s] LastBeat:%d hatchery:%d status:%s", w[i].Name, w[i].ID, w[i].LastBeat, w[i].HatcheryID, w[i].Status)
-					if err = DeleteWorker(db, w[i].ID); err != nil {
+					if err = DeleteWorker(db, store, w[i].ID); err != nil {
 						log.Warning("WorkerHeartbeat> Cannot delete worker %s: %s", w[i].ID, err)
 						continue
 					}
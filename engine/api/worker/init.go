@@ -10,7 +10,7 @@ import (
 
 //Initialize init the package
 func Initialize(c context.Context, DBFunc func() *gorp.DbMap, store cache.Store) error {
-	go CheckHeartbeat(c, DBFunc)
+	go CheckHeartbeat(c, DBFunc, store)
 	go ModelCapabilititiesCacheLoader(c, 10*time.Second, DBFunc, store)
 	return nil
 }
@@ -70,6 +70,15 @@ func LoadWorkerModels(db gorp.SqlExecutor) ([]sdk.Model, error) {
 	return scanWorkerModels(db, wms)
 }
 
+// LoadEnabledWorkerModelNames retrieves the names of the worker models which are not disabled
+func LoadEnabledWorkerModelNames(db gorp.SqlExecutor) ([]string, error) {
+	names := []string{}
+	if _, err := db.Select(&names, "select name from worker_model where disabled = false"); err != nil {
+		return nil, sdk.WrapError(err, "LoadEnabledWorkerModelNames> ")
+	}
+	return names, nil
+}
+
 // loadWorkerModel retrieves a specific worker model in database
 func loadWorkerModel(db gorp.SqlExecutor, query string, args ...interface{}) (*sdk.Model, error) {
 	wms := []dbResultWMS{}
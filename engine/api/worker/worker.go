@@ -10,9 +10,12 @@ import (
 
 	"github.com/go-gorp/gorp"
 
+	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/group"
 	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/token"
+	"github.com/ovh/cds/engine/api/workflow"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
@@ -37,13 +40,14 @@ type WorkflowNodeJobRunInfo struct {
 var ErrNoWorker = fmt.Errorf("cds: no worker found")
 
 // DeleteWorker remove worker from database
-func DeleteWorker(db *gorp.DbMap, id string) error {
+func DeleteWorker(db *gorp.DbMap, store cache.Store, id string) error {
 	tx, errb := db.Begin()
 	if errb != nil {
 		return fmt.Errorf("DeleteWorker> Cannot start tx: %s", errb)
 	}
 	defer tx.Rollback()
 
+	events := &workflow.RunEvents{}
 	query := `SELECT name, status, action_build_id FROM worker WHERE id = $1 FOR UPDATE`
 	var st, name string
 	var pbJobID sql.NullInt64
@@ -60,7 +64,12 @@ func DeleteWorker(db *gorp.DbMap, id string) error {
 		}
 
 		log.Info("Worker %s crashed while building %d !", name, pbJobID.Int64)
-		if err := pipeline.RestartPipelineBuildJob(tx, pbJobID.Int64); err != nil {
+		isWorkflowJob, errR := requeueWorkflowJob(tx, store, events, pbJobID.Int64, id)
+		if errR != nil {
+			log.Error("DeleteWorker[%s]> Cannot requeue workflow node job run: %s", id, errR)
+		} else if isWorkflowJob {
+			log.Info("DeleteWorker[%s]> WorkflowNodeJobRun %d released after crash", id, pbJobID.Int64)
+		} else if err := pipeline.RestartPipelineBuildJob(tx, pbJobID.Int64); err != nil {
 			log.Error("DeleteWorker[%d]> Cannot restart pipeline build job: %s", id, err)
 		} else {
 			log.Info("DeleteWorker[%d]> PipelineBuildJob %d restarted after crash", id, pbJobID.Int64)
@@ -76,10 +85,24 @@ func DeleteWorker(db *gorp.DbMap, id string) error {
 	if err := tx.Commit(); err != nil {
 		return err
 	}
+	events.Publish()
 
 	return nil
 }
 
+// requeueWorkflowJob requeues the workflow node job run built by a worker which vanished.
+// It returns false if the job is not a workflow node job run built by this worker
+func requeueWorkflowJob(db gorp.SqlExecutor, store cache.Store, events *workflow.RunEvents, jobID int64, workerID string) (bool, error) {
+	p, errP := project.LoadProjectByNodeJobRunID(db, store, jobID, nil, project.LoadOptions.WithVariables)
+	if errP == sdk.ErrNoProject {
+		return false, nil
+	}
+	if errP != nil {
+		return false, sdk.WrapError(errP, "requeueWorkflowJob> Cannot load project")
+	}
+	return workflow.RequeueNodeJobRunOfLostWorker(db, store, events, p, jobID, workerID)
+}
+
 // InsertWorker inserts worker representation into database
 func InsertWorker(db gorp.SqlExecutor, w *sdk.Worker, groupID int64) error {
 	query := `INSERT INTO worker (id, name, last_beat, model, status, hatchery_id, hatchery_name, group_id) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
@@ -9,12 +9,12 @@ import (
 )
 
 func TestInsertWorker(t *testing.T) {
-	db, _ := test.SetupPG(t, bootstrap.InitiliazeDB)
+	db, store := test.SetupPG(t, bootstrap.InitiliazeDB)
 
 	workers, err := LoadWorkers(db)
 	test.NoError(t, err)
 	for _, w := range workers {
-		DeleteWorker(db, w.ID)
+		DeleteWorker(db, store, w.ID)
 	}
 
 	w := &sdk.Worker{
@@ -29,12 +29,12 @@ func TestInsertWorker(t *testing.T) {
 }
 
 func TestDeletetWorker(t *testing.T) {
-	db, _ := test.SetupPG(t, bootstrap.InitiliazeDB)
+	db, store := test.SetupPG(t, bootstrap.InitiliazeDB)
 
 	workers, errl := LoadWorkers(db)
 	test.NoError(t, errl)
 	for _, w := range workers {
-		DeleteWorker(db, w.ID)
+		DeleteWorker(db, store, w.ID)
 	}
 
 	w := &sdk.Worker{
@@ -46,18 +46,18 @@ func TestDeletetWorker(t *testing.T) {
 		t.Fatalf("Cannot insert worker: %s", err)
 	}
 
-	if err := DeleteWorker(db, w.ID); err != nil {
+	if err := DeleteWorker(db, store, w.ID); err != nil {
 		t.Fatalf("Cannot delete worker: %s", err)
 	}
 }
 
 func TestLoadWorkers(t *testing.T) {
-	db, _ := test.SetupPG(t, bootstrap.InitiliazeDB)
+	db, store := test.SetupPG(t, bootstrap.InitiliazeDB)
 
 	workers, errl := LoadWorkers(db)
 	test.NoError(t, errl)
 	for _, w := range workers {
-		DeleteWorker(db, w.ID)
+		DeleteWorker(db, store, w.ID)
 	}
 
 	w := &sdk.Worker{ID: "foo1", Name: "aa.bar.io"}
@@ -0,0 +1,200 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/engine/api/workercache"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// cacheReader counts the bytes read from a cache archive and fails when it exceeds the maximum size
+type cacheReader struct {
+	io.ReadCloser
+	max      int64
+	size     int64
+	tooLarge bool
+}
+
+func (c *cacheReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.size += int64(n)
+	if c.max > 0 && c.size > c.max {
+		c.tooLarge = true
+		return n, sdk.ErrWorkerCacheTooLarge
+	}
+	return n, err
+}
+
+func (api *API) postWorkflowJobCacheHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, errI := requestVarInt(r, "permID")
+		if errI != nil {
+			return sdk.WrapError(sdk.ErrInvalidID, "postWorkflowJobCacheHandler> Invalid node job run ID")
+		}
+
+		key := mux.Vars(r)["key"]
+		if !sdk.IsValidWorkerCacheKey(key) {
+			return sdk.WrapError(sdk.ErrInvalidWorkerCacheKey, "postWorkflowJobCacheHandler> Invalid key %s", key)
+		}
+
+		maxSize := int64(api.Config.Workers.CacheMaxSize) * 1024 * 1024
+		if maxSize > 0 && r.ContentLength > maxSize {
+			return sdk.WrapError(sdk.ErrWorkerCacheTooLarge, "postWorkflowJobCacheHandler> Cache %s is %d bytes", key, r.ContentLength)
+		}
+
+		nodeJobRun, errJ := workflow.LoadNodeJobRun(api.mustDB(), api.Cache, id)
+		if errJ != nil {
+			return sdk.WrapError(errJ, "postWorkflowJobCacheHandler> Cannot load node job run")
+		}
+
+		// The cache is stored as a new object, the previous one is deleted only once the new one is saved
+		now := time.Now()
+		c := sdk.WorkerCache{
+			ProjectID:  nodeJobRun.ProjectID,
+			Key:        key,
+			ObjectName: fmt.Sprintf("%s.%d", key, now.UnixNano()),
+		}
+		body := &cacheReader{ReadCloser: r.Body, max: maxSize}
+		if _, err := objectstore.StoreArtifact(&c, body); err != nil {
+			_ = objectstore.DeleteArtifact(&c)
+			if body.tooLarge {
+				return sdk.WrapError(sdk.ErrWorkerCacheTooLarge, "postWorkflowJobCacheHandler> Cache %s exceeds %d bytes", key, maxSize)
+			}
+			return sdk.WrapError(err, "postWorkflowJobCacheHandler> Cannot store cache %s", key)
+		}
+
+		c.Size = body.size
+		c.Created = now
+		c.Expire = now.Add(time.Duration(api.Config.Workers.CacheTTL) * 24 * time.Hour)
+
+		old, errL := workercache.LoadByKey(api.mustDB(), c.ProjectID, key)
+		switch errL {
+		case sdk.ErrNotFound:
+			if err := workercache.Insert(api.mustDB(), &c); err != nil {
+				_ = objectstore.DeleteArtifact(&c)
+				return sdk.WrapError(err, "postWorkflowJobCacheHandler> Cannot insert cache %s", key)
+			}
+		case nil:
+			c.ID = old.ID
+			if err := workercache.Update(api.mustDB(), &c); err != nil {
+				_ = objectstore.DeleteArtifact(&c)
+				return sdk.WrapError(err, "postWorkflowJobCacheHandler> Cannot update cache %s", key)
+			}
+			if err := objectstore.DeleteArtifact(old); err != nil {
+				log.Warning("postWorkflowJobCacheHandler> Cannot delete previous object %s of cache %s: %s", old.GetName(), key, err)
+			}
+		default:
+			_ = objectstore.DeleteArtifact(&c)
+			return sdk.WrapError(errL, "postWorkflowJobCacheHandler> Cannot load cache %s", key)
+		}
+
+		return WriteJSON(w, r, c, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowJobCacheHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, errI := requestVarInt(r, "permID")
+		if errI != nil {
+			return sdk.WrapError(sdk.ErrInvalidID, "getWorkflowJobCacheHandler> Invalid node job run ID")
+		}
+
+		keys := r.URL.Query()["key"]
+		if len(keys) == 0 {
+			return sdk.WrapError(sdk.ErrWrongRequest, "getWorkflowJobCacheHandler> Missing key")
+		}
+		for _, k := range keys {
+			if !sdk.IsValidWorkerCacheKey(k) {
+				return sdk.WrapError(sdk.ErrInvalidWorkerCacheKey, "getWorkflowJobCacheHandler> Invalid key %s", k)
+			}
+		}
+
+		nodeJobRun, errJ := workflow.LoadNodeJobRun(api.mustDB(), api.Cache, id)
+		if errJ != nil {
+			return sdk.WrapError(errJ, "getWorkflowJobCacheHandler> Cannot load node job run")
+		}
+
+		caches, errC := workercache.LoadAllByProject(api.mustDB(), nodeJobRun.ProjectID)
+		if errC != nil {
+			return sdk.WrapError(errC, "getWorkflowJobCacheHandler> Cannot load caches")
+		}
+
+		c := sdk.FindWorkerCache(caches, keys)
+		if c == nil {
+			return sdk.ErrNotFound
+		}
+		return WriteJSON(w, r, c, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowJobCacheDownloadHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, errI := requestVarInt(r, "permID")
+		if errI != nil {
+			return sdk.WrapError(sdk.ErrInvalidID, "getWorkflowJobCacheDownloadHandler> Invalid node job run ID")
+		}
+
+		key := mux.Vars(r)["key"]
+		if !sdk.IsValidWorkerCacheKey(key) {
+			return sdk.WrapError(sdk.ErrInvalidWorkerCacheKey, "getWorkflowJobCacheDownloadHandler> Invalid key %s", key)
+		}
+
+		nodeJobRun, errJ := workflow.LoadNodeJobRun(api.mustDB(), api.Cache, id)
+		if errJ != nil {
+			return sdk.WrapError(errJ, "getWorkflowJobCacheDownloadHandler> Cannot load node job run")
+		}
+
+		c, errC := workercache.LoadByKey(api.mustDB(), nodeJobRun.ProjectID, key)
+		if errC != nil {
+			return sdk.WrapError(errC, "getWorkflowJobCacheDownloadHandler> Cannot load cache %s", key)
+		}
+		if c.Expire.Before(time.Now()) {
+			return sdk.WrapError(sdk.ErrNotFound, "getWorkflowJobCacheDownloadHandler> Cache %s has expired", key)
+		}
+
+		f, errF := objectstore.FetchArtifact(c)
+		if errF != nil {
+			return sdk.WrapError(errF, "getWorkflowJobCacheDownloadHandler> Cannot fetch cache %s", key)
+		}
+		defer f.Close()
+
+		w.Header().Add("Content-Type", "application/x-tar")
+		if _, err := io.Copy(w, f); err != nil {
+			return sdk.WrapError(err, "getWorkflowJobCacheDownloadHandler> Cannot stream cache %s", key)
+		}
+		return nil
+	}
+}
+
+// workerCachePurgeRoutine deletes the expired caches of the workers every hour
+func workerCachePurgeRoutine(c context.Context, DBFunc func() *gorp.DbMap) {
+	tick := time.NewTicker(time.Hour).C
+
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting workerCachePurgeRoutine: %v", c.Err())
+			}
+			return
+		case <-tick:
+			db := DBFunc()
+			if db == nil {
+				continue
+			}
+			if err := workercache.PurgeExpired(db); err != nil {
+				log.Warning("workerCachePurgeRoutine> %s", err)
+			}
+		}
+	}
+}
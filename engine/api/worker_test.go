@@ -30,7 +30,7 @@ func Test_workerCheckingHandler(t *testing.T) {
 	}
 	//2. Delete all workers and hatcheries
 	for _, w := range workers {
-		if err := worker.DeleteWorker(api.mustDB(), w.ID); err != nil {
+		if err := worker.DeleteWorker(api.mustDB(), api.Cache, w.ID); err != nil {
 			t.Fatal(err)
 		}
 	}
@@ -123,7 +123,7 @@ func Test_workerWaitingHandler(t *testing.T) {
 	}
 	//2. Delete all workers and hatcheries
 	for _, w := range workers {
-		if err := worker.DeleteWorker(api.mustDB(), w.ID); err != nil {
+		if err := worker.DeleteWorker(api.mustDB(), api.Cache, w.ID); err != nil {
 			t.Fatal(err)
 		}
 	}
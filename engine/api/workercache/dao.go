@@ -0,0 +1,72 @@
+package workercache
+
+import (
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Insert a cache
+func Insert(db gorp.SqlExecutor, c *sdk.WorkerCache) error {
+	dbc := dbWorkerCache(*c)
+	if err := db.Insert(&dbc); err != nil {
+		return sdk.WrapError(err, "workercache.Insert> Unable to insert cache %s", c.Key)
+	}
+	c.ID = dbc.ID
+	return nil
+}
+
+// Update a cache
+func Update(db gorp.SqlExecutor, c *sdk.WorkerCache) error {
+	dbc := dbWorkerCache(*c)
+	if _, err := db.Update(&dbc); err != nil {
+		return sdk.WrapError(err, "workercache.Update> Unable to update cache %s", c.Key)
+	}
+	return nil
+}
+
+// Delete a cache
+func Delete(db gorp.SqlExecutor, c *sdk.WorkerCache) error {
+	dbc := dbWorkerCache(*c)
+	if _, err := db.Delete(&dbc); err != nil {
+		return sdk.WrapError(err, "workercache.Delete> Unable to delete cache %s", c.Key)
+	}
+	return nil
+}
+
+// LoadByKey loads a cache of a project given its key
+func LoadByKey(db gorp.SqlExecutor, projectID int64, key string) (*sdk.WorkerCache, error) {
+	var dbc dbWorkerCache
+	if err := db.SelectOne(&dbc, "SELECT * FROM worker_cache WHERE project_id = $1 AND cache_key = $2", projectID, key); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, sdk.ErrNotFound
+		}
+		return nil, sdk.WrapError(err, "workercache.LoadByKey> Unable to load cache %s", key)
+	}
+	c := sdk.WorkerCache(dbc)
+	return &c, nil
+}
+
+// LoadAllByProject loads the caches of a project which have not expired
+func LoadAllByProject(db gorp.SqlExecutor, projectID int64) ([]sdk.WorkerCache, error) {
+	return load(db, "SELECT * FROM worker_cache WHERE project_id = $1 AND expire > now() ORDER BY cache_key", projectID)
+}
+
+// LoadExpired loads the caches which have expired
+func LoadExpired(db gorp.SqlExecutor) ([]sdk.WorkerCache, error) {
+	return load(db, "SELECT * FROM worker_cache WHERE expire < now()")
+}
+
+func load(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.WorkerCache, error) {
+	var dbcs []dbWorkerCache
+	if _, err := db.Select(&dbcs, query, args...); err != nil {
+		return nil, sdk.WrapError(err, "workercache.load> Unable to load caches")
+	}
+	cs := make([]sdk.WorkerCache, len(dbcs))
+	for i := range dbcs {
+		cs[i] = sdk.WorkerCache(dbcs[i])
+	}
+	return cs, nil
+}
@@ -0,0 +1,29 @@
+package workercache
+
+import (
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/objectstore"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// PurgeExpired deletes the expired caches from the objectstore and the database
+func PurgeExpired(db gorp.SqlExecutor) error {
+	cs, err := LoadExpired(db)
+	if err != nil {
+		return err
+	}
+
+	for i := range cs {
+		c := &cs[i]
+		if err := objectstore.DeleteArtifact(c); err != nil {
+			log.Warning("workercache.PurgeExpired> Unable to delete cache %s of project %d from objectstore: %s", c.Key, c.ProjectID, err)
+			continue
+		}
+		if err := Delete(db, c); err != nil {
+			return err
+		}
+		log.Debug("workercache.PurgeExpired> Cache %s of project %d deleted", c.Key, c.ProjectID)
+	}
+	return nil
+}
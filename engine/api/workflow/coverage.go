@@ -0,0 +1,211 @@
+package workflow
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/fatih/structs"
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/sdk"
+)
+
+// coverageCommentFiles is the maximum number of files listed in a pull request comment
+const coverageCommentFiles = 10
+
+// LoadCoverageReference loads the coverage of the last run of a node on a branch before the given run number.
+// It returns the number of this run, 0 if there is none.
+func LoadCoverageReference(db gorp.SqlExecutor, nodeID int64, branch string, number int64) (int64, *sdk.CoverageReport, error) {
+	var res = struct {
+		Number   int64          `db:"num"`
+		Coverage sql.NullString `db:"coverage"`
+	}{}
+	query := `select workflow_node_run.num, workflow_node_run.coverage
+	from workflow_node_run
+	join workflow_run_tag on workflow_run_tag.workflow_run_id = workflow_node_run.workflow_run_id
+	where workflow_node_run.workflow_node_id = $1
+	and workflow_run_tag.tag = 'git.branch'
+	and workflow_run_tag.value = $2
+	and workflow_node_run.num < $3
+	and workflow_node_run.coverage is not null
+	order by workflow_node_run.num desc, workflow_node_run.sub_num desc
+	limit 1`
+	if err := db.SelectOne(&res, query, nodeID, branch, number); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil, nil
+		}
+		return 0, nil, sdk.WrapError(err, "LoadCoverageReference> Unable to load coverage of node %d on %s", nodeID, branch)
+	}
+
+	report := new(sdk.CoverageReport)
+	if err := gorpmapping.JSONNullString(res.Coverage, report); err != nil {
+		return 0, nil, sdk.WrapError(err, "LoadCoverageReference> Unable to read coverage of node %d on run %d", nodeID, res.Number)
+	}
+	return res.Number, report, nil
+}
+
+// NewCoverageVCSClient returns a client of the VCS µservices for the repository of the application of a node.
+// The client is nil if the application has no repository or if there is no VCS µservice.
+func NewCoverageVCSClient(db gorp.SqlExecutor, srvs []sdk.Service, wr *sdk.WorkflowRun, node *sdk.WorkflowNode) (*repositoriesmanager.VCSServiceClient, string, error) {
+	if len(srvs) == 0 || node.Context == nil || node.Context.Application == nil {
+		return nil, "", nil
+	}
+	app := node.Context.Application
+	if app.RepositoriesManager == nil || app.RepositoryFullname == "" {
+		return nil, "", nil
+	}
+	client, err := repositoriesmanager.NewVCSServiceClient(db, srvs, wr.Workflow.ProjectKey, app.RepositoriesManager.Name)
+	if err != nil {
+		return nil, "", err
+	}
+	return client, app.RepositoryFullname, nil
+}
+
+// DefaultBranch returns the default branch of a repository, master if it is unknown
+func DefaultBranch(client *repositoriesmanager.VCSServiceClient, repo string) string {
+	if client == nil {
+		return "master"
+	}
+	branches, err := client.Branches(repo)
+	if err != nil {
+		return "master"
+	}
+	for _, b := range branches {
+		if b.Default {
+			return b.DisplayID
+		}
+	}
+	return "master"
+}
+
+// NodeRunCoverage returns the coverage of a node run compared to the last run of the node on the default branch
+func NodeRunCoverage(db gorp.SqlExecutor, wr *sdk.WorkflowRun, nr *sdk.WorkflowNodeRun, defaultBranch string) (*sdk.WorkflowNodeRunCoverage, error) {
+	if nr.Coverage == nil {
+		return nil, nil
+	}
+
+	c := &sdk.WorkflowNodeRunCoverage{
+		WorkflowNodeRunID: nr.ID,
+		Number:            nr.Number,
+		SubNumber:         nr.SubNumber,
+		Branch:            sdk.ParameterValue(nr.BuildParameters, "git.branch"),
+		Report:            *nr.Coverage,
+		DefaultBranch:     defaultBranch,
+	}
+	if node := wr.Workflow.GetNode(nr.WorkflowNodeID); node != nil {
+		c.NodeName = node.Name
+	}
+
+	number, reference, err := LoadCoverageReference(db, nr.WorkflowNodeID, defaultBranch, nr.Number)
+	if err != nil {
+		return nil, sdk.WrapError(err, "NodeRunCoverage> Unable to load reference of node run %d", nr.ID)
+	}
+	if reference != nil {
+		delta := sdk.CompareCoverage(*nr.Coverage, *reference)
+		c.ReferenceNumber = number
+		c.Delta = &delta
+	}
+	return c, nil
+}
+
+// coverageStatusDescription returns the description of the commit status of a coverage
+func coverageStatusDescription(c *sdk.WorkflowNodeRunCoverage) string {
+	desc := fmt.Sprintf("Coverage %.2f%%", c.Report.Summary.Percent)
+	if c.Delta != nil {
+		desc += fmt.Sprintf(" (%+.2f%% compared to %s)", c.Delta.Percent, c.DefaultBranch)
+	}
+	if c.Report.IsBelowMinimum() {
+		desc += fmt.Sprintf(", minimum is %.2f%%", c.Report.Minimum)
+	}
+	return desc
+}
+
+// coverageComment returns the markdown comment of a coverage on a pull request
+func coverageComment(c *sdk.WorkflowNodeRunCoverage, buildURL string) string {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "### [CDS](%s) coverage of %s: %s\n\n", buildURL, c.NodeName, coverageStatusDescription(c))
+	buf.WriteString("| | Covered | Total | Percent |\n|---|---:|---:|---:|\n")
+	s := c.Report.Summary
+	fmt.Fprintf(&buf, "| Lines | %d | %d | %.2f%% |\n", s.LinesCovered, s.LinesTotal, s.Percent)
+	if s.BranchesTotal > 0 {
+		fmt.Fprintf(&buf, "| Branches | %d | %d | %.2f%% |\n", s.BranchesCovered, s.BranchesTotal, float64(s.BranchesCovered)/float64(s.BranchesTotal)*100)
+	}
+
+	if c.Delta == nil {
+		fmt.Fprintf(&buf, "\nNo coverage on %s to compare with.\n", c.DefaultBranch)
+		return buf.String()
+	}
+	if len(c.Delta.Files) == 0 {
+		return buf.String()
+	}
+
+	fmt.Fprintf(&buf, "\nFiles changed compared to %s #%d:\n\n| File | Percent | Delta |\n|---|---:|---:|\n", c.DefaultBranch, c.ReferenceNumber)
+	for i, f := range c.Delta.Files {
+		if i == coverageCommentFiles {
+			fmt.Fprintf(&buf, "\n%d more file(s)\n", len(c.Delta.Files)-coverageCommentFiles)
+			break
+		}
+		delta := fmt.Sprintf("%+.2f%%", f.Delta)
+		if f.New {
+			delta = "new"
+		}
+		fmt.Fprintf(&buf, "| %s | %.2f%% | %s |\n", f.Path, f.Percent, delta)
+	}
+	return buf.String()
+}
+
+// SendCoverageStatus sets a commit status with the coverage of a node run, and comments the pull requests of its branch
+func SendCoverageStatus(client *repositoriesmanager.VCSServiceClient, repo, uiURL string, wr *sdk.WorkflowRun, c *sdk.WorkflowNodeRunCoverage, nr *sdk.WorkflowNodeRun) error {
+	buildURL := fmt.Sprintf("%s/project/%s/workflow/%s/run/%d/node/%d?name=%s", uiURL,
+		wr.Workflow.ProjectKey, wr.Workflow.Name, nr.Number, nr.ID, url.QueryEscape(sdk.ParameterValue(nr.BuildParameters, "cds.pipeline")))
+
+	status := sdk.StatusSuccess
+	if c.Report.IsBelowMinimum() {
+		status = sdk.StatusFail
+	}
+	payload := sdk.EventCommitStatus{
+		RepositoryFullname: repo,
+		Hash:               sdk.ParameterValue(nr.BuildParameters, "git.hash"),
+		BranchName:         c.Branch,
+		Context:            fmt.Sprintf("continuous-delivery/CDS/coverage/%s", c.NodeName),
+		Status:             status,
+		Description:        coverageStatusDescription(c),
+		URL:                buildURL,
+	}
+	event := sdk.Event{
+		Timestamp: time.Now(),
+		EventType: fmt.Sprintf("%T", payload),
+		Payload:   structs.Map(payload),
+	}
+	if err := client.SetStatus(repo, event); err != nil {
+		return sdk.WrapError(err, "SendCoverageStatus> Unable to set status on %s", repo)
+	}
+
+	if c.Branch == "" || c.Branch == c.DefaultBranch {
+		return nil
+	}
+	prs, err := client.PullRequests(repo)
+	if err != nil {
+		return sdk.WrapError(err, "SendCoverageStatus> Unable to load pull requests of %s", repo)
+	}
+	// The marker identifies the comment of the node, which is updated at each run
+	marker := fmt.Sprintf("<!-- cds-coverage %s/%s -->", wr.Workflow.Name, c.NodeName)
+	comment := sdk.VCSPullRequestComment{
+		Message: coverageComment(c, buildURL) + "\n" + marker + "\n",
+		Marker:  marker,
+	}
+	for _, pr := range prs {
+		if pr.Head.Branch.DisplayID != c.Branch {
+			continue
+		}
+		if err := client.PullRequestComment(repo, pr.ID, comment); err != nil {
+			return sdk.WrapError(err, "SendCoverageStatus> Unable to comment pull request %d of %s", pr.ID, repo)
+		}
+	}
+	return nil
+}
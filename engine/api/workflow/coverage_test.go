@@ -0,0 +1,43 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_coverageComment(t *testing.T) {
+	c := &sdk.WorkflowNodeRunCoverage{
+		NodeName:      "build",
+		Branch:        "feat/coverage",
+		DefaultBranch: "master",
+		Report: sdk.CoverageReport{
+			Minimum: 80,
+			Summary: sdk.CoverageSummary{LinesCovered: 75, LinesTotal: 100, Percent: 75},
+		},
+	}
+
+	assert.Equal(t, "Coverage 75.00%, minimum is 80.00%", coverageStatusDescription(c))
+	assert.Contains(t, coverageComment(c, "http://cds/run"), "No coverage on master to compare with.")
+
+	c.ReferenceNumber = 12
+	c.Delta = &sdk.CoverageDelta{
+		Percent: -1.5,
+		Files: []sdk.CoverageFileDelta{
+			{Path: "a.go", Percent: 50, Delta: -10},
+			{Path: "b.go", Percent: 90, Delta: 90, New: true},
+		},
+	}
+	assert.Equal(t, "Coverage 75.00% (-1.50% compared to master), minimum is 80.00%", coverageStatusDescription(c))
+
+	comment := coverageComment(c, "http://cds/run")
+	assert.True(t, strings.HasPrefix(comment, "### [CDS](http://cds/run) coverage of build: "))
+	assert.Contains(t, comment, "| Lines | 75 | 100 | 75.00% |")
+	assert.NotContains(t, comment, "| Branches |")
+	assert.Contains(t, comment, "Files changed compared to master #12")
+	assert.Contains(t, comment, "| a.go | 50.00% | -10.00% |")
+	assert.Contains(t, comment, "| b.go | 90.00% | new |")
+}
@@ -2,11 +2,13 @@ package workflow
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 
 	"github.com/go-gorp/gorp"
 
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/engine/api/sessionstore"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -34,6 +36,25 @@ func insertHook(db gorp.SqlExecutor, node *sdk.WorkflowNode, hook *sdk.WorkflowN
 	}
 	hook.WorkflowHookModelID = hook.WorkflowHookModel.ID
 
+	if hook.Config == nil {
+		hook.Config = sdk.WorkflowNodeHookConfig{}
+	}
+
+	//The repository poller polls the repository of the node application
+	if hook.WorkflowHookModel.Name == GitPollerModel.Name {
+		if node.Context == nil || node.Context.Application == nil {
+			return sdk.WrapError(sdk.ErrNoReposManager, "insertHook> Repository poller needs an application on node %s", node.Name)
+		}
+		hook.Config["application"] = node.Context.Application.Name
+	}
+
+	//Keys added to the model are set to their default value in the hooks created before
+	for _, k := range optionalConfigKeys[hook.WorkflowHookModel.Name] {
+		if _, ok := hook.Config[k]; !ok {
+			hook.Config[k] = hook.WorkflowHookModel.DefaultConfig[k]
+		}
+	}
+
 	errmu := sdk.MultiError{}
 	// Check configuration of the hook vs the model
 	for k := range hook.WorkflowHookModel.DefaultConfig {
@@ -69,12 +90,35 @@ func (r *NodeHook) PostInsert(db gorp.SqlExecutor) error {
 		return err
 	}
 
-	sConfig, errgo := gorpmapping.JSONToNullString(r.Config)
+	//The secrets are encrypted in another column
+	config := sdk.WorkflowNodeHookConfig{}
+	secrets := sdk.WorkflowNodeHookConfig{}
+	for k, v := range r.Config {
+		if r.Config.IsSecret(k) && v != "" {
+			secrets[k] = v
+		} else {
+			config[k] = v
+		}
+	}
+
+	sConfig, errgo := gorpmapping.JSONToNullString(config)
 	if errgo != nil {
 		return errgo
 	}
 
-	if _, err := db.Exec("update workflow_node_hook set conditions = $2, config = $3 where id = $1", r.ID, sConditions, sConfig); err != nil {
+	var encryptedSecrets []byte
+	if len(secrets) > 0 {
+		btes, err := json.Marshal(secrets)
+		if err != nil {
+			return err
+		}
+		encryptedSecrets, err = secret.Encrypt(btes)
+		if err != nil {
+			return sdk.WrapError(err, "NodeHook.PostInsert> Unable to encrypt secrets of hook %s", r.UUID)
+		}
+	}
+
+	if _, err := db.Exec("update workflow_node_hook set conditions = $2, config = $3, config_secrets = $4 where id = $1", r.ID, sConditions, sConfig, encryptedSecrets); err != nil {
 		return err
 	}
 	return nil
@@ -83,10 +127,11 @@ func (r *NodeHook) PostInsert(db gorp.SqlExecutor) error {
 //PostGet is a db hook
 func (r *NodeHook) PostGet(db gorp.SqlExecutor) error {
 	var res = struct {
-		Conditions sql.NullString `db:"conditions"`
-		Config     sql.NullString `db:"config"`
+		Conditions    sql.NullString `db:"conditions"`
+		Config        sql.NullString `db:"config"`
+		ConfigSecrets []byte         `db:"config_secrets"`
 	}{}
-	if err := db.SelectOne(&res, "select conditions, config from workflow_node_hook where id = $1", r.ID); err != nil {
+	if err := db.SelectOne(&res, "select conditions, config, config_secrets from workflow_node_hook where id = $1", r.ID); err != nil {
 		return err
 	}
 
@@ -109,6 +154,17 @@ func (r *NodeHook) PostGet(db gorp.SqlExecutor) error {
 		return err
 	}
 
+	//Secrets of hooks saved before their encryption are in the config
+	if len(res.ConfigSecrets) > 0 {
+		btes, err := secret.Decrypt(res.ConfigSecrets)
+		if err != nil {
+			return sdk.WrapError(err, "NodeHook.PostGet> Unable to decrypt secrets of hook %s", r.UUID)
+		}
+		if err := json.Unmarshal(btes, &conf); err != nil {
+			return err
+		}
+	}
+
 	r.Config = conf
 
 	//Load the model
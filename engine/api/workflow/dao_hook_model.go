@@ -18,7 +18,11 @@ var (
 		Name:       "WebHook",
 		Icon:       "",
 		DefaultConfig: sdk.WorkflowNodeHookConfig{
-			"method": "POST",
+			"method":          "POST",
+			"secret":          "",
+			"events":          "",
+			"branches":        "",
+			"ignoredBranches": "",
 		},
 	}
 
@@ -28,6 +32,11 @@ var (
 		Identifier: "github.com/ovh/cds/hook/builtin/poller",
 		Name:       "Git Repository Poller",
 		Icon:       "",
+		DefaultConfig: sdk.WorkflowNodeHookConfig{
+			"branches": "",
+			"tags":     "false",
+			"interval": "60",
+		},
 	}
 
 	SchedulerModel = &sdk.WorkflowHookModel{
@@ -42,10 +51,46 @@ var (
 		},
 	}
 
+	KafkaHookModel = &sdk.WorkflowHookModel{
+		Author:     "CDS",
+		Type:       sdk.WorkflowHookModelBuiltin,
+		Identifier: "github.com/ovh/cds/hook/builtin/kafka",
+		Name:       "Kafka hook",
+		Icon:       "",
+		DefaultConfig: sdk.WorkflowNodeHookConfig{
+			"brokers":       "",
+			"topic":         "",
+			"consumerGroup": "",
+			"username":      "",
+			"password":      "",
+		},
+	}
+
+	AMQPHookModel = &sdk.WorkflowHookModel{
+		Author:     "CDS",
+		Type:       sdk.WorkflowHookModelBuiltin,
+		Identifier: "github.com/ovh/cds/hook/builtin/amqp",
+		Name:       "AMQP hook",
+		Icon:       "",
+		DefaultConfig: sdk.WorkflowNodeHookConfig{
+			"url":         "",
+			"queue":       "",
+			"consumerTag": "",
+		},
+	}
+
+	// optionalConfigKeys are the keys added to the default configuration of a model after hooks have been created with it.
+	// The default value is used if they are missing in the configuration of a hook
+	optionalConfigKeys = map[string][]string{
+		WebHookModel.Name: {"secret", "events", "branches", "ignoredBranches"},
+	}
+
 	builtinModels = []*sdk.WorkflowHookModel{
 		WebHookModel,
 		GitPollerModel,
 		SchedulerModel,
+		KafkaHookModel,
+		AMQPHookModel,
 	}
 )
 
@@ -84,7 +129,7 @@ func (r *NodeHookModel) PostGet(db gorp.SqlExecutor) error {
 	return nil
 }
 
-//CreateBuiltinWorkflowHookModels insert all builtin hook models in database
+//CreateBuiltinWorkflowHookModels insert or update all builtin hook models in database
 func CreateBuiltinWorkflowHookModels(db *gorp.DbMap) error {
 	tx, err := db.Begin()
 	if err != nil {
@@ -106,6 +151,12 @@ func CreateBuiltinWorkflowHookModels(db *gorp.DbMap) error {
 			if err := InsertHookModel(tx, h); err != nil {
 				return sdk.WrapError(err, "CreateBuiltinWorkflowHookModels")
 			}
+			continue
+		}
+
+		//Builtin models are updated to get the last default configuration
+		if err := UpdateHookModel(tx, h); err != nil {
+			return sdk.WrapError(err, "CreateBuiltinWorkflowHookModels")
 		}
 	}
 	return tx.Commit()
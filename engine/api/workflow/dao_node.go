@@ -92,6 +92,13 @@ func insertNode(db gorp.SqlExecutor, w *sdk.Workflow, n *sdk.WorkflowNode, u *sd
 		}
 	}
 
+	//Insert notifications
+	for i := range n.Notifications {
+		if err := insertNotification(db, n, &n.Notifications[i]); err != nil {
+			return sdk.WrapError(err, "InsertOrUpdateNode> Unable to insert workflow node notification")
+		}
+	}
+
 	//Insert triggers
 	for i := range n.Triggers {
 		t := &n.Triggers[i]
@@ -110,6 +117,7 @@ type sqlContext struct {
 	EnvID                     sql.NullInt64  `db:"environment_id"`
 	DefaultPayload            sql.NullString `db:"default_payload"`
 	DefaultPipelineParameters sql.NullString `db:"default_pipeline_parameters"`
+	Priority                  int64          `db:"priority"`
 }
 
 func insertNodeContext(db gorp.SqlExecutor, c *sdk.WorkflowNodeContext) error {
@@ -120,6 +128,7 @@ func insertNodeContext(db gorp.SqlExecutor, c *sdk.WorkflowNodeContext) error {
 	var sqlContext = sqlContext{}
 	sqlContext.ID = c.ID
 	sqlContext.WorkflowNodeID = c.WorkflowNodeID
+	sqlContext.Priority = c.Priority
 
 	// Set ApplicationID in context
 	if c.ApplicationID != 0 {
@@ -201,6 +210,13 @@ func loadNode(db gorp.SqlExecutor, store cache.Store, w *sdk.Workflow, id int64,
 	}
 	wn.Hooks = hooks
 
+	//Load notifications
+	notifs, errNotifs := loadNotifications(db, &wn)
+	if errNotifs != nil {
+		return nil, sdk.WrapError(errNotifs, "LoadNode> Unable to load notifications of %d", id)
+	}
+	wn.Notifications = notifs
+
 	//Load pipeline
 	pip, err := pipeline.LoadPipelineByID(db, wn.PipelineID, true)
 	if err != nil {
@@ -225,9 +241,10 @@ func loadNodeContext(db gorp.SqlExecutor, store cache.Store, wn *sdk.WorkflowNod
 
 	var sqlContext = sqlContext{}
 	if err := db.SelectOne(&sqlContext,
-		"select application_id, environment_id, default_payload, default_pipeline_parameters from workflow_node_context where id = $1", ctx.ID); err != nil {
+		"select application_id, environment_id, default_payload, default_pipeline_parameters, priority from workflow_node_context where id = $1", ctx.ID); err != nil {
 		return nil, err
 	}
+	ctx.Priority = sqlContext.Priority
 	if sqlContext.AppID.Valid {
 		ctx.ApplicationID = sqlContext.AppID.Int64
 	}
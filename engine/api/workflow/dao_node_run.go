@@ -102,12 +102,13 @@ type sqlNodeRun struct {
 	PipelineParameters sql.NullString `db:"pipeline_parameters"`
 	BuildParameters    sql.NullString `db:"build_parameters"`
 	Tests              sql.NullString `db:"tests"`
+	Coverage           sql.NullString `db:"coverage"`
 	Commits            sql.NullString `db:"commits"`
 	Stages             sql.NullString `db:"stages"`
 }
 
 //PostInsert is a db hook on WorkflowNodeRun in table workflow_node_run
-//it stores columns hook_event, manual, trigger_id, payload, pipeline_parameters, tests, commits
+//it stores columns hook_event, manual, trigger_id, payload, pipeline_parameters, tests, coverage, commits
 func (r *NodeRun) PostInsert(db gorp.SqlExecutor) error {
 	var rr = sqlNodeRun{ID: r.ID}
 	if r.Stages != nil {
@@ -166,6 +167,13 @@ func (r *NodeRun) PostInsert(db gorp.SqlExecutor) error {
 		}
 		rr.Tests = s
 	}
+	if r.Coverage != nil {
+		s, err := gorpmapping.JSONToNullString(r.Coverage)
+		if err != nil {
+			return sdk.WrapError(err, "NodeRun.PostInsert> unable to get json from coverage")
+		}
+		rr.Coverage = s
+	}
 	if r.Commits != nil {
 		s, err := gorpmapping.JSONToNullString(r.Commits)
 		if err != nil {
@@ -183,7 +191,7 @@ func (r *NodeRun) PostInsert(db gorp.SqlExecutor) error {
 }
 
 //PostUpdate is a db hook on WorkflowNodeRun in table workflow_node_run
-//it stores columns hook_event, manual, trigger_id, payload, pipeline_parameters, tests, commits
+//it stores columns hook_event, manual, trigger_id, payload, pipeline_parameters, tests, coverage, commits
 func (r *NodeRun) PostUpdate(db gorp.SqlExecutor) error {
 	return r.PostInsert(db)
 }
@@ -239,6 +247,12 @@ func (r *NodeRun) PostGet(db gorp.SqlExecutor) error {
 	if err := gorpmapping.JSONNullString(rr.Tests, r.Tests); err != nil {
 		return sdk.WrapError(err, "NodeRun.PostGet> Error loading node run %d", r.ID)
 	}
+	if rr.Coverage.Valid {
+		r.Coverage = new(sdk.CoverageReport)
+	}
+	if err := gorpmapping.JSONNullString(rr.Coverage, r.Coverage); err != nil {
+		return sdk.WrapError(err, "NodeRun.PostGet> Error loading node run %d", r.ID)
+	}
 
 	arts, errA := loadArtifactByNodeRunID(db, r.ID)
 	if errA != nil {
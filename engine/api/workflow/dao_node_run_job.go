@@ -37,6 +37,7 @@ func LoadNodeJobRunQueue(db gorp.SqlExecutor, store cache.Store, groupsID []int6
 		true = $4
 	)
 	and workflow_node_run_job.queued >= $2
+	and workflow_node_run_job.retry_after <= now()
 	and workflow_node_run_job.status = ANY(string_to_array($3, ','))`
 
 	var groupID string
@@ -67,6 +68,14 @@ func LoadNodeJobRunQueue(db gorp.SqlExecutor, store cache.Store, groupsID []int6
 		jobs[i] = sdk.WorkflowNodeJobRun(sqlJobs[i])
 	}
 
+	if len(jobs) > 1 {
+		building, err := countBuildingJobsByProject(db)
+		if err != nil {
+			return nil, sdk.WrapError(err, "workflow.LoadNodeJobRun> Unable to sort job runs")
+		}
+		sortQueue(jobs, building)
+	}
+
 	return jobs, nil
 }
 
@@ -142,7 +151,7 @@ func DeleteNodeJobRuns(db gorp.SqlExecutor, nodeID int64) error {
 }
 
 //UpdateNodeJobRun updates a workflow_node_run_job
-func UpdateNodeJobRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, j *sdk.WorkflowNodeJobRun) error {
+func UpdateNodeJobRun(db gorp.SqlExecutor, store cache.Store, events *RunEvents, p *sdk.Project, j *sdk.WorkflowNodeJobRun) error {
 	dbj := JobRun(*j)
 	if _, err := db.Update(&dbj); err != nil {
 		return err
@@ -152,7 +161,7 @@ func UpdateNodeJobRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, j
 	if errR != nil {
 		return errR
 	}
-	return execute(db, store, p, nRun)
+	return execute(db, store, events, p, nRun)
 }
 
 func keyBookJob(id int64) string {
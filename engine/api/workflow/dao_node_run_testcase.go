@@ -0,0 +1,135 @@
+package workflow
+
+import (
+	"time"
+
+	"github.com/go-gorp/gorp"
+	"github.com/ovh/venom"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// InsertNodeRunTestCases stores the result of each test case of the test suites sent by a job of the node run
+func InsertNodeRunTestCases(db gorp.SqlExecutor, nr *sdk.WorkflowNodeRun, tests venom.Tests) error {
+	var res = struct {
+		WorkflowID int64  `db:"workflow_id"`
+		NodeName   string `db:"node_name"`
+	}{}
+	query := `select workflow_run.workflow_id, coalesce(workflow_node.name, '') as node_name
+	from workflow_run
+	left join workflow_node on workflow_node.id = $2
+	where workflow_run.id = $1`
+	if err := db.SelectOne(&res, query, nr.WorkflowRunID, nr.WorkflowNodeID); err != nil {
+		return sdk.WrapError(err, "InsertNodeRunTestCases> Unable to load workflow of run %d", nr.WorkflowRunID)
+	}
+	if res.NodeName == "" {
+		res.NodeName = sdk.ParameterValue(nr.BuildParameters, "cds.pipeline")
+	}
+
+	now := time.Now()
+	for _, t := range sdk.NewWorkflowNodeRunTestCases(tests) {
+		t.WorkflowID = res.WorkflowID
+		t.WorkflowRunID = nr.WorkflowRunID
+		t.WorkflowNodeRunID = nr.ID
+		t.Number = nr.Number
+		t.SubNumber = nr.SubNumber
+		t.NodeName = res.NodeName
+		t.Branch = sdk.ParameterValue(nr.BuildParameters, "git.branch")
+		t.Hash = sdk.ParameterValue(nr.BuildParameters, "git.hash")
+		t.Created = now
+
+		dbt := NodeRunTestCase(t)
+		if err := db.Insert(&dbt); err != nil {
+			return sdk.WrapError(err, "InsertNodeRunTestCases> Unable to insert test case %s of node run %d", t.Name, nr.ID)
+		}
+	}
+	return nil
+}
+
+func loadNodeRunTestCases(db gorp.SqlExecutor, query string, args ...interface{}) ([]sdk.WorkflowNodeRunTestCase, error) {
+	res := []NodeRunTestCase{}
+	if _, err := db.Select(&res, query, args...); err != nil {
+		return nil, err
+	}
+	tests := make([]sdk.WorkflowNodeRunTestCase, len(res))
+	for i := range res {
+		tests[i] = sdk.WorkflowNodeRunTestCase(res[i])
+	}
+	return tests, nil
+}
+
+// LoadTestCaseHistory loads the last results of a test case in the runs of a workflow, the most recent first
+func LoadTestCaseHistory(db gorp.SqlExecutor, projectKey, workflowName, nodeName, testSuite, name string, limit int) ([]sdk.WorkflowNodeRunTestCase, error) {
+	query := `select workflow_node_run_testcase.*
+	from workflow_node_run_testcase
+	join workflow on workflow.id = workflow_node_run_testcase.workflow_id
+	join project on project.id = workflow.project_id
+	where project.projectkey = $1
+	and workflow.name = $2
+	and workflow_node_run_testcase.node_name = $3
+	and workflow_node_run_testcase.testsuite = $4
+	and workflow_node_run_testcase.name = $5
+	order by workflow_node_run_testcase.num desc, workflow_node_run_testcase.sub_num desc
+	limit $6`
+	tests, err := loadNodeRunTestCases(db, query, projectKey, workflowName, nodeName, testSuite, name, limit)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadTestCaseHistory> Unable to load history of %s/%s", testSuite, name)
+	}
+	return tests, nil
+}
+
+// LoadFlakyTestCases loads the test cases of a workflow which succeeded and failed on the same commit, the last seen first
+func LoadFlakyTestCases(db gorp.SqlExecutor, projectKey, workflowName string, limit int) ([]sdk.FlakyTestCase, error) {
+	query := `select node_name, testsuite, name, count(hash) as flaky_hashes, max(last_seen) as last_seen
+	from (
+		select workflow_node_run_testcase.node_name, workflow_node_run_testcase.testsuite, workflow_node_run_testcase.name,
+			workflow_node_run_testcase.hash, max(workflow_node_run_testcase.created) as last_seen
+		from workflow_node_run_testcase
+		join workflow on workflow.id = workflow_node_run_testcase.workflow_id
+		join project on project.id = workflow.project_id
+		where project.projectkey = $1
+		and workflow.name = $2
+		and workflow_node_run_testcase.hash <> ''
+		group by workflow_node_run_testcase.node_name, workflow_node_run_testcase.testsuite, workflow_node_run_testcase.name, workflow_node_run_testcase.hash
+		having bool_or(workflow_node_run_testcase.status = $3) and bool_or(workflow_node_run_testcase.status = $4)
+	) as flaky
+	group by node_name, testsuite, name
+	order by last_seen desc
+	limit $5`
+	res := []sdk.FlakyTestCase{}
+	if _, err := db.Select(&res, query, projectKey, workflowName, sdk.StatusSuccess.String(), sdk.StatusFail.String(), limit); err != nil {
+		return nil, sdk.WrapError(err, "LoadFlakyTestCases> Unable to load flaky tests of %s/%s", projectKey, workflowName)
+	}
+	return res, nil
+}
+
+// LoadRunTestCases loads the results of the test cases of a workflow run
+func LoadRunTestCases(db gorp.SqlExecutor, workflowRunID int64) ([]sdk.WorkflowNodeRunTestCase, error) {
+	query := `select * from workflow_node_run_testcase where workflow_run_id = $1 order by id`
+	tests, err := loadNodeRunTestCases(db, query, workflowRunID)
+	if err != nil {
+		return nil, sdk.WrapError(err, "LoadRunTestCases> Unable to load tests of run %d", workflowRunID)
+	}
+	return tests, nil
+}
+
+// LoadPreviousRunTestCases loads the results of the test cases of the last run with tests on the same branch before the given run number.
+// It returns the number of this run, 0 if there is none.
+func LoadPreviousRunTestCases(db gorp.SqlExecutor, workflowID, number int64, branch string) (int64, []sdk.WorkflowNodeRunTestCase, error) {
+	query := `select coalesce(max(num), 0) from workflow_node_run_testcase
+	where workflow_id = $1 and branch = $2 and num < $3`
+	previous, err := db.SelectInt(query, workflowID, branch, number)
+	if err != nil {
+		return 0, nil, sdk.WrapError(err, "LoadPreviousRunTestCases> Unable to load previous run of %d on %s", number, branch)
+	}
+	if previous == 0 {
+		return 0, []sdk.WorkflowNodeRunTestCase{}, nil
+	}
+
+	query = `select * from workflow_node_run_testcase where workflow_id = $1 and num = $2 order by id`
+	tests, err := loadNodeRunTestCases(db, query, workflowID, previous)
+	if err != nil {
+		return 0, nil, sdk.WrapError(err, "LoadPreviousRunTestCases> Unable to load tests of run %d", previous)
+	}
+	return previous, tests, nil
+}
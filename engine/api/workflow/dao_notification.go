@@ -0,0 +1,79 @@
+package workflow
+
+import (
+	"database/sql"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/database/gorpmapping"
+	"github.com/ovh/cds/sdk"
+)
+
+// insertNotification inserts a notification of a node
+func insertNotification(db gorp.SqlExecutor, node *sdk.WorkflowNode, notif *sdk.WorkflowNodeNotification) error {
+	if err := notif.IsValid(); err != nil {
+		return sdk.WrapError(err, "insertNotification> Invalid %s notification on node %s", notif.Type, node.Name)
+	}
+
+	notif.WorkflowNodeID = node.ID
+	dbnotif := NodeNotification(*notif)
+	if err := db.Insert(&dbnotif); err != nil {
+		return sdk.WrapError(err, "insertNotification> Unable to insert notification")
+	}
+	*notif = sdk.WorkflowNodeNotification(dbnotif)
+	return nil
+}
+
+// PostInsert is a db hook
+func (n *NodeNotification) PostInsert(db gorp.SqlExecutor) error {
+	settings, err := gorpmapping.JSONToNullString(n.Settings)
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec("update workflow_node_notification set settings = $2 where id = $1", n.ID, settings); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PostGet is a db hook
+func (n *NodeNotification) PostGet(db gorp.SqlExecutor) error {
+	var settings sql.NullString
+	if err := db.QueryRow("select settings from workflow_node_notification where id = $1", n.ID).Scan(&settings); err != nil {
+		return err
+	}
+	return gorpmapping.JSONNullString(settings, &n.Settings)
+}
+
+// loadNotifications loads the notifications of a node
+func loadNotifications(db gorp.SqlExecutor, node *sdk.WorkflowNode) ([]sdk.WorkflowNodeNotification, error) {
+	res := []NodeNotification{}
+	if _, err := db.Select(&res, "select id, workflow_node_id, type from workflow_node_notification where workflow_node_id = $1 order by id", node.ID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, sdk.WrapError(err, "loadNotifications")
+	}
+
+	notifs := make([]sdk.WorkflowNodeNotification, len(res))
+	for i := range res {
+		notifs[i] = sdk.WorkflowNodeNotification(res[i])
+	}
+	return notifs, nil
+}
+
+// loadPreviousNodeRunStatus returns the status of the previous run of the node which is over, empty if there is no such run
+func loadPreviousNodeRunStatus(db gorp.SqlExecutor, n *sdk.WorkflowNodeRun) (string, error) {
+	query := `select status from workflow_node_run
+	where workflow_node_id = $1
+	and (num < $2 or (num = $2 and sub_num < $3))
+	and status in ($4, $5, $6)
+	order by num desc, sub_num desc
+	limit 1`
+	status, err := db.SelectNullStr(query, n.WorkflowNodeID, n.Number, n.SubNumber,
+		sdk.StatusSuccess.String(), sdk.StatusFail.String(), sdk.StatusStopped.String())
+	if err != nil {
+		return "", sdk.WrapError(err, "loadPreviousNodeRunStatus> Unable to load previous run of node %d", n.WorkflowNodeID)
+	}
+	return status.String, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/go-gorp/gorp"
 
+	"github.com/ovh/cds/engine/api/artifact"
 	"github.com/ovh/cds/engine/api/database/gorpmapping"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
@@ -387,12 +388,48 @@ func PurgeWorkflowRun(db gorp.SqlExecutor, wf sdk.Workflow) error {
 }
 
 // deleteWorkflowRunsHistory is useful to delete all the workflow run marked with to delete flag in db
-func deleteWorkflowRunsHistory(db gorp.SqlExecutor) error {
+func deleteWorkflowRunsHistory(db *gorp.DbMap) error {
+	tx, errB := db.Begin()
+	if errB != nil {
+		return sdk.WrapError(errB, "deleteWorkflowRunsHistory> Unable to start transaction")
+	}
+	defer tx.Rollback()
+
+	// Artifacts are deleted with their workflow run, release their blobs first
+	var blobs []string
+	queryBlobs := `
+		SELECT workflow_node_run_artifacts.sha256sum
+		FROM workflow_node_run_artifacts
+		JOIN workflow_run ON workflow_run.id = workflow_node_run_artifacts.workflow_run_id
+		WHERE workflow_run.to_delete = true AND workflow_node_run_artifacts.sha256sum <> ''
+	`
+	if _, err := tx.Select(&blobs, queryBlobs); err != nil {
+		log.Warning("deleteWorkflowRunsHistory> Unable to load artifacts of workflow history %s", err)
+		return err
+	}
+
+	for _, sha256sum := range blobs {
+		if err := artifact.ReleaseBlob(tx, sha256sum); err != nil {
+			log.Warning("deleteWorkflowRunsHistory> Unable to release artifact blob %s: %s", sha256sum, err)
+			return err
+		}
+	}
+
 	query := `DELETE FROM workflow_run WHERE to_delete = true`
 
-	if _, err := db.Exec(query); err != nil {
+	if _, err := tx.Exec(query); err != nil {
 		log.Warning("deleteWorkflowRunsHistory> Unable to delete workflow history %s", err)
 		return err
 	}
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "deleteWorkflowRunsHistory> Unable to commit transaction")
+	}
+
+	// The blobs are deleted from the objectstore once they are not referenced anymore in db
+	for _, sha256sum := range blobs {
+		if err := artifact.DeleteBlob(db, sha256sum); err != nil {
+			log.Warning("deleteWorkflowRunsHistory> Unable to delete artifact blob %s: %s", sha256sum, err)
+		}
+	}
 	return nil
 }
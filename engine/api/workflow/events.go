@@ -0,0 +1,38 @@
+package workflow
+
+import (
+	"github.com/ovh/cds/engine/api/event"
+	"github.com/ovh/cds/sdk"
+)
+
+// RunEvents collects the events of the runs updated within a transaction.
+// They have to be published with Publish once the transaction is committed
+type RunEvents struct {
+	publishers []func()
+}
+
+// workflowRun collects a workflow run event, built from the current state of the workflow run
+func (e *RunEvents) workflowRun(wr *sdk.WorkflowRun) {
+	r := *wr
+	e.publishers = append(e.publishers, func() { event.PublishWorkflowRun(&r) })
+}
+
+// nodeRun collects a workflow node run event, built from the current state of the node run
+func (e *RunEvents) nodeRun(wr *sdk.WorkflowRun, n *sdk.WorkflowNodeRun) {
+	nr := *n
+	e.publishers = append(e.publishers, func() { event.PublishWorkflowNodeRun(wr, &nr) })
+}
+
+// jobRun collects a workflow node job run event, built from the current state of the job run
+func (e *RunEvents) jobRun(n *sdk.WorkflowNodeRun, j *sdk.WorkflowNodeJobRun) {
+	nr, jr := *n, *j
+	e.publishers = append(e.publishers, func() { event.PublishJobRun(&nr, &jr) })
+}
+
+// Publish publishes the collected events, in the order they have been collected
+func (e *RunEvents) Publish() {
+	for _, p := range e.publishers {
+		p()
+	}
+	e.publishers = nil
+}
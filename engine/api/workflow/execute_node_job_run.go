@@ -11,14 +11,13 @@ import (
 	"github.com/ovh/cds/engine/api/application"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/engine/api/environment"
-	"github.com/ovh/cds/engine/api/event"
 	"github.com/ovh/cds/engine/api/secret"
 	"github.com/ovh/cds/sdk"
 	"github.com/ovh/cds/sdk/log"
 )
 
 // UpdateNodeJobRunStatus Update status of an workflow_node_run_job
-func UpdateNodeJobRunStatus(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, job *sdk.WorkflowNodeJobRun, status sdk.Status) error {
+func UpdateNodeJobRunStatus(db gorp.SqlExecutor, store cache.Store, events *RunEvents, p *sdk.Project, job *sdk.WorkflowNodeJobRun, status sdk.Status) error {
 	log.Debug("UpdateNodeJobRunStatus> job.ID=%d status=%s", job.ID, status.String())
 
 	node, errLoad := LoadNodeRunByID(db, job.WorkflowNodeRunID)
@@ -53,6 +52,9 @@ func UpdateNodeJobRunStatus(db gorp.SqlExecutor, store cache.Store, p *sdk.Proje
 			// too late, Nate
 			return nil
 		}
+		if status == sdk.StatusFail && currentStatus == sdk.StatusBuilding.String() && canRetryNodeJobRun(job, false) {
+			return requeueNodeJobRun(db, store, events, p, job, false)
+		}
 		job.Done = time.Now()
 		job.Status = status.String()
 		wf.LastExecution = time.Now()
@@ -97,13 +99,18 @@ func UpdateNodeJobRunStatus(db gorp.SqlExecutor, store cache.Store, p *sdk.Proje
 
 	if stageUpdated {
 		log.Debug("UpdateNodeJobRunStatus> stageUpdated, set status node from %s to %s", node.Status, job.Status)
+		previousStatus := node.Status
 		node.Status = job.Status
 		if err := UpdateNodeRun(db, node); err != nil {
 			return sdk.WrapError(err, "workflow.UpdateNodeJobRunStatus> Unable to update workflow node run %d", node.ID)
 		}
+		if node.Status != previousStatus {
+			events.nodeRun(wf, node)
+			sendNodeRunNotifications(store, wf, node)
+		}
 	} else {
 		log.Debug("UpdateNodeJobRunStatus> call execute node")
-		if errE := execute(db, store, p, node); errE != nil {
+		if errE := execute(db, store, events, p, node); errE != nil {
 			return sdk.WrapError(errE, "workflow.UpdateNodeJobRunStatus> Cannot execute sync node")
 		}
 	}
@@ -112,17 +119,17 @@ func UpdateNodeJobRunStatus(db gorp.SqlExecutor, store cache.Store, p *sdk.Proje
 		return sdk.WrapError(err, "workflow.UpdateNodeJobRunStatus> Cannot update WorkflowRun %d", wf.ID)
 	}
 
-	if err := UpdateNodeJobRun(db, store, p, job); err != nil {
+	if err := UpdateNodeJobRun(db, store, events, p, job); err != nil {
 		return sdk.WrapError(err, "workflow.UpdateNodeJobRunStatus> Cannot update WorkflowNodeJobRun %d", job.ID)
 	}
 
-	event.PublishJobRun(node, job)
+	events.jobRun(node, job)
 
 	return nil
 }
 
 // AddSpawnInfosNodeJobRun saves spawn info before starting worker
-func AddSpawnInfosNodeJobRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, id int64, infos []sdk.SpawnInfo) (*sdk.WorkflowNodeJobRun, error) {
+func AddSpawnInfosNodeJobRun(db gorp.SqlExecutor, store cache.Store, events *RunEvents, p *sdk.Project, id int64, infos []sdk.SpawnInfo) (*sdk.WorkflowNodeJobRun, error) {
 	j, err := LoadAndLockNodeJobRunNoWait(db, store, id)
 	if err != nil {
 		return nil, sdk.WrapError(err, "AddSpawnInfosNodeJobRun> Cannot load node job run")
@@ -131,7 +138,15 @@ func AddSpawnInfosNodeJobRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Proj
 		return nil, sdk.WrapError(err, "AddSpawnInfosNodeJobRun> Cannot prepare spawn infos")
 	}
 
-	if err := UpdateNodeJobRun(db, store, p, j); err != nil {
+	// The hatchery could not spawn a worker: the job is booked again later, or fails after too many spawn errors
+	if j.Status == sdk.StatusWaiting.String() && hasSpawnError(infos) {
+		if err := spawnErrorNodeJobRun(db, store, events, p, j); err != nil {
+			return nil, sdk.WrapError(err, "AddSpawnInfosNodeJobRun> Cannot handle spawn error")
+		}
+		return j, nil
+	}
+
+	if err := UpdateNodeJobRun(db, store, events, p, j); err != nil {
 		return nil, sdk.WrapError(err, "AddSpawnInfosNodeJobRun> Cannot update node job run")
 	}
 	return j, nil
@@ -150,7 +165,7 @@ func prepareSpawnInfos(j *sdk.WorkflowNodeJobRun, infos []sdk.SpawnInfo) error {
 }
 
 // TakeNodeJobRun Take an a job run for update
-func TakeNodeJobRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, id int64, workerModel string, workerName string, workerID string, infos []sdk.SpawnInfo) (*sdk.WorkflowNodeJobRun, error) {
+func TakeNodeJobRun(db gorp.SqlExecutor, store cache.Store, events *RunEvents, p *sdk.Project, id int64, workerModel string, workerName string, workerID string, infos []sdk.SpawnInfo) (*sdk.WorkflowNodeJobRun, error) {
 	job, err := LoadAndLockNodeJobRunNoWait(db, store, id)
 	if err != nil {
 		if errPG, ok := err.(*pq.Error); ok && errPG.Code == "55P03" {
@@ -176,7 +191,7 @@ func TakeNodeJobRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, id i
 		return nil, sdk.WrapError(err, "TakeNodeJobRun> Cannot prepare spawn infos")
 	}
 
-	if err := UpdateNodeJobRunStatus(db, store, p, job, sdk.StatusBuilding); err != nil {
+	if err := UpdateNodeJobRunStatus(db, store, events, p, job, sdk.StatusBuilding); err != nil {
 		log.Debug("TakeNodeJobRun> call UpdateNodeJobRunStatus on job %d set status from %s to %s", job.ID, job.Status, sdk.StatusBuilding)
 		return nil, sdk.WrapError(err, "TakeNodeJobRun>Cannot update node job run")
 	}
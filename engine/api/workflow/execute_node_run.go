@@ -13,7 +13,7 @@ import (
 )
 
 //execute is called by the scheduler. You should not call this by yourself
-func execute(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, n *sdk.WorkflowNodeRun) (errExecute error) {
+func execute(db gorp.SqlExecutor, store cache.Store, events *RunEvents, p *sdk.Project, n *sdk.WorkflowNodeRun) (errExecute error) {
 	t0 := time.Now()
 	log.Debug("workflow.execute> Begin [#%d.%d] runID=%d (%s)", n.Number, n.SubNumber, n.WorkflowRunID, n.Status)
 	defer func() {
@@ -37,6 +37,7 @@ func execute(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, n *sdk.Work
 		return nil
 	}
 
+	var previousStatus = n.Status
 	var newStatus = n.Status
 
 	//If no stages ==> success
@@ -58,7 +59,7 @@ func execute(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, n *sdk.Work
 			//Add job to Queue
 			//Insert data in workflow_node_run_job
 			log.Debug("workflow.execute> stage %s call addJobsToQueue", stage.Name)
-			if err := addJobsToQueue(db, stage, n); err != nil {
+			if err := addJobsToQueue(db, events, p, stage, n); err != nil {
 				return err
 			}
 			if stage.Status == sdk.StatusSkipped || stage.Status == sdk.StatusDisabled {
@@ -120,10 +121,15 @@ func execute(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, n *sdk.Work
 		return sdk.WrapError(err, "workflow.execute> Unable to reload workflow run id=%d", n.WorkflowRunID)
 	}
 
+	if n.Status != previousStatus {
+		events.nodeRun(updatedWorkflowRun, n)
+		sendNodeRunNotifications(store, updatedWorkflowRun, n)
+	}
+
 	// If pipeline build succeed, reprocess the workflow (in the same transaction)
 	//Delete jobs only when node is over
 	if n.Status == sdk.StatusSuccess.String() || n.Status == sdk.StatusFail.String() {
-		if err := processWorkflowRun(db, store, p, updatedWorkflowRun, nil, nil, nil); err != nil {
+		if err := processWorkflowRun(db, store, events, p, updatedWorkflowRun, nil, nil, nil); err != nil {
 			return sdk.WrapError(err, "workflow.execute> Unable to reprocess workflow !")
 		}
 
@@ -136,7 +142,7 @@ func execute(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, n *sdk.Work
 	return nil
 }
 
-func addJobsToQueue(db gorp.SqlExecutor, stage *sdk.Stage, run *sdk.WorkflowNodeRun) error {
+func addJobsToQueue(db gorp.SqlExecutor, events *RunEvents, p *sdk.Project, stage *sdk.Stage, run *sdk.WorkflowNodeRun) error {
 	log.Debug("addJobsToQueue> add %d in stage %s", run.ID, stage.Name)
 
 	conditionsOK, err := sdk.WorkflowCheckConditions(stage.Conditions(), run.BuildParameters)
@@ -154,63 +160,85 @@ func addJobsToQueue(db gorp.SqlExecutor, stage *sdk.Stage, run *sdk.WorkflowNode
 	//Browse the jobs
 	for j := range stage.Jobs {
 		job := &stage.Jobs[j]
-		errs := sdk.MultiError{}
-		//Process variables for the jobs
-		jobParams, errParam := getNodeJobRunParameters(db, *job, run, stage)
-		if errParam != nil {
-			errs.Join(*errParam)
-		}
-		jobRequirements, errReq := getNodeJobRunRequirements(db, *job, run)
-		if errReq != nil {
-			errs.Join(*errReq)
+		//A job with a matrix is expanded into one job run per combination
+		for _, ejob := range expandJobMatrix(*job) {
+			if err := addJobToQueue(db, events, p, stage, run, job, ejob, conditionsOK); err != nil {
+				return err
+			}
 		}
-		job.Action.Requirements = jobRequirements
+	}
 
-		//Create the job run
-		wjob := sdk.WorkflowNodeJobRun{
-			WorkflowNodeRunID: run.ID,
-			Start:             time.Time{},
-			Queued:            time.Now(),
-			Status:            sdk.StatusWaiting.String(),
-			Parameters:        jobParams,
-			Job: sdk.ExecutedJob{
-				Job: *job,
-			},
-		}
+	return nil
+}
 
-		if !stage.Enabled || !wjob.Job.Enabled {
-			wjob.Status = sdk.StatusDisabled.String()
-		} else if !conditionsOK {
-			wjob.Status = sdk.StatusSkipped.String()
-		}
+func addJobToQueue(db gorp.SqlExecutor, events *RunEvents, p *sdk.Project, stage *sdk.Stage, run *sdk.WorkflowNodeRun, job *sdk.Job, ejob sdk.ExecutedJob, conditionsOK bool) error {
+	errs := sdk.MultiError{}
+	//Process variables for the jobs
+	jobParams, errParam := getNodeJobRunParameters(db, ejob, run, stage)
+	if errParam != nil {
+		errs.Join(*errParam)
+	}
+	jobRequirements, errReq := getNodeJobRunRequirements(db, ejob.Job, jobParams)
+	if errReq != nil {
+		errs.Join(*errReq)
+	}
+	ejob.Action.Requirements = jobRequirements
+	//The requirements of the job runs of a matrix differ, the job keeps its own
+	if ejob.MatrixValues == nil {
+		job.Action.Requirements = jobRequirements
+	}
 
-		if errParam != nil {
-			wjob.Status = sdk.StatusFail.String()
-			spawnInfos := sdk.SpawnMsg{
-				ID: sdk.MsgSpawnInfoJobError.ID,
-			}
+	//Create the job run
+	wjob := sdk.WorkflowNodeJobRun{
+		WorkflowNodeRunID: run.ID,
+		Start:             time.Time{},
+		Queued:            time.Now(),
+		Status:            sdk.StatusWaiting.String(),
+		Parameters:        jobParams,
+		ProjectID:         p.ID,
+		Priority:          run.Priority,
+		Job:               ejob,
+	}
 
-			for _, e := range *errParam {
-				spawnInfos.Args = append(spawnInfos.Args, e.Error())
-			}
+	if !stage.Enabled || !wjob.Job.Enabled {
+		wjob.Status = sdk.StatusDisabled.String()
+	} else if !conditionsOK {
+		wjob.Status = sdk.StatusSkipped.String()
+	}
 
-			wjob.SpawnInfos = []sdk.SpawnInfo{sdk.SpawnInfo{
-				APITime:    time.Now(),
-				Message:    spawnInfos,
-				RemoteTime: time.Now(),
-			}}
+	if ejob.MatrixValues != nil {
+		wjob.SpawnInfos = append(wjob.SpawnInfos, sdk.SpawnInfo{
+			APITime:    time.Now(),
+			Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobMatrix.ID, Args: []interface{}{sdk.JobMatrixCombinationString(ejob.MatrixValues)}},
+			RemoteTime: time.Now(),
+		})
+	}
+
+	if errParam != nil {
+		wjob.Status = sdk.StatusFail.String()
+		spawnInfos := sdk.SpawnMsg{
+			ID: sdk.MsgSpawnInfoJobError.ID,
 		}
 
-		//Insert in database
-		if err := insertWorkflowNodeJobRun(db, &wjob); err != nil {
-			return sdk.WrapError(err, "addJobsToQueue> Unable to insert in table workflow_node_run_job")
+		for _, e := range *errParam {
+			spawnInfos.Args = append(spawnInfos.Args, e.Error())
 		}
 
-		//Put the job run in database
-		event.PublishJobRun(run, &wjob)
-		stage.RunJobs = append(stage.RunJobs, wjob)
+		wjob.SpawnInfos = append(wjob.SpawnInfos, sdk.SpawnInfo{
+			APITime:    time.Now(),
+			Message:    spawnInfos,
+			RemoteTime: time.Now(),
+		})
+	}
+
+	//Insert in database
+	if err := insertWorkflowNodeJobRun(db, &wjob); err != nil {
+		return sdk.WrapError(err, "addJobToQueue> Unable to insert in table workflow_node_run_job")
 	}
 
+	//Put the job run in database
+	events.jobRun(run, &wjob)
+	stage.RunJobs = append(stage.RunJobs, wjob)
 	return nil
 }
 
@@ -329,13 +357,14 @@ func StopWorkflowNodeRun(db *gorp.DbMap, store cache.Store, proj *sdk.Project, n
 	}
 	defer tx.Rollback()
 
+	events := &RunEvents{}
 	for _, nrjID := range ids {
 		njr, errNRJ := LoadAndLockNodeJobRunWait(tx, store, nrjID)
 		if errNRJ != nil {
 			return sdk.WrapError(errNRJ, "StopWorkflowNodeRun> Cannot load node job run id")
 		}
 		njr.SpawnInfos = append(njr.SpawnInfos, stopInfos)
-		if err := UpdateNodeJobRunStatus(tx, store, proj, njr, sdk.StatusStopped); err != nil {
+		if err := UpdateNodeJobRunStatus(tx, store, events, proj, njr, sdk.StatusStopped); err != nil {
 			return sdk.WrapError(err, "StopWorkflowNodeRun> Cannot update node job run")
 		}
 	}
@@ -347,6 +376,19 @@ func StopWorkflowNodeRun(db *gorp.DbMap, store cache.Store, proj *sdk.Project, n
 	if err := tx.Commit(); err != nil {
 		return sdk.WrapError(err, "StopWorkflowNodeRun> Cannot commit transaction")
 	}
+	events.Publish()
+
+	// Without any job run, the event has not been sent by UpdateNodeJobRunStatus
+	if len(ids) == 0 {
+		nodeRun.Status = sdk.StatusStopped.String()
+		nodeRun.Done = time.Now()
+		wr, errW := LoadRunByID(db, nodeRun.WorkflowRunID)
+		if errW != nil {
+			log.Warning("StopWorkflowNodeRun> Unable to load workflow run %d: %v", nodeRun.WorkflowRunID, errW)
+		}
+		event.PublishWorkflowNodeRun(wr, &nodeRun)
+		sendNodeRunNotifications(store, wr, &nodeRun)
+	}
 
 	return nil
 }
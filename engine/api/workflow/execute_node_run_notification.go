@@ -0,0 +1,98 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/notification"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	nodeRunNotificationsQueue       = "workflow_notifications"
+	nodeRunNotificationsMaxAttempts = 10
+)
+
+// nodeRunNotification is a node run whose notifications have to be sent once its status is committed
+type nodeRunNotification struct {
+	NodeRunID int64  `json:"node_run_id"`
+	Status    string `json:"status"`
+	Attempts  int    `json:"attempts"`
+}
+
+// sendNodeRunNotifications enqueues the notifications of the node of a node run which is over.
+// They are sent by sendNodeRunNotificationsRoutine, out of the transaction of the caller
+func sendNodeRunNotifications(store cache.Store, wr *sdk.WorkflowRun, n *sdk.WorkflowNodeRun) {
+	if wr == nil || !sdk.StatusIsTerminated(n.Status) {
+		return
+	}
+	node := wr.Workflow.GetNode(n.WorkflowNodeID)
+	if node == nil || len(node.Notifications) == 0 {
+		return
+	}
+	store.Enqueue(nodeRunNotificationsQueue, nodeRunNotification{NodeRunID: n.ID, Status: n.Status})
+}
+
+// sendNodeRunNotificationsRoutine sends the enqueued notifications. A node run is notified only once its status
+// has been committed, so nothing is sent for a status which has been rolled back
+func sendNodeRunNotificationsRoutine(c context.Context, store cache.Store, DBFunc func() *gorp.DbMap) {
+	for {
+		n := nodeRunNotification{}
+		store.DequeueWithContext(c, nodeRunNotificationsQueue, &n)
+		if err := c.Err(); err != nil {
+			log.Error("Exiting workflow.sendNodeRunNotificationsRoutine: %v", err)
+			return
+		}
+
+		db := DBFunc()
+		if db == nil {
+			retryNodeRunNotification(store, n)
+			continue
+		}
+
+		nr, err := LoadNodeRunByID(db, n.NodeRunID)
+		if err != nil {
+			log.Warning("sendNodeRunNotificationsRoutine> Unable to load node run %d: %v", n.NodeRunID, err)
+			retryNodeRunNotification(store, n)
+			continue
+		}
+		// The transaction which has updated the node run is not committed yet
+		if nr.Status != n.Status {
+			retryNodeRunNotification(store, n)
+			continue
+		}
+
+		wr, err := LoadRunByID(db, nr.WorkflowRunID)
+		if err != nil {
+			log.Warning("sendNodeRunNotificationsRoutine> Unable to load workflow run %d: %v", nr.WorkflowRunID, err)
+			retryNodeRunNotification(store, n)
+			continue
+		}
+		node := wr.Workflow.GetNode(nr.WorkflowNodeID)
+		if node == nil {
+			continue
+		}
+
+		previousStatus, err := loadPreviousNodeRunStatus(db, nr)
+		if err != nil {
+			log.Warning("sendNodeRunNotificationsRoutine> %v", err)
+		}
+		notification.SendWorkflowNodeRunNotifications(node, nr, previousStatus)
+	}
+}
+
+// retryNodeRunNotification enqueues the notification again after a delay growing with the attempts
+func retryNodeRunNotification(store cache.Store, n nodeRunNotification) {
+	n.Attempts++
+	if n.Attempts > nodeRunNotificationsMaxAttempts {
+		log.Warning("sendNodeRunNotificationsRoutine> Aborting notifications of node run %d at status %s", n.NodeRunID, n.Status)
+		return
+	}
+	time.AfterFunc(time.Duration(n.Attempts)*time.Second, func() {
+		store.Enqueue(nodeRunNotificationsQueue, n)
+	})
+}
@@ -36,12 +36,18 @@ type JobRun sdk.WorkflowNodeJobRun
 // NodeRunArtifact is a gorp wrapper around sdk.WorkflowNodeRunArtifact
 type NodeRunArtifact sdk.WorkflowNodeRunArtifact
 
+// NodeRunTestCase is a gorp wrapper around sdk.WorkflowNodeRunTestCase
+type NodeRunTestCase sdk.WorkflowNodeRunTestCase
+
 // RunTag is a gorp wrapper around sdk.WorkflowRunTag
 type RunTag sdk.WorkflowRunTag
 
 // NodeHook is a gorp wrapper around sdk.WorkflowNodeHook
 type NodeHook sdk.WorkflowNodeHook
 
+// NodeNotification is a gorp wrapper around sdk.WorkflowNodeNotification
+type NodeNotification sdk.WorkflowNodeNotification
+
 // NodeHookModel is a gorp wrapper around sdk.WorkflowHookModel
 type NodeHookModel sdk.WorkflowHookModel
 
@@ -52,6 +58,7 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(NodeContext{}, "workflow_node_context", true, "id"))
 	gorpmapping.Register(gorpmapping.New(sqlContext{}, "workflow_node_context", true, "id"))
 	gorpmapping.Register(gorpmapping.New(NodeHook{}, "workflow_node_hook", true, "id"))
+	gorpmapping.Register(gorpmapping.New(NodeNotification{}, "workflow_node_notification", true, "id"))
 	gorpmapping.Register(gorpmapping.New(Join{}, "workflow_node_join", true, "id"))
 	gorpmapping.Register(gorpmapping.New(JoinTrigger{}, "workflow_node_join_trigger", true, "id"))
 	gorpmapping.Register(gorpmapping.New(Run{}, "workflow_run", true, "id"))
@@ -59,6 +66,7 @@ func init() {
 	gorpmapping.Register(gorpmapping.New(sqlNodeRun{}, "workflow_node_run", true, "id"))
 	gorpmapping.Register(gorpmapping.New(JobRun{}, "workflow_node_run_job", true, "id"))
 	gorpmapping.Register(gorpmapping.New(NodeRunArtifact{}, "workflow_node_run_artifacts", true, "id"))
+	gorpmapping.Register(gorpmapping.New(NodeRunTestCase{}, "workflow_node_run_testcase", true, "id"))
 	gorpmapping.Register(gorpmapping.New(RunTag{}, "workflow_run_tag", false, "workflow_run_id", "tag"))
 	gorpmapping.Register(gorpmapping.New(NodeHookModel{}, "workflow_hook_model", true, "id"))
 }
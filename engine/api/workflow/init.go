@@ -16,6 +16,8 @@ func Initialize(c context.Context, store cache.Store, DBFunc func() *gorp.DbMap)
 	rand.Seed(time.Now().Unix())
 	tickPurge := time.NewTicker(1 * time.Hour)
 
+	go sendNodeRunNotificationsRoutine(c, store, DBFunc)
+
 	for {
 		time.Sleep(time.Duration(rand.Intn(500)) * time.Millisecond)
 
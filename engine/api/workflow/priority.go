@@ -0,0 +1,82 @@
+package workflow
+
+import (
+	"sort"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// nodeRunPriority computes the priority of the jobs of a node run. The priority of a manual run overrides the
+// priority of the node, then the priority of the pipeline. A node run is never less urgent than its sources
+func nodeRunPriority(n *sdk.WorkflowNode, m *sdk.WorkflowNodeRunManual, sources []sdk.WorkflowNodeRun) int64 {
+	if m != nil && m.Priority != nil {
+		return *m.Priority
+	}
+
+	priority := n.Pipeline.Priority
+	if n.Context != nil && n.Context.Priority != 0 {
+		priority = n.Context.Priority
+	}
+	for _, r := range sources {
+		if r.Priority > priority {
+			priority = r.Priority
+		}
+	}
+	return priority
+}
+
+// countBuildingJobsByProject returns the number of jobs being built by project
+func countBuildingJobsByProject(db gorp.SqlExecutor) (map[int64]int, error) {
+	rows, err := db.Query("select project_id, count(id) from workflow_node_run_job where status = $1 group by project_id", sdk.StatusBuilding.String())
+	if err != nil {
+		return nil, sdk.WrapError(err, "countBuildingJobsByProject> Unable to count jobs")
+	}
+	defer rows.Close()
+
+	res := map[int64]int{}
+	for rows.Next() {
+		var projectID int64
+		var n int
+		if err := rows.Scan(&projectID, &n); err != nil {
+			return nil, sdk.WrapError(err, "countBuildingJobsByProject> Unable to scan")
+		}
+		res[projectID] = n
+	}
+	return res, nil
+}
+
+// sortQueue sorts the jobs of the queue: the highest priority first, then the jobs of a same priority are shared
+// between the projects. The jobs of a project are ranked from its number of jobs being built, so a project with a
+// lot of running or waiting jobs does not block the other projects. The oldest job comes first for a same rank.
+func sortQueue(jobs []sdk.WorkflowNodeJobRun, building map[int64]int) {
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if jobs[i].Priority != jobs[j].Priority {
+			return jobs[i].Priority > jobs[j].Priority
+		}
+		if !jobs[i].Queued.Equal(jobs[j].Queued) {
+			return jobs[i].Queued.Before(jobs[j].Queued)
+		}
+		return jobs[i].ID < jobs[j].ID
+	})
+
+	type projectPriority struct {
+		projectID int64
+		priority  int64
+	}
+	ranks := make(map[int64]int, len(jobs))
+	waiting := map[projectPriority]int{}
+	for _, j := range jobs {
+		k := projectPriority{j.ProjectID, j.Priority}
+		ranks[j.ID] = building[j.ProjectID] + waiting[k]
+		waiting[k]++
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		if jobs[i].Priority != jobs[j].Priority {
+			return jobs[i].Priority > jobs[j].Priority
+		}
+		return ranks[jobs[i].ID] < ranks[jobs[j].ID]
+	})
+}
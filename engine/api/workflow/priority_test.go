@@ -0,0 +1,62 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_nodeRunPriority(t *testing.T) {
+	n := &sdk.WorkflowNode{
+		Pipeline: sdk.Pipeline{Priority: 1},
+		Context:  &sdk.WorkflowNodeContext{},
+	}
+	assert.Equal(t, int64(1), nodeRunPriority(n, nil, nil))
+
+	n.Context.Priority = 5
+	assert.Equal(t, int64(5), nodeRunPriority(n, nil, nil))
+
+	// A node run is never less urgent than its sources
+	sources := []sdk.WorkflowNodeRun{{Priority: 3}, {Priority: 10}}
+	assert.Equal(t, int64(10), nodeRunPriority(n, nil, sources))
+
+	// The priority of a manual run overrides all the others
+	priority := int64(-5)
+	assert.Equal(t, int64(-5), nodeRunPriority(n, &sdk.WorkflowNodeRunManual{Priority: &priority}, sources))
+	assert.Equal(t, int64(10), nodeRunPriority(n, &sdk.WorkflowNodeRunManual{}, sources))
+}
+
+func Test_sortQueue(t *testing.T) {
+	now := time.Now()
+	jobs := []sdk.WorkflowNodeJobRun{}
+	// A nightly batch of project 1
+	for i := 1; i <= 5; i++ {
+		jobs = append(jobs, sdk.WorkflowNodeJobRun{ID: int64(i), ProjectID: 1, Queued: now.Add(time.Duration(i) * time.Second)})
+	}
+	// Then jobs of project 2 and 3
+	jobs = append(jobs,
+		sdk.WorkflowNodeJobRun{ID: 6, ProjectID: 2, Queued: now.Add(6 * time.Second)},
+		sdk.WorkflowNodeJobRun{ID: 7, ProjectID: 2, Queued: now.Add(7 * time.Second)},
+		sdk.WorkflowNodeJobRun{ID: 8, ProjectID: 3, Queued: now.Add(8 * time.Second)},
+		// An urgent deployment
+		sdk.WorkflowNodeJobRun{ID: 9, ProjectID: 3, Queued: now.Add(9 * time.Second), Priority: 10},
+	)
+
+	ids := func(jobs []sdk.WorkflowNodeJobRun) []int64 {
+		res := make([]int64, len(jobs))
+		for i := range jobs {
+			res[i] = jobs[i].ID
+		}
+		return res
+	}
+
+	sortQueue(jobs, nil)
+	assert.Equal(t, []int64{9, 1, 6, 8, 2, 7, 3, 4, 5}, ids(jobs))
+
+	// The jobs being built are taken into account
+	sortQueue(jobs, map[int64]int{1: 2, 2: 1})
+	assert.Equal(t, []int64{9, 8, 6, 1, 7, 2, 3, 4, 5}, ids(jobs))
+}
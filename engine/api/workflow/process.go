@@ -15,9 +15,10 @@ import (
 
 // processWorkflowRun triggers workflow node for every workflow.
 // It contains all the logic for triggers and joins processing.
-func processWorkflowRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, w *sdk.WorkflowRun, hookEvent *sdk.WorkflowNodeRunHookEvent, manual *sdk.WorkflowNodeRunManual, startingFromNode *int64) error {
+func processWorkflowRun(db gorp.SqlExecutor, store cache.Store, events *RunEvents, p *sdk.Project, w *sdk.WorkflowRun, hookEvent *sdk.WorkflowNodeRunHookEvent, manual *sdk.WorkflowNodeRunManual, startingFromNode *int64) error {
 	var nodesRunFailed, nodesRunStopped, nodesRunBuilding, nodesRunSuccess int
 	t0 := time.Now()
+	previousStatus := w.Status
 	w.Status = string(sdk.StatusBuilding)
 	log.Debug("processWorkflowRun> Begin [#%d]%s", w.Number, w.Workflow.Name)
 	defer func() {
@@ -53,9 +54,12 @@ func processWorkflowRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Project,
 				return sdk.ErrWorkflowNodeParentNotRun
 			}
 		}
-		if err := processWorkflowNodeRun(db, store, p, w, start, int(nextSubNumber), sourceNodesRunID, hookEvent, manual); err != nil {
+		if err := processWorkflowNodeRun(db, store, events, p, w, start, int(nextSubNumber), sourceNodesRunID, hookEvent, manual); err != nil {
 			return sdk.WrapError(err, "processWorkflowRun> Unable to process workflow node run")
 		}
+		if w.Status != previousStatus {
+			events.workflowRun(w)
+		}
 		return nil
 	}
 
@@ -71,7 +75,7 @@ func processWorkflowRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Project,
 			},
 		})
 
-		if err := processWorkflowNodeRun(db, store, p, w, w.Workflow.Root, 0, nil, hookEvent, manual); err != nil {
+		if err := processWorkflowNodeRun(db, store, events, p, w, w.Workflow.Root, 0, nil, hookEvent, manual); err != nil {
 			return sdk.WrapError(err, "processWorkflowRun> Unable to process workflow node run")
 		}
 		return nil
@@ -156,7 +160,7 @@ func processWorkflowRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Project,
 
 					if !abortTrigger {
 						//Keep the subnumber of the previous node in the graph
-						if err := processWorkflowNodeRun(db, store, p, w, &t.WorkflowDestNode, int(nodeRun.SubNumber), []int64{nodeRun.ID}, nil, nil); err != nil {
+						if err := processWorkflowNodeRun(db, store, events, p, w, &t.WorkflowDestNode, int(nodeRun.SubNumber), []int64{nodeRun.ID}, nil, nil); err != nil {
 							log.Error("processWorkflowRun> Unable to process node ID=%d: %s", t.WorkflowDestNode.ID, err)
 							AddWorkflowRunInfo(w, true, sdk.SpawnMsg{
 								ID:   sdk.MsgWorkflowError.ID,
@@ -284,7 +288,7 @@ func processWorkflowRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Project,
 
 				if !abortTrigger {
 					//Keep the subnumber of the previous node in the graph
-					if err := processWorkflowNodeRun(db, store, p, w, &t.WorkflowDestNode, int(maxsn), nodeRunIDs, nil, nil); err != nil {
+					if err := processWorkflowNodeRun(db, store, events, p, w, &t.WorkflowDestNode, int(maxsn), nodeRunIDs, nil, nil); err != nil {
 						AddWorkflowRunInfo(w, true, sdk.SpawnMsg{
 							ID:   sdk.MsgWorkflowError.ID,
 							Args: []interface{}{err},
@@ -302,12 +306,15 @@ func processWorkflowRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Project,
 	if err := updateWorkflowRun(db, w); err != nil {
 		return sdk.WrapError(err, "processWorkflowRun>")
 	}
+	if w.Status != previousStatus {
+		events.workflowRun(w)
+	}
 
 	return nil
 }
 
 //processWorkflowNodeRun triggers execution of a node run
-func processWorkflowNodeRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Project, w *sdk.WorkflowRun, n *sdk.WorkflowNode, subnumber int, sourceNodeRuns []int64, h *sdk.WorkflowNodeRunHookEvent, m *sdk.WorkflowNodeRunManual) error {
+func processWorkflowNodeRun(db gorp.SqlExecutor, store cache.Store, events *RunEvents, p *sdk.Project, w *sdk.WorkflowRun, n *sdk.WorkflowNode, subnumber int, sourceNodeRuns []int64, h *sdk.WorkflowNodeRunHookEvent, m *sdk.WorkflowNodeRunManual) error {
 	t0 := time.Now()
 	log.Debug("processWorkflowNodeRun> Begin [#%d.%d]%s.%d", w.Number, subnumber, w.Workflow.Name, n.ID)
 	defer func() {
@@ -330,9 +337,9 @@ func processWorkflowNodeRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Proje
 	}
 
 	run.SourceNodeRuns = sourceNodeRuns
+	//Get all the nodeRun from the sources
+	runs := []sdk.WorkflowNodeRun{}
 	if sourceNodeRuns != nil {
-		//Get all the nodeRun from the sources
-		runs := []sdk.WorkflowNodeRun{}
 		for _, id := range sourceNodeRuns {
 			for _, v := range w.WorkflowNodeRuns {
 				for _, run := range v {
@@ -394,6 +401,8 @@ func processWorkflowNodeRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Proje
 		})
 	}
 
+	run.Priority = nodeRunPriority(n, m, runs)
+
 	// Process parameters for the jobs
 	jobParams, errParam := getNodeRunBuildParameters(db, p, run)
 	if errParam != nil {
@@ -486,6 +495,7 @@ func processWorkflowNodeRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Proje
 	if err := insertWorkflowNodeRun(db, run); err != nil {
 		return sdk.WrapError(err, "processWorkflowNodeRun> unable to insert run")
 	}
+	events.nodeRun(w, run)
 
 	//Update workflow run
 	if w.WorkflowNodeRuns == nil {
@@ -498,7 +508,7 @@ func processWorkflowNodeRun(db gorp.SqlExecutor, store cache.Store, p *sdk.Proje
 	}
 
 	//Execute the node run !
-	if err := execute(db, store, p, run); err != nil {
+	if err := execute(db, store, events, p, run); err != nil {
 		return sdk.WrapError(err, "processWorkflowNodeRun> unable to execute workflow run")
 	}
 
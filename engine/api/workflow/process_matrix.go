@@ -0,0 +1,23 @@
+package workflow
+
+import (
+	"github.com/ovh/cds/sdk"
+)
+
+// expandJobMatrix returns the job runs of a job: one for each combination of its matrix,
+// or the job itself if it has no matrix
+func expandJobMatrix(job sdk.Job) []sdk.ExecutedJob {
+	combinations := job.Matrix.Combinations()
+	if len(combinations) == 0 {
+		return []sdk.ExecutedJob{{Job: job}}
+	}
+
+	jobs := make([]sdk.ExecutedJob, len(combinations))
+	for i, c := range combinations {
+		jobs[i] = sdk.ExecutedJob{
+			Job:          job,
+			MatrixValues: c,
+		}
+	}
+	return jobs
+}
@@ -0,0 +1,52 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_expandJobMatrix(t *testing.T) {
+	job := sdk.Job{Action: sdk.Action{Name: "build"}}
+	jobs := expandJobMatrix(job)
+	assert.Len(t, jobs, 1)
+	assert.Nil(t, jobs[0].MatrixValues)
+
+	job.Matrix = sdk.JobMatrix{
+		"go": {"1.8", "1.9"},
+		"os": {"linux", "windows", "darwin"},
+	}
+	jobs = expandJobMatrix(job)
+	assert.Len(t, jobs, 6)
+	assert.Equal(t, map[string]string{"go": "1.8", "os": "linux"}, jobs[0].MatrixValues)
+	assert.Equal(t, map[string]string{"go": "1.9", "os": "darwin"}, jobs[5].MatrixValues)
+	for _, j := range jobs {
+		assert.Equal(t, "build", j.Action.Name)
+	}
+}
+
+func Test_getNodeJobRunRequirementsWithMatrix(t *testing.T) {
+	job := sdk.Job{
+		Action: sdk.Action{
+			Name:         "build",
+			Requirements: []sdk.Requirement{{Name: "model", Type: sdk.ModelRequirement, Value: "golang-{{.cds.matrix.go}}"}},
+		},
+		Matrix: sdk.JobMatrix{"go": {"1.8", "1.9"}},
+	}
+	run := &sdk.WorkflowNodeRun{BuildParameters: []sdk.Parameter{{Name: "cds.version", Type: sdk.StringParameter, Value: "1"}}}
+	stage := &sdk.Stage{Name: "Build"}
+
+	var models []string
+	for _, ejob := range expandJobMatrix(job) {
+		params, errP := getNodeJobRunParameters(nil, ejob, run, stage)
+		assert.Nil(t, errP)
+		req, errR := getNodeJobRunRequirements(nil, ejob.Job, params)
+		assert.Nil(t, errR)
+		models = append(models, req[0].Value)
+	}
+	assert.Equal(t, []string{"golang-1.8", "golang-1.9"}, models)
+	// The build parameters of the run are not modified
+	assert.Len(t, run.BuildParameters, 1)
+}
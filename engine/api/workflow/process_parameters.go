@@ -11,12 +11,17 @@ import (
 	"github.com/ovh/cds/sdk/log"
 )
 
-func getNodeJobRunParameters(db gorp.SqlExecutor, j sdk.Job, run *sdk.WorkflowNodeRun, stage *sdk.Stage) ([]sdk.Parameter, *sdk.MultiError) {
-	params := run.BuildParameters
+func getNodeJobRunParameters(db gorp.SqlExecutor, j sdk.ExecutedJob, run *sdk.WorkflowNodeRun, stage *sdk.Stage) ([]sdk.Parameter, *sdk.MultiError) {
+	// Copy the build parameters, the parameters of each job run are appended to them
+	params := make([]sdk.Parameter, len(run.BuildParameters))
+	copy(params, run.BuildParameters)
 	tmp := map[string]string{}
 
 	tmp["cds.stage"] = stage.Name
 	tmp["cds.job"] = j.Action.Name
+	for k, v := range j.MatrixValues {
+		tmp[sdk.JobMatrixVariablePrefix+k] = v
+	}
 	errm := &sdk.MultiError{}
 
 	for k, v := range tmp {
@@ -6,12 +6,12 @@ import (
 	"github.com/ovh/cds/sdk"
 )
 
-func getNodeJobRunRequirements(db gorp.SqlExecutor, j sdk.Job, run *sdk.WorkflowNodeRun) ([]sdk.Requirement, *sdk.MultiError) {
+func getNodeJobRunRequirements(db gorp.SqlExecutor, j sdk.Job, params []sdk.Parameter) ([]sdk.Requirement, *sdk.MultiError) {
 	requirements := []sdk.Requirement{}
 	tmp := map[string]string{}
 	errm := &sdk.MultiError{}
 
-	for _, v := range run.BuildParameters {
+	for _, v := range params {
 		tmp[v.Name] = v.Value
 	}
 
@@ -0,0 +1,178 @@
+package workflow
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// WorkerLostRetry is the minimum number of times a job run is requeued when its worker vanished while building it
+var WorkerLostRetry = 2
+
+// SpawnErrorRetry is the number of workers which can fail to spawn for a job run before it fails
+var SpawnErrorRetry = 5
+
+// spawnErrorRetryDelay is the delay in seconds before a job run can be booked again after a spawn error, doubled at each spawn error
+const spawnErrorRetryDelay = 10
+
+// maxNodeJobRunRetry returns the number of times a job run can be requeued. The jobs of lost workers
+// are requeued even if the job has no retry policy
+func maxNodeJobRunRetry(job *sdk.WorkflowNodeJobRun, workerLost bool) int {
+	max := job.Job.Retry
+	if workerLost && max < WorkerLostRetry {
+		max = WorkerLostRetry
+	}
+	return max
+}
+
+// canRetryNodeJobRun returns true if a job run can be requeued for a new attempt
+func canRetryNodeJobRun(job *sdk.WorkflowNodeJobRun, workerLost bool) bool {
+	return job.Retry < maxNodeJobRunRetry(job, workerLost)
+}
+
+// requeueNodeJobRun sets a job run back to waiting for a new attempt, which will start after the retry
+// delay of the job. The attempt is recorded in the spawn infos and the logs of the job run
+func requeueNodeJobRun(db gorp.SqlExecutor, store cache.Store, events *RunEvents, p *sdk.Project, job *sdk.WorkflowNodeJobRun, workerLost bool) error {
+	// Reload the job run to keep the spawn infos added in the same transaction
+	j, errLoad := LoadNodeJobRun(db, store, job.ID)
+	if errLoad != nil {
+		return sdk.WrapError(errLoad, "requeueNodeJobRun> Unable to load node job run %d", job.ID)
+	}
+
+	maxAttempts := maxNodeJobRunRetry(j, workerLost) + 1
+	workerName := j.Job.WorkerName
+	j.Retry++
+	delay := sdk.RetryDelay(j.Job.RetryDelay, j.Retry)
+
+	msg := sdk.MsgSpawnInfoJobRetry
+	if workerLost {
+		msg = sdk.MsgSpawnInfoJobRequeueWorkerLost
+	}
+	if err := prepareSpawnInfos(j, []sdk.SpawnInfo{{
+		RemoteTime: time.Now(),
+		Message:    sdk.SpawnMsg{ID: msg.ID, Args: []interface{}{workerName, fmt.Sprintf("%d", j.Retry+1), fmt.Sprintf("%d", maxAttempts), delay.String()}},
+	}}); err != nil {
+		return sdk.WrapError(err, "requeueNodeJobRun> Cannot prepare spawn infos")
+	}
+
+	l := sdk.NewLog(j.ID, fmt.Sprintf("Attempt %d/%d on worker %s failed, attempt %d/%d will start in %s\n", j.Retry, maxAttempts, workerName, j.Retry+1, maxAttempts, delay), j.WorkflowNodeRunID, 0)
+	if err := AddLog(db, j, l); err != nil {
+		return sdk.WrapError(err, "requeueNodeJobRun> Cannot add log")
+	}
+
+	j.Status = sdk.StatusWaiting.String()
+	j.Start = time.Time{}
+	j.Model = ""
+	j.RetryAfter = time.Now().Add(delay)
+	j.Job.WorkerName = ""
+	j.Job.WorkerID = ""
+	j.Job.StepStatus = nil
+	store.Delete(keyBookJob(j.ID))
+
+	log.Info("requeueNodeJobRun> job %d requeued, attempt %d/%d in %s", j.ID, j.Retry+1, maxAttempts, delay)
+	if err := UpdateNodeJobRun(db, store, events, p, j); err != nil {
+		return sdk.WrapError(err, "requeueNodeJobRun> Cannot update node job run %d", j.ID)
+	}
+
+	node, errN := LoadNodeRunByID(db, j.WorkflowNodeRunID)
+	if errN != nil {
+		return sdk.WrapError(errN, "requeueNodeJobRun> Unable to load node run %d", j.WorkflowNodeRunID)
+	}
+	events.jobRun(node, j)
+
+	*job = *j
+	return nil
+}
+
+// RequeueNodeJobRunOfLostWorker requeues the job run built by a worker which vanished, or fails it if it can't
+// be retried anymore. It returns false if the job run is not built by this worker
+func RequeueNodeJobRunOfLostWorker(db gorp.SqlExecutor, store cache.Store, events *RunEvents, p *sdk.Project, id int64, workerID string) (bool, error) {
+	job, errLoad := LoadAndLockNodeJobRunWait(db, store, id)
+	if errLoad == sql.ErrNoRows {
+		return false, nil
+	}
+	if errLoad != nil {
+		return false, sdk.WrapError(errLoad, "RequeueNodeJobRunOfLostWorker> Unable to load node job run %d", id)
+	}
+
+	if job.Status != sdk.StatusBuilding.String() || job.Job.WorkerID != workerID {
+		return false, nil
+	}
+
+	if canRetryNodeJobRun(job, true) {
+		return true, requeueNodeJobRun(db, store, events, p, job, true)
+	}
+
+	if err := prepareSpawnInfos(job, []sdk.SpawnInfo{{
+		RemoteTime: time.Now(),
+		Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobWorkerLost.ID, Args: []interface{}{job.Job.WorkerName}},
+	}}); err != nil {
+		return true, sdk.WrapError(err, "RequeueNodeJobRunOfLostWorker> Cannot prepare spawn infos")
+	}
+	if err := UpdateNodeJobRunStatus(db, store, events, p, job, sdk.StatusFail); err != nil {
+		return true, sdk.WrapError(err, "RequeueNodeJobRunOfLostWorker> Cannot fail node job run %d", id)
+	}
+	return true, nil
+}
+
+// hasSpawnError returns true if the spawn infos sent by a hatchery report that a worker could not be spawned
+func hasSpawnError(infos []sdk.SpawnInfo) bool {
+	for _, info := range infos {
+		if info.Message.ID == sdk.MsgSpawnInfoHatcheryErrorSpawn.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// countSpawnErrors returns the number of workers which could not be spawned for a job run
+func countSpawnErrors(job *sdk.WorkflowNodeJobRun) int {
+	var n int
+	for _, info := range job.SpawnInfos {
+		if info.Message.ID == sdk.MsgSpawnInfoHatcheryErrorSpawn.ID {
+			n++
+		}
+	}
+	return n
+}
+
+// spawnErrorNodeJobRun releases the booking of a waiting job run whose worker could not be spawned. The job run can be booked
+// again after a delay which doubles at each spawn error, it fails after SpawnErrorRetry spawn errors
+func spawnErrorNodeJobRun(db gorp.SqlExecutor, store cache.Store, events *RunEvents, p *sdk.Project, job *sdk.WorkflowNodeJobRun) error {
+	store.Delete(keyBookJob(job.ID))
+
+	spawnErrors := countSpawnErrors(job)
+	if spawnErrors >= SpawnErrorRetry {
+		if err := prepareSpawnInfos(job, []sdk.SpawnInfo{{
+			RemoteTime: time.Now(),
+			Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobSpawnError.ID, Args: []interface{}{fmt.Sprintf("%d", spawnErrors)}},
+		}}); err != nil {
+			return sdk.WrapError(err, "spawnErrorNodeJobRun> Cannot prepare spawn infos")
+		}
+		if err := UpdateNodeJobRunStatus(db, store, events, p, job, sdk.StatusFail); err != nil {
+			return sdk.WrapError(err, "spawnErrorNodeJobRun> Cannot fail node job run %d", job.ID)
+		}
+		return nil
+	}
+
+	delay := sdk.RetryDelay(spawnErrorRetryDelay, spawnErrors)
+	if err := prepareSpawnInfos(job, []sdk.SpawnInfo{{
+		RemoteTime: time.Now(),
+		Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobRequeueSpawnError.ID, Args: []interface{}{fmt.Sprintf("%d", spawnErrors), fmt.Sprintf("%d", SpawnErrorRetry), delay.String()}},
+	}}); err != nil {
+		return sdk.WrapError(err, "spawnErrorNodeJobRun> Cannot prepare spawn infos")
+	}
+	job.RetryAfter = time.Now().Add(delay)
+
+	log.Info("spawnErrorNodeJobRun> job %d released after %d spawn errors, it can be booked again in %s", job.ID, spawnErrors, delay)
+	if err := UpdateNodeJobRun(db, store, events, p, job); err != nil {
+		return sdk.WrapError(err, "spawnErrorNodeJobRun> Cannot update node job run %d", job.ID)
+	}
+	return nil
+}
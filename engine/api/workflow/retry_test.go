@@ -0,0 +1,38 @@
+package workflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_canRetryNodeJobRun(t *testing.T) {
+	job := &sdk.WorkflowNodeJobRun{}
+	assert.False(t, canRetryNodeJobRun(job, false))
+	// The job of a lost worker is requeued even without retry policy
+	assert.True(t, canRetryNodeJobRun(job, true))
+
+	job.Retry = WorkerLostRetry
+	assert.False(t, canRetryNodeJobRun(job, true))
+
+	job.Job.Retry = 3
+	assert.True(t, canRetryNodeJobRun(job, false))
+	assert.True(t, canRetryNodeJobRun(job, true))
+
+	job.Retry = 3
+	assert.False(t, canRetryNodeJobRun(job, false))
+	assert.False(t, canRetryNodeJobRun(job, true))
+}
+
+func Test_countSpawnErrors(t *testing.T) {
+	spawnError := sdk.SpawnInfo{Message: sdk.SpawnMsg{ID: sdk.MsgSpawnInfoHatcheryErrorSpawn.ID}}
+	starts := sdk.SpawnInfo{Message: sdk.SpawnMsg{ID: sdk.MsgSpawnInfoHatcheryStarts.ID}}
+
+	assert.False(t, hasSpawnError([]sdk.SpawnInfo{starts}))
+	assert.True(t, hasSpawnError([]sdk.SpawnInfo{starts, spawnError}))
+
+	job := &sdk.WorkflowNodeJobRun{SpawnInfos: []sdk.SpawnInfo{starts, spawnError, starts, spawnError}}
+	assert.Equal(t, 2, countSpawnErrors(job))
+}
@@ -0,0 +1,66 @@
+package workflow
+
+import (
+	"sort"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// runLogsNodeRuns returns the node runs of a workflow run in the order they were started
+func runLogsNodeRuns(wr *sdk.WorkflowRun) []sdk.WorkflowNodeRun {
+	var nodeRuns []sdk.WorkflowNodeRun
+	for _, nrs := range wr.WorkflowNodeRuns {
+		nodeRuns = append(nodeRuns, nrs...)
+	}
+	sort.Slice(nodeRuns, func(i, j int) bool {
+		return nodeRuns[i].ID < nodeRuns[j].ID
+	})
+	return nodeRuns
+}
+
+// LoadRunLogs loads the logs of all the started steps of a workflow run, in the order of the node runs, stages, jobs and steps.
+// The steps for which skip returns true are not loaded, skip may be nil
+func LoadRunLogs(db gorp.SqlExecutor, wr *sdk.WorkflowRun, skip func(sdk.WorkflowRunLog) bool) ([]sdk.WorkflowRunLog, error) {
+	var logs []sdk.WorkflowRunLog
+	for _, nr := range runLogsNodeRuns(wr) {
+		var nodeName string
+		if n := wr.Workflow.GetNode(nr.WorkflowNodeID); n != nil {
+			nodeName = n.Name
+		}
+
+		for _, s := range nr.Stages {
+			for _, rj := range s.RunJobs {
+				for _, ss := range rj.Job.StepStatus {
+					l := sdk.WorkflowRunLog{
+						NodeName:  nodeName,
+						SubNumber: nr.SubNumber,
+						StageName: s.Name,
+						JobID:     rj.ID,
+						JobName:   rj.Job.Action.Name,
+						Retry:     rj.Retry,
+						StepOrder: ss.StepOrder,
+						Status:    ss.Status,
+					}
+					if ss.StepOrder < len(rj.Job.Action.Actions) {
+						l.StepName = rj.Job.Action.Actions[ss.StepOrder].Name
+					}
+					if skip != nil && skip(l) {
+						continue
+					}
+
+					stepLogs, err := LoadStepLogs(db, rj.ID, int64(ss.StepOrder))
+					if err != nil {
+						return nil, sdk.WrapError(err, "LoadRunLogs> Cannot load logs of step %d of job %d", ss.StepOrder, rj.ID)
+					}
+					if stepLogs != nil {
+						l.Value = stepLogs.Val
+					}
+					logs = append(logs, l)
+				}
+			}
+		}
+	}
+	return logs, nil
+}
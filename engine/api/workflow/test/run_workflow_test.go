@@ -90,7 +90,7 @@ func TestManualRun1(t *testing.T) {
 	w1, err := workflow.Load(db, cache, key, "test_1", u)
 	test.NoError(t, err)
 
-	_, err = workflow.ManualRun(db, cache, proj, w1, &sdk.WorkflowNodeRunManual{
+	_, err = workflow.ManualRun(db, cache, &workflow.RunEvents{}, proj, w1, &sdk.WorkflowNodeRunManual{
 		User: *u,
 		Payload: map[string]string{
 			"git.branch": "master",
@@ -98,7 +98,7 @@ func TestManualRun1(t *testing.T) {
 	})
 	test.NoError(t, err)
 
-	wr1, err := workflow.ManualRun(db, cache, proj, w1, &sdk.WorkflowNodeRunManual{User: *u})
+	wr1, err := workflow.ManualRun(db, cache, &workflow.RunEvents{}, proj, w1, &sdk.WorkflowNodeRunManual{User: *u})
 	test.NoError(t, err)
 
 	m1, _ := dump.ToMap(wr1)
@@ -151,7 +151,7 @@ func TestManualRun1(t *testing.T) {
 	}
 
 	//TestprocessWorkflowRun
-	wr2, err := workflow.ManualRunFromNode(db, cache, proj, w1, 2, &sdk.WorkflowNodeRunManual{User: *u}, w1.RootID)
+	wr2, err := workflow.ManualRunFromNode(db, cache, &workflow.RunEvents{}, proj, w1, 2, &sdk.WorkflowNodeRunManual{User: *u}, w1.RootID)
 	test.NoError(t, err)
 	assert.NotNil(t, wr2)
 
@@ -252,16 +252,16 @@ func TestManualRun2(t *testing.T) {
 	w1, err := workflow.Load(db, cache, key, "test_1", u)
 	test.NoError(t, err)
 
-	_, err = workflow.ManualRun(db, cache, proj, w1, &sdk.WorkflowNodeRunManual{
+	_, err = workflow.ManualRun(db, cache, &workflow.RunEvents{}, proj, w1, &sdk.WorkflowNodeRunManual{
 		User: *u,
 	})
 	test.NoError(t, err)
 
-	_, err = workflow.ManualRun(db, cache, proj, w1, &sdk.WorkflowNodeRunManual{User: *u})
+	_, err = workflow.ManualRun(db, cache, &workflow.RunEvents{}, proj, w1, &sdk.WorkflowNodeRunManual{User: *u})
 	test.NoError(t, err)
 
 	//TestprocessWorkflowRun
-	_, err = workflow.ManualRunFromNode(db, cache, proj, w1, 1, &sdk.WorkflowNodeRunManual{User: *u}, w1.RootID)
+	_, err = workflow.ManualRunFromNode(db, cache, &workflow.RunEvents{}, proj, w1, 1, &sdk.WorkflowNodeRunManual{User: *u}, w1.RootID)
 	test.NoError(t, err)
 
 	jobs, err := workflow.LoadNodeJobRunQueue(db, cache, []int64{proj.ProjectGroups[0].Group.ID}, nil)
@@ -346,7 +346,7 @@ func TestManualRun3(t *testing.T) {
 	w1, err := workflow.Load(db, cache, key, "test_1", u)
 	test.NoError(t, err)
 
-	workflow.ManualRun(db, cache, proj, w1, &sdk.WorkflowNodeRunManual{
+	workflow.ManualRun(db, cache, &workflow.RunEvents{}, proj, w1, &sdk.WorkflowNodeRunManual{
 		User: *u,
 	})
 	test.NoError(t, err)
@@ -370,7 +370,7 @@ func TestManualRun3(t *testing.T) {
 		}
 
 		//AddSpawnInfosNodeJobRun
-		j, err := workflow.AddSpawnInfosNodeJobRun(db, cache, proj, j.ID, []sdk.SpawnInfo{
+		j, err := workflow.AddSpawnInfosNodeJobRun(db, cache, &workflow.RunEvents{}, proj, j.ID, []sdk.SpawnInfo{
 			sdk.SpawnInfo{
 				APITime:    time.Now(),
 				RemoteTime: time.Now(),
@@ -386,7 +386,7 @@ func TestManualRun3(t *testing.T) {
 		}
 
 		//TakeNodeJobRun
-		j, err = workflow.TakeNodeJobRun(db, cache, proj, j.ID, "model", "worker", "1", []sdk.SpawnInfo{
+		j, err = workflow.TakeNodeJobRun(db, cache, &workflow.RunEvents{}, proj, j.ID, "model", "worker", "1", []sdk.SpawnInfo{
 			sdk.SpawnInfo{
 				APITime:    time.Now(),
 				RemoteTime: time.Now(),
@@ -432,7 +432,7 @@ func TestManualRun3(t *testing.T) {
 		}
 
 		//TestUpdateNodeJobRunStatus
-		assert.NoError(t, workflow.UpdateNodeJobRunStatus(db, cache, proj, j, sdk.StatusSuccess))
+		assert.NoError(t, workflow.UpdateNodeJobRunStatus(db, cache, &workflow.RunEvents{}, proj, j, sdk.StatusSuccess))
 		if t.Failed() {
 			tx.Rollback()
 			t.FailNow()
@@ -0,0 +1,76 @@
+package workflow
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// isNodeJobRunTimedOut returns true if a building job run lasts more than its timeout plus a grace delay,
+// which lets the worker stop the job and send its result
+func isNodeJobRunTimedOut(job *sdk.WorkflowNodeJobRun, grace time.Duration, now time.Time) bool {
+	if job.Status != sdk.StatusBuilding.String() || job.Job.Timeout <= 0 || job.Start.IsZero() {
+		return false
+	}
+	timeout := time.Duration(job.Job.Timeout)*time.Second + grace
+	return now.Sub(job.Start) > timeout
+}
+
+// LoadTimedOutNodeJobRunIDs loads the ids of the building job runs which last more than their timeout plus a grace delay
+func LoadTimedOutNodeJobRunIDs(db gorp.SqlExecutor, grace time.Duration) ([]int64, error) {
+	query := `select id from workflow_node_run_job
+	where status = $1
+	and coalesce((job->>'timeout')::int, 0) > 0
+	and start + (coalesce((job->>'timeout')::int, 0) + $2) * interval '1 second' < now()`
+	var ids []int64
+	if _, err := db.Select(&ids, query, sdk.StatusBuilding.String(), int64(grace.Seconds())); err != nil {
+		return nil, sdk.WrapError(err, "LoadTimedOutNodeJobRunIDs> Unable to load job runs")
+	}
+	return ids, nil
+}
+
+// FailTimedOutNodeJobRun fails a job run which lasts more than its timeout. The job run is requeued if it can be retried.
+// It returns false if the job run is not building or has not timed out anymore
+func FailTimedOutNodeJobRun(db gorp.SqlExecutor, store cache.Store, events *RunEvents, p *sdk.Project, id int64, grace time.Duration) (bool, error) {
+	job, errLoad := LoadAndLockNodeJobRunWait(db, store, id)
+	if errLoad == sql.ErrNoRows {
+		return false, nil
+	}
+	if errLoad != nil {
+		return false, sdk.WrapError(errLoad, "FailTimedOutNodeJobRun> Unable to load node job run %d", id)
+	}
+
+	if !isNodeJobRunTimedOut(job, grace, time.Now()) {
+		return false, nil
+	}
+
+	timeout := time.Duration(job.Job.Timeout) * time.Second
+	if err := prepareSpawnInfos(job, []sdk.SpawnInfo{{
+		RemoteTime: time.Now(),
+		Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoJobTimeout.ID, Args: []interface{}{timeout.String()}},
+	}}); err != nil {
+		return true, sdk.WrapError(err, "FailTimedOutNodeJobRun> Cannot prepare spawn infos")
+	}
+	// Save the spawn infos now, the job run is reloaded if it is requeued
+	dbj := JobRun(*job)
+	if _, err := db.Update(&dbj); err != nil {
+		return true, sdk.WrapError(err, "FailTimedOutNodeJobRun> Cannot update node job run %d", id)
+	}
+
+	l := sdk.NewLog(job.ID, fmt.Sprintf("Job exceeded its timeout of %s on worker %s and has been stopped\n", timeout, job.Job.WorkerName), job.WorkflowNodeRunID, 0)
+	if err := AddLog(db, job, l); err != nil {
+		return true, sdk.WrapError(err, "FailTimedOutNodeJobRun> Cannot add log")
+	}
+
+	log.Info("FailTimedOutNodeJobRun> job %d exceeded its timeout of %s", job.ID, timeout)
+	if err := UpdateNodeJobRunStatus(db, store, events, p, job, sdk.StatusFail); err != nil {
+		return true, sdk.WrapError(err, "FailTimedOutNodeJobRun> Cannot fail node job run %d", id)
+	}
+	return true, nil
+}
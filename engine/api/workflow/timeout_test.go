@@ -0,0 +1,25 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_isNodeJobRunTimedOut(t *testing.T) {
+	now := time.Now()
+	job := &sdk.WorkflowNodeJobRun{Status: sdk.StatusBuilding.String(), Start: now.Add(-10 * time.Minute)}
+	// No timeout
+	assert.False(t, isNodeJobRunTimedOut(job, time.Minute, now))
+
+	job.Job.Timeout = 300
+	assert.True(t, isNodeJobRunTimedOut(job, time.Minute, now))
+	// The worker still has time to stop the job
+	assert.False(t, isNodeJobRunTimedOut(job, 6*time.Minute, now))
+
+	job.Status = sdk.StatusFail.String()
+	assert.False(t, isNodeJobRunTimedOut(job, time.Minute, now))
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/repositoriesmanager"
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+func (api *API) getWorkflowRunCoverageHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		number, errNu := requestVarInt(r, "number")
+		if errNu != nil {
+			return sdk.WrapError(errNu, "getWorkflowRunCoverageHandler> Invalid number")
+		}
+
+		wr, errW := workflow.LoadRun(api.mustDB(), key, name, number)
+		if errW != nil {
+			return sdk.WrapError(errW, "getWorkflowRunCoverageHandler> Unable to load run %d", number)
+		}
+
+		srvs, errS := services.NewRepository(api.mustDB, api.Cache).FindByType("vcs")
+		if errS != nil {
+			return sdk.WrapError(errS, "getWorkflowRunCoverageHandler> Unable to load vcs services")
+		}
+
+		res := []sdk.WorkflowNodeRunCoverage{}
+		for nodeID, nodeRuns := range wr.WorkflowNodeRuns {
+			// node runs are sorted from the last sub number
+			var nr *sdk.WorkflowNodeRun
+			for i := range nodeRuns {
+				if nodeRuns[i].Coverage != nil {
+					nr = &nodeRuns[i]
+					break
+				}
+			}
+			if nr == nil {
+				continue
+			}
+
+			// without repository, the default branch is master
+			var client *repositoriesmanager.VCSServiceClient
+			var repo string
+			if node := wr.Workflow.GetNode(nodeID); node != nil {
+				var err error
+				client, repo, err = workflow.NewCoverageVCSClient(api.mustDB(), srvs, wr, node)
+				if err != nil {
+					log.Warning("getWorkflowRunCoverageHandler> Unable to get vcs client of node %d: %v", nodeID, err)
+				}
+			}
+
+			c, err := workflow.NodeRunCoverage(api.mustDB(), wr, nr, workflow.DefaultBranch(client, repo))
+			if err != nil {
+				return sdk.WrapError(err, "getWorkflowRunCoverageHandler> Unable to load coverage of node run %d", nr.ID)
+			}
+			res = append(res, *c)
+		}
+		sort.Slice(res, func(i, j int) bool { return res[i].NodeName < res[j].NodeName })
+
+		return WriteJSON(w, r, res, http.StatusOK)
+	}
+}
+
+// sendCoverageStatus sets a commit status and comments the pull request with the coverage of a node run, through the VCS µservice
+func (api *API) sendCoverageStatus(nr *sdk.WorkflowNodeRun) {
+	db := api.mustDB()
+	wr, err := workflow.LoadRunByID(db, nr.WorkflowRunID)
+	if err != nil {
+		log.Warning("sendCoverageStatus> Unable to load run %d: %v", nr.WorkflowRunID, err)
+		return
+	}
+	node := wr.Workflow.GetNode(nr.WorkflowNodeID)
+	if node == nil {
+		return
+	}
+
+	srvs, err := services.NewRepository(api.mustDB, api.Cache).FindByType("vcs")
+	if err != nil {
+		log.Warning("sendCoverageStatus> Unable to load vcs services: %v", err)
+		return
+	}
+
+	client, repo, err := workflow.NewCoverageVCSClient(db, srvs, wr, node)
+	if err != nil {
+		log.Warning("sendCoverageStatus> Unable to get vcs client of node %s: %v", node.Name, err)
+		return
+	}
+	if client == nil {
+		log.Debug("sendCoverageStatus> node %s has no repository or no vcs service is available", node.Name)
+		return
+	}
+
+	c, err := workflow.NodeRunCoverage(db, wr, nr, workflow.DefaultBranch(client, repo))
+	if err != nil {
+		log.Warning("sendCoverageStatus> %v", err)
+		return
+	}
+	if err := workflow.SendCoverageStatus(client, repo, api.Config.URL.UI, wr, c, nr); err != nil {
+		log.Warning("sendCoverageStatus> %v", err)
+	}
+}
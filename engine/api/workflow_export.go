@@ -0,0 +1,54 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+// exportWorkflowHandler returns the workflow as code, in yaml or json format
+func (api *API) exportWorkflowHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		format := r.FormValue("format")
+		if format == "" {
+			format = "yaml"
+		}
+
+		f, errF := exportentities.GetFormat(format)
+		if errF != nil || (f != exportentities.FormatYAML && f != exportentities.FormatJSON) {
+			return sdk.WrapError(sdk.ErrWrongRequest, "exportWorkflowHandler> Unsupported format %s", format)
+		}
+
+		wf, errW := workflow.Load(api.mustDB(), api.Cache, key, name, getUser(ctx))
+		if errW != nil {
+			return sdk.WrapError(errW, "exportWorkflowHandler> Cannot load workflow %s/%s", key, name)
+		}
+
+		e, errE := exportentities.NewWorkflow(*wf)
+		if errE != nil {
+			return sdk.WrapError(errE, "exportWorkflowHandler> Cannot export workflow %s/%s", key, name)
+		}
+
+		btes, errM := exportentities.Marshal(e, f)
+		if errM != nil {
+			return sdk.WrapError(errM, "exportWorkflowHandler> Cannot marshal workflow %s/%s", key, name)
+		}
+
+		if f == exportentities.FormatJSON {
+			w.Header().Set("Content-Type", "application/json")
+		} else {
+			w.Header().Set("Content-Type", "application/x-yaml")
+		}
+		w.WriteHeader(http.StatusOK)
+		_, err := w.Write(btes)
+		return err
+	}
+}
@@ -0,0 +1,237 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/services"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// importWorkflowHandler creates or updates a workflow from its code. With the dryRun flag,
+// the workflow is checked and the diff with the current workflow is returned but nothing is saved
+func (api *API) importWorkflowHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["permProjectKey"]
+		format := r.FormValue("format")
+		forceUpdate := FormBool(r, "forceUpdate")
+		dryRun := FormBool(r, "dryRun")
+
+		proj, errp := project.Load(api.mustDB(), api.Cache, key, getUser(ctx), project.LoadOptions.WithApplications, project.LoadOptions.WithPipelines, project.LoadOptions.WithEnvironments, project.LoadOptions.WithGroups)
+		if errp != nil {
+			return sdk.WrapError(errp, "importWorkflowHandler> Unable to load project %s", key)
+		}
+
+		// Get body
+		data, errRead := ioutil.ReadAll(r.Body)
+		if errRead != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "importWorkflowHandler> Unable to read body")
+		}
+
+		// Compute format
+		f, errF := exportentities.GetFormat(format)
+		if errF != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "importWorkflowHandler> Unable to get format : %s", errF)
+		}
+
+		// Parse the workflow
+		payload := exportentities.Workflow{}
+		var errorParse error
+		switch f {
+		case exportentities.FormatJSON:
+			errorParse = json.Unmarshal(data, &payload)
+		case exportentities.FormatYAML:
+			errorParse = yaml.Unmarshal(data, &payload)
+		default:
+			errorParse = exportentities.ErrUnsupportedFormat
+		}
+		if errorParse != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "importWorkflowHandler> Cannot parse workflow: %s", errorParse)
+		}
+
+		// Pipeline parameters are needed to type the default pipeline parameters of the nodes
+		for i := range proj.Pipelines {
+			params, errpp := pipeline.GetAllParametersInPipeline(api.mustDB(), proj.Pipelines[i].ID)
+			if errpp != nil {
+				return sdk.WrapError(errpp, "importWorkflowHandler> Unable to load parameters of pipeline %s", proj.Pipelines[i].Name)
+			}
+			proj.Pipelines[i].Parameter = params
+		}
+
+		wf, errW := payload.GetWorkflow(proj)
+		if errW != nil {
+			return sdk.WrapError(errW, "importWorkflowHandler> Unable to read workflow %s", payload.Name)
+		}
+
+		oldW, errL := workflow.Load(api.mustDB(), api.Cache, key, wf.Name, getUser(ctx))
+		if errL != nil && errL != sdk.ErrWorkflowNotFound {
+			return sdk.WrapError(errL, "importWorkflowHandler> Unable to load workflow %s", wf.Name)
+		}
+		if oldW != nil && !forceUpdate {
+			return sdk.ErrWorkflowAlreadyExists
+		}
+		// Updating a workflow needs the same permission as putWorkflowHandler
+		if oldW != nil && !getUser(ctx).Admin && !api.checkPermission(ctx, map[string]string{"key": key, "permWorkflowName": oldW.Name}, permission.PermissionReadWriteExecute) {
+			return sdk.WrapError(sdk.ErrForbidden, "importWorkflowHandler> %s cannot update workflow %s", getUser(ctx).Username, oldW.Name)
+		}
+
+		tx, errBegin := api.mustDB().Begin()
+		if errBegin != nil {
+			return sdk.WrapError(errBegin, "importWorkflowHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		if oldW != nil {
+			wf.ID = oldW.ID
+			wf.RootID = oldW.RootID
+			wf.Root.ID = oldW.RootID
+			keepWorkflowHooks(wf, oldW)
+			if err := workflow.Update(tx, api.Cache, wf, oldW, proj, getUser(ctx)); err != nil {
+				return sdk.WrapError(err, "importWorkflowHandler> Cannot update workflow %s", wf.Name)
+			}
+		} else {
+			if err := workflow.Insert(tx, api.Cache, wf, proj, getUser(ctx)); err != nil {
+				return sdk.WrapError(err, "importWorkflowHandler> Cannot insert workflow %s", wf.Name)
+			}
+			for _, gp := range proj.ProjectGroups {
+				if gp.Permission == permission.PermissionReadWriteExecute {
+					if err := workflow.AddGroup(tx, wf, gp); err != nil {
+						return sdk.WrapError(err, "importWorkflowHandler> Cannot add group %s", gp.Group.Name)
+					}
+				}
+			}
+		}
+
+		action := "created"
+		if oldW != nil {
+			action = "updated"
+		}
+
+		if dryRun {
+			var current *exportentities.Workflow
+			if oldW != nil {
+				e, err := exportentities.NewWorkflow(*oldW)
+				if err != nil {
+					return sdk.WrapError(err, "importWorkflowHandler> Unable to export workflow %s", oldW.Name)
+				}
+				current = &e
+			}
+			imported, err := exportentities.NewWorkflow(*wf)
+			if err != nil {
+				return sdk.WrapError(err, "importWorkflowHandler> Unable to export workflow %s", wf.Name)
+			}
+			diff, err := exportentities.WorkflowDiff(current, imported)
+			if err != nil {
+				return sdk.WrapError(err, "importWorkflowHandler> Unable to compute diff of workflow %s", wf.Name)
+			}
+
+			// The transaction is rolled back
+			msgs := []string{fmt.Sprintf("Workflow %s would be %s", wf.Name, action)}
+			if diff != "" {
+				msgs = append(msgs, diff)
+			}
+			return WriteJSON(w, r, msgs, http.StatusOK)
+		}
+
+		if err := project.UpdateLastModified(tx, api.Cache, getUser(ctx), proj); err != nil {
+			return sdk.WrapError(err, "importWorkflowHandler> Cannot update project last modified date")
+		}
+
+		hooks := wf.GetHooks()
+		if len(hooks) > 0 {
+			//Push the hook to hooks µService
+			dao := services.NewRepository(api.mustDB, api.Cache)
+			//Load service "hooks"
+			srvs, err := dao.FindByType("hooks")
+			if err != nil {
+				return sdk.WrapError(err, "importWorkflowHandler> Unable to get services dao")
+			}
+
+			//Perform the request on one off the hooks service
+			if len(srvs) < 1 {
+				return sdk.WrapError(fmt.Errorf("importWorkflowHandler> No hooks service available, please try again"), "Unable to get services dao")
+			}
+			var errHooks error
+			for _, s := range srvs {
+				code, errBulk := services.DoJSONRequest(&s, http.MethodPost, "/task/bulk", hooks, nil)
+				errHooks = errBulk
+				if errBulk == nil {
+					log.Debug("importWorkflowHandler> %d hooks created for workflow %s/%s (HTTP status code %d)", len(hooks), wf.ProjectKey, wf.Name, code)
+					break
+				}
+			}
+			if errHooks != nil {
+				return sdk.WrapError(errHooks, "importWorkflowHandler> Unable to create hooks")
+			}
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "importWorkflowHandler> Cannot commit transaction")
+		}
+
+		return WriteJSON(w, r, []string{fmt.Sprintf("Workflow %s has been %s", wf.Name, action)}, http.StatusOK)
+	}
+}
+
+// keepWorkflowHooks reuses the uuid of the hooks of the old workflow, matching them by node name and model,
+// so that webhook urls don't change on each import. The secrets, which are not exported, are kept if they are not in the imported hooks
+func keepWorkflowHooks(wf, oldW *sdk.Workflow) {
+	oldHooks := map[string][]sdk.WorkflowNodeHook{}
+	var browseOld func(n *sdk.WorkflowNode)
+	browseOld = func(n *sdk.WorkflowNode) {
+		oldHooks[n.Name] = append(oldHooks[n.Name], n.Hooks...)
+		for i := range n.Triggers {
+			browseOld(&n.Triggers[i].WorkflowDestNode)
+		}
+	}
+
+	var browseNew func(n *sdk.WorkflowNode)
+	browseNew = func(n *sdk.WorkflowNode) {
+		for i := range n.Hooks {
+			h := &n.Hooks[i]
+			candidates := oldHooks[n.Name]
+			for j := range candidates {
+				if candidates[j].UUID != "" && candidates[j].WorkflowHookModel.Name == h.WorkflowHookModel.Name {
+					h.UUID = candidates[j].UUID
+					if h.Config == nil {
+						h.Config = sdk.WorkflowNodeHookConfig{}
+					}
+					h.Config.RestoreSecrets(candidates[j].Config)
+					candidates[j].UUID = ""
+					break
+				}
+			}
+		}
+		for i := range n.Triggers {
+			browseNew(&n.Triggers[i].WorkflowDestNode)
+		}
+	}
+
+	forEachWorkflowRootNode(oldW, browseOld)
+	forEachWorkflowRootNode(wf, browseNew)
+}
+
+// forEachWorkflowRootNode calls f on the root and on the destination nodes of the joins
+func forEachWorkflowRootNode(w *sdk.Workflow, f func(n *sdk.WorkflowNode)) {
+	if w.Root != nil {
+		f(w.Root)
+	}
+	for i := range w.Joins {
+		for j := range w.Joins[i].Triggers {
+			f(&w.Joins[i].Triggers[j].WorkflowDestNode)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/engine/api/group"
+	"github.com/ovh/cds/engine/api/permission"
+	"github.com/ovh/cds/engine/api/pipeline"
+	"github.com/ovh/cds/engine/api/test"
+	"github.com/ovh/cds/engine/api/test/assets"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/exportentities"
+)
+
+func Test_importWorkflowHandler(t *testing.T) {
+	api, db, router := newTestAPI(t)
+
+	u, pass := assets.InsertAdminUser(api.mustDB())
+	key := sdk.RandomString(10)
+	proj := assets.InsertTestProject(t, db, api.Cache, key, key, u)
+	for _, name := range []string{"build", "deploy"} {
+		pip := sdk.Pipeline{
+			Name:      name,
+			ProjectID: proj.ID,
+			Type:      sdk.BuildPipeline,
+		}
+		test.NoError(t, pipeline.InsertPipeline(api.mustDB(), proj, &pip, nil))
+	}
+
+	payload := exportentities.Workflow{
+		Name: "my-workflow",
+		Workflow: map[string]exportentities.NodeEntry{
+			"build":  {PipelineName: "build"},
+			"deploy": {PipelineName: "deploy", DependsOn: []string{"build"}, Manual: true},
+		},
+	}
+
+	vars := map[string]string{
+		"permProjectKey": proj.Key,
+	}
+	uri := router.GetRoute("POST", api.importWorkflowHandler, vars)
+	test.NotEmpty(t, uri)
+
+	//Dry run: nothing is saved
+	req := assets.NewAuthentifiedRequest(t, u, pass, "POST", uri+"?format=json&dryRun=true", payload)
+	w := httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	msgs := []string{}
+	test.NoError(t, json.Unmarshal(w.Body.Bytes(), &msgs))
+	assert.Len(t, msgs, 2)
+	assert.Contains(t, msgs[1], "+    pipeline: deploy")
+
+	_, err := workflow.Load(api.mustDB(), api.Cache, proj.Key, "my-workflow", u)
+	assert.Equal(t, sdk.ErrWorkflowNotFound, err)
+
+	//Import
+	req = assets.NewAuthentifiedRequest(t, u, pass, "POST", uri+"?format=json", payload)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+
+	wf, err := workflow.Load(api.mustDB(), api.Cache, proj.Key, "my-workflow", u)
+	test.NoError(t, err)
+	assert.Equal(t, "build", wf.Root.Name)
+	assert.Len(t, wf.Root.Triggers, 1)
+	assert.True(t, wf.Root.Triggers[0].Manual)
+
+	//Import an existing workflow needs the force flag
+	req = assets.NewAuthentifiedRequest(t, u, pass, "POST", uri+"?format=json", payload)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 409, w.Code)
+
+	//Forcing the update needs the write permission on the workflow, not only on the project
+	g := &sdk.Group{Name: sdk.RandomString(10)}
+	lambda, lambdaPass := assets.InsertLambdaUser(api.mustDB(), g)
+	test.NoError(t, group.InsertGroupInProject(api.mustDB(), proj.ID, g.ID, permission.PermissionReadWriteExecute))
+	req = assets.NewAuthentifiedRequest(t, lambda, lambdaPass, "POST", uri+"?format=json&forceUpdate=true", payload)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 403, w.Code)
+
+	//Export
+	uri = router.GetRoute("GET", api.exportWorkflowHandler, map[string]string{
+		"key":              proj.Key,
+		"permWorkflowName": "my-workflow",
+	})
+	test.NotEmpty(t, uri)
+	req = assets.NewAuthentifiedRequest(t, u, pass, "GET", uri, nil)
+	w = httptest.NewRecorder()
+	router.Mux.ServeHTTP(w, req)
+	assert.Equal(t, 200, w.Code)
+	assert.True(t, strings.Contains(w.Body.String(), "pipeline: deploy"))
+}
@@ -14,7 +14,6 @@ import (
 	"github.com/ovh/venom"
 
 	"github.com/ovh/cds/engine/api/artifact"
-	"github.com/ovh/cds/engine/api/objectstore"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/worker"
 	"github.com/ovh/cds/engine/api/workflow"
@@ -66,6 +65,17 @@ func (api *API) postTakeWorkflowJobHandler() Handler {
 		}
 		defer tx.Rollback()
 
+		if err := api.checkJobQuota(tx, fmt.Sprintf("workflow-%d", id), p.ID, getWorker(ctx).ModelID, func(infos []sdk.SpawnInfo) error {
+			events := &workflow.RunEvents{}
+			if _, err := workflow.AddSpawnInfosNodeJobRun(api.mustDB(), api.Cache, events, p, id, infos); err != nil {
+				return err
+			}
+			events.Publish()
+			return nil
+		}); err != nil {
+			return sdk.WrapError(err, "postTakeWorkflowJobHandler> Cannot take job %d", id)
+		}
+
 		//Load worker model
 		workerModel := getWorker(ctx).Name
 		if getWorker(ctx).ModelID != 0 {
@@ -88,8 +98,9 @@ func (api *API) postTakeWorkflowJobHandler() Handler {
 			})
 		}
 
+		events := &workflow.RunEvents{}
 		//Take node job run
-		job, errTake := workflow.TakeNodeJobRun(tx, api.Cache, p, id, workerModel, getWorker(ctx).Name, getWorker(ctx).ID, infos)
+		job, errTake := workflow.TakeNodeJobRun(tx, api.Cache, events, p, id, workerModel, getWorker(ctx).Name, getWorker(ctx).ID, infos)
 		if errTake != nil {
 			return sdk.WrapError(errTake, "postTakeWorkflowJobHandler> Cannot take job %d", id)
 		}
@@ -150,6 +161,7 @@ func (api *API) postTakeWorkflowJobHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "postTakeWorkflowJobHandler> Cannot commit transaction")
 		}
+		events.Publish()
 
 		return WriteJSON(w, r, pbji, http.StatusOK)
 	}
@@ -162,6 +174,23 @@ func (api *API) postBookWorkflowJobHandler() Handler {
 			return sdk.WrapError(errc, "postBookWorkflowJobHandler> invalid id")
 		}
 
+		p, errP := project.LoadProjectByNodeJobRunID(api.mustDB(), api.Cache, id, getUser(ctx))
+		if errP != nil {
+			return sdk.WrapError(errP, "postBookWorkflowJobHandler> Cannot load project")
+		}
+
+		// The booking is not counted in the quotas: it only avoids to spawn a worker for a job which can't be taken
+		if err := api.checkJobQuota(api.mustDB(), fmt.Sprintf("workflow-%d", id), p.ID, 0, func(infos []sdk.SpawnInfo) error {
+			events := &workflow.RunEvents{}
+			if _, err := workflow.AddSpawnInfosNodeJobRun(api.mustDB(), api.Cache, events, p, id, infos); err != nil {
+				return err
+			}
+			events.Publish()
+			return nil
+		}); err != nil {
+			return sdk.WrapError(err, "postBookWorkflowJobHandler> Cannot book job %d", id)
+		}
+
 		if _, err := workflow.BookNodeJobRun(api.Cache, id, getHatchery(ctx)); err != nil {
 			return sdk.WrapError(err, "postBookWorkflowJobHandler> job already booked")
 		}
@@ -189,32 +218,54 @@ func (api *API) postSpawnInfosWorkflowJobHandler() Handler {
 		if errc != nil {
 			return sdk.WrapError(errc, "postSpawnInfosWorkflowJobHandler> invalid id")
 		}
-		var s []sdk.SpawnInfo
-		if err := UnmarshalBody(r, &s); err != nil {
-			return sdk.WrapError(err, "postSpawnInfosWorkflowJobHandler> cannot unmarshal request")
+		if err := api.addSpawnInfosWorkflowJob(ctx, r, id); err != nil {
+			return sdk.WrapError(err, "postSpawnInfosWorkflowJobHandler> Cannot add spawn infos on job %d", id)
 		}
+		return WriteJSON(w, r, nil, http.StatusOK)
+	}
+}
 
-		p, errP := project.LoadProjectByNodeJobRunID(api.mustDB(), api.Cache, id, getUser(ctx), project.LoadOptions.WithVariables)
-		if errP != nil {
-			return sdk.WrapError(errP, "postSpawnInfosWorkflowJobHandler> Cannot load project")
+// postWorkflowJobStepSpawnInfosHandler records the spawn infos sent by the worker which has taken the job, such as the retries of its steps
+func (api *API) postWorkflowJobStepSpawnInfosHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		id, errc := requestVarInt(r, "permID")
+		if errc != nil {
+			return sdk.WrapError(errc, "postWorkflowJobStepSpawnInfosHandler> invalid id")
 		}
-
-		tx, errBegin := api.mustDB().Begin()
-		if errBegin != nil {
-			return sdk.WrapError(errBegin, "postSpawnInfosWorkflowJobHandler> Cannot start transaction")
+		if err := api.addSpawnInfosWorkflowJob(ctx, r, id); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobStepSpawnInfosHandler> Cannot add spawn infos on job %d", id)
 		}
-		defer tx.Rollback()
+		return WriteJSON(w, r, nil, http.StatusOK)
+	}
+}
 
-		if _, err := workflow.AddSpawnInfosNodeJobRun(tx, api.Cache, p, id, s); err != nil {
-			return sdk.WrapError(err, "postSpawnInfosWorkflowJobHandler> Cannot save job %d", id)
-		}
+func (api *API) addSpawnInfosWorkflowJob(ctx context.Context, r *http.Request, id int64) error {
+	var s []sdk.SpawnInfo
+	if err := UnmarshalBody(r, &s); err != nil {
+		return sdk.WrapError(err, "addSpawnInfosWorkflowJob> cannot unmarshal request")
+	}
 
-		if err := tx.Commit(); err != nil {
-			return sdk.WrapError(err, "addSpawnInfosPipelineBuildJobHandler> Cannot commit tx")
-		}
+	p, errP := project.LoadProjectByNodeJobRunID(api.mustDB(), api.Cache, id, getUser(ctx), project.LoadOptions.WithVariables)
+	if errP != nil {
+		return sdk.WrapError(errP, "addSpawnInfosWorkflowJob> Cannot load project")
+	}
 
-		return WriteJSON(w, r, nil, http.StatusOK)
+	tx, errBegin := api.mustDB().Begin()
+	if errBegin != nil {
+		return sdk.WrapError(errBegin, "addSpawnInfosWorkflowJob> Cannot start transaction")
 	}
+	defer tx.Rollback()
+
+	events := &workflow.RunEvents{}
+	if _, err := workflow.AddSpawnInfosNodeJobRun(tx, api.Cache, events, p, id, s); err != nil {
+		return sdk.WrapError(err, "addSpawnInfosWorkflowJob> Cannot save job %d", id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "addSpawnInfosWorkflowJob> Cannot commit tx")
+	}
+	events.Publish()
+	return nil
 }
 
 func (api *API) postWorkflowJobResultHandler() Handler {
@@ -263,21 +314,23 @@ func (api *API) postWorkflowJobResultHandler() Handler {
 			Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoWorkerEnd.ID, Args: []interface{}{getWorker(ctx).Name, res.Duration}},
 		}}
 
+		events := &workflow.RunEvents{}
 		//Add spawn infos
-		if _, err := workflow.AddSpawnInfosNodeJobRun(tx, api.Cache, p, job.ID, infos); err != nil {
+		if _, err := workflow.AddSpawnInfosNodeJobRun(tx, api.Cache, events, p, job.ID, infos); err != nil {
 			log.Error("addQueueResultHandler> Cannot save spawn info job %d: %s", job.ID, err)
 			return err
 		}
 
 		// Update action status
 		log.Debug("postWorkflowJobResultHandler> Updating %d to %s in queue", id, res.Status)
-		if err := workflow.UpdateNodeJobRunStatus(tx, api.Cache, p, job, sdk.Status(res.Status)); err != nil {
+		if err := workflow.UpdateNodeJobRunStatus(tx, api.Cache, events, p, job, sdk.Status(res.Status)); err != nil {
 			return sdk.WrapError(err, "postWorkflowJobResultHandler> Cannot update %d status", id)
 		}
 
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "postWorkflowJobResultHandler> Cannot commit tx")
 		}
+		events.Publish()
 
 		return nil
 	}
@@ -348,11 +401,17 @@ func (api *API) postWorkflowJobStepStatusHandler() Handler {
 		}
 		defer tx.Rollback()
 
-		if err := workflow.UpdateNodeJobRun(tx, api.Cache, p, nodeJobRun); err != nil {
+		events := &workflow.RunEvents{}
+		if err := workflow.UpdateNodeJobRun(tx, api.Cache, events, p, nodeJobRun); err != nil {
 			return sdk.WrapError(err, "postWorkflowJobStepStatusHandler> Error while update job run")
 		}
 
-		return tx.Commit()
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobStepStatusHandler> Cannot commit transaction")
+		}
+		events.Publish()
+
+		return nil
 	}
 }
 
@@ -373,10 +432,42 @@ func (api *API) getWorkflowJobQueueHandler() Handler {
 			return sdk.WrapError(err, "getWorkflowJobQueueHandler> Unable to load queue")
 		}
 
+		if FormBool(r, "skipUnserviceable") {
+			models, err := worker.LoadEnabledWorkerModelNames(api.mustDB())
+			if err != nil {
+				return sdk.WrapError(err, "getWorkflowJobQueueHandler> Unable to load worker models")
+			}
+			jobs = serviceableJobs(jobs, models)
+		}
+
 		return WriteJSON(w, r, jobs, http.StatusOK)
 	}
 }
 
+// serviceableJobs removes the jobs which cannot be served currently: the jobs which need
+// a worker model which does not exist or which is disabled
+func serviceableJobs(jobs []sdk.WorkflowNodeJobRun, models []string) []sdk.WorkflowNodeJobRun {
+	enabled := make(map[string]bool, len(models))
+	for _, m := range models {
+		enabled[m] = true
+	}
+
+	res := make([]sdk.WorkflowNodeJobRun, 0, len(jobs))
+	for _, j := range jobs {
+		serviceable := true
+		for _, r := range j.Job.Action.Requirements {
+			if r.Type == sdk.ModelRequirement && !enabled[r.Value] {
+				serviceable = false
+				break
+			}
+		}
+		if serviceable {
+			res = append(res, j)
+		}
+	}
+	return res
+}
+
 func (api *API) postWorkflowJobTestsResultsHandler() Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		// Unmarshal into results
@@ -412,15 +503,17 @@ func (api *API) postWorkflowJobTestsResultsHandler() Handler {
 		}
 
 		for k := range new.TestSuites {
+			ts := new.TestSuites[k]
 			for i := range wnjr.Tests.TestSuites {
-				if wnjr.Tests.TestSuites[i].Name == new.TestSuites[k].Name {
+				if wnjr.Tests.TestSuites[i].Name == ts.Name {
 					// testsuite with same name already exists,
-					// Create a unique name
-					new.TestSuites[k].Name = fmt.Sprintf("%s.%d", new.TestSuites[k].Name, id)
+					// Create a unique name in the report of the node run only,
+					// the test cases keep the name of their suite
+					ts.Name = fmt.Sprintf("%s.%d", ts.Name, id)
 					break
 				}
 			}
-			wnjr.Tests.TestSuites = append(wnjr.Tests.TestSuites, new.TestSuites[k])
+			wnjr.Tests.TestSuites = append(wnjr.Tests.TestSuites, ts)
 		}
 
 		// update total values
@@ -439,6 +532,10 @@ func (api *API) postWorkflowJobTestsResultsHandler() Handler {
 			return sdk.WrapError(err, "postWorkflowJobTestsResultsHandler> Cannot update node run")
 		}
 
+		if err := workflow.InsertNodeRunTestCases(tx, wnjr, new); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobTestsResultsHandler> Cannot insert test cases")
+		}
+
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "postWorkflowJobTestsResultsHandler> Cannot update node run")
 		}
@@ -446,6 +543,55 @@ func (api *API) postWorkflowJobTestsResultsHandler() Handler {
 	}
 }
 
+func (api *API) postWorkflowJobCoverageHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		var report sdk.CoverageReport
+		if err := UnmarshalBody(r, &report); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobCoverageHandler> cannot unmarshal request")
+		}
+
+		id, errI := requestVarInt(r, "permID")
+		if errI != nil {
+			return sdk.WrapError(errI, "postWorkflowJobCoverageHandler> Invalid node job run ID")
+		}
+
+		nodeRunJob, errJobRun := workflow.LoadNodeJobRun(api.mustDB(), api.Cache, id)
+		if errJobRun != nil {
+			return sdk.WrapError(errJobRun, "postWorkflowJobCoverageHandler> Cannot load node run job")
+		}
+
+		tx, errB := api.mustDB().Begin()
+		if errB != nil {
+			return sdk.WrapError(errB, "postWorkflowJobCoverageHandler> Cannot start transaction")
+		}
+		defer tx.Rollback()
+
+		nr, err := workflow.LoadAndLockNodeRunByID(tx, nodeRunJob.WorkflowNodeRunID)
+		if err != nil {
+			return sdk.WrapError(err, "postWorkflowJobCoverageHandler> Cannot load node run")
+		}
+
+		// the files already sent by another job are replaced
+		if nr.Coverage == nil {
+			nr.Coverage = &sdk.CoverageReport{}
+		}
+		nr.Coverage.Merge(report)
+
+		if err := workflow.UpdateNodeRun(tx, nr); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobCoverageHandler> Cannot update node run")
+		}
+
+		if err := tx.Commit(); err != nil {
+			return sdk.WrapError(err, "postWorkflowJobCoverageHandler> Cannot commit transaction")
+		}
+
+		if r.FormValue("vcs_status") == "true" {
+			go api.sendCoverageStatus(nr)
+		}
+		return nil
+	}
+}
+
 func (api *API) postWorkflowJobVariableHandler() Handler {
 	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 		id, errr := requestVarInt(r, "permID")
@@ -488,7 +634,8 @@ func (api *API) postWorkflowJobVariableHandler() Handler {
 			sdk.AddParameter(&job.Parameters, v.Name, sdk.StringParameter, v.Value)
 		}
 
-		if err := workflow.UpdateNodeJobRun(tx, api.Cache, p, job); err != nil {
+		events := &workflow.RunEvents{}
+		if err := workflow.UpdateNodeJobRun(tx, api.Cache, events, p, job); err != nil {
 			return sdk.WrapError(err, "postWorkflowJobVariableHandler> Unable to update node job run")
 		}
 
@@ -517,6 +664,7 @@ func (api *API) postWorkflowJobVariableHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "postWorkflowJobVariableHandler> Unable to commit tx")
 		}
+		events.Publish()
 
 		return nil
 	}
@@ -610,7 +758,7 @@ func (api *API) postWorkflowJobArtifactHandler() Handler {
 
 			}
 
-			if err := artifact.SaveWorkflowFile(&art, file); err != nil {
+			if err := artifact.SaveWorkflowFile(api.mustDB(), &art, file); err != nil {
 				return sdk.WrapError(err, "postWorkflowJobArtifactHandler> Cannot save artifact in store")
 			}
 			file.Close()
@@ -618,7 +766,9 @@ func (api *API) postWorkflowJobArtifactHandler() Handler {
 
 		nodeRun.Artifacts = append(nodeRun.Artifacts, art)
 		if err := workflow.InsertArtifact(api.mustDB(), &art); err != nil {
-			_ = objectstore.DeleteArtifact(&art)
+			if errR := artifact.ReleaseBlob(api.mustDB(), art.SHA256sum); errR == nil {
+				_ = artifact.DeleteBlob(api.mustDB(), art.SHA256sum)
+			}
 			return sdk.WrapError(err, "postWorkflowJobArtifactHandler> Cannot update workflow node run")
 		}
 		return nil
@@ -455,6 +455,30 @@ func Test_postWorkflowJobTestsResultsHandler(t *testing.T) {
 
 	assert.NotNil(t, nodeRun.Tests)
 	assert.Equal(t, 2, nodeRun.Tests.Total)
+
+	testCases, errT := workflow.LoadRunTestCases(api.mustDB(), nodeRun.WorkflowRunID)
+	test.NoError(t, errT)
+	assert.Len(t, testCases, 2)
+
+	//Compare with the previous run
+	vars = map[string]string{
+		"key":              ctx.project.Key,
+		"permWorkflowName": ctx.workflow.Name,
+		"number":           fmt.Sprintf("%d", nodeRun.Number),
+	}
+	uri = router.GetRoute("GET", api.getWorkflowRunTestsComparisonHandler, vars)
+	test.NotEmpty(t, uri)
+
+	req = assets.NewAuthentifiedRequest(t, ctx.user, ctx.password, "GET", uri, nil)
+	rec = httptest.NewRecorder()
+	router.Mux.ServeHTTP(rec, req)
+	assert.Equal(t, 200, rec.Code)
+
+	var comparison sdk.TestsComparison
+	test.NoError(t, json.Unmarshal(rec.Body.Bytes(), &comparison))
+	assert.Equal(t, int64(0), comparison.PreviousNumber)
+	assert.Len(t, comparison.NewTests, 2)
+	assert.Len(t, comparison.NewFailures, 1)
 }
 func Test_postWorkflowJobVariableHandler(t *testing.T) {
 	api, db, router := newTestAPI(t)
@@ -631,3 +655,23 @@ func Test_getDownloadArtifactHandler(t *testing.T) {
 	//api, db, router := newTestAPI(t)
 	//ctx := runWorkflow(t, db, "Test_postWorkflowJobRequirementsErrorHandler")
 }
+
+func Test_serviceableJobs(t *testing.T) {
+	job := func(id int64, requirements ...sdk.Requirement) sdk.WorkflowNodeJobRun {
+		j := sdk.WorkflowNodeJobRun{ID: id}
+		j.Job.Action.Requirements = requirements
+		return j
+	}
+	jobs := []sdk.WorkflowNodeJobRun{
+		job(1),
+		job(2, sdk.Requirement{Type: sdk.ModelRequirement, Value: "golang"}),
+		job(3, sdk.Requirement{Type: sdk.ModelRequirement, Value: "disabled-model"}),
+		job(4, sdk.Requirement{Type: sdk.BinaryRequirement, Value: "git"}),
+	}
+
+	res := serviceableJobs(jobs, []string{"golang", "node"})
+	assert.Len(t, res, 3)
+	assert.Equal(t, int64(1), res[0].ID)
+	assert.Equal(t, int64(2), res[1].ID)
+	assert.Equal(t, int64(4), res[2].ID)
+}
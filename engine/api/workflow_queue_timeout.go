@@ -0,0 +1,68 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-gorp/gorp"
+
+	"github.com/ovh/cds/engine/api/cache"
+	"github.com/ovh/cds/engine/api/project"
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// workflowJobTimeoutRoutine fails the job runs which last more than their timeout plus a grace delay,
+// when their worker did not stop them
+func workflowJobTimeoutRoutine(c context.Context, DBFunc func() *gorp.DbMap, store cache.Store, grace time.Duration) {
+	tick := time.NewTicker(30 * time.Second).C
+
+	for {
+		select {
+		case <-c.Done():
+			if c.Err() != nil {
+				log.Error("Exiting workflowJobTimeoutRoutine: %v", c.Err())
+			}
+			return
+		case <-tick:
+			db := DBFunc()
+			if db == nil {
+				continue
+			}
+			ids, err := workflow.LoadTimedOutNodeJobRunIDs(db, grace)
+			if err != nil {
+				log.Warning("workflowJobTimeoutRoutine> %s", err)
+				continue
+			}
+			for _, id := range ids {
+				if err := failTimedOutNodeJobRun(db, store, id, grace); err != nil {
+					log.Warning("workflowJobTimeoutRoutine> Cannot fail job %d: %s", id, err)
+				}
+			}
+		}
+	}
+}
+
+func failTimedOutNodeJobRun(db *gorp.DbMap, store cache.Store, id int64, grace time.Duration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return sdk.WrapError(err, "failTimedOutNodeJobRun> Cannot start transaction")
+	}
+	defer tx.Rollback()
+
+	p, errP := project.LoadProjectByNodeJobRunID(tx, store, id, nil, project.LoadOptions.WithVariables)
+	if errP != nil {
+		return sdk.WrapError(errP, "failTimedOutNodeJobRun> Cannot load project")
+	}
+
+	events := &workflow.RunEvents{}
+	if _, err := workflow.FailTimedOutNodeJobRun(tx, store, events, p, id, grace); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return sdk.WrapError(err, "failTimedOutNodeJobRun> Cannot commit transaction")
+	}
+	events.Publish()
+	return nil
+}
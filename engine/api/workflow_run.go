@@ -11,6 +11,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/ovh/cds/engine/api/artifact"
+	"github.com/ovh/cds/engine/api/event"
 	"github.com/ovh/cds/engine/api/permission"
 	"github.com/ovh/cds/engine/api/project"
 	"github.com/ovh/cds/engine/api/workflow"
@@ -244,6 +245,9 @@ func (api *API) stopWorkflowRunHandler() Handler {
 		if errU := workflow.UpdateWorkflowRunStatus(api.mustDB(), run.ID, sdk.StatusStopped.String()); errU != nil {
 			return sdk.WrapError(errU, "stopWorkflowRunHandler> Unable to update workflow run status %d", run.ID)
 		}
+		run.Status = sdk.StatusStopped.String()
+		run.LastModified = time.Now()
+		event.PublishWorkflowRun(run)
 
 		return WriteJSON(w, r, run, http.StatusOK)
 	}
@@ -392,11 +396,12 @@ func (api *API) postWorkflowRunHandler() Handler {
 		}
 
 		var wr *sdk.WorkflowRun
+		events := &workflow.RunEvents{}
 
 		//Run from hook
 		if opts.Hook != nil {
 			var errfh error
-			wr, errfh = workflow.RunFromHook(tx, api.Cache, p, wf, opts.Hook)
+			wr, errfh = workflow.RunFromHook(tx, api.Cache, events, p, wf, opts.Hook)
 			if errfh != nil {
 				return sdk.WrapError(errfh, "postWorkflowRunHandler> Unable to run workflow from hook")
 			}
@@ -442,7 +447,7 @@ func (api *API) postWorkflowRunHandler() Handler {
 				//Manual run
 				if lastRun != nil {
 					var errmr error
-					wr, errmr = workflow.ManualRunFromNode(tx, api.Cache, p, wf, lastRun.Number, opts.Manual, fromNode.ID)
+					wr, errmr = workflow.ManualRunFromNode(tx, api.Cache, events, p, wf, lastRun.Number, opts.Manual, fromNode.ID)
 					if errmr != nil {
 						return sdk.WrapError(errmr, "postWorkflowRunHandler> Unable to run workflow from node")
 					}
@@ -451,7 +456,7 @@ func (api *API) postWorkflowRunHandler() Handler {
 
 			if lastRun == nil {
 				var errmr error
-				wr, errmr = workflow.ManualRun(tx, api.Cache, p, wf, opts.Manual)
+				wr, errmr = workflow.ManualRun(tx, api.Cache, events, p, wf, opts.Manual)
 				if errmr != nil {
 					return sdk.WrapError(errmr, "postWorkflowRunHandler> Unable to run workflow")
 				}
@@ -462,6 +467,7 @@ func (api *API) postWorkflowRunHandler() Handler {
 		if err := tx.Commit(); err != nil {
 			return sdk.WrapError(err, "postWorkflowRunHandler> Unable to commit transaction")
 		}
+		events.Publish()
 
 		// Purge workflow run
 		go workflow.PurgeWorkflowRun(api.mustDB(), *wf)
@@ -481,6 +487,10 @@ func (api *API) downloadworkflowArtifactDirectHandler() Handler {
 			return sdk.WrapError(err, "downloadworkflowArtifactDirectHandler> Could not load artifact with hash %s", hash)
 		}
 
+		if redirectArtifact(w, r, art) {
+			return nil
+		}
+
 		w.Header().Add("Content-Type", "application/octet-stream")
 		w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", art.Name))
 
@@ -537,6 +547,10 @@ func (api *API) getDownloadArtifactHandler() Handler {
 			return sdk.WrapError(errA, "getDownloadArtifactHandler> Cannot load artifacts")
 		}
 
+		if redirectArtifact(w, r, art) {
+			return nil
+		}
+
 		w.Header().Add("Content-Type", "application/octet-stream")
 		w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s\"", art.Name))
 
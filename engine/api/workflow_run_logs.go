@@ -0,0 +1,188 @@
+package api
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+// runLogsFollowDelay is the delay between two reads of the logs of a followed workflow run
+const runLogsFollowDelay = time.Second
+
+// runLogsCursor remembers what has been sent of the logs of a workflow run. A requeued job keeps its ID
+// and appends the logs of its new attempt, so done remembers the attempt of the job whose step is over
+type runLogsCursor struct {
+	offsets  map[string]int
+	statuses map[string]string
+	done     map[string]int
+}
+
+func newRunLogsCursor() *runLogsCursor {
+	return &runLogsCursor{
+		offsets:  map[string]int{},
+		statuses: map[string]string{},
+		done:     map[string]int{},
+	}
+}
+
+// skip returns true if all the logs of a step have been sent for the current attempt of its job: its logs do not need to be loaded again
+func (c *runLogsCursor) skip(l sdk.WorkflowRunLog) bool {
+	retry, done := c.done[l.StepKey()]
+	return done && retry == l.Retry
+}
+
+// next returns the parts of the logs which have not been sent yet. A part is also returned when the status of a step changes
+func (c *runLogsCursor) next(logs []sdk.WorkflowRunLog) []sdk.WorkflowRunLog {
+	var parts []sdk.WorkflowRunLog
+	for _, l := range logs {
+		k := l.StepKey()
+		offset, known := c.offsets[k]
+		if offset > len(l.Value) {
+			offset = len(l.Value)
+		}
+		if known && offset == len(l.Value) && c.statuses[k] == l.Status {
+			// A terminated step is read once more after its status has been sent, for its last logs
+			if sdk.StatusIsTerminated(l.Status) {
+				c.done[k] = l.Retry
+			}
+			continue
+		}
+		l.Offset = offset
+		l.Value = l.Value[offset:]
+		c.offsets[k] = offset + len(l.Value)
+		c.statuses[k] = l.Status
+		parts = append(parts, l)
+	}
+	return parts
+}
+
+func (api *API) getWorkflowRunLogsHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		number, errN := requestVarInt(r, "number")
+		if errN != nil {
+			return sdk.WrapError(errN, "getWorkflowRunLogsHandler> Invalid run number")
+		}
+		follow := FormBool(r, "follow")
+
+		f, ok := w.(http.Flusher)
+		if !ok {
+			return sdk.WrapError(sdk.ErrWrongRequest, "getWorkflowRunLogsHandler> Streaming unsupported")
+		}
+
+		// Check the run exists before streaming
+		wr, errR := workflow.LoadRun(api.mustDB(), key, name, number)
+		if errR != nil {
+			return sdk.WrapError(errR, "getWorkflowRunLogsHandler> Cannot load workflow run %d", number)
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		f.Flush()
+
+		cursor := newRunLogsCursor()
+		tick := time.NewTicker(runLogsFollowDelay)
+		defer tick.Stop()
+
+		for {
+			// The status is read before the logs, so that the last logs of a terminated run are sent
+			terminated := sdk.StatusIsTerminated(wr.Status)
+
+			// Only the logs of the steps which are not terminated are loaded again
+			logs, errL := workflow.LoadRunLogs(api.mustDB(), wr, cursor.skip)
+			if errL != nil {
+				return sdk.WrapError(errL, "getWorkflowRunLogsHandler> Cannot load logs of workflow run %d", number)
+			}
+			for _, p := range cursor.next(logs) {
+				b, err := json.Marshal(p)
+				if err != nil {
+					return sdk.WrapError(err, "getWorkflowRunLogsHandler> Cannot marshal logs")
+				}
+				fmt.Fprintf(w, "data: %s\n\n", b)
+			}
+			// Comments keep the connection opened through proxies while nothing is logged
+			fmt.Fprint(w, ": keep-alive\n\n")
+			f.Flush()
+
+			if !follow || terminated {
+				return nil
+			}
+
+			select {
+			case <-w.(http.CloseNotifier).CloseNotify():
+				return nil
+			case <-ctx.Done():
+				return nil
+			case <-tick.C:
+			}
+
+			wr, errR = workflow.LoadRun(api.mustDB(), key, name, number)
+			if errR != nil {
+				return sdk.WrapError(errR, "getWorkflowRunLogsHandler> Cannot load workflow run %d", number)
+			}
+		}
+	}
+}
+
+func (api *API) getWorkflowRunLogsDownloadHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+		number, errN := requestVarInt(r, "number")
+		if errN != nil {
+			return sdk.WrapError(errN, "getWorkflowRunLogsDownloadHandler> Invalid run number")
+		}
+
+		wr, errR := workflow.LoadRun(api.mustDB(), key, name, number)
+		if errR != nil {
+			return sdk.WrapError(errR, "getWorkflowRunLogsDownloadHandler> Cannot load workflow run %d", number)
+		}
+
+		logs, errL := workflow.LoadRunLogs(api.mustDB(), wr, nil)
+		if errL != nil {
+			return sdk.WrapError(errL, "getWorkflowRunLogsDownloadHandler> Cannot load logs of workflow run %d", number)
+		}
+
+		w.Header().Add("Content-Type", "application/gzip")
+		w.Header().Add("Content-Disposition", fmt.Sprintf("attachment; filename=\"%s-%d-logs.tar.gz\"", name, number))
+
+		gz := gzip.NewWriter(w)
+		tw := tar.NewWriter(gz)
+		for _, l := range logs {
+			hdr := &tar.Header{
+				Name:    l.FileName(),
+				Mode:    0644,
+				Size:    int64(len(l.Value)),
+				ModTime: wr.LastModified,
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return sdk.WrapError(err, "getWorkflowRunLogsDownloadHandler> Cannot write archive")
+			}
+			if _, err := tw.Write([]byte(l.Value)); err != nil {
+				return sdk.WrapError(err, "getWorkflowRunLogsDownloadHandler> Cannot write archive")
+			}
+		}
+		if err := tw.Close(); err != nil {
+			return sdk.WrapError(err, "getWorkflowRunLogsDownloadHandler> Cannot close archive")
+		}
+		if err := gz.Close(); err != nil {
+			return sdk.WrapError(err, "getWorkflowRunLogsDownloadHandler> Cannot close archive")
+		}
+		return nil
+	}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_runLogsCursor(t *testing.T) {
+	c := newRunLogsCursor()
+
+	parts := c.next([]sdk.WorkflowRunLog{
+		{JobID: 1, StepOrder: 0, Status: sdk.StatusSuccess.String(), Value: "step 0\n"},
+		{JobID: 1, StepOrder: 1, Status: sdk.StatusBuilding.String(), Value: "line 1\n"},
+	})
+	assert.Len(t, parts, 2)
+	assert.Equal(t, "line 1\n", parts[1].Value)
+	assert.Equal(t, 0, parts[1].Offset)
+
+	// Nothing new
+	parts = c.next([]sdk.WorkflowRunLog{
+		{JobID: 1, StepOrder: 0, Status: sdk.StatusSuccess.String(), Value: "step 0\n"},
+		{JobID: 1, StepOrder: 1, Status: sdk.StatusBuilding.String(), Value: "line 1\n"},
+	})
+	assert.Len(t, parts, 0)
+
+	// Only the new logs are sent
+	parts = c.next([]sdk.WorkflowRunLog{
+		{JobID: 1, StepOrder: 0, Status: sdk.StatusSuccess.String(), Value: "step 0\n"},
+		{JobID: 1, StepOrder: 1, Status: sdk.StatusBuilding.String(), Value: "line 1\nline 2\n"},
+	})
+	assert.Len(t, parts, 1)
+	assert.Equal(t, "line 2\n", parts[0].Value)
+	assert.Equal(t, 7, parts[0].Offset)
+
+	// A change of status is sent without logs
+	parts = c.next([]sdk.WorkflowRunLog{
+		{JobID: 1, StepOrder: 1, Status: sdk.StatusFail.String(), Value: "line 1\nline 2\n"},
+	})
+	assert.Len(t, parts, 1)
+	assert.Equal(t, sdk.StatusFail.String(), parts[0].Status)
+	assert.Equal(t, "", parts[0].Value)
+
+	// Terminated steps are skipped once they have been read without change
+	assert.False(t, c.skip(sdk.WorkflowRunLog{JobID: 1, StepOrder: 1}))
+	parts = c.next([]sdk.WorkflowRunLog{
+		{JobID: 1, StepOrder: 0, Status: sdk.StatusSuccess.String(), Value: "step 0\n"},
+		{JobID: 1, StepOrder: 1, Status: sdk.StatusFail.String(), Value: "line 1\nline 2\n"},
+	})
+	assert.Len(t, parts, 0)
+	assert.True(t, c.skip(sdk.WorkflowRunLog{JobID: 1, StepOrder: 0}))
+	assert.True(t, c.skip(sdk.WorkflowRunLog{JobID: 1, StepOrder: 1}))
+}
+
+func Test_runLogsCursorRetry(t *testing.T) {
+	c := newRunLogsCursor()
+
+	c.next([]sdk.WorkflowRunLog{{JobID: 1, StepOrder: 0, Status: sdk.StatusFail.String(), Value: "attempt 1\n"}})
+	c.next([]sdk.WorkflowRunLog{{JobID: 1, StepOrder: 0, Status: sdk.StatusFail.String(), Value: "attempt 1\n"}})
+	assert.True(t, c.skip(sdk.WorkflowRunLog{JobID: 1, StepOrder: 0}))
+
+	// The job has been requeued: its step is loaded again and only the logs of the new attempt are sent
+	assert.False(t, c.skip(sdk.WorkflowRunLog{JobID: 1, StepOrder: 0, Retry: 1}))
+	parts := c.next([]sdk.WorkflowRunLog{{JobID: 1, StepOrder: 0, Retry: 1, Status: sdk.StatusSuccess.String(), Value: "attempt 1\nattempt 2\n"}})
+	assert.Len(t, parts, 1)
+	assert.Equal(t, "attempt 2\n", parts[0].Value)
+	assert.Equal(t, sdk.StatusSuccess.String(), parts[0].Status)
+
+	c.next([]sdk.WorkflowRunLog{{JobID: 1, StepOrder: 0, Retry: 1, Status: sdk.StatusSuccess.String(), Value: "attempt 1\nattempt 2\n"}})
+	assert.True(t, c.skip(sdk.WorkflowRunLog{JobID: 1, StepOrder: 0, Retry: 1}))
+}
@@ -94,14 +94,14 @@ func Test_getWorkflowNodeRunHistoryHandler(t *testing.T) {
 	w1, err := workflow.Load(db, api.Cache, key, "test_1", u)
 	test.NoError(t, err)
 
-	wr, errMR := workflow.ManualRun(db, api.Cache, proj, w1, &sdk.WorkflowNodeRunManual{
+	wr, errMR := workflow.ManualRun(db, api.Cache, &workflow.RunEvents{}, proj, w1, &sdk.WorkflowNodeRunManual{
 		User: *u,
 	})
 	if errMR != nil {
 		test.NoError(t, errMR)
 	}
 
-	_, errMR2 := workflow.ManualRunFromNode(db, api.Cache, proj, &wr.Workflow, wr.Number, &sdk.WorkflowNodeRunManual{User: *u}, wr.Workflow.RootID)
+	_, errMR2 := workflow.ManualRunFromNode(db, api.Cache, &workflow.RunEvents{}, proj, &wr.Workflow, wr.Number, &sdk.WorkflowNodeRunManual{User: *u}, wr.Workflow.RootID)
 	if errMR2 != nil {
 		test.NoError(t, errMR2)
 	}
@@ -203,7 +203,7 @@ func Test_getWorkflowRunsHandler(t *testing.T) {
 	test.NoError(t, err)
 
 	for i := 0; i < 10; i++ {
-		_, err = workflow.ManualRun(api.mustDB(), api.Cache, proj, w1, &sdk.WorkflowNodeRunManual{
+		_, err = workflow.ManualRun(api.mustDB(), api.Cache, &workflow.RunEvents{}, proj, w1, &sdk.WorkflowNodeRunManual{
 			User: *u,
 		})
 		test.NoError(t, err)
@@ -330,7 +330,7 @@ func Test_getLatestWorkflowRunHandler(t *testing.T) {
 	test.NoError(t, err)
 
 	for i := 0; i < 10; i++ {
-		_, err = workflow.ManualRun(api.mustDB(), api.Cache, proj, w1, &sdk.WorkflowNodeRunManual{
+		_, err = workflow.ManualRun(api.mustDB(), api.Cache, &workflow.RunEvents{}, proj, w1, &sdk.WorkflowNodeRunManual{
 			User: *u,
 			Payload: map[string]string{
 				"git.branch": "master",
@@ -450,7 +450,7 @@ func Test_getWorkflowRunHandler(t *testing.T) {
 	test.NoError(t, err)
 
 	for i := 0; i < 10; i++ {
-		_, err = workflow.ManualRun(api.mustDB(), api.Cache, proj, w1, &sdk.WorkflowNodeRunManual{
+		_, err = workflow.ManualRun(api.mustDB(), api.Cache, &workflow.RunEvents{}, proj, w1, &sdk.WorkflowNodeRunManual{
 			User: *u,
 		})
 		test.NoError(t, err)
@@ -550,7 +550,7 @@ func Test_getWorkflowNodeRunHandler(t *testing.T) {
 	w1, err := workflow.Load(api.mustDB(), api.Cache, key, "test_1", u)
 	test.NoError(t, err)
 
-	_, err = workflow.ManualRun(api.mustDB(), api.Cache, proj, w1, &sdk.WorkflowNodeRunManual{
+	_, err = workflow.ManualRun(api.mustDB(), api.Cache, &workflow.RunEvents{}, proj, w1, &sdk.WorkflowNodeRunManual{
 		User: *u,
 	})
 	test.NoError(t, err)
@@ -913,7 +913,7 @@ func Test_getWorkflowNodeRunJobStepHandler(t *testing.T) {
 	w1, err := workflow.Load(api.mustDB(), api.Cache, key, "test_1", u)
 	test.NoError(t, err)
 
-	_, err = workflow.ManualRun(api.mustDB(), api.Cache, proj, w1, &sdk.WorkflowNodeRunManual{
+	_, err = workflow.ManualRun(api.mustDB(), api.Cache, &workflow.RunEvents{}, proj, w1, &sdk.WorkflowNodeRunManual{
 		User: *u,
 	})
 	test.NoError(t, err)
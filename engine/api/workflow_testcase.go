@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/ovh/cds/engine/api/workflow"
+	"github.com/ovh/cds/sdk"
+)
+
+const (
+	defaultTestCasesLimit = 50
+	maxTestCasesLimit     = 500
+)
+
+// testCasesLimit returns the limit query parameter, with a default value and a maximum value
+func testCasesLimit(r *http.Request) (int, error) {
+	limitS := r.FormValue("limit")
+	if limitS == "" {
+		return defaultTestCasesLimit, nil
+	}
+	limit, err := strconv.Atoi(limitS)
+	if err != nil || limit <= 0 || limit > maxTestCasesLimit {
+		return 0, sdk.ErrWrongRequest
+	}
+	return limit, nil
+}
+
+func (api *API) getWorkflowTestCaseHistoryHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		nodeName := r.FormValue("node")
+		testSuite := r.FormValue("testsuite")
+		testName := r.FormValue("name")
+		if nodeName == "" || testSuite == "" || testName == "" {
+			return sdk.WrapError(sdk.ErrWrongRequest, "getWorkflowTestCaseHistoryHandler> node, testsuite and name are mandatory")
+		}
+
+		limit, err := testCasesLimit(r)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowTestCaseHistoryHandler> Invalid limit")
+		}
+
+		runs, err := workflow.LoadTestCaseHistory(api.mustDB(), key, name, nodeName, testSuite, testName, limit)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowTestCaseHistoryHandler> Unable to load history")
+		}
+
+		return WriteJSON(w, r, sdk.NewTestCaseHistory(nodeName, testSuite, testName, runs), http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowFlakyTestCasesHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		limit, err := testCasesLimit(r)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowFlakyTestCasesHandler> Invalid limit")
+		}
+
+		tests, err := workflow.LoadFlakyTestCases(api.mustDB(), key, name, limit)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowFlakyTestCasesHandler> Unable to load flaky tests")
+		}
+
+		return WriteJSON(w, r, tests, http.StatusOK)
+	}
+}
+
+func (api *API) getWorkflowRunTestsComparisonHandler() Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		vars := mux.Vars(r)
+		key := vars["key"]
+		name := vars["permWorkflowName"]
+
+		number, errNu := requestVarInt(r, "number")
+		if errNu != nil {
+			return sdk.WrapError(errNu, "getWorkflowRunTestsComparisonHandler> Invalid number")
+		}
+
+		wr, errW := workflow.LoadRun(api.mustDB(), key, name, number)
+		if errW != nil {
+			return sdk.WrapError(errW, "getWorkflowRunTestsComparisonHandler> Unable to load run %d", number)
+		}
+
+		current, err := workflow.LoadRunTestCases(api.mustDB(), wr.ID)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowRunTestsComparisonHandler> Unable to load tests")
+		}
+
+		var branch string
+		for _, t := range wr.Tags {
+			if t.Tag == "git.branch" {
+				branch = t.Value
+			}
+		}
+
+		previousNumber, previous, err := workflow.LoadPreviousRunTestCases(api.mustDB(), wr.WorkflowID, wr.Number, branch)
+		if err != nil {
+			return sdk.WrapError(err, "getWorkflowRunTestsComparisonHandler> Unable to load tests of the previous run")
+		}
+
+		c := sdk.CompareTestCases(current, previous)
+		c.Number = wr.Number
+		c.PreviousNumber = previousNumber
+		c.Branch = branch
+		return WriteJSON(w, r, c, http.StatusOK)
+	}
+}
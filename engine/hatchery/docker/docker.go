@@ -88,10 +88,10 @@ func (*HatcheryDocker) ModelType() string {
 }
 
 // CanSpawn return wether or not hatchery can spawn model
-// requirement are not supported
+// memory requirement is not supported
 func (h *HatcheryDocker) CanSpawn(model *sdk.Model, jobID int64, requirements []sdk.Requirement) bool {
 	for _, r := range requirements {
-		if r.Type == sdk.ServiceRequirement || r.Type == sdk.MemoryRequirement {
+		if r.Type == sdk.MemoryRequirement {
 			return false
 		}
 	}
@@ -180,14 +180,14 @@ func (h *HatcheryDocker) killAwolWorker() {
 					}
 				}
 
-				// Remove container
-				go func() {
+				// Remove container and its services
+				go func(name string) {
 					cmd := exec.Command("docker", "rm", "-f", name)
-					err = cmd.Run()
-					if err != nil {
+					if err := cmd.Run(); err != nil {
 						log.Warning("HatcheryDocker.killAwolWorker: cannot rm container %s: %s", name, err)
 					}
-				}()
+					removeServices(name, workerNetwork(name))
+				}(name)
 
 				delete(h.workers, name)
 				log.Info("HatcheryDocker.killAwolWorker> Killed disabled worker %s", name)
@@ -240,6 +240,21 @@ func (h *HatcheryDocker) SpawnWorker(wm *sdk.Model, jobID int64, requirements []
 		name = "register-" + name
 	}
 
+	var services []hatchery.Service
+	if jobID > 0 {
+		var errS error
+		services, errS = hatchery.ServicesFromRequirements(requirements)
+		if errS != nil {
+			return "", errS
+		}
+	}
+	if len(services) > 0 {
+		if err := h.startServices(name, workerNetwork(name), services); err != nil {
+			removeServices(name, workerNetwork(name))
+			return "", err
+		}
+	}
+
 	var args []string
 	args = append(args, "run", "--rm", "-a", "STDOUT", "-a", "STDERR")
 	args = append(args, fmt.Sprintf("--name=%s", name))
@@ -272,6 +287,10 @@ func (h *HatcheryDocker) SpawnWorker(wm *sdk.Model, jobID int64, requirements []
 		args = append(args, "-e", fmt.Sprintf("CDS_BOOKED_JOB_ID=%d", jobID))
 	}
 
+	if len(services) > 0 {
+		args = append(args, fmt.Sprintf("--network=%s", workerNetwork(name)))
+	}
+
 	if h.Config.DockerAddHost != "" {
 		args = append(args, fmt.Sprintf("--add-host=%s", h.Config.DockerAddHost))
 	}
@@ -285,6 +304,9 @@ func (h *HatcheryDocker) SpawnWorker(wm *sdk.Model, jobID int64, requirements []
 	log.Debug("Running %s", cmd.Args)
 
 	if err := cmd.Start(); err != nil {
+		if len(services) > 0 {
+			removeServices(name, workerNetwork(name))
+		}
 		return "", err
 	}
 	h.Lock()
@@ -293,8 +315,12 @@ func (h *HatcheryDocker) SpawnWorker(wm *sdk.Model, jobID int64, requirements []
 
 	// Wait in a goroutine so that when process exits, Wait() update cmd.ProcessState
 	// ProcessState is then checked in nextAvailableLocalID
+	// The services of the worker are removed when the job is over
 	go func() {
 		cmd.Wait()
+		if len(services) > 0 {
+			removeServices(name, workerNetwork(name))
+		}
 	}()
 
 	// Do not spam docker daemon
@@ -302,6 +328,11 @@ func (h *HatcheryDocker) SpawnWorker(wm *sdk.Model, jobID int64, requirements []
 	return name, nil
 }
 
+// workerNetwork returns the name of the network of a worker and its services
+func workerNetwork(name string) string {
+	return name + "-net"
+}
+
 func randSeq(n int) (string, error) {
 	b := make([]byte, 64)
 	if _, err := rand.Read(b); err != nil {
@@ -0,0 +1,106 @@
+package docker
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// serviceReadyTimeout is the maximum wait for the services of a job to be ready
+const serviceReadyTimeout = 2 * time.Minute
+
+// startServices creates the network of a worker and starts its service containers on it, then waits for them
+// to be ready. The services are reachable by the worker with their name as hostname
+func (h *HatcheryDocker) startServices(workerName, network string, services []hatchery.Service) error {
+	if out, err := exec.Command("docker", "network", "create", "--label", "worker_net="+workerName, network).CombinedOutput(); err != nil {
+		return fmt.Errorf("cannot create network %s: %s %s", network, err, out)
+	}
+
+	names := make([]string, 0, len(services))
+	for _, s := range services {
+		serviceName := s.Name + "-" + workerName
+		args := []string{"run", "-d",
+			fmt.Sprintf("--name=%s", serviceName),
+			fmt.Sprintf("--network=%s", network),
+			fmt.Sprintf("--network-alias=%s", s.Name),
+			fmt.Sprintf("--memory=%dm", s.Memory),
+			"--label", "service_worker=" + workerName,
+			"--label", "hatchery=" + h.Config.Name,
+		}
+		for _, e := range s.Env {
+			args = append(args, "-e", e)
+		}
+		args = append(args, s.Image)
+
+		log.Info("startServices> Starting service %s (%s) for worker %s", s.Name, s.Image, workerName)
+		if out, err := exec.Command("docker", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("cannot start service %s: %s %s", s.Name, err, out)
+		}
+		names = append(names, serviceName)
+	}
+
+	for _, name := range names {
+		if err := waitServiceReady(name, serviceReadyTimeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitServiceReady waits for a service container to be running, and healthy if its image has a healthcheck
+func waitServiceReady(name string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		out, err := exec.Command("docker", "inspect", "-f", "{{.State.Status}} {{if .State.Health}}{{.State.Health.Status}}{{end}}", name).Output()
+		if err != nil {
+			return fmt.Errorf("cannot inspect service %s: %s", name, err)
+		}
+
+		state := strings.Fields(string(out))
+		var status, health string
+		if len(state) > 0 {
+			status = state[0]
+		}
+		if len(state) > 1 {
+			health = state[1]
+		}
+
+		ready, errState := hatchery.ServiceReady(status, health)
+		if errState != nil {
+			return fmt.Errorf("service %s is not ready: %s", name, errState)
+		}
+		if ready {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("service %s is not ready after %s", name, timeout)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// removeServices removes the service containers and the network of a worker
+func removeServices(workerName, network string) {
+	out, err := exec.Command("docker", "ps", "-a", "-q", "--filter", "label=service_worker="+workerName).Output()
+	if err != nil {
+		log.Warning("removeServices> Cannot list services of worker %s: %s", workerName, err)
+	}
+	for _, id := range strings.Fields(string(out)) {
+		if err := exec.Command("docker", "rm", "-f", id).Run(); err != nil {
+			log.Warning("removeServices> Cannot remove service %s of worker %s: %s", id, workerName, err)
+		}
+	}
+
+	if err := exec.Command("docker", "network", "inspect", network).Run(); err != nil {
+		// No network: the worker has no service
+		return
+	}
+	if err := exec.Command("docker", "network", "rm", network).Run(); err != nil {
+		log.Warning("removeServices> Cannot remove network %s: %s", network, err)
+	}
+}
@@ -0,0 +1,221 @@
+package kubernetes
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/facebookgo/httpcontrol"
+)
+
+// Service account mounted in each pod, used when the hatchery runs inside the cluster
+const (
+	serviceAccountTokenFile = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+	serviceAccountCAFile    = "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt"
+)
+
+// kubernetesClient manages the pods of a namespace
+type kubernetesClient interface {
+	createPod(p *pod) error
+	deletePod(name string) error
+	listPods(labelSelector string) ([]pod, error)
+}
+
+// pod is the subset of the kubernetes v1.Pod used by the hatchery
+type pod struct {
+	APIVersion string     `json:"apiVersion,omitempty"`
+	Kind       string     `json:"kind,omitempty"`
+	Metadata   objectMeta `json:"metadata"`
+	Spec       podSpec    `json:"spec"`
+	Status     podStatus  `json:"status,omitempty"`
+}
+
+type objectMeta struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	CreationTimestamp *time.Time        `json:"creationTimestamp,omitempty"`
+}
+
+type podSpec struct {
+	RestartPolicy string      `json:"restartPolicy,omitempty"`
+	HostAliases   []hostAlias `json:"hostAliases,omitempty"`
+	Containers    []container `json:"containers"`
+}
+
+type hostAlias struct {
+	IP        string   `json:"ip"`
+	Hostnames []string `json:"hostnames"`
+}
+
+type container struct {
+	Name            string               `json:"name"`
+	Image           string               `json:"image"`
+	Command         []string             `json:"command,omitempty"`
+	Env             []envVar             `json:"env,omitempty"`
+	Resources       resourceRequirements `json:"resources,omitempty"`
+	ImagePullPolicy string               `json:"imagePullPolicy,omitempty"`
+}
+
+type envVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type resourceRequirements struct {
+	Limits   map[string]string `json:"limits,omitempty"`
+	Requests map[string]string `json:"requests,omitempty"`
+}
+
+type podStatus struct {
+	Phase string `json:"phase,omitempty"`
+}
+
+type podList struct {
+	Items []pod `json:"items"`
+}
+
+// Pod phases
+const (
+	podPending   = "Pending"
+	podSucceeded = "Succeeded"
+	podFailed    = "Failed"
+)
+
+// restClient calls the REST API of kubernetes master
+type restClient struct {
+	url        string
+	namespace  string
+	token      string
+	httpClient *http.Client
+}
+
+// newRestClient returns a client for kubernetes master. If the master url is empty, the in-cluster configuration is used
+func newRestClient(cfg HatcheryConfiguration) (*restClient, error) {
+	c := &restClient{
+		url:       strings.TrimSuffix(cfg.KubernetesMasterURL, "/"),
+		namespace: cfg.KubernetesNamespace,
+		token:     cfg.KubernetesToken,
+	}
+	caData := []byte(cfg.KubernetesCertAuthData)
+
+	if c.url == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			return nil, fmt.Errorf("Kubernetes master URL is mandatory outside of a kubernetes cluster")
+		}
+		c.url = "https://" + host + ":" + port
+
+		if c.token == "" {
+			btes, err := ioutil.ReadFile(serviceAccountTokenFile)
+			if err != nil {
+				return nil, fmt.Errorf("Unable to read service account token: %v", err)
+			}
+			c.token = strings.TrimSpace(string(btes))
+		}
+		if len(caData) == 0 {
+			btes, err := ioutil.ReadFile(serviceAccountCAFile)
+			if err != nil {
+				return nil, fmt.Errorf("Unable to read service account certificate authority: %v", err)
+			}
+			caData = btes
+		}
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.KubernetesInsecure}
+	if len(caData) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("Invalid kubernetes certificate authority")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	c.httpClient = &http.Client{
+		Transport: &httpcontrol.Transport{
+			RequestTimeout:  time.Minute,
+			MaxTries:        3,
+			TLSClientConfig: tlsConfig,
+		},
+	}
+	return c, nil
+}
+
+func (c *restClient) podsPath() string {
+	return fmt.Sprintf("%s/api/v1/namespaces/%s/pods", c.url, url.PathEscape(c.namespace))
+}
+
+func (c *restClient) do(method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		btes, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(btes)
+	}
+
+	req, err := http.NewRequest(method, path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	btes, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: HTTP %d: %s", method, path, resp.StatusCode, string(btes))
+	}
+
+	if out != nil {
+		return json.Unmarshal(btes, out)
+	}
+	return nil
+}
+
+func (c *restClient) createPod(p *pod) error {
+	p.APIVersion = "v1"
+	p.Kind = "Pod"
+	p.Metadata.Namespace = c.namespace
+	return c.do(http.MethodPost, c.podsPath(), p, p)
+}
+
+func (c *restClient) deletePod(name string) error {
+	return c.do(http.MethodDelete, c.podsPath()+"/"+url.PathEscape(name), nil, nil)
+}
+
+func (c *restClient) listPods(labelSelector string) ([]pod, error) {
+	path := c.podsPath()
+	if labelSelector != "" {
+		path += "?labelSelector=" + url.QueryEscape(labelSelector)
+	}
+	var l podList
+	if err := c.do(http.MethodGet, path, nil, &l); err != nil {
+		return nil, err
+	}
+	return l.Items, nil
+}
@@ -0,0 +1,451 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/pkg/namesgenerator"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Labels set on each pod spawned by the hatchery
+const (
+	labelHatchery    = "cds-hatchery"
+	labelWorkerModel = "cds-worker-model"
+)
+
+// Maximum number of pending pods, we don't spawn more workers while kubernetes is scheduling them
+const maxPendingPods = 10
+
+// New instanciates a new Hatchery Kubernetes
+func New() *HatcheryKubernetes {
+	return new(HatcheryKubernetes)
+}
+
+// ApplyConfiguration apply an object of type HatcheryConfiguration after checking it
+func (h *HatcheryKubernetes) ApplyConfiguration(cfg interface{}) error {
+	if err := h.CheckConfiguration(cfg); err != nil {
+		return err
+	}
+
+	var ok bool
+	h.Config, ok = cfg.(HatcheryConfiguration)
+	if !ok {
+		return fmt.Errorf("Invalid configuration")
+	}
+
+	return nil
+}
+
+// CheckConfiguration checks the validity of the configuration object
+func (h *HatcheryKubernetes) CheckConfiguration(cfg interface{}) error {
+	hconfig, ok := cfg.(HatcheryConfiguration)
+	if !ok {
+		return fmt.Errorf("Invalid configuration")
+	}
+
+	if hconfig.API.HTTP.URL == "" {
+		return fmt.Errorf("API HTTP(s) URL is mandatory")
+	}
+
+	if hconfig.API.Token == "" {
+		return fmt.Errorf("API Token URL is mandatory")
+	}
+
+	if hconfig.KubernetesNamespace == "" {
+		return fmt.Errorf("Kubernetes namespace is mandatory")
+	}
+
+	if hconfig.WorkerTTL <= 0 {
+		return fmt.Errorf("worker-ttl must be > 0")
+	}
+
+	if hconfig.DefaultMemory <= 1 {
+		return fmt.Errorf("worker-memory must be > 1")
+	}
+
+	return nil
+}
+
+// Serve start the HatcheryKubernetes server
+func (h *HatcheryKubernetes) Serve(ctx context.Context) error {
+	hatchery.Create(h)
+	return nil
+}
+
+// ID must returns hatchery id
+func (h *HatcheryKubernetes) ID() int64 {
+	if h.hatch == nil {
+		return 0
+	}
+	return h.hatch.ID
+}
+
+//Hatchery returns hatchery instance
+func (h *HatcheryKubernetes) Hatchery() *sdk.Hatchery {
+	return h.hatch
+}
+
+//Client returns cdsclient instance
+func (h *HatcheryKubernetes) Client() cdsclient.Interface {
+	return h.client
+}
+
+//Configuration returns Hatchery CommonConfiguration
+func (h *HatcheryKubernetes) Configuration() hatchery.CommonConfiguration {
+	return h.Config.CommonConfiguration
+}
+
+// ModelType returns type of hatchery
+func (*HatcheryKubernetes) ModelType() string {
+	return sdk.Docker
+}
+
+// NeedRegistration return true if worker model need regsitration
+func (h *HatcheryKubernetes) NeedRegistration(m *sdk.Model) bool {
+	if m.NeedRegistration || m.LastRegistration.Unix() < m.UserLastModified.Unix() {
+		return true
+	}
+	return false
+}
+
+// Init connects the hatchery to kubernetes master and starts killing routine of workers not registered
+func (h *HatcheryKubernetes) Init() error {
+	h.hatch = &sdk.Hatchery{
+		Name:    hatchery.GenerateName("kubernetes", h.Configuration().Name),
+		Version: sdk.VERSION,
+	}
+
+	h.client = cdsclient.NewHatchery(
+		h.Configuration().API.HTTP.URL,
+		h.Configuration().API.Token,
+		h.Configuration().Provision.RegisterFrequency,
+		h.Configuration().API.HTTP.Insecure,
+		h.hatch.Name,
+	)
+	if err := hatchery.Register(h); err != nil {
+		return fmt.Errorf("Cannot register: %s", err)
+	}
+
+	k8sClient, err := newRestClient(h.Config)
+	if err != nil {
+		return fmt.Errorf("Cannot connect to kubernetes: %s", err)
+	}
+	h.k8sClient = k8sClient
+
+	go h.killAwolWorkersRoutine()
+	return nil
+}
+
+var invalidNameChars = regexp.MustCompile("[^a-z0-9-]+")
+
+// kubernetesName returns a valid kubernetes name (RFC 1123 label) from s
+func kubernetesName(s string) string {
+	s = invalidNameChars.ReplaceAllString(strings.ToLower(s), "-")
+	if len(s) > 63 {
+		s = s[:63]
+	}
+	return strings.Trim(s, "-")
+}
+
+// hatcheryLabel returns the value of the label which identifies the pods of this hatchery
+func (h *HatcheryKubernetes) hatcheryLabel() string {
+	if name := kubernetesName(h.Config.Name); name != "" {
+		return name
+	}
+	return "kubernetes"
+}
+
+// listPods lists the pods spawned by this hatchery
+func (h *HatcheryKubernetes) listPods() ([]pod, error) {
+	return h.k8sClient.listPods(labelHatchery + "=" + h.hatcheryLabel())
+}
+
+// CanSpawn return wether or not hatchery can spawn model
+func (h *HatcheryKubernetes) CanSpawn(model *sdk.Model, jobID int64, requirements []sdk.Requirement) bool {
+	pods, err := h.listPods()
+	if err != nil {
+		log.Warning("CanSpawn> Unable to list pods: %s", err)
+		return false
+	}
+
+	var running, pending int
+	for _, p := range pods {
+		switch p.Status.Phase {
+		case podSucceeded, podFailed:
+			continue
+		case podPending:
+			pending++
+		}
+		running++
+	}
+
+	if running >= h.Configuration().Provision.MaxWorker {
+		log.Info("CanSpawn> max number of pods reached, aborting. Current: %d. Max: %d", running, h.Configuration().Provision.MaxWorker)
+		return false
+	}
+
+	// Do not DOS kubernetes
+	if pending >= maxPendingPods {
+		log.Info("CanSpawn> %d pods are pending, waiting", pending)
+		return false
+	}
+
+	return true
+}
+
+// SpawnWorker creates a pod running the worker and a sidecar container for each service requirement
+func (h *HatcheryKubernetes) SpawnWorker(model *sdk.Model, jobID int64, requirements []sdk.Requirement, registerOnly bool, logInfo string) (string, error) {
+	name := fmt.Sprintf("k8s-%s-%s", kubernetesName(model.Name), strings.Replace(namesgenerator.GetRandomName(0), "_", "-", -1))
+	if registerOnly {
+		name = "register-" + name
+	}
+
+	if jobID > 0 {
+		log.Info("SpawnWorker> spawning worker %s (%s) for job %d - %s", name, model.Image, jobID, logInfo)
+	} else {
+		log.Info("SpawnWorker> spawning worker %s (%s) - %s", name, model.Image, logInfo)
+	}
+
+	p, err := h.workerPod(name, model, jobID, requirements, registerOnly)
+	if err != nil {
+		return "", err
+	}
+
+	if err := h.k8sClient.createPod(p); err != nil {
+		return "", sdk.WrapError(err, "SpawnWorker> Unable to create pod %s", name)
+	}
+
+	return name, nil
+}
+
+// workerPod computes the pod of a worker
+func (h *HatcheryKubernetes) workerPod(name string, model *sdk.Model, jobID int64, requirements []sdk.Requirement, registerOnly bool) (*pod, error) {
+	//Memory for the worker
+	memory := int64(h.Config.DefaultMemory)
+
+	services := []container{}
+	aliases := []string{}
+
+	if jobID > 0 {
+		for _, r := range requirements {
+			switch r.Type {
+			case sdk.MemoryRequirement:
+				var err error
+				memory, err = strconv.ParseInt(r.Value, 10, 64)
+				if err != nil {
+					log.Warning("SpawnWorker> Unable to parse memory requirement %s: %s", r.Value, err)
+					return nil, err
+				}
+			case sdk.ServiceRequirement:
+				service, err := hatchery.ParseServiceRequirement(r)
+				if err != nil {
+					log.Warning("SpawnWorker> Unable to parse service requirement %s: %s", r.Name, err)
+					return nil, err
+				}
+				env := make([]envVar, 0, len(service.Env))
+				for _, e := range service.Env {
+					kv := strings.SplitN(e, "=", 2)
+					env = append(env, envVar{Name: kv[0], Value: kv[1]})
+				}
+
+				services = append(services, container{
+					Name:            "service-" + kubernetesName(service.Name),
+					Image:           service.Image,
+					Env:             env,
+					Resources:       memoryResources(service.Memory),
+					ImagePullPolicy: imagePullPolicy(service.Image),
+				})
+				aliases = append(aliases, r.Name)
+			}
+		}
+	}
+
+	var registerCmd string
+	if registerOnly {
+		registerCmd = " register"
+	}
+
+	//cmd is the command to start the worker (we need curl to download current version of the worker binary)
+	cmd := []string{"sh", "-c", fmt.Sprintf("curl %s/download/worker/`uname -m` -o worker && chmod +x worker && exec ./worker%s", h.Client().APIURL(), registerCmd)}
+
+	//CDS env needed by the worker binary
+	env := []envVar{
+		{Name: "CDS_API", Value: h.Configuration().API.HTTP.URL},
+		{Name: "CDS_NAME", Value: name},
+		{Name: "CDS_TOKEN", Value: h.Configuration().API.Token},
+		{Name: "CDS_MODEL", Value: strconv.FormatInt(model.ID, 10)},
+		{Name: "CDS_HATCHERY", Value: strconv.FormatInt(h.hatch.ID, 10)},
+		{Name: "CDS_HATCHERY_NAME", Value: h.hatch.Name},
+		{Name: "CDS_TTL", Value: strconv.Itoa(h.Config.WorkerTTL)},
+		{Name: "CDS_SINGLE_USE", Value: "1"},
+	}
+
+	if h.Configuration().Provision.WorkerLogsOptions.Graylog.Host != "" {
+		env = append(env, envVar{Name: "CDS_GRAYLOG_HOST", Value: h.Configuration().Provision.WorkerLogsOptions.Graylog.Host})
+	}
+	if h.Configuration().Provision.WorkerLogsOptions.Graylog.Port > 0 {
+		env = append(env, envVar{Name: "CDS_GRAYLOG_PORT", Value: strconv.Itoa(h.Configuration().Provision.WorkerLogsOptions.Graylog.Port)})
+	}
+	if h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraKey != "" {
+		env = append(env, envVar{Name: "CDS_GRAYLOG_EXTRA_KEY", Value: h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraKey})
+	}
+	if h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue != "" {
+		env = append(env, envVar{Name: "CDS_GRAYLOG_EXTRA_VALUE", Value: h.Configuration().Provision.WorkerLogsOptions.Graylog.ExtraValue})
+	}
+	if h.Configuration().API.GRPC.URL != "" && model.Communication == sdk.GRPC {
+		env = append(env, envVar{Name: "CDS_GRPC_API", Value: h.Configuration().API.GRPC.URL})
+		env = append(env, envVar{Name: "CDS_GRPC_INSECURE", Value: strconv.FormatBool(h.Configuration().API.GRPC.Insecure)})
+	}
+
+	if jobID > 0 {
+		env = append(env, envVar{Name: "CDS_BOOKED_JOB_ID", Value: strconv.FormatInt(jobID, 10)})
+	}
+
+	p := &pod{
+		Metadata: objectMeta{
+			Name: name,
+			Labels: map[string]string{
+				labelHatchery:    h.hatcheryLabel(),
+				labelWorkerModel: strconv.FormatInt(model.ID, 10),
+			},
+		},
+		Spec: podSpec{
+			RestartPolicy: "Never",
+			Containers: append([]container{{
+				Name:            "worker",
+				Image:           model.Image,
+				Command:         cmd,
+				Env:             env,
+				Resources:       memoryResources(memory),
+				ImagePullPolicy: imagePullPolicy(model.Image),
+			}}, services...),
+		},
+	}
+
+	// Containers of a pod share the same network: services are reachable on localhost with their alias
+	if len(aliases) > 0 {
+		p.Spec.HostAliases = []hostAlias{{IP: "127.0.0.1", Hostnames: aliases}}
+	}
+
+	return p, nil
+}
+
+// memoryResources requests the memory (in Mo) and sets a limit to 110% of it
+func memoryResources(memory int64) resourceRequirements {
+	//Memory is set to 1GB by default
+	if memory <= 4 {
+		memory = 1024
+	}
+	return resourceRequirements{
+		Requests: map[string]string{"memory": fmt.Sprintf("%dMi", memory)},
+		Limits:   map[string]string{"memory": fmt.Sprintf("%dMi", memory*110/100)},
+	}
+}
+
+func imagePullPolicy(image string) string {
+	if strings.HasSuffix(image, ":latest") || !strings.Contains(image, ":") {
+		return "Always"
+	}
+	return "IfNotPresent"
+}
+
+// WorkersStarted returns the number of instances started but
+// not necessarily register on CDS yet
+func (h *HatcheryKubernetes) WorkersStarted() int {
+	pods, err := h.listPods()
+	if err != nil {
+		log.Warning("WorkersStarted> Unable to list pods: %s", err)
+		return 0
+	}
+
+	var x int
+	for _, p := range pods {
+		if p.Status.Phase != podSucceeded && p.Status.Phase != podFailed {
+			x++
+		}
+	}
+	return x
+}
+
+// WorkersStartedByModel returns the number of instances of given model started but
+// not necessarily register on CDS yet
+func (h *HatcheryKubernetes) WorkersStartedByModel(model *sdk.Model) int {
+	pods, err := h.listPods()
+	if err != nil {
+		log.Warning("WorkersStartedByModel> Unable to list pods: %s", err)
+		return 0
+	}
+
+	modelID := strconv.FormatInt(model.ID, 10)
+	var x int
+	for _, p := range pods {
+		if p.Metadata.Labels[labelWorkerModel] == modelID && p.Status.Phase != podSucceeded && p.Status.Phase != podFailed {
+			x++
+		}
+	}
+	return x
+}
+
+func (h *HatcheryKubernetes) killAwolWorkersRoutine() {
+	for {
+		time.Sleep(10 * time.Second)
+		if err := h.killAwolWorkers(); err != nil {
+			log.Warning("Cannot kill awol workers: %s", err)
+		}
+	}
+}
+
+// killAwolWorkers deletes the pods which are terminated, the pods of disabled workers
+// and the pods of workers which are not registered after the spawn timeout
+func (h *HatcheryKubernetes) killAwolWorkers() error {
+	workers, err := h.Client().WorkerList()
+	if err != nil {
+		return err
+	}
+
+	pods, err := h.listPods()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pods {
+		var kill bool
+		switch p.Status.Phase {
+		case podSucceeded, podFailed:
+			kill = true
+		default:
+			var found bool
+			for _, w := range workers {
+				if w.Name == p.Metadata.Name {
+					found = true
+					kill = w.Status == sdk.StatusDisabled
+					break
+				}
+			}
+			// The worker is maybe still downloading its binary
+			if !found && p.Metadata.CreationTimestamp != nil {
+				kill = time.Since(*p.Metadata.CreationTimestamp) > time.Duration(h.Config.WorkerSpawnTimeout)*time.Second
+			}
+		}
+
+		if !kill {
+			continue
+		}
+
+		log.Info("killAwolWorkers> Delete pod %s (%s)", p.Metadata.Name, p.Status.Phase)
+		if err := h.k8sClient.deletePod(p.Metadata.Name); err != nil {
+			log.Warning("killAwolWorkers> Unable to delete pod %s: %s", p.Metadata.Name, err)
+		}
+	}
+
+	return nil
+}
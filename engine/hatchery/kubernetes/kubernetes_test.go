@@ -0,0 +1,205 @@
+package kubernetes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+type fakeKubernetesClient struct {
+	pods    []pod
+	deleted []string
+}
+
+func (c *fakeKubernetesClient) createPod(p *pod) error {
+	p.Status.Phase = podPending
+	now := time.Now()
+	p.Metadata.CreationTimestamp = &now
+	c.pods = append(c.pods, *p)
+	return nil
+}
+
+func (c *fakeKubernetesClient) deletePod(name string) error {
+	c.deleted = append(c.deleted, name)
+	for i := range c.pods {
+		if c.pods[i].Metadata.Name == name {
+			c.pods = append(c.pods[:i], c.pods[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (c *fakeKubernetesClient) listPods(labelSelector string) ([]pod, error) {
+	return append([]pod{}, c.pods...), nil
+}
+
+type fakeCDSClient struct {
+	cdsclient.Interface
+	workers []sdk.Worker
+}
+
+func (c *fakeCDSClient) APIURL() string {
+	return "http://cds-api"
+}
+
+func (c *fakeCDSClient) WorkerList() ([]sdk.Worker, error) {
+	return c.workers, nil
+}
+
+func newTestHatchery() (*HatcheryKubernetes, *fakeKubernetesClient, *fakeCDSClient) {
+	k8s := &fakeKubernetesClient{}
+	cds := &fakeCDSClient{}
+	h := &HatcheryKubernetes{
+		Config: HatcheryConfiguration{
+			CommonConfiguration: hatchery.CommonConfiguration{
+				Name: "My_Hatchery",
+			},
+			KubernetesNamespace: "cds",
+			DefaultMemory:       1024,
+			WorkerTTL:           10,
+			WorkerSpawnTimeout:  120,
+		},
+		hatch:     &sdk.Hatchery{ID: 1, Name: "my-hatchery"},
+		client:    cds,
+		k8sClient: k8s,
+	}
+	h.Config.Provision.MaxWorker = 2
+	return h, k8s, cds
+}
+
+func TestHatcheryKubernetes_SpawnWorker(t *testing.T) {
+	h, k8s, _ := newTestHatchery()
+
+	model := &sdk.Model{ID: 42, Name: "My Model", Image: "golang:1.9"}
+	requirements := []sdk.Requirement{
+		{Name: "Mem", Type: sdk.MemoryRequirement, Value: "2048"},
+		{Name: "pg", Type: sdk.ServiceRequirement, Value: "postgres:9.5 POSTGRES_PASSWORD=cds CDS_SERVICE_MEMORY=512"},
+	}
+
+	name, err := h.SpawnWorker(model, 666, requirements, false, "")
+	assert.NoError(t, err)
+	assert.Contains(t, name, "k8s-my-model-")
+	assert.Len(t, k8s.pods, 1)
+
+	p := k8s.pods[0]
+	assert.Equal(t, name, p.Metadata.Name)
+	assert.Equal(t, "my-hatchery", p.Metadata.Labels[labelHatchery])
+	assert.Equal(t, "42", p.Metadata.Labels[labelWorkerModel])
+	assert.Equal(t, "Never", p.Spec.RestartPolicy)
+	assert.Equal(t, []hostAlias{{IP: "127.0.0.1", Hostnames: []string{"pg"}}}, p.Spec.HostAliases)
+
+	assert.Len(t, p.Spec.Containers, 2)
+	worker := p.Spec.Containers[0]
+	assert.Equal(t, "golang:1.9", worker.Image)
+	assert.Equal(t, "2048Mi", worker.Resources.Requests["memory"])
+	assert.Equal(t, "2252Mi", worker.Resources.Limits["memory"])
+	assert.Contains(t, worker.Env, envVar{Name: "CDS_BOOKED_JOB_ID", Value: "666"})
+	assert.Contains(t, worker.Env, envVar{Name: "CDS_NAME", Value: name})
+	assert.Contains(t, worker.Command[2], "http://cds-api/download/worker/")
+
+	service := p.Spec.Containers[1]
+	assert.Equal(t, "service-pg", service.Name)
+	assert.Equal(t, "postgres:9.5", service.Image)
+	assert.Equal(t, []envVar{{Name: "POSTGRES_PASSWORD", Value: "cds"}}, service.Env)
+	assert.Equal(t, "512Mi", service.Resources.Requests["memory"])
+
+	name, err = h.SpawnWorker(model, 0, nil, true, "")
+	assert.NoError(t, err)
+	assert.Contains(t, name, "register-k8s-my-model-")
+	assert.Equal(t, "1024Mi", k8s.pods[1].Spec.Containers[0].Resources.Requests["memory"])
+	assert.Contains(t, k8s.pods[1].Spec.Containers[0].Command[2], "./worker register")
+}
+
+func TestHatcheryKubernetes_CanSpawn(t *testing.T) {
+	h, k8s, _ := newTestHatchery()
+	model := &sdk.Model{ID: 42, Name: "model", Image: "golang"}
+
+	assert.True(t, h.CanSpawn(model, 1, nil))
+	_, err := h.SpawnWorker(model, 1, nil, false, "")
+	assert.NoError(t, err)
+	assert.True(t, h.CanSpawn(model, 2, nil))
+	_, err = h.SpawnWorker(model, 2, nil, false, "")
+	assert.NoError(t, err)
+	assert.False(t, h.CanSpawn(model, 3, nil))
+
+	// A terminated pod is not a started worker
+	k8s.pods[0].Status.Phase = podSucceeded
+	assert.True(t, h.CanSpawn(model, 3, nil))
+	assert.Equal(t, 1, h.WorkersStarted())
+	assert.Equal(t, 1, h.WorkersStartedByModel(model))
+	assert.Equal(t, 0, h.WorkersStartedByModel(&sdk.Model{ID: 43}))
+}
+
+func TestHatcheryKubernetes_killAwolWorkers(t *testing.T) {
+	h, k8s, cds := newTestHatchery()
+	old := time.Now().Add(-time.Hour)
+	recent := time.Now()
+	k8s.pods = []pod{
+		{Metadata: objectMeta{Name: "finished", CreationTimestamp: &recent}, Status: podStatus{Phase: podSucceeded}},
+		{Metadata: objectMeta{Name: "disabled", CreationTimestamp: &old}, Status: podStatus{Phase: "Running"}},
+		{Metadata: objectMeta{Name: "building", CreationTimestamp: &old}, Status: podStatus{Phase: "Running"}},
+		{Metadata: objectMeta{Name: "starting", CreationTimestamp: &recent}, Status: podStatus{Phase: podPending}},
+		{Metadata: objectMeta{Name: "lost", CreationTimestamp: &old}, Status: podStatus{Phase: "Running"}},
+	}
+	cds.workers = []sdk.Worker{
+		{Name: "disabled", Status: sdk.StatusDisabled},
+		{Name: "building", Status: sdk.StatusBuilding},
+	}
+
+	assert.NoError(t, h.killAwolWorkers())
+	assert.Equal(t, []string{"finished", "disabled", "lost"}, k8s.deleted)
+}
+
+func TestRestClient(t *testing.T) {
+	var methods []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method+" "+r.URL.String())
+		assert.Equal(t, "Bearer my-token", r.Header.Get("Authorization"))
+		switch r.Method {
+		case http.MethodPost:
+			var p pod
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&p))
+			assert.Equal(t, "Pod", p.Kind)
+			assert.Equal(t, "my-ns", p.Metadata.Namespace)
+			p.Status.Phase = podPending
+			json.NewEncoder(w).Encode(p)
+		case http.MethodGet:
+			json.NewEncoder(w).Encode(podList{Items: []pod{{Metadata: objectMeta{Name: "my-pod"}}}})
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer ts.Close()
+
+	c, err := newRestClient(HatcheryConfiguration{
+		KubernetesMasterURL: ts.URL + "/",
+		KubernetesNamespace: "my-ns",
+		KubernetesToken:     "my-token",
+	})
+	assert.NoError(t, err)
+
+	p := &pod{Metadata: objectMeta{Name: "my-pod"}}
+	assert.NoError(t, c.createPod(p))
+	assert.Equal(t, podPending, p.Status.Phase)
+
+	pods, err := c.listPods("cds-hatchery=my-hatchery")
+	assert.NoError(t, err)
+	assert.Len(t, pods, 1)
+
+	assert.Error(t, c.deletePod("my-pod"))
+
+	assert.Equal(t, []string{
+		"POST /api/v1/namespaces/my-ns/pods",
+		"GET /api/v1/namespaces/my-ns/pods?labelSelector=cds-hatchery%3Dmy-hatchery",
+		"DELETE /api/v1/namespaces/my-ns/pods/my-pod",
+	}, methods)
+}
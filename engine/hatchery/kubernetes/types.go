@@ -0,0 +1,45 @@
+package kubernetes
+
+import (
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+	"github.com/ovh/cds/sdk/hatchery"
+)
+
+// HatcheryConfiguration is the configuration for hatchery
+type HatcheryConfiguration struct {
+	hatchery.CommonConfiguration `mapstructure:"commonConfiguration" toml:"commonConfiguration"`
+
+	// KubernetesMasterURL Address of kubernetes master
+	KubernetesMasterURL string `mapstructure:"kubernetesMasterURL" toml:"kubernetesMasterURL" default:"" commented:"false" comment:"Address of kubernetes master. Let it empty if the hatchery runs inside the cluster, the service account of the pod will be used"`
+
+	// KubernetesNamespace is the kubernetes namespace in which workers are spawned"
+	KubernetesNamespace string `mapstructure:"namespace" toml:"namespace" default:"cds" commented:"false" comment:"Kubernetes namespace in which workers are spawned"`
+
+	// KubernetesToken Bearer token used to authenticate on kubernetes master
+	KubernetesToken string `mapstructure:"token" toml:"token" default:"" commented:"true" comment:"Bearer token used to authenticate on kubernetes master"`
+
+	// KubernetesCertAuthData Certificate authority used to check the certificate of kubernetes master
+	KubernetesCertAuthData string `mapstructure:"certAuthorityData" toml:"certAuthorityData" default:"" commented:"true" comment:"PEM encoded certificate authority used to check the certificate of kubernetes master"`
+
+	// KubernetesInsecure skips the verification of the certificate of kubernetes master
+	KubernetesInsecure bool `mapstructure:"insecure" toml:"insecure" default:"false" commented:"true" comment:"Skip the verification of the certificate of kubernetes master"`
+
+	// DefaultMemory Worker default memory
+	DefaultMemory int `mapstructure:"defaultMemory" toml:"defaultMemory" default:"1024" commented:"false" comment:"Worker default memory in Mo"`
+
+	// WorkerTTL Worker TTL (minutes)
+	WorkerTTL int `mapstructure:"workerTTL" toml:"workerTTL" default:"10" commented:"false" comment:"Worker TTL (minutes)"`
+
+	// WorkerSpawnTimeout Worker Timeout Spawning (seconds)
+	WorkerSpawnTimeout int `mapstructure:"workerSpawnTimeout" toml:"workerSpawnTimeout" default:"120" commented:"false" comment:"Worker Timeout Spawning (seconds). A pod without registered worker after this delay is deleted"`
+}
+
+// HatcheryKubernetes implements HatcheryMode interface for kubernetes mode
+type HatcheryKubernetes struct {
+	Config HatcheryConfiguration
+	hatch  *sdk.Hatchery
+	client cdsclient.Interface
+
+	k8sClient kubernetesClient
+}
@@ -0,0 +1,97 @@
+package swarm
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/fsouza/go-dockerclient"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/hatchery"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// defaultServiceReadyTimeout is the maximum wait for the services of a job when it is not configured
+const defaultServiceReadyTimeout = 2 * time.Minute
+
+// startServices starts the service containers of a worker on its network, then waits for them to be ready.
+// The services are reachable by the worker with their name as hostname
+func (h *HatcherySwarm) startServices(workerName, network string, services []hatchery.Service) ([]string, error) {
+	names := make([]string, 0, len(services))
+	for _, s := range services {
+		serviceName := s.Name + "-" + workerName
+
+		//labels are used to make container cleanup easier. We "link" the service to its worker this way.
+		labels := map[string]string{
+			"service_worker": workerName,
+			"service_name":   serviceName,
+			"hatchery":       h.Config.Name,
+		}
+		if err := h.createAndStartContainer(serviceName, s.Image, network, s.Name, []string{}, s.Env, labels, s.Memory); err != nil {
+			return names, sdk.WrapError(err, "startServices> Unable to start service %s", s.Name)
+		}
+		names = append(names, serviceName)
+	}
+
+	timeout := time.Duration(h.Config.ServiceReadyTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = defaultServiceReadyTimeout
+	}
+	for _, name := range names {
+		if err := h.waitServiceReady(name, timeout); err != nil {
+			return names, err
+		}
+	}
+	return names, nil
+}
+
+// waitServiceReady waits for a service container to be running, and healthy if its image has a healthcheck
+func (h *HatcherySwarm) waitServiceReady(name string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		c, err := h.dockerClient.InspectContainer(name)
+		if err != nil {
+			return sdk.WrapError(err, "waitServiceReady> Unable to inspect service %s", name)
+		}
+
+		ready, errState := hatchery.ServiceReady(c.State.Status, c.State.Health.Status)
+		if errState != nil {
+			return sdk.WrapError(errState, "waitServiceReady> Service %s is not ready (exit code %d)", name, c.State.ExitCode)
+		}
+		if ready {
+			log.Debug("waitServiceReady> Service %s is ready", name)
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("waitServiceReady> Service %s is not ready after %s", name, timeout)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// removeServices kills and removes the service containers and the network of a worker
+func (h *HatcherySwarm) removeServices(workerName, network string) {
+	containers, err := h.dockerClient.ListContainers(docker.ListContainersOptions{
+		All:     true,
+		Filters: map[string][]string{"label": {"service_worker=" + workerName}},
+	})
+	if err != nil {
+		log.Warning("removeServices> Unable to list services of worker %s: %s", workerName, err)
+	}
+	for _, c := range containers {
+		h.killAndRemoveContainer(c.ID)
+	}
+	h.removeNetwork(network)
+}
+
+// removeNetwork removes a worker network, once all its containers are removed
+func (h *HatcherySwarm) removeNetwork(network string) {
+	log.Info("removeNetwork> Remove network %s", network)
+	if err := h.dockerClient.RemoveNetwork(network); err != nil {
+		if !strings.Contains(err.Error(), "No such network") && !strings.Contains(err.Error(), "not found") {
+			log.Warning("removeNetwork> Unable to remove network %s: %s", network, err)
+		}
+	}
+}
@@ -206,6 +206,7 @@ func (h *HatcherySwarm) killAndRemove(ID string) {
 			for id := range network.Containers {
 				h.killAndRemoveContainer(id)
 			}
+			h.removeNetwork(network.ID)
 		}
 	}
 }
@@ -220,15 +221,10 @@ func (h *HatcherySwarm) SpawnWorker(model *sdk.Model, jobID int64, requirements
 
 	log.Info("SpawnWorker> Spawning worker %s - %s", name, logInfo)
 
-	//Create a network
-	network := name + "-net"
-	h.createNetwork(network)
-
 	//Memory for the worker
 	memory := int64(h.Config.DefaultMemory)
 
-	services := []string{}
-
+	var services []hatchery.Service
 	if jobID > 0 {
 		for _, r := range requirements {
 			if r.Type == sdk.MemoryRequirement {
@@ -238,44 +234,30 @@ func (h *HatcherySwarm) SpawnWorker(model *sdk.Model, jobID int64, requirements
 					log.Warning("SpawnWorker>Unable to parse memory requirement %s :s", memory, err)
 					return "", err
 				}
-			} else if r.Type == sdk.ServiceRequirement {
-				//name= <alias> => the name of the host put in /etc/hosts of the worker
-				//value= "postgres:latest env_1=blabla env_2=blabla"" => we can add env variables in requirement name
-				tuple := strings.Split(r.Value, " ")
-				img := tuple[0]
-				env := []string{}
-				serviceMemory := int64(1024)
-				if len(tuple) > 1 {
-					env = append(env, tuple[1:]...)
-				}
-				//option for power user : set the service memory with CDS_SERVICE_MEMORY=1024
-				for _, e := range env {
-					if strings.HasPrefix(e, "CDS_SERVICE_MEMORY=") {
-						m := strings.Replace(e, "CDS_SERVICE_MEMORY=", "", -1)
-						i, err := strconv.Atoi(m)
-						if err != nil {
-							log.Warning("SpawnWorker> Unable to parse service option %s : %s", e, err)
-							continue
-						}
-						serviceMemory = int64(i)
-					}
-				}
-				serviceName := r.Name + "-" + name
-
-				//labels are used to make container cleanup easier. We "link" the service to its worker this way.
-				labels := map[string]string{
-					"service_worker": name,
-					"service_name":   serviceName,
-					"hatchery":       h.Config.Name,
-				}
-				//Start the services
-				if err := h.createAndStartContainer(serviceName, img, network, r.Name, []string{}, env, labels, serviceMemory); err != nil {
-					log.Warning("SpawnWorker>Unable to start required container: %s", err)
-					return "", err
-				}
-				services = append(services, serviceName)
 			}
 		}
+
+		var errS error
+		services, errS = hatchery.ServicesFromRequirements(requirements)
+		if errS != nil {
+			log.Warning("SpawnWorker> Unable to parse service requirements: %s", errS)
+			return "", errS
+		}
+	}
+
+	//Create a network for the worker and its services
+	network := name + "-net"
+	if err := h.createNetwork(network); err != nil {
+		log.Warning("SpawnWorker> Unable to create network %s: %s", network, err)
+		return "", err
+	}
+
+	//Start the services and wait for them to be ready
+	serviceNames, errS := h.startServices(name, network, services)
+	if errS != nil {
+		log.Warning("SpawnWorker> Unable to start services: %s", errS)
+		h.removeServices(name, network)
+		return "", errS
 	}
 
 	var registerCmd string
@@ -323,13 +305,16 @@ func (h *HatcherySwarm) SpawnWorker(model *sdk.Model, jobID int64, requirements
 	labels := map[string]string{
 		"worker_model":        strconv.FormatInt(model.ID, 10),
 		"worker_name":         name,
-		"worker_requirements": strings.Join(services, ","),
+		"worker_requirements": strings.Join(serviceNames, ","),
 		"hatchery":            h.Config.Name,
 	}
 
 	//start the worker
 	if err := h.createAndStartContainer(name, model.Image, network, "worker", cmd, env, labels, memory); err != nil {
 		log.Warning("SpawnWorker> Unable to start container named %s with image %s err:%s", name, model.Image, err)
+		h.killAndRemoveContainer(name)
+		h.removeServices(name, network)
+		return "", err
 	}
 
 	return name, nil
@@ -662,20 +647,21 @@ func (h *HatcherySwarm) killAwolWorker() {
 	}
 
 	//Checking services
+	oldServices := []docker.APIContainers{}
 	for _, c := range containers {
 		if c.Labels["service_worker"] == "" {
 			continue
 		}
 		//check if the service is linked to a worker which doesn't exist
 		if w, _ := h.getContainer(c.Labels["service_worker"]); w == nil {
-			oldContainers = append(oldContainers, c)
+			oldServices = append(oldServices, c)
 			continue
 		}
 	}
 
-	for _, c := range oldContainers {
+	for _, c := range oldServices {
 		h.killAndRemove(c.ID)
-		log.Info("killAwolWorker> Delete worker %s", c.Names[0])
+		log.Info("killAwolWorker> Delete service %s", c.Names[0])
 	}
 
 	//Checking networks
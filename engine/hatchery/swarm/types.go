@@ -23,6 +23,9 @@ type HatcheryConfiguration struct {
 
 	// WorkerTTL Worker TTL (minutes)
 	WorkerTTL int `mapstructure:"workerTTL" toml:"workerTTL" default:"10" commented:"false" comment:"Worker TTL (minutes)"`
+
+	// ServiceReadyTimeout Maximum wait for the services of a job to be ready (seconds)
+	ServiceReadyTimeout int `mapstructure:"serviceReadyTimeout" toml:"serviceReadyTimeout" default:"120" commented:"false" comment:"Maximum wait for the services of a job to be ready (seconds)"`
 }
 
 // HatcherySwarm is a hatchery which can be connected to a remote to a docker remote api
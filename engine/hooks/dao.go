@@ -44,16 +44,17 @@ func (d *dao) SaveTask(r *Task) {
 
 func (d *dao) DeleteTask(r *Task) {
 	d.store.SetRemove(rootKey, r.UUID, r)
+	d.store.Delete(cache.Key(repoPollerStateRootKey, r.UUID))
 	execs, _ := d.FindAllTaskExecutions(r)
 	for _, e := range execs {
 		d.DeleteTaskExecution(&e)
 	}
 }
 
-func (d *dao) SaveTaskExecution(r *TaskExecution) {
+func (d *dao) SaveTaskExecution(r *TaskExecution) error {
 	setKey := cache.Key(executionRootKey, r.Type, r.UUID)
 	execKey := fmt.Sprintf("%d", r.Timestamp)
-	d.store.SetAdd(setKey, execKey, r)
+	return d.store.SetAdd(setKey, execKey, r)
 }
 
 func (d *dao) DeleteTaskExecution(r *TaskExecution) {
@@ -84,3 +85,16 @@ func (d *dao) FindAllTaskExecutions(t *Task) ([]TaskExecution, error) {
 
 	return allexecs, nil
 }
+
+func (d *dao) FindRepoPollerState(uuid string) *RepoPollerState {
+	key := cache.Key(repoPollerStateRootKey, uuid)
+	st := &RepoPollerState{}
+	if d.store.Get(key, st) {
+		return st
+	}
+	return nil
+}
+
+func (d *dao) SaveRepoPollerState(uuid string, st *RepoPollerState) {
+	d.store.SetWithTTL(cache.Key(repoPollerStateRootKey, uuid), st, -1)
+}
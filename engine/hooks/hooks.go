@@ -56,6 +56,8 @@ func (s *Service) Serve(c context.Context) error {
 	defer cancel()
 
 	log.Info("Hooks> Starting service %s...", s.Cfg.Name)
+	s.background = ctx
+	s.consumers = map[string]*messageQueueTask{}
 
 	//Instanciate a cds client
 	s.cds = cdsclient.NewService(s.Cfg.API.HTTP.URL)
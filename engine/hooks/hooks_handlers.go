@@ -37,6 +37,11 @@ func (s *Service) webhookHandler() api.Handler {
 			return sdk.WrapError(err, "Hook> webhookHandler> unable to read request")
 		}
 
+		//Check the signature of the request if the webhook has a secret
+		if err := checkWebHookSignature(webHook.Config["secret"], r.Header, req); err != nil {
+			return sdk.WrapError(sdk.ErrUnauthorized, "Hook> webhookHandler> %s: %v", uuid, err)
+		}
+
 		//Prepare a web hook execution
 		exec := &TaskExecution{
 			Timestamp: time.Now().UnixNano(),
@@ -143,8 +148,8 @@ func (s *Service) deleteTaskHandler() api.Handler {
 
 		//Load the task
 		t := s.Dao.FindTask(uuid)
-		if t != nil {
-			return api.WriteJSON(w, r, t, http.StatusOK)
+		if t == nil {
+			return sdk.WrapError(sdk.ErrNotFound, "Hook> deleteTaskHandler> unknown uuid")
 		}
 
 		//Stop the task
@@ -0,0 +1,235 @@
+package hooks
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/streadway/amqp"
+	"gopkg.in/bsm/sarama-cluster.v2"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Delay before reconnecting a consumer after an error
+const messageQueueRetryDelay = 10 * time.Second
+
+// messageQueueConsumer consumes messages from a kafka topic or an amqp queue.
+// A message is acknowledged only once the handler has returned without error, so the delivery is at-least-once
+type messageQueueConsumer interface {
+	// consume blocks until the context is done or an error occurs
+	consume(ctx context.Context, handler func(*MessageQueueExecution) error) error
+}
+
+func newMessageQueueConsumer(t *Task) (messageQueueConsumer, error) {
+	switch t.Type {
+	case TypeKafka:
+		if t.Config["brokers"] == "" || t.Config["topic"] == "" || t.Config["consumerGroup"] == "" {
+			return nil, fmt.Errorf("Invalid kafka configuration: brokers, topic and consumerGroup are mandatory")
+		}
+		return &kafkaConsumer{
+			brokers:  strings.Split(t.Config["brokers"], ","),
+			topic:    t.Config["topic"],
+			group:    t.Config["consumerGroup"],
+			user:     t.Config["username"],
+			password: t.Config["password"],
+		}, nil
+	case TypeAMQP:
+		if t.Config["url"] == "" || t.Config["queue"] == "" {
+			return nil, fmt.Errorf("Invalid amqp configuration: url and queue are mandatory")
+		}
+		return &amqpConsumer{
+			url:         t.Config["url"],
+			queue:       t.Config["queue"],
+			consumerTag: t.Config["consumerTag"],
+		}, nil
+	}
+	return nil, fmt.Errorf("Unsupported message queue task type %s", t.Type)
+}
+
+// messageQueueTask is a running consumer, with the configuration of the task it has been started with
+type messageQueueTask struct {
+	config sdk.WorkflowNodeHookConfig
+	cancel context.CancelFunc
+}
+
+// startMessageQueueTask starts the consumer of the task. A running consumer is restarted if the configuration of the task has changed
+func (s *Service) startMessageQueueTask(t *Task) error {
+	s.consumersMutex.Lock()
+	defer s.consumersMutex.Unlock()
+
+	if running, ok := s.consumers[t.UUID]; ok {
+		if reflect.DeepEqual(running.config, t.Config) {
+			return nil
+		}
+		log.Info("Hooks> %s task %s configuration has changed, restarting consumer", t.Type, t.UUID)
+		running.cancel()
+		delete(s.consumers, t.UUID)
+	}
+
+	consumer, err := newMessageQueueConsumer(t)
+	if err != nil {
+		return sdk.WrapError(err, "startTask> Unable to start task %s", t.UUID)
+	}
+
+	ctx, cancel := context.WithCancel(s.background)
+	s.consumers[t.UUID] = &messageQueueTask{config: t.Config, cancel: cancel}
+	go s.runMessageQueueConsumer(ctx, *t, consumer)
+
+	log.Debug("Hooks> %s task %s consuming", t.Type, t.UUID)
+	return nil
+}
+
+// stopMessageQueueTask stops the consumer of the task
+func (s *Service) stopMessageQueueTask(t *Task) {
+	s.consumersMutex.Lock()
+	defer s.consumersMutex.Unlock()
+
+	if running, ok := s.consumers[t.UUID]; ok {
+		running.cancel()
+		delete(s.consumers, t.UUID)
+	}
+}
+
+// runMessageQueueConsumer saves and enqueues a task execution for each message. The consumer is restarted on error
+func (s *Service) runMessageQueueConsumer(ctx context.Context, t Task, consumer messageQueueConsumer) {
+	for {
+		err := consumer.consume(ctx, func(m *MessageQueueExecution) error {
+			exec := &TaskExecution{
+				Timestamp:    time.Now().UnixNano(),
+				Type:         t.Type,
+				UUID:         t.UUID,
+				Config:       t.Config,
+				MessageQueue: m,
+			}
+
+			//Save the execution before acknowledging the message, the scheduler will process it.
+			//The message is not acknowledged if the execution can't be saved, it will be consumed again
+			if err := s.Dao.SaveTaskExecution(exec); err != nil {
+				return sdk.WrapError(err, "Hooks> %s task %s: Unable to save execution", t.Type, t.UUID)
+			}
+			s.Dao.EnqueueTaskExecution(exec)
+			return nil
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Error("Hooks> %s task %s: consumer error: %v", t.Type, t.UUID, err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(messageQueueRetryDelay):
+		}
+	}
+}
+
+type kafkaConsumer struct {
+	brokers  []string
+	topic    string
+	group    string
+	user     string
+	password string
+}
+
+func (c *kafkaConsumer) consume(ctx context.Context, handler func(*MessageQueueExecution) error) error {
+	var config = sarama.NewConfig()
+	if c.user != "" {
+		config.Net.TLS.Enable = true
+		config.Net.SASL.Enable = true
+		config.Net.SASL.User = c.user
+		config.Net.SASL.Password = c.password
+		config.ClientID = c.user
+	}
+	config.Version = sarama.V0_10_0_1
+
+	clusterConfig := cluster.NewConfig()
+	clusterConfig.Config = *config
+	clusterConfig.Consumer.Return.Errors = true
+
+	consumer, err := cluster.NewConsumer(c.brokers, c.group, []string{c.topic}, clusterConfig)
+	if err != nil {
+		return fmt.Errorf("Unable to create kafka consumer: %v", err)
+	}
+	defer consumer.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case err := <-consumer.Errors():
+			log.Warning("Hooks> kafka consumer on topic %s: %v", c.topic, err)
+		case msg, ok := <-consumer.Messages():
+			if !ok {
+				return fmt.Errorf("kafka consumer closed")
+			}
+			m := &MessageQueueExecution{
+				Topic:     msg.Topic,
+				Partition: msg.Partition,
+				Offset:    msg.Offset,
+				Key:       string(msg.Key),
+				Message:   msg.Value,
+			}
+			if err := handler(m); err != nil {
+				return err
+			}
+			consumer.MarkOffset(msg, "")
+		}
+	}
+}
+
+type amqpConsumer struct {
+	url         string
+	queue       string
+	consumerTag string
+}
+
+func (c *amqpConsumer) consume(ctx context.Context, handler func(*MessageQueueExecution) error) error {
+	conn, err := amqp.Dial(c.url)
+	if err != nil {
+		return fmt.Errorf("Unable to connect to amqp: %v", err)
+	}
+	defer conn.Close()
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return fmt.Errorf("Unable to open amqp channel: %v", err)
+	}
+	defer ch.Close()
+
+	if err := ch.Qos(1, 0, false); err != nil {
+		return fmt.Errorf("Unable to set amqp qos: %v", err)
+	}
+
+	deliveries, err := ch.Consume(c.queue, c.consumerTag, false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("Unable to consume amqp queue %s: %v", c.queue, err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case d, ok := <-deliveries:
+			if !ok {
+				return fmt.Errorf("amqp channel closed")
+			}
+			m := &MessageQueueExecution{
+				Topic:   c.queue,
+				Key:     d.RoutingKey,
+				Message: d.Body,
+			}
+			if err := handler(m); err != nil {
+				d.Nack(false, true)
+				return err
+			}
+			if err := d.Ack(false); err != nil {
+				return fmt.Errorf("Unable to ack amqp message: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package hooks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_startMessageQueueTask(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s := &Service{background: ctx, consumers: map[string]*messageQueueTask{}}
+
+	task := &Task{
+		UUID:   "uuid",
+		Type:   TypeAMQP,
+		Config: sdk.WorkflowNodeHookConfig{"url": "amqp://127.0.0.1:1/", "queue": "builds"},
+	}
+	assert.NoError(t, s.startMessageQueueTask(task))
+	running := s.consumers[task.UUID]
+
+	// The same hook is posted again: the consumer keeps running
+	assert.NoError(t, s.startMessageQueueTask(&Task{UUID: task.UUID, Type: TypeAMQP, Config: sdk.WorkflowNodeHookConfig{"url": "amqp://127.0.0.1:1/", "queue": "builds"}}))
+	assert.True(t, running == s.consumers[task.UUID])
+
+	// The queue has changed: the consumer is restarted
+	task.Config = sdk.WorkflowNodeHookConfig{"url": "amqp://127.0.0.1:1/", "queue": "deployments"}
+	assert.NoError(t, s.startMessageQueueTask(task))
+	assert.False(t, running == s.consumers[task.UUID])
+	assert.Equal(t, "deployments", s.consumers[task.UUID].config["queue"])
+
+	s.stopMessageQueueTask(task)
+	assert.Empty(t, s.consumers)
+}
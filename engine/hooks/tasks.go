@@ -7,6 +7,10 @@ import (
 	"mime"
 	"net/http"
 	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/fsamin/go-dump"
@@ -20,14 +24,22 @@ import (
 
 //This are all the types
 const (
-	TypeWebHook   = "Webhook"
-	TypeScheduler = "Scheduler"
+	TypeWebHook    = "Webhook"
+	TypeScheduler  = "Scheduler"
+	TypeRepoPoller = "RepoPoller"
+	TypeKafka      = "Kafka"
+	TypeAMQP       = "AMQP"
+
+	defaultRepoPollerInterval = 60
+	minRepoPollerInterval     = 10
 )
 
 var (
 	rootKey           = cache.Key("hooks", "tasks")
 	executionRootKey  = cache.Key("hooks", "tasks", "executions")
 	schedulerQueueKey = cache.Key("hooks", "scheduler", "queue")
+
+	repoPollerStateRootKey = cache.Key("hooks", "repopoller", "state")
 )
 
 // runTasks should run as a long-running goroutine
@@ -113,6 +125,24 @@ func (s *Service) hookToTask(h sdk.WorkflowNodeHook) (*Task, error) {
 			Type:   TypeScheduler,
 			Config: h.Config,
 		}, nil
+	case workflow.GitPollerModel.Name:
+		return &Task{
+			UUID:   h.UUID,
+			Type:   TypeRepoPoller,
+			Config: h.Config,
+		}, nil
+	case workflow.KafkaHookModel.Name:
+		return &Task{
+			UUID:   h.UUID,
+			Type:   TypeKafka,
+			Config: h.Config,
+		}, nil
+	case workflow.AMQPHookModel.Name:
+		return &Task{
+			UUID:   h.UUID,
+			Type:   TypeAMQP,
+			Config: h.Config,
+		}, nil
 	}
 
 	return nil, fmt.Errorf("Unsupported hook: %s", h.WorkflowHookModel.Name)
@@ -151,6 +181,10 @@ func (s *Service) startTask(ctx context.Context, t *Task) error {
 		return nil
 	case TypeScheduler:
 		return s.prepareNextScheduledTaskExecution(t)
+	case TypeRepoPoller:
+		return s.prepareNextRepoPollerExecution(t)
+	case TypeKafka, TypeAMQP:
+		return s.startMessageQueueTask(t)
 	default:
 		return fmt.Errorf("Unsupported task type %s", t.Type)
 	}
@@ -208,13 +242,74 @@ func (s *Service) prepareNextScheduledTaskExecution(t *Task) error {
 	return nil
 }
 
+func (s *Service) prepareNextRepoPollerExecution(t *Task) error {
+	if t.Stopped {
+		return nil
+	}
+
+	//Load the last execution of this task
+	execs, err := s.Dao.FindAllTaskExecutions(t)
+	if err != nil {
+		return sdk.WrapError(err, "startTask> unable to load last executions")
+	}
+
+	//The last execution has not been executed, let it go
+	if len(execs) > 0 && execs[len(execs)-1].ProcessingTimestamp == 0 {
+		log.Debug("Hooks> Repository poller %s ready. Next execution scheduled on %v", t.UUID, time.Unix(0, execs[len(execs)-1].Timestamp))
+		return nil
+	}
+
+	interval, err := repoPollerInterval(t.Config)
+	if err != nil {
+		return sdk.WrapError(err, "startTask> invalid interval: %s", t.Config["interval"])
+	}
+
+	//Craft a new execution
+	t1 := time.Now().Add(interval)
+	exec := &TaskExecution{
+		Timestamp: t1.UnixNano(),
+		Type:      t.Type,
+		UUID:      t.UUID,
+		Config:    t.Config,
+		RepoPoller: &RepoPollerExecution{
+			DateScheduledExecution: fmt.Sprintf("%v", t1),
+		},
+	}
+
+	s.Dao.SaveTaskExecution(exec)
+	//We don't push in queue, we will the scheduler to run it
+
+	log.Debug("Hooks> Repository poller %v ready. Next execution scheduled on %v", t.UUID, time.Unix(0, exec.Timestamp))
+
+	return nil
+}
+
+// repoPollerInterval returns the polling interval of a repository poller task, in seconds in the configuration
+func repoPollerInterval(config sdk.WorkflowNodeHookConfig) (time.Duration, error) {
+	if config["interval"] == "" {
+		return defaultRepoPollerInterval * time.Second, nil
+	}
+	i, err := strconv.Atoi(config["interval"])
+	if err != nil {
+		return 0, err
+	}
+	if i < minRepoPollerInterval {
+		i = minRepoPollerInterval
+	}
+	return time.Duration(i) * time.Second, nil
+}
+
 func (s *Service) stopTask(ctx context.Context, t *Task) error {
 	log.Info("Hooks> Stopping task %s", t.UUID)
 	t.Stopped = true
 	s.Dao.SaveTask(t)
 
 	switch t.Type {
-	case TypeWebHook, TypeScheduler:
+	case TypeWebHook, TypeScheduler, TypeRepoPoller:
+		log.Debug("Hooks> Tasks %s has been stopped", t.UUID)
+		return nil
+	case TypeKafka, TypeAMQP:
+		s.stopMessageQueueTask(t)
 		log.Debug("Hooks> Tasks %s has been stopped", t.UUID)
 		return nil
 	default:
@@ -228,6 +323,7 @@ func (s *Service) doTask(ctx context.Context, t *Task, e *TaskExecution) error {
 	}
 
 	var h *sdk.WorkflowNodeRunHookEvent
+	var hs []sdk.WorkflowNodeRunHookEvent
 	var err error
 
 	switch {
@@ -235,6 +331,10 @@ func (s *Service) doTask(ctx context.Context, t *Task, e *TaskExecution) error {
 		h, err = s.doWebHookExecution(e)
 	case e.ScheduledTask != nil:
 		h, err = s.doScheduledTaskExecution(e)
+	case e.RepoPoller != nil:
+		hs, err = s.doRepoPollerExecution(e)
+	case e.MessageQueue != nil:
+		h, err = s.doMessageQueueExecution(e)
 	default:
 		err = fmt.Errorf("Unsupported task type %s", e.Type)
 	}
@@ -243,17 +343,272 @@ func (s *Service) doTask(ctx context.Context, t *Task, e *TaskExecution) error {
 		return err
 	}
 
-	// Call CDS API
-	run, err := s.cds.WorkflowRunFromHook(t.Config["project"], t.Config["workflow"], *h)
+	if h != nil {
+		hs = append(hs, *h)
+	}
+
+	//A failed trigger does not prevent the other events to be triggered
+	var errRun error
+	for _, h := range hs {
+		// Call CDS API
+		run, err := s.cds.WorkflowRunFromHook(t.Config["project"], t.Config["workflow"], h)
+		if err != nil {
+			errRun = sdk.WrapError(err, "Hooks> Unable to run workflow")
+			continue
+		}
+
+		//Save the run number
+		e.WorkflowRun = run.Number
+		log.Info("Hooks> workflow %s/%s#%d has been triggered", t.Config["project"], t.Config["workflow"], run.Number)
+
+		if e.RepoPoller != nil {
+			e.RepoPoller.WorkflowRuns = append(e.RepoPoller.WorkflowRuns, run.Number)
+			s.saveRepoPollerTrigger(t.UUID, h)
+		}
+	}
+
+	return errRun
+}
+
+func (s *Service) doMessageQueueExecution(t *TaskExecution) (*sdk.WorkflowNodeRunHookEvent, error) {
+	log.Info("Hooks> Processing message %s/%d/%d of task %s", t.MessageQueue.Topic, t.MessageQueue.Partition, t.MessageQueue.Offset, t.UUID)
+
+	// Prepare a struct to send to CDS API
+	h := sdk.WorkflowNodeRunHookEvent{
+		WorkflowNodeHookUUID: t.UUID,
+	}
+
+	//Prepare the payload
+	//Anything can be pushed in the configuration, juste avoid sending
+	payloadValues := map[string]string{}
+	for k, v := range t.Config {
+		switch k {
+		case "project", "workflow", "brokers", "topic", "consumerGroup", "username", "password", "url", "queue", "consumerTag":
+		default:
+			payloadValues[k] = v
+		}
+	}
+
+	//Fields of JSON messages are sent as payload parameters, other messages are sent as is
+	m, err := messageToPayload(t.MessageQueue.Message)
 	if err != nil {
-		return sdk.WrapError(err, "Hooks> Unable to run workflow")
+		return nil, sdk.WrapError(err, "Hooks> Unable to read message %s", t.MessageQueue.Message)
+	}
+	for k, v := range m {
+		payloadValues[k] = v
+	}
+	if t.MessageQueue.Key != "" {
+		payloadValues["message.key"] = t.MessageQueue.Key
 	}
+	h.Payload = payloadValues
 
-	//Save the run number
-	e.WorkflowRun = run.Number
-	log.Info("Hooks> workflow %s/%s#%d has been triggered", t.Config["project"], t.Config["workflow"], run.Number)
+	return &h, nil
+}
 
-	return nil
+// messageToPayload flattens a JSON object message, other messages are returned in the "message" parameter
+func messageToPayload(msg []byte) (map[string]string, error) {
+	var bodyJSON map[string]interface{}
+	if err := json.Unmarshal(msg, &bodyJSON); err != nil {
+		return map[string]string{"message": string(msg)}, nil
+	}
+	return dump.ToMap(bodyJSON, dump.WithDefaultLowerCaseFormatter())
+}
+
+func (s *Service) doRepoPollerExecution(t *TaskExecution) ([]sdk.WorkflowNodeRunHookEvent, error) {
+	log.Info("Hooks> Processing repository poller %s", t.UUID)
+
+	projectKey := t.Config["project"]
+	appName := t.Config["application"]
+	if appName == "" {
+		return nil, fmt.Errorf("Hooks> Repository poller %s has no application", t.UUID)
+	}
+
+	branches, err := s.cds.ApplicationVCSBranches(projectKey, appName)
+	if err != nil {
+		return nil, sdk.WrapError(err, "Hooks> Unable to get branches of %s/%s", projectKey, appName)
+	}
+
+	filters := parseBranchFilters(t.Config["branches"])
+	current := &RepoPollerState{Branches: map[string]string{}}
+	for _, b := range branches {
+		if matchBranchFilters(filters, b.DisplayID) {
+			current.Branches[b.DisplayID] = b.LatestCommit
+		}
+	}
+
+	previous := s.Dao.FindRepoPollerState(t.UUID)
+
+	if t.Config["tags"] == "true" {
+		tags, err := s.cds.ApplicationVCSTags(projectKey, appName)
+		if err != nil {
+			//Some repositories managers can't list tags: the branches are polled anyway, and the known tags are kept
+			log.Warning("Hooks> Unable to get tags of %s/%s: %v", projectKey, appName, err)
+			if previous != nil {
+				current.Tags = previous.Tags
+			}
+		} else {
+			current.Tags = make(map[string]string, len(tags))
+			for _, tag := range tags {
+				current.Tags[tag.Tag] = tag.Hash
+			}
+		}
+	}
+
+	newBranches, newTags := diffRepoPollerState(previous, current)
+
+	//Prepare the payload
+	//Anything can be pushed in the configuration, juste avoid sending
+	payloadValues := map[string]string{}
+	for k, v := range t.Config {
+		switch k {
+		case "project", "workflow", "application", "branches", "tags", "interval":
+		default:
+			payloadValues[k] = v
+		}
+	}
+
+	hs := make([]sdk.WorkflowNodeRunHookEvent, 0, len(newBranches)+len(newTags))
+	for _, b := range newBranches {
+		payload := copyPayload(payloadValues)
+		payload["git.branch"] = b
+		payload["git.hash"] = current.Branches[b]
+
+		//Load the last commit to get its author and its message
+		c, err := s.cds.ApplicationVCSCommit(projectKey, appName, current.Branches[b])
+		if err != nil {
+			log.Warning("Hooks> Unable to get commit %s of %s/%s on branch %s: %v", current.Branches[b], projectKey, appName, b, err)
+		} else {
+			payload["git.author"] = c.Author.Name
+			payload["git.message"] = c.Message
+		}
+
+		hs = append(hs, sdk.WorkflowNodeRunHookEvent{
+			WorkflowNodeHookUUID: t.UUID,
+			Payload:              payload,
+		})
+	}
+
+	for _, tag := range newTags {
+		payload := copyPayload(payloadValues)
+		payload["git.tag"] = tag
+		payload["git.hash"] = current.Tags[tag]
+		hs = append(hs, sdk.WorkflowNodeRunHookEvent{
+			WorkflowNodeHookUUID: t.UUID,
+			Payload:              payload,
+		})
+	}
+
+	//The new branches and tags are saved in the state once their workflow run is triggered, so they are polled again on failure
+	s.Dao.SaveRepoPollerState(t.UUID, untriggeredRepoPollerState(previous, current, newBranches, newTags))
+	t.RepoPoller.NbTriggers = len(hs)
+
+	return hs, nil
+}
+
+// untriggeredRepoPollerState returns the current state without the changes of the new branches and tags
+func untriggeredRepoPollerState(previous, current *RepoPollerState, newBranches, newTags []string) *RepoPollerState {
+	st := &RepoPollerState{Branches: make(map[string]string, len(current.Branches))}
+	for b, hash := range current.Branches {
+		st.Branches[b] = hash
+	}
+	if current.Tags != nil {
+		st.Tags = make(map[string]string, len(current.Tags))
+		for tag, hash := range current.Tags {
+			st.Tags[tag] = hash
+		}
+	}
+
+	for _, b := range newBranches {
+		if hash, ok := previous.Branches[b]; ok {
+			st.Branches[b] = hash
+		} else {
+			delete(st.Branches, b)
+		}
+	}
+	for _, tag := range newTags {
+		delete(st.Tags, tag)
+	}
+	return st
+}
+
+// saveRepoPollerTrigger saves the branch or the tag of a triggered event in the state of a repository poller
+func (s *Service) saveRepoPollerTrigger(uuid string, h sdk.WorkflowNodeRunHookEvent) {
+	st := s.Dao.FindRepoPollerState(uuid)
+	if st == nil {
+		return
+	}
+	if tag, ok := h.Payload["git.tag"]; ok {
+		if st.Tags == nil {
+			st.Tags = map[string]string{}
+		}
+		st.Tags[tag] = h.Payload["git.hash"]
+	} else if b, ok := h.Payload["git.branch"]; ok {
+		if st.Branches == nil {
+			st.Branches = map[string]string{}
+		}
+		st.Branches[b] = h.Payload["git.hash"]
+	}
+	s.Dao.SaveRepoPollerState(uuid, st)
+}
+
+// diffRepoPollerState returns the branches with new commits and the new tags. On first poll, nothing is returned:
+// the current state is only used as reference for the next polls
+func diffRepoPollerState(previous, current *RepoPollerState) ([]string, []string) {
+	var branches, tags []string
+	if previous == nil {
+		return branches, tags
+	}
+
+	for b, hash := range current.Branches {
+		if previous.Branches[b] != hash {
+			branches = append(branches, b)
+		}
+	}
+
+	//Tags polling may have been enabled since the last poll
+	if previous.Tags != nil {
+		for tag := range current.Tags {
+			if _, ok := previous.Tags[tag]; !ok {
+				tags = append(tags, tag)
+			}
+		}
+	}
+
+	sort.Strings(branches)
+	sort.Strings(tags)
+	return branches, tags
+}
+
+// parseBranchFilters parses a comma separated list of branch patterns
+func parseBranchFilters(s string) []string {
+	var filters []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			filters = append(filters, f)
+		}
+	}
+	return filters
+}
+
+// matchBranchFilters checks a branch against patterns such as "master" or "release/*". Without any pattern, all branches match
+func matchBranchFilters(filters []string, branch string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, f := range filters {
+		if ok, _ := path.Match(f, branch); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func copyPayload(src map[string]string) map[string]string {
+	dst := make(map[string]string, len(src))
+	for k, v := range src {
+		dst[k] = v
+	}
+	return dst
 }
 
 func (s *Service) doScheduledTaskExecution(t *TaskExecution) (*sdk.WorkflowNodeRunHookEvent, error) {
@@ -287,6 +642,11 @@ func (s *Service) doWebHookExecution(t *TaskExecution) (*sdk.WorkflowNodeRunHook
 		WorkflowNodeHookUUID: t.UUID,
 	}
 
+	// Payloads sent by repositories managers are mapped on git parameters
+	if provider := vcsWebHookProvider(http.Header(t.WebHook.RequestHeader)); provider != "" {
+		return s.doVCSWebHookExecution(t, provider)
+	}
+
 	// Compute the payload, from the header, the body and the url
 	// For all requests, parse the raw query from the URL
 	values, err := url.ParseQuery(t.WebHook.RequestURL)
@@ -309,7 +669,7 @@ func (s *Service) doWebHookExecution(t *TaskExecution) (*sdk.WorkflowNodeRunHook
 		switch {
 		case ct == "application/x-www-form-urlencoded":
 			formValues, err := url.ParseQuery(string(t.WebHook.RequestBody))
-			if err == nil {
+			if err != nil {
 				return nil, sdk.WrapError(err, "Hooks> Unable webhookto parse body %s", t.WebHook.RequestBody)
 			}
 			copyValues(values, formValues)
@@ -331,7 +691,7 @@ func (s *Service) doWebHookExecution(t *TaskExecution) (*sdk.WorkflowNodeRunHook
 
 			//Go Dump
 			m, err := dump.ToMap(bodyJSON, dump.WithDefaultLowerCaseFormatter())
-			if err == nil {
+			if err != nil {
 				return nil, sdk.WrapError(err, "Hooks> Unable to dump body %s", t.WebHook.RequestBody)
 			}
 
@@ -343,14 +703,7 @@ func (s *Service) doWebHookExecution(t *TaskExecution) (*sdk.WorkflowNodeRunHook
 	}
 
 	//Prepare the payload
-	payloadValues := map[string]string{}
-	for k, v := range t.Config {
-		switch k {
-		case "project", "workflow", "method":
-		default:
-			payloadValues[k] = v
-		}
-	}
+	payloadValues := webHookPayloadFromConfig(t.Config)
 	//try to find some specific values
 	for k := range values {
 		switch k {
@@ -366,10 +719,49 @@ func (s *Service) doWebHookExecution(t *TaskExecution) (*sdk.WorkflowNodeRunHook
 			payloadValues[k] = values.Get(k)
 		}
 	}
+	h.Payload = payloadValues
 
 	return &h, nil
 }
 
+func (s *Service) doVCSWebHookExecution(t *TaskExecution, provider string) (*sdk.WorkflowNodeRunHookEvent, error) {
+	e, err := parseVCSWebHook(provider, http.Header(t.WebHook.RequestHeader), t.WebHook.RequestBody)
+	if err != nil {
+		return nil, sdk.WrapError(err, "Hooks> Unable to read %s webhook %s", provider, t.UUID)
+	}
+	if e == nil {
+		log.Info("Hooks> %s webhook %s: event ignored", provider, t.UUID)
+		return nil, nil
+	}
+	if !filterVCSWebHookEvent(t.Config, e) {
+		log.Info("Hooks> %s webhook %s: %s event on %s%s filtered", provider, t.UUID, e.Kind, e.Branch, e.Tag)
+		return nil, nil
+	}
+
+	payloadValues := webHookPayloadFromConfig(t.Config)
+	for k, v := range e.payload() {
+		payloadValues[k] = v
+	}
+
+	return &sdk.WorkflowNodeRunHookEvent{
+		WorkflowNodeHookUUID: t.UUID,
+		Payload:              payloadValues,
+	}, nil
+}
+
+// webHookPayloadFromConfig returns the configuration of a webhook task which is sent in the payload
+func webHookPayloadFromConfig(config sdk.WorkflowNodeHookConfig) map[string]string {
+	payloadValues := map[string]string{}
+	for k, v := range config {
+		switch k {
+		case "project", "workflow", "method", "secret", "events", "branches", "ignoredBranches":
+		default:
+			payloadValues[k] = v
+		}
+	}
+	return payloadValues
+}
+
 func copyValues(dst, src url.Values) {
 	for k, vs := range src {
 		for _, value := range vs {
@@ -0,0 +1,70 @@
+package hooks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_diffRepoPollerState(t *testing.T) {
+	current := &RepoPollerState{
+		Branches: map[string]string{"master": "bbb", "feat/a": "ccc"},
+		Tags:     map[string]string{"v1.0": "aaa", "v1.1": "bbb"},
+	}
+
+	// First poll: nothing is triggered
+	branches, tags := diffRepoPollerState(nil, current)
+	assert.Empty(t, branches)
+	assert.Empty(t, tags)
+
+	previous := &RepoPollerState{
+		Branches: map[string]string{"master": "aaa"},
+		Tags:     map[string]string{"v1.0": "aaa"},
+	}
+	branches, tags = diffRepoPollerState(previous, current)
+	assert.Equal(t, []string{"feat/a", "master"}, branches)
+	assert.Equal(t, []string{"v1.1"}, tags)
+
+	// Tags polling has just been enabled
+	previous.Tags = nil
+	_, tags = diffRepoPollerState(previous, current)
+	assert.Empty(t, tags)
+}
+
+func Test_untriggeredRepoPollerState(t *testing.T) {
+	previous := &RepoPollerState{
+		Branches: map[string]string{"master": "aaa", "old": "ddd"},
+		Tags:     map[string]string{"v1.0": "aaa"},
+	}
+	current := &RepoPollerState{
+		Branches: map[string]string{"master": "bbb", "feat/a": "ccc"},
+		Tags:     map[string]string{"v1.0": "aaa", "v1.1": "bbb"},
+	}
+
+	st := untriggeredRepoPollerState(previous, current, []string{"feat/a", "master"}, []string{"v1.1"})
+	assert.Equal(t, map[string]string{"master": "aaa"}, st.Branches)
+	assert.Equal(t, map[string]string{"v1.0": "aaa"}, st.Tags)
+
+	// The current state is not modified
+	assert.Equal(t, "bbb", current.Branches["master"])
+	assert.Len(t, current.Tags, 2)
+}
+
+func Test_matchBranchFilters(t *testing.T) {
+	assert.True(t, matchBranchFilters(parseBranchFilters(""), "feat/a"))
+	filters := parseBranchFilters("master, release/*")
+	assert.True(t, matchBranchFilters(filters, "master"))
+	assert.True(t, matchBranchFilters(filters, "release/1.0"))
+	assert.False(t, matchBranchFilters(filters, "feat/a"))
+}
+
+func Test_messageToPayload(t *testing.T) {
+	p, err := messageToPayload([]byte(`{"version": "1.2.0", "env": "prod"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.0", p["version"])
+	assert.Equal(t, "prod", p["env"])
+
+	p, err = messageToPayload([]byte("deploy"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"message": "deploy"}, p)
+}
@@ -1,6 +1,9 @@
 package hooks
 
 import (
+	"context"
+	"sync"
+
 	"github.com/ovh/cds/engine/api"
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/sdk"
@@ -15,6 +18,11 @@ type Service struct {
 	cds    cdsclient.Interface
 	Dao    dao
 	hash   string
+
+	//Running message queue consumers, by task UUID
+	background     context.Context
+	consumers      map[string]*messageQueueTask
+	consumersMutex sync.Mutex
 }
 
 // Configuration is the hooks configuration structure
@@ -69,6 +77,8 @@ type TaskExecution struct {
 	Config              sdk.WorkflowNodeHookConfig
 	WebHook             *WebHookExecution
 	ScheduledTask       *ScheduledTaskExecution
+	RepoPoller          *RepoPollerExecution
+	MessageQueue        *MessageQueueExecution
 }
 
 // WebHookExecution contains specific data for a webhook execution
@@ -82,3 +92,25 @@ type WebHookExecution struct {
 type ScheduledTaskExecution struct {
 	DateScheduledExecution string
 }
+
+// RepoPollerExecution contains specific data for a repository poller execution
+type RepoPollerExecution struct {
+	DateScheduledExecution string
+	NbTriggers             int
+	WorkflowRuns           []int64
+}
+
+// RepoPollerState is the last state of a repository seen by a repository poller task: the last commit of each branch and the known tags
+type RepoPollerState struct {
+	Branches map[string]string
+	Tags     map[string]string
+}
+
+// MessageQueueExecution contains the message received by a kafka or an amqp task. For amqp, Topic is the queue and Key the routing key
+type MessageQueueExecution struct {
+	Topic     string
+	Partition int32
+	Offset    int64
+	Key       string
+	Message   []byte
+}
@@ -0,0 +1,420 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Repositories managers recognized by webhook tasks
+const (
+	GithubWebHook    = "github"
+	GitlabWebHook    = "gitlab"
+	BitbucketWebHook = "bitbucket"
+)
+
+// Kinds of events sent by repositories managers
+const (
+	VCSEventPush        = "push"
+	VCSEventTag         = "tag"
+	VCSEventPullRequest = "pull_request"
+)
+
+// vcsWebHookEvent contains the git information read from a repositories manager webhook payload
+type vcsWebHookEvent struct {
+	Kind          string
+	Repository    string
+	Branch        string
+	Tag           string
+	Hash          string
+	Author        string
+	Message       string
+	PullRequestID int64
+	TargetBranch  string
+}
+
+// payload returns the standard git parameters of the event
+func (e *vcsWebHookEvent) payload() map[string]string {
+	p := map[string]string{
+		"git.repository": e.Repository,
+		"git.hash":       e.Hash,
+		"git.author":     e.Author,
+		"git.message":    e.Message,
+	}
+	if e.Branch != "" {
+		p["git.branch"] = e.Branch
+	}
+	if e.Tag != "" {
+		p["git.tag"] = e.Tag
+	}
+	if e.Kind == VCSEventPullRequest {
+		p["git.pr.id"] = strconv.FormatInt(e.PullRequestID, 10)
+		p["git.pr.target.branch"] = e.TargetBranch
+	}
+	return p
+}
+
+// vcsWebHookProvider returns the repositories manager which has sent the request, or an empty string
+func vcsWebHookProvider(header http.Header) string {
+	switch {
+	case header.Get("X-GitHub-Event") != "":
+		return GithubWebHook
+	case header.Get("X-Gitlab-Event") != "":
+		return GitlabWebHook
+	case header.Get("X-Event-Key") != "":
+		return BitbucketWebHook
+	}
+	return ""
+}
+
+// checkWebHookSignature checks the signature or the token sent by the repositories manager against the secret of the task.
+// Without secret, all requests are accepted. With a secret, requests which are not signed are refused
+func checkWebHookSignature(secret string, header http.Header, body []byte) error {
+	if secret == "" {
+		return nil
+	}
+
+	switch vcsWebHookProvider(header) {
+	case GithubWebHook:
+		if sig := header.Get("X-Hub-Signature-256"); sig != "" {
+			return checkHMAC(sha256.New, "sha256=", secret, sig, body)
+		}
+		return checkHMAC(sha1.New, "sha1=", secret, header.Get("X-Hub-Signature"), body)
+	case GitlabWebHook:
+		if !hmac.Equal([]byte(header.Get("X-Gitlab-Token")), []byte(secret)) {
+			return fmt.Errorf("invalid gitlab token")
+		}
+		return nil
+	case BitbucketWebHook:
+		return checkHMAC(sha256.New, "sha256=", secret, header.Get("X-Hub-Signature"), body)
+	}
+	return fmt.Errorf("missing signature")
+}
+
+func checkHMAC(h func() hash.Hash, prefix, secret, signature string, body []byte) error {
+	if !strings.HasPrefix(signature, prefix) {
+		return fmt.Errorf("missing signature")
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, prefix))
+	if err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+	mac := hmac.New(h, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// parseVCSWebHook reads the payload sent by a repositories manager. It returns nil without error for the events
+// which must not trigger anything, such as pings, deleted branches or closed pull requests
+func parseVCSWebHook(provider string, header http.Header, body []byte) (*vcsWebHookEvent, error) {
+	switch provider {
+	case GithubWebHook:
+		return parseGithubWebHook(header.Get("X-GitHub-Event"), body)
+	case GitlabWebHook:
+		return parseGitlabWebHook(body)
+	case BitbucketWebHook:
+		return parseBitbucketWebHook(header.Get("X-Event-Key"), body)
+	}
+	return nil, fmt.Errorf("unsupported repositories manager %s", provider)
+}
+
+// refToEvent fills the branch or the tag of an event from a git reference
+func refToEvent(e *vcsWebHookEvent, ref string) {
+	switch {
+	case strings.HasPrefix(ref, "refs/tags/"):
+		e.Kind = VCSEventTag
+		e.Tag = strings.TrimPrefix(ref, "refs/tags/")
+	default:
+		e.Kind = VCSEventPush
+		e.Branch = strings.TrimPrefix(ref, "refs/heads/")
+	}
+}
+
+const emptyGitHash = "0000000000000000000000000000000000000000"
+
+type githubPushEvent struct {
+	Ref        string `json:"ref"`
+	After      string `json:"after"`
+	Deleted    bool   `json:"deleted"`
+	HeadCommit *struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+		Author  struct {
+			Name string `json:"name"`
+		} `json:"author"`
+	} `json:"head_commit"`
+	Pusher struct {
+		Name string `json:"name"`
+	} `json:"pusher"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+type githubPullRequestEvent struct {
+	Action      string `json:"action"`
+	Number      int64  `json:"number"`
+	PullRequest struct {
+		Title string `json:"title"`
+		Head  struct {
+			Ref string `json:"ref"`
+			Sha string `json:"sha"`
+		} `json:"head"`
+		Base struct {
+			Ref string `json:"ref"`
+		} `json:"base"`
+		User struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+func parseGithubWebHook(event string, body []byte) (*vcsWebHookEvent, error) {
+	switch event {
+	case "push":
+		var p githubPushEvent
+		if err := json.Unmarshal(body, &p); err != nil {
+			return nil, sdk.WrapError(err, "parseGithubWebHook> Unable to read push event")
+		}
+		if p.Deleted || p.After == emptyGitHash {
+			return nil, nil
+		}
+		e := &vcsWebHookEvent{
+			Repository: p.Repository.FullName,
+			Hash:       p.After,
+			Author:     p.Pusher.Name,
+		}
+		refToEvent(e, p.Ref)
+		if p.HeadCommit != nil {
+			e.Author = p.HeadCommit.Author.Name
+			e.Message = p.HeadCommit.Message
+		}
+		return e, nil
+	case "pull_request":
+		var p githubPullRequestEvent
+		if err := json.Unmarshal(body, &p); err != nil {
+			return nil, sdk.WrapError(err, "parseGithubWebHook> Unable to read pull request event")
+		}
+		switch p.Action {
+		case "opened", "reopened", "synchronize":
+		default:
+			return nil, nil
+		}
+		return &vcsWebHookEvent{
+			Kind:          VCSEventPullRequest,
+			Repository:    p.Repository.FullName,
+			Branch:        p.PullRequest.Head.Ref,
+			Hash:          p.PullRequest.Head.Sha,
+			Author:        p.PullRequest.User.Login,
+			Message:       p.PullRequest.Title,
+			PullRequestID: p.Number,
+			TargetBranch:  p.PullRequest.Base.Ref,
+		}, nil
+	}
+	// ping and all the other events are ignored
+	return nil, nil
+}
+
+type gitlabEvent struct {
+	ObjectKind  string `json:"object_kind"`
+	Ref         string `json:"ref"`
+	After       string `json:"after"`
+	CheckoutSha string `json:"checkout_sha"`
+	UserName    string `json:"user_name"`
+	User        struct {
+		Name string `json:"name"`
+	} `json:"user"`
+	Commits []struct {
+		ID      string `json:"id"`
+		Message string `json:"message"`
+		Author  struct {
+			Name string `json:"name"`
+		} `json:"author"`
+	} `json:"commits"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes struct {
+		IID          int64  `json:"iid"`
+		Title        string `json:"title"`
+		SourceBranch string `json:"source_branch"`
+		TargetBranch string `json:"target_branch"`
+		Action       string `json:"action"`
+		LastCommit   struct {
+			ID string `json:"id"`
+		} `json:"last_commit"`
+	} `json:"object_attributes"`
+}
+
+func parseGitlabWebHook(body []byte) (*vcsWebHookEvent, error) {
+	var p gitlabEvent
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, sdk.WrapError(err, "parseGitlabWebHook> Unable to read event")
+	}
+
+	switch p.ObjectKind {
+	case "push", "tag_push":
+		if p.After == emptyGitHash || p.CheckoutSha == "" {
+			return nil, nil
+		}
+		e := &vcsWebHookEvent{
+			Repository: p.Project.PathWithNamespace,
+			Hash:       p.CheckoutSha,
+			Author:     p.UserName,
+		}
+		refToEvent(e, p.Ref)
+		for _, c := range p.Commits {
+			if c.ID == p.CheckoutSha {
+				e.Author = c.Author.Name
+				e.Message = c.Message
+			}
+		}
+		return e, nil
+	case "merge_request":
+		switch p.ObjectAttributes.Action {
+		case "open", "reopen", "update":
+		default:
+			return nil, nil
+		}
+		return &vcsWebHookEvent{
+			Kind:          VCSEventPullRequest,
+			Repository:    p.Project.PathWithNamespace,
+			Branch:        p.ObjectAttributes.SourceBranch,
+			Hash:          p.ObjectAttributes.LastCommit.ID,
+			Author:        p.User.Name,
+			Message:       p.ObjectAttributes.Title,
+			PullRequestID: p.ObjectAttributes.IID,
+			TargetBranch:  p.ObjectAttributes.TargetBranch,
+		}, nil
+	}
+	return nil, nil
+}
+
+type bitbucketRepository struct {
+	Slug    string `json:"slug"`
+	Project struct {
+		Key string `json:"key"`
+	} `json:"project"`
+}
+
+func (r bitbucketRepository) fullname() string {
+	return r.Project.Key + "/" + r.Slug
+}
+
+type bitbucketEvent struct {
+	Actor struct {
+		Name string `json:"name"`
+	} `json:"actor"`
+	Repository bitbucketRepository `json:"repository"`
+	Changes    []struct {
+		Ref struct {
+			ID string `json:"id"`
+		} `json:"ref"`
+		ToHash string `json:"toHash"`
+		Type   string `json:"type"`
+	} `json:"changes"`
+	PullRequest struct {
+		ID      int64  `json:"id"`
+		Title   string `json:"title"`
+		FromRef struct {
+			DisplayID    string              `json:"displayId"`
+			LatestCommit string              `json:"latestCommit"`
+			Repository   bitbucketRepository `json:"repository"`
+		} `json:"fromRef"`
+		ToRef struct {
+			DisplayID string `json:"displayId"`
+		} `json:"toRef"`
+	} `json:"pullRequest"`
+}
+
+func parseBitbucketWebHook(event string, body []byte) (*vcsWebHookEvent, error) {
+	switch event {
+	case "repo:refs_changed", "pr:opened", "pr:from_ref_updated":
+	default:
+		// diagnostics:ping and all the other events are ignored
+		return nil, nil
+	}
+
+	var p bitbucketEvent
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, sdk.WrapError(err, "parseBitbucketWebHook> Unable to read event %s", event)
+	}
+
+	if event != "repo:refs_changed" {
+		return &vcsWebHookEvent{
+			Kind:          VCSEventPullRequest,
+			Repository:    p.PullRequest.FromRef.Repository.fullname(),
+			Branch:        p.PullRequest.FromRef.DisplayID,
+			Hash:          p.PullRequest.FromRef.LatestCommit,
+			Author:        p.Actor.Name,
+			Message:       p.PullRequest.Title,
+			PullRequestID: p.PullRequest.ID,
+			TargetBranch:  p.PullRequest.ToRef.DisplayID,
+		}, nil
+	}
+
+	// Only the first change which is not a deletion is considered
+	for _, c := range p.Changes {
+		if c.Type == "DELETE" {
+			continue
+		}
+		e := &vcsWebHookEvent{
+			Repository: p.Repository.fullname(),
+			Hash:       c.ToHash,
+			Author:     p.Actor.Name,
+		}
+		refToEvent(e, c.Ref.ID)
+		return e, nil
+	}
+	return nil, nil
+}
+
+// filterVCSWebHookEvent checks the event against the filters of the task configuration:
+// "events" is a comma separated list of kinds of events (push, tag, pull_request),
+// "branches" and "ignoredBranches" are comma separated lists of branch patterns.
+// For pull requests, filters are applied on the target branch
+func filterVCSWebHookEvent(config sdk.WorkflowNodeHookConfig, e *vcsWebHookEvent) bool {
+	if events := parseBranchFilters(config["events"]); len(events) > 0 {
+		var found bool
+		for _, ev := range events {
+			if ev == e.Kind {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	branch := e.Branch
+	if e.Kind == VCSEventPullRequest {
+		branch = e.TargetBranch
+	}
+	if branch == "" {
+		return true
+	}
+
+	if !matchBranchFilters(parseBranchFilters(config["branches"]), branch) {
+		return false
+	}
+	if ignored := parseBranchFilters(config["ignoredBranches"]); len(ignored) > 0 && matchBranchFilters(ignored, branch) {
+		return false
+	}
+	return true
+}
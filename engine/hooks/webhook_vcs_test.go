@@ -0,0 +1,91 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_checkWebHookSignature(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/master"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	github := http.Header{}
+	github.Set("X-GitHub-Event", "push")
+	assert.NoError(t, checkWebHookSignature("", github, body))
+	assert.Error(t, checkWebHookSignature("secret", github, body))
+	github.Set("X-Hub-Signature-256", signature)
+	assert.NoError(t, checkWebHookSignature("secret", github, body))
+	assert.Error(t, checkWebHookSignature("other", github, body))
+
+	gitlab := http.Header{}
+	gitlab.Set("X-Gitlab-Event", "Push Hook")
+	gitlab.Set("X-Gitlab-Token", "secret")
+	assert.NoError(t, checkWebHookSignature("secret", gitlab, body))
+	assert.Error(t, checkWebHookSignature("other", gitlab, body))
+
+	bitbucket := http.Header{}
+	bitbucket.Set("X-Event-Key", "repo:refs_changed")
+	bitbucket.Set("X-Hub-Signature", signature)
+	assert.NoError(t, checkWebHookSignature("secret", bitbucket, body))
+
+	// Unsigned requests are refused as soon as there is a secret
+	assert.Error(t, checkWebHookSignature("secret", http.Header{}, body))
+}
+
+func Test_parseVCSWebHook(t *testing.T) {
+	e, err := parseGithubWebHook("push", []byte(`{"ref":"refs/heads/master","after":"abc","head_commit":{"id":"abc","message":"fix","author":{"name":"john"}},"repository":{"full_name":"ovh/cds"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"git.repository": "ovh/cds",
+		"git.branch":     "master",
+		"git.hash":       "abc",
+		"git.author":     "john",
+		"git.message":    "fix",
+	}, e.payload())
+
+	e, err = parseGithubWebHook("push", []byte(`{"ref":"refs/tags/v1.0","after":"abc"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, VCSEventTag, e.Kind)
+	assert.Equal(t, "v1.0", e.Tag)
+
+	e, err = parseGithubWebHook("push", []byte(`{"ref":"refs/heads/feat","deleted":true}`))
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+
+	e, err = parseGithubWebHook("ping", []byte(`{}`))
+	assert.NoError(t, err)
+	assert.Nil(t, e)
+
+	e, err = parseGitlabWebHook([]byte(`{"object_kind":"merge_request","user":{"name":"john"},"project":{"path_with_namespace":"ovh/cds"},"object_attributes":{"iid":12,"title":"feat","source_branch":"feat","target_branch":"master","action":"open","last_commit":{"id":"abc"}}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "12", e.payload()["git.pr.id"])
+	assert.Equal(t, "master", e.payload()["git.pr.target.branch"])
+	assert.Equal(t, "feat", e.payload()["git.branch"])
+
+	e, err = parseBitbucketWebHook("repo:refs_changed", []byte(`{"actor":{"name":"john"},"repository":{"slug":"cds","project":{"key":"OVH"}},"changes":[{"ref":{"id":"refs/heads/old"},"type":"DELETE"},{"ref":{"id":"refs/heads/master"},"toHash":"abc","type":"UPDATE"}]}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "OVH/cds", e.Repository)
+	assert.Equal(t, "master", e.Branch)
+	assert.Equal(t, "abc", e.Hash)
+}
+
+func Test_filterVCSWebHookEvent(t *testing.T) {
+	push := &vcsWebHookEvent{Kind: VCSEventPush, Branch: "feat/a"}
+	pr := &vcsWebHookEvent{Kind: VCSEventPullRequest, Branch: "feat/a", TargetBranch: "master"}
+
+	assert.True(t, filterVCSWebHookEvent(sdk.WorkflowNodeHookConfig{}, push))
+	assert.False(t, filterVCSWebHookEvent(sdk.WorkflowNodeHookConfig{"events": "pull_request"}, push))
+	assert.True(t, filterVCSWebHookEvent(sdk.WorkflowNodeHookConfig{"events": "pull_request"}, pr))
+	assert.False(t, filterVCSWebHookEvent(sdk.WorkflowNodeHookConfig{"ignoredBranches": "feat/*"}, push))
+	assert.True(t, filterVCSWebHookEvent(sdk.WorkflowNodeHookConfig{"ignoredBranches": "feat/*"}, pr))
+	assert.False(t, filterVCSWebHookEvent(sdk.WorkflowNodeHookConfig{"branches": "master"}, push))
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/ovh/cds/engine/api"
 	"github.com/ovh/cds/engine/api/database"
 	"github.com/ovh/cds/engine/hatchery/docker"
+	"github.com/ovh/cds/engine/hatchery/kubernetes"
 	"github.com/ovh/cds/engine/hatchery/local"
 	"github.com/ovh/cds/engine/hatchery/marathon"
 	"github.com/ovh/cds/engine/hatchery/openstack"
@@ -108,6 +109,7 @@ Comming soon...`,
 		conf.Hatchery.VSphere.API.Token = conf.API.Auth.SharedInfraToken
 		conf.Hatchery.Swarm.API.Token = conf.API.Auth.SharedInfraToken
 		conf.Hatchery.Marathon.API.Token = conf.API.Auth.SharedInfraToken
+		conf.Hatchery.Kubernetes.API.Token = conf.API.Auth.SharedInfraToken
 		conf.Hooks.Name = hatchery.GenerateName("hooks", "")
 		conf.Hooks.API.Token = conf.API.Auth.SharedInfraToken
 		conf.VCS.API.Token = conf.API.Auth.SharedInfraToken
@@ -185,6 +187,13 @@ var configCheckCmd = &cobra.Command{
 			}
 		}
 
+		if conf.Hatchery.Kubernetes.API.HTTP.URL != "" {
+			if err := kubernetes.New().CheckConfiguration(conf.Hatchery.Kubernetes); err != nil {
+				fmt.Println(err)
+				hasError = true
+			}
+		}
+
 		if conf.Hatchery.Marathon.API.HTTP.URL != "" {
 			if err := marathon.New().CheckConfiguration(conf.Hatchery.Marathon); err != nil {
 				fmt.Println(err)
@@ -227,13 +236,14 @@ Start CDS Engine Services:
 	 * Docker Swarm
 	 * Openstack
 	 * Vsphere
+	 * Kubernetes
  * Hooks:
  	This component operates CDS workflow hooks
  * VCS:
  	This component operates CDS VCS connectivity
 
 Start all of this with a single command:
-	$ engine start [api] [hatchery:local] [hatchery:docker] [hatchery:marathon] [hatchery:openstack] [hatchery:swarm] [hatchery:vsphere] [hooks] [vcs]
+	$ engine start [api] [hatchery:local] [hatchery:docker] [hatchery:kubernetes] [hatchery:marathon] [hatchery:openstack] [hatchery:swarm] [hatchery:vsphere] [hooks] [vcs]
 All the services are using the same configuration file format.
 You have to specify where the toml configuration is. It can be a local file, provided by consul or vault.
 You can also use or override toml file with environment variable.
@@ -293,6 +303,9 @@ See $ engine config command for more details.
 			case "hatchery:docker":
 				s = docker.New()
 				cfg = conf.Hatchery.Docker
+			case "hatchery:kubernetes":
+				s = kubernetes.New()
+				cfg = conf.Hatchery.Kubernetes
 			case "hatchery:local":
 				s = local.New()
 				cfg = conf.Hatchery.Local
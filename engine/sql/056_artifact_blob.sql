@@ -0,0 +1,14 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "artifact_blob" (
+    sha256sum VARCHAR(64) PRIMARY KEY,
+    object_path TEXT,
+    ref_count BIGINT NOT NULL DEFAULT 0,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+ALTER TABLE artifact ADD COLUMN sha256sum TEXT DEFAULT '';
+ALTER TABLE workflow_node_run_artifacts ADD COLUMN sha256sum TEXT DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE artifact DROP COLUMN sha256sum;
+ALTER TABLE workflow_node_run_artifacts DROP COLUMN sha256sum;
+DROP TABLE artifact_blob;
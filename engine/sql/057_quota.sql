@@ -0,0 +1,11 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "quota" (
+    id BIGSERIAL PRIMARY KEY,
+    scope VARCHAR(50) NOT NULL,
+    target_id BIGINT NOT NULL,
+    max_jobs BIGINT NOT NULL
+);
+SELECT create_unique_index('quota', 'IDX_QUOTA_SCOPE_TARGET', 'scope, target_id');
+
+-- +migrate Down
+DROP TABLE quota;
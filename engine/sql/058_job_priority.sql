@@ -0,0 +1,18 @@
+-- +migrate Up
+ALTER TABLE pipeline ADD COLUMN priority BIGINT DEFAULT 0;
+ALTER TABLE workflow_node_context ADD COLUMN priority BIGINT DEFAULT 0;
+ALTER TABLE workflow_node_run ADD COLUMN priority BIGINT DEFAULT 0;
+ALTER TABLE workflow_node_run_job ADD COLUMN priority BIGINT DEFAULT 0;
+ALTER TABLE workflow_node_run_job ADD COLUMN project_id BIGINT DEFAULT 0;
+UPDATE workflow_node_run_job SET project_id = workflow_run.project_id
+    FROM workflow_node_run, workflow_run
+    WHERE workflow_node_run.id = workflow_node_run_job.workflow_node_run_id
+    AND workflow_run.id = workflow_node_run.workflow_run_id;
+SELECT create_index('workflow_node_run_job', 'IDX_WORKFLOW_NODE_RUN_JOB_PROJECT_STATUS', 'project_id,status');
+
+-- +migrate Down
+ALTER TABLE pipeline DROP COLUMN priority;
+ALTER TABLE workflow_node_context DROP COLUMN priority;
+ALTER TABLE workflow_node_run DROP COLUMN priority;
+ALTER TABLE workflow_node_run_job DROP COLUMN priority;
+ALTER TABLE workflow_node_run_job DROP COLUMN project_id;
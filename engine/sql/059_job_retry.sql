@@ -0,0 +1,15 @@
+-- +migrate Up
+ALTER TABLE pipeline_action ADD COLUMN retry INT DEFAULT 0;
+ALTER TABLE pipeline_action ADD COLUMN retry_delay INT DEFAULT 0;
+ALTER TABLE action_edge ADD COLUMN retry INT DEFAULT 0;
+ALTER TABLE action_edge ADD COLUMN retry_delay INT DEFAULT 0;
+ALTER TABLE workflow_node_run_job ADD COLUMN retry INT DEFAULT 0;
+ALTER TABLE workflow_node_run_job ADD COLUMN retry_after TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP;
+
+-- +migrate Down
+ALTER TABLE pipeline_action DROP COLUMN retry;
+ALTER TABLE pipeline_action DROP COLUMN retry_delay;
+ALTER TABLE action_edge DROP COLUMN retry;
+ALTER TABLE action_edge DROP COLUMN retry_delay;
+ALTER TABLE workflow_node_run_job DROP COLUMN retry;
+ALTER TABLE workflow_node_run_job DROP COLUMN retry_after;
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE pipeline_action ADD COLUMN timeout INT DEFAULT 0;
+ALTER TABLE action_edge ADD COLUMN timeout INT DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE pipeline_action DROP COLUMN timeout;
+ALTER TABLE action_edge DROP COLUMN timeout;
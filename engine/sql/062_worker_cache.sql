@@ -0,0 +1,15 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "worker_cache" (
+    id BIGSERIAL PRIMARY KEY,
+    project_id BIGINT NOT NULL,
+    cache_key VARCHAR(256) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    expire TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_unique_index('worker_cache', 'IDX_WORKER_CACHE_PROJECT_KEY', 'project_id,cache_key');
+SELECT create_index('worker_cache', 'IDX_WORKER_CACHE_EXPIRE', 'expire');
+SELECT create_foreign_key_idx_cascade('FK_WORKER_CACHE_PROJECT', 'worker_cache', 'project', 'project_id', 'id');
+
+-- +migrate Down
+DROP TABLE worker_cache;
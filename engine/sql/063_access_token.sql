@@ -0,0 +1,17 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "access_token" (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    name VARCHAR(256) NOT NULL,
+    scopes JSONB,
+    hash VARCHAR(128) NOT NULL,
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    expire TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP,
+    last_used TIMESTAMP WITH TIME ZONE
+);
+SELECT create_unique_index('access_token', 'IDX_ACCESS_TOKEN_HASH', 'hash');
+SELECT create_unique_index('access_token', 'IDX_ACCESS_TOKEN_USER_NAME', 'user_id,name');
+SELECT create_foreign_key_idx_cascade('FK_ACCESS_TOKEN_USER', 'access_token', 'user', 'user_id', 'id');
+
+-- +migrate Down
+DROP TABLE access_token;
@@ -0,0 +1,11 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_node_notification" (
+    id BIGSERIAL PRIMARY KEY,
+    workflow_node_id BIGINT NOT NULL,
+    type VARCHAR(256) NOT NULL,
+    settings JSONB
+);
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_NOTIFICATION_WORKFLOW_NODE', 'workflow_node_notification', 'workflow_node', 'workflow_node_id', 'id');
+
+-- +migrate Down
+DROP TABLE workflow_node_notification;
@@ -0,0 +1,25 @@
+-- +migrate Up
+CREATE TABLE IF NOT EXISTS "workflow_node_run_testcase" (
+    id BIGSERIAL PRIMARY KEY,
+    workflow_id BIGINT NOT NULL,
+    workflow_run_id BIGINT NOT NULL,
+    workflow_node_run_id BIGINT NOT NULL,
+    num BIGINT NOT NULL,
+    sub_num BIGINT NOT NULL,
+    node_name VARCHAR(256) NOT NULL,
+    testsuite TEXT NOT NULL,
+    name TEXT NOT NULL,
+    status VARCHAR(64) NOT NULL,
+    duration DOUBLE PRECISION NOT NULL DEFAULT 0,
+    branch VARCHAR(256) NOT NULL DEFAULT '',
+    hash VARCHAR(256) NOT NULL DEFAULT '',
+    created TIMESTAMP WITH TIME ZONE DEFAULT LOCALTIMESTAMP
+);
+SELECT create_index('workflow_node_run_testcase', 'IDX_WORKFLOW_NODE_RUN_TESTCASE_NAME', 'workflow_id,testsuite,name');
+SELECT create_index('workflow_node_run_testcase', 'IDX_WORKFLOW_NODE_RUN_TESTCASE_BRANCH', 'workflow_id,branch,num');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_TESTCASE_WORKFLOW', 'workflow_node_run_testcase', 'workflow', 'workflow_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_TESTCASE_WORKFLOW_RUN', 'workflow_node_run_testcase', 'workflow_run', 'workflow_run_id', 'id');
+SELECT create_foreign_key_idx_cascade('FK_WORKFLOW_NODE_RUN_TESTCASE_WORKFLOW_NODE_RUN', 'workflow_node_run_testcase', 'workflow_node_run', 'workflow_node_run_id', 'id');
+
+-- +migrate Down
+DROP TABLE workflow_node_run_testcase;
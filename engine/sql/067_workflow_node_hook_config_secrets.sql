@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE workflow_node_hook ADD COLUMN config_secrets BYTEA;
+
+-- +migrate Down
+ALTER TABLE workflow_node_hook DROP COLUMN config_secrets;
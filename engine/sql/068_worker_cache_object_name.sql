@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE worker_cache ADD COLUMN object_name VARCHAR(300) NOT NULL DEFAULT '';
+
+-- +migrate Down
+ALTER TABLE worker_cache DROP COLUMN object_name;
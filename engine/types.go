@@ -10,6 +10,7 @@ import (
 
 	"github.com/ovh/cds/engine/api"
 	"github.com/ovh/cds/engine/hatchery/docker"
+	"github.com/ovh/cds/engine/hatchery/kubernetes"
 	"github.com/ovh/cds/engine/hatchery/local"
 	"github.com/ovh/cds/engine/hatchery/marathon"
 	"github.com/ovh/cds/engine/hatchery/openstack"
@@ -30,12 +31,13 @@ type Configuration struct {
 	} `toml:"debug" comment:"#####################\n Debug with gops \n####################"`
 	API      api.Configuration `toml:"api" comment:"#####################\n API Configuration \n####################"`
 	Hatchery struct {
-		Docker    docker.HatcheryConfiguration    `toml:"docker" comment:"Hatchery Docker."`
-		Local     local.HatcheryConfiguration     `toml:"local" comment:"Hatchery Local."`
-		Marathon  marathon.HatcheryConfiguration  `toml:"marathon" comment:"Hatchery Marathon."`
-		Openstack openstack.HatcheryConfiguration `toml:"openstack" comment:"Hatchery OpenStack. Doc: https://ovh.github.io/cds/advanced/advanced.hatcheries.openstack/"`
-		Swarm     swarm.HatcheryConfiguration     `toml:"swarm" comment:"Hatchery Swarm. Doc: https://ovh.github.io/cds/advanced/advanced.hatcheries.swarm/"`
-		VSphere   vsphere.HatcheryConfiguration   `toml:"vsphere" comment:"Hatchery VShpere. Doc: https://ovh.github.io/cds/advanced/advanced.hatcheries.vsphere/"`
+		Docker     docker.HatcheryConfiguration     `toml:"docker" comment:"Hatchery Docker."`
+		Kubernetes kubernetes.HatcheryConfiguration `toml:"kubernetes" comment:"Hatchery Kubernetes. Doc: https://ovh.github.io/cds/advanced/advanced.hatcheries.kubernetes/"`
+		Local      local.HatcheryConfiguration      `toml:"local" comment:"Hatchery Local."`
+		Marathon   marathon.HatcheryConfiguration   `toml:"marathon" comment:"Hatchery Marathon."`
+		Openstack  openstack.HatcheryConfiguration  `toml:"openstack" comment:"Hatchery OpenStack. Doc: https://ovh.github.io/cds/advanced/advanced.hatcheries.openstack/"`
+		Swarm      swarm.HatcheryConfiguration      `toml:"swarm" comment:"Hatchery Swarm. Doc: https://ovh.github.io/cds/advanced/advanced.hatcheries.swarm/"`
+		VSphere    vsphere.HatcheryConfiguration    `toml:"vsphere" comment:"Hatchery VShpere. Doc: https://ovh.github.io/cds/advanced/advanced.hatcheries.vsphere/"`
 	} `toml:"hatchery"`
 	Hooks hooks.Configuration `toml:"hooks" comment:"######################\n CDS Hooks Settings \n######################"`
 	VCS   vcs.Configuration   `toml:"vcs" comment:"######################\n CDS VCS Settings \n######################"`
@@ -9,3 +9,7 @@ import (
 func (b *bitbucketClient) PullRequests(repo string) ([]sdk.VCSPullRequest, error) {
 	return nil, fmt.Errorf("Not yet implemented")
 }
+
+func (b *bitbucketClient) PullRequestComment(repo string, id int, comment sdk.VCSPullRequestComment) error {
+	return fmt.Errorf("Not yet implemented")
+}
@@ -14,6 +14,10 @@ func (b *bitbucketClient) SetStatus(event sdk.Event) error {
 	log.Debug("process> receive: type:%s all: %+v", event.EventType, event)
 	var eventpb sdk.EventPipelineBuild
 
+	if event.EventType == fmt.Sprintf("%T", sdk.EventCommitStatus{}) {
+		return b.setCommitStatus(event)
+	}
+
 	if event.EventType != fmt.Sprintf("%T", sdk.EventPipelineBuild{}) {
 		return nil
 	}
@@ -61,6 +65,28 @@ func (b *bitbucketClient) SetStatus(event sdk.Event) error {
 	return b.do("POST", "build-status", fmt.Sprintf("/commits/%s", eventpb.Hash), nil, values, nil)
 }
 
+// setCommitStatus sets a build status which is not related to a pipeline build
+func (b *bitbucketClient) setCommitStatus(event sdk.Event) error {
+	var eventcs sdk.EventCommitStatus
+	if err := mapstructure.Decode(event.Payload, &eventcs); err != nil {
+		return sdk.WrapError(err, "Error during consumption")
+	}
+
+	status := Status{
+		Key:         eventcs.Context,
+		Name:        eventcs.Context,
+		State:       getBitbucketStateFromStatus(eventcs.Status),
+		URL:         eventcs.URL,
+		Description: eventcs.Description,
+	}
+
+	values, err := json.Marshal(status)
+	if err != nil {
+		return err
+	}
+	return b.do("POST", "build-status", fmt.Sprintf("/commits/%s", eventcs.Hash), nil, values, nil)
+}
+
 const (
 	inProgress = "INPROGRESS"
 	successful = "SUCCESSFUL"
@@ -0,0 +1,48 @@
+package bitbucket
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (b *bitbucketClient) Tags(fullname string) ([]sdk.VCSTag, error) {
+	t := strings.Split(fullname, "/")
+	if len(t) != 2 {
+		return nil, sdk.ErrRepoNotFound
+	}
+
+	stashTags := []Tag{}
+
+	path := fmt.Sprintf("/projects/%s/repos/%s/tags", t[0], t[1])
+	params := url.Values{}
+
+	nextPage := 0
+	for {
+		if nextPage != 0 {
+			params.Set("start", fmt.Sprintf("%d", nextPage))
+		}
+
+		var response TagResponse
+		if err := b.do("GET", "core", path, params, nil, &response); err != nil {
+			return nil, sdk.WrapError(err, "vcs> bitbucket> tags> Unable to get tags %s", path)
+		}
+
+		stashTags = append(stashTags, response.Values...)
+		if response.IsLastPage {
+			break
+		}
+		nextPage += response.Size
+	}
+
+	tags := make([]sdk.VCSTag, 0, len(stashTags))
+	for _, st := range stashTags {
+		tags = append(tags, sdk.VCSTag{
+			Tag:  st.DisplayID,
+			Hash: st.LatestHash,
+		})
+	}
+	return tags, nil
+}
@@ -19,6 +19,18 @@ type BranchResponse struct {
 	IsLastPage bool     `json:"isLastPage"`
 }
 
+type Tag struct {
+	ID         string `json:"id"`
+	DisplayID  string `json:"displayId"`
+	LatestHash string `json:"latestChangeset"`
+}
+
+type TagResponse struct {
+	Values     []Tag `json:"values"`
+	Size       int   `json:"size"`
+	IsLastPage bool  `json:"isLastPage"`
+}
+
 type Author struct {
 	Name  string `json:"name"`
 	Email string `json:"emailAddress"`
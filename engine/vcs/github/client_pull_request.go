@@ -1,8 +1,12 @@
 package github
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strings"
 
 	"github.com/ovh/cds/engine/api/cache"
 	"github.com/ovh/cds/sdk"
@@ -52,6 +56,7 @@ func (g *githubClient) PullRequests(fullname string) ([]sdk.VCSPullRequest, erro
 	prResults := []sdk.VCSPullRequest{}
 	for _, pullr := range pullRequests {
 		pr := sdk.VCSPullRequest{
+			ID: pullr.Number,
 			Base: sdk.VCSPushEvent{
 				Repo: pullr.Base.Repo.FullName,
 				Branch: sdk.VCSBranch{
@@ -102,3 +107,71 @@ func (g *githubClient) PullRequests(fullname string) ([]sdk.VCSPullRequest, erro
 
 	return prResults, nil
 }
+
+// PullRequestComment adds a comment on a pull request, or updates the comment containing the marker of the comment
+func (g *githubClient) PullRequestComment(repo string, id int, comment sdk.VCSPullRequestComment) error {
+	b, err := json.Marshal(map[string]string{"body": comment.Message})
+	if err != nil {
+		return err
+	}
+
+	if comment.Marker != "" {
+		commentID, err := g.findPullRequestComment(repo, id, comment.Marker)
+		if err != nil {
+			return err
+		}
+		if commentID != 0 {
+			res, err := g.patch(fmt.Sprintf("/repos/%s/issues/comments/%d", repo, commentID), "application/json", bytes.NewBuffer(b))
+			if err != nil {
+				return err
+			}
+			defer res.Body.Close()
+
+			if res.StatusCode != http.StatusOK {
+				body, _ := ioutil.ReadAll(res.Body)
+				return fmt.Errorf("Unable to update comment %d of pull request %d on github. Status code : %d - Body: %s", commentID, id, res.StatusCode, body)
+			}
+			return nil
+		}
+	}
+
+	path := fmt.Sprintf("/repos/%s/issues/%d/comments", repo, id)
+	res, err := g.post(path, "application/json", bytes.NewBuffer(b), false)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusCreated {
+		body, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("Unable to comment pull request %d on github. Status code : %d - Body: %s", id, res.StatusCode, body)
+	}
+	return nil
+}
+
+// findPullRequestComment returns the id of the first comment of a pull request containing the marker, 0 if there is none
+func (g *githubClient) findPullRequestComment(repo string, id int, marker string) (int64, error) {
+	nextPage := fmt.Sprintf("/repos/%s/issues/%d/comments", repo, id)
+	for nextPage != "" {
+		status, body, headers, err := g.get(nextPage, withoutETag)
+		if err != nil {
+			return 0, err
+		}
+		if status >= 400 {
+			return 0, sdk.NewError(sdk.ErrUnknownError, errorAPI(body))
+		}
+
+		comments := []IssueComment{}
+		if err := json.Unmarshal(body, &comments); err != nil {
+			return 0, sdk.WrapError(err, "githubClient.findPullRequestComment> Unable to parse comments of pull request %d", id)
+		}
+		for _, c := range comments {
+			if strings.Contains(c.Body, marker) {
+				return c.ID, nil
+			}
+		}
+
+		nextPage = getNextPage(headers)
+	}
+	return 0, nil
+}
@@ -18,6 +18,10 @@ func (g *githubClient) SetStatus(event sdk.Event) error {
 	log.Debug("github.SetStatus> receive: type:%s all: %+v", event.EventType, event)
 	var eventpb sdk.EventPipelineBuild
 
+	if event.EventType == fmt.Sprintf("%T", sdk.EventCommitStatus{}) {
+		return g.setCommitStatus(event)
+	}
+
 	if event.EventType != fmt.Sprintf("%T", sdk.EventPipelineBuild{}) {
 		return nil
 	}
@@ -93,7 +97,12 @@ func (g *githubClient) SetStatus(event sdk.Event) error {
 		Context:     context,
 	}
 
-	path := fmt.Sprintf("/repos/%s/statuses/%s", eventpb.RepositoryFullname, eventpb.Hash)
+	return g.createStatus(eventpb.RepositoryFullname, eventpb.Hash, ghStatus)
+}
+
+//createStatus posts a status on a commit
+func (g *githubClient) createStatus(repo, hash string, ghStatus CreateStatus) error {
+	path := fmt.Sprintf("/repos/%s/statuses/%s", repo, hash)
 
 	b, err := json.Marshal(ghStatus)
 	if err != nil {
@@ -129,3 +138,39 @@ func (g *githubClient) SetStatus(event sdk.Event) error {
 
 	return nil
 }
+
+//setCommitStatus creates a status which is not related to a pipeline build
+func (g *githubClient) setCommitStatus(event sdk.Event) error {
+	if g.DisableSetStatus {
+		log.Warning("⚠ Github statuses are disabled")
+		return nil
+	}
+
+	var eventcs sdk.EventCommitStatus
+	if err := mapstructure.Decode(event.Payload, &eventcs); err != nil {
+		log.Warning("Error during consumption: %s", err)
+		return err
+	}
+
+	var status string
+	switch eventcs.Status {
+	case sdk.StatusSuccess:
+		status = "success"
+	case sdk.StatusFail:
+		status = "failure"
+	default:
+		status = "pending"
+	}
+
+	url := eventcs.URL
+	if g.DisableStatusURL {
+		url = ""
+	}
+
+	return g.createStatus(eventcs.RepositoryFullname, eventcs.Hash, CreateStatus{
+		Description: eventcs.Description,
+		TargetURL:   url,
+		State:       status,
+		Context:     eventcs.Context,
+	})
+}
@@ -0,0 +1,43 @@
+package github
+
+import (
+	"encoding/json"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// Tags returns list of tags for a repo
+func (g *githubClient) Tags(fullname string) ([]sdk.VCSTag, error) {
+	var tags = []Tag{}
+	var nextPage = "/repos/" + fullname + "/tags"
+
+	for nextPage != "" {
+		status, body, headers, err := g.get(nextPage, withoutETag)
+		if err != nil {
+			log.Warning("githubClient.Tags> Error %s", err)
+			return nil, err
+		}
+		if status >= 400 {
+			return nil, sdk.NewError(sdk.ErrUnknownError, errorAPI(body))
+		}
+
+		nextTags := []Tag{}
+		if err := json.Unmarshal(body, &nextTags); err != nil {
+			log.Warning("githubClient.Tags> Unable to parse github tags: %s", err)
+			return nil, err
+		}
+		tags = append(tags, nextTags...)
+
+		nextPage = getNextPage(headers)
+	}
+
+	tagsResult := make([]sdk.VCSTag, len(tags))
+	for i, t := range tags {
+		tagsResult[i] = sdk.VCSTag{
+			Tag:  t.Name,
+			Hash: t.Commit.Sha,
+		}
+	}
+	return tagsResult, nil
+}
@@ -136,6 +136,26 @@ func (c *githubClient) post(path string, bodyType string, body io.Reader, skipDe
 	return httpClient.Do(req)
 }
 
+func (c *githubClient) patch(path string, bodyType string, body io.Reader) (*http.Response, error) {
+	if !strings.HasPrefix(path, APIURL) {
+		path = APIURL + path
+	}
+
+	req, err := http.NewRequest(http.MethodPatch, path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", bodyType)
+	req.Header.Set("User-Agent", "CDS-gh_client_id="+c.ClientID)
+	req.Header.Add("Accept", "application/json")
+	req.Header.Add("Authorization", fmt.Sprintf("token %s", c.OAuthToken))
+
+	log.Debug("Github API>> Request URL %s", req.URL.String())
+
+	return httpClient.Do(req)
+}
+
 func (c *githubClient) get(path string, opts ...getArgFunc) (int, []byte, http.Header, error) {
 	if RateLimitRemaining < 100 {
 		return 0, nil, nil, ErrorRateLimit
@@ -199,6 +199,12 @@ type Branch struct {
 	Protection Protection `json:"protection,omitempty"`
 }
 
+// Tag represents a repository tag
+type Tag struct {
+	Name   string `json:"name,omitempty"`
+	Commit Commit `json:"commit,omitempty"`
+}
+
 // Protection represents a repository branch's protection
 type Protection struct {
 	Enabled bool `json:"enabled,omitempty"`
@@ -433,6 +439,12 @@ type Cursor struct {
 	Repo  Repository `json:"repo"`
 }
 
+// IssueComment represents a comment of an issue or a pull request from github api
+type IssueComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+}
+
 // PullRequest represents pull request from github api
 type PullRequest struct {
 	URL                 string    `json:"url"`
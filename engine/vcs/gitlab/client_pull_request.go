@@ -1,8 +1,87 @@
 package gitlab
 
-import "github.com/ovh/cds/sdk"
+import (
+	"strings"
 
-// PullRequests fetch all the pull request for a repository
-func (c *gitlabClient) PullRequests(string) ([]sdk.VCSPullRequest, error) {
-	return []sdk.VCSPullRequest{}, nil
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// PullRequests fetch all the opened merge requests for a repository
+func (c *gitlabClient) PullRequests(repo string) ([]sdk.VCSPullRequest, error) {
+	state := "opened"
+	opts := &gitlab.ListMergeRequestsOptions{State: &state}
+	opts.PerPage = 100
+
+	prs := []sdk.VCSPullRequest{}
+	for {
+		mrs, resp, err := c.client.MergeRequests.ListMergeRequests(repo, opts)
+		if err != nil {
+			return nil, sdk.WrapError(err, "gitlabClient.PullRequests> Unable to list merge requests of %s", repo)
+		}
+
+		for _, mr := range mrs {
+			author := sdk.VCSAuthor{
+				Avatar:      mr.Author.AvatarURL,
+				DisplayName: mr.Author.Username,
+				Name:        mr.Author.Name,
+			}
+			prs = append(prs, sdk.VCSPullRequest{
+				ID: mr.IID,
+				Base: sdk.VCSPushEvent{
+					Repo: repo,
+					Branch: sdk.VCSBranch{
+						ID:        mr.TargetBranch,
+						DisplayID: mr.TargetBranch,
+					},
+				},
+				Head: sdk.VCSPushEvent{
+					Repo: repo,
+					Branch: sdk.VCSBranch{
+						ID:           mr.SourceBranch,
+						DisplayID:    mr.SourceBranch,
+						LatestCommit: mr.SHA,
+					},
+					Commit: sdk.VCSCommit{
+						Author: author,
+						Hash:   mr.SHA,
+					},
+				},
+				User: author,
+			})
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return prs, nil
+}
+
+// PullRequestComment adds a note on a merge request, or updates the note containing the marker of the comment
+func (c *gitlabClient) PullRequestComment(repo string, id int, comment sdk.VCSPullRequestComment) error {
+	if comment.Marker != "" {
+		notes, _, err := c.client.Notes.ListMergeRequestNotes(repo, id)
+		if err != nil {
+			return sdk.WrapError(err, "gitlabClient.PullRequestComment> Unable to list notes of merge request %d of %s", id, repo)
+		}
+		for _, n := range notes {
+			if !strings.Contains(n.Body, comment.Marker) {
+				continue
+			}
+			opt := &gitlab.UpdateMergeRequestNoteOptions{Body: &comment.Message}
+			if _, _, err := c.client.Notes.UpdateMergeRequestNote(repo, id, n.ID, opt); err != nil {
+				return sdk.WrapError(err, "gitlabClient.PullRequestComment> Unable to update note %d of merge request %d of %s", n.ID, id, repo)
+			}
+			return nil
+		}
+	}
+
+	opt := &gitlab.CreateMergeRequestNoteOptions{Body: &comment.Message}
+	if _, _, err := c.client.Notes.CreateMergeRequestNote(repo, id, opt); err != nil {
+		return sdk.WrapError(err, "gitlabClient.PullRequestComment> Unable to comment merge request %d of %s", id, repo)
+	}
+	return nil
 }
@@ -39,6 +39,9 @@ func getGitlabStateFromStatus(s sdk.Status) gitlab.BuildState {
 //SetStatus set build status on Gitlab
 func (c *gitlabClient) SetStatus(event sdk.Event) error {
 	var eventpb sdk.EventPipelineBuild
+	if event.EventType == fmt.Sprintf("%T", sdk.EventCommitStatus{}) {
+		return c.setCommitStatus(event)
+	}
 	if event.EventType != fmt.Sprintf("%T", sdk.EventPipelineBuild{}) {
 		return nil
 	}
@@ -88,3 +91,25 @@ func (c *gitlabClient) SetStatus(event sdk.Event) error {
 
 	return nil
 }
+
+//setCommitStatus sets a status which is not related to a pipeline build
+func (c *gitlabClient) setCommitStatus(event sdk.Event) error {
+	var eventcs sdk.EventCommitStatus
+	if err := mapstructure.Decode(event.Payload, &eventcs); err != nil {
+		return err
+	}
+
+	opt := &gitlab.SetCommitStatusOptions{
+		Name:        &eventcs.Context,
+		Context:     &eventcs.Context,
+		State:       getGitlabStateFromStatus(eventcs.Status),
+		Ref:         &eventcs.BranchName,
+		TargetURL:   &eventcs.URL,
+		Description: &eventcs.Description,
+	}
+
+	if _, _, err := c.client.Commits.SetCommitStatus(eventcs.RepositoryFullname, eventcs.Hash, opt); err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package gitlab
+
+import (
+	"github.com/ovh/cds/sdk"
+)
+
+//Tags returns the tags of a repository
+func (c *gitlabClient) Tags(fullname string) ([]sdk.VCSTag, error) {
+	tags, _, err := c.client.Tags.ListTags(fullname)
+	if err != nil {
+		return nil, err
+	}
+
+	vcsTags := make([]sdk.VCSTag, 0, len(tags))
+	for _, t := range tags {
+		tag := sdk.VCSTag{Tag: t.Name}
+		if t.Commit != nil {
+			tag.Hash = t.Commit.ID
+		}
+		vcsTags = append(vcsTags, tag)
+	}
+	return vcsTags, nil
+}
@@ -12,8 +12,8 @@ import (
 
 // HTTP Headers
 const (
-	HeaderXAccessToken       = "X-CDS-ACCESS-TOKEN"
-	HeaderXAccessTokenSecret = "X-CDS-ACCESS-TOKEN-SECRET"
+	HeaderXAccessToken       = sdk.HeaderXAccessToken
+	HeaderXAccessTokenSecret = sdk.HeaderXAccessTokenSecret
 )
 
 // Context
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/gorilla/mux"
 
@@ -200,3 +201,131 @@ func (s *Service) getCommitHandler() api.Handler {
 		return api.WriteJSON(w, r, c, http.StatusOK)
 	}
 }
+
+func (s *Service) getTagsHandler() api.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := muxVar(r, "name")
+		owner := muxVar(r, "owner")
+		repo := muxVar(r, "repo")
+
+		accessToken, accessTokenSecret, ok := getAccessTokens(ctx)
+		if !ok {
+			return sdk.WrapError(sdk.ErrUnauthorized, "VCS> getTagsHandler> Unable to get access token headers")
+		}
+
+		consumer, err := s.getConsumer(name)
+		if err != nil {
+			return sdk.WrapError(err, "VCS> getTagsHandler> VCS server unavailable")
+		}
+
+		client, err := consumer.GetAuthorizedClient(accessToken, accessTokenSecret)
+		if err != nil {
+			return sdk.WrapError(err, "VCS> getTagsHandler> Unable to get authorized client")
+		}
+
+		tags, err := client.Tags(fmt.Sprintf("%s/%s", owner, repo))
+		if err != nil {
+			return sdk.WrapError(err, "VCS> getTagsHandler> Unable to get repo %s/%s tags", owner, repo)
+		}
+		return api.WriteJSON(w, r, tags, http.StatusOK)
+	}
+}
+
+func (s *Service) getPullRequestsHandler() api.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := muxVar(r, "name")
+		owner := muxVar(r, "owner")
+		repo := muxVar(r, "repo")
+
+		accessToken, accessTokenSecret, ok := getAccessTokens(ctx)
+		if !ok {
+			return sdk.WrapError(sdk.ErrUnauthorized, "VCS> getPullRequestsHandler> Unable to get access token headers")
+		}
+
+		consumer, err := s.getConsumer(name)
+		if err != nil {
+			return sdk.WrapError(err, "VCS> getPullRequestsHandler> VCS server unavailable")
+		}
+
+		client, err := consumer.GetAuthorizedClient(accessToken, accessTokenSecret)
+		if err != nil {
+			return sdk.WrapError(err, "VCS> getPullRequestsHandler> Unable to get authorized client")
+		}
+
+		prs, err := client.PullRequests(fmt.Sprintf("%s/%s", owner, repo))
+		if err != nil {
+			return sdk.WrapError(err, "VCS> getPullRequestsHandler> Unable to get pull requests on %s/%s", owner, repo)
+		}
+		return api.WriteJSON(w, r, prs, http.StatusOK)
+	}
+}
+
+func (s *Service) postPullRequestCommentHandler() api.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := muxVar(r, "name")
+		owner := muxVar(r, "owner")
+		repo := muxVar(r, "repo")
+		id, err := strconv.Atoi(muxVar(r, "id"))
+		if err != nil {
+			return sdk.WrapError(sdk.ErrWrongRequest, "VCS> postPullRequestCommentHandler> Invalid pull request id %s", muxVar(r, "id"))
+		}
+
+		var comment sdk.VCSPullRequestComment
+		if err := api.UnmarshalBody(r, &comment); err != nil {
+			return sdk.WrapError(err, "VCS> postPullRequestCommentHandler> Unable to read body")
+		}
+
+		accessToken, accessTokenSecret, ok := getAccessTokens(ctx)
+		if !ok {
+			return sdk.WrapError(sdk.ErrUnauthorized, "VCS> postPullRequestCommentHandler> Unable to get access token headers")
+		}
+
+		consumer, err := s.getConsumer(name)
+		if err != nil {
+			return sdk.WrapError(err, "VCS> postPullRequestCommentHandler> VCS server unavailable")
+		}
+
+		client, err := consumer.GetAuthorizedClient(accessToken, accessTokenSecret)
+		if err != nil {
+			return sdk.WrapError(err, "VCS> postPullRequestCommentHandler> Unable to get authorized client")
+		}
+
+		if err := client.PullRequestComment(fmt.Sprintf("%s/%s", owner, repo), id, comment); err != nil {
+			return sdk.WrapError(err, "VCS> postPullRequestCommentHandler> Unable to comment pull request %d on %s/%s", id, owner, repo)
+		}
+		return nil
+	}
+}
+
+func (s *Service) postStatusHandler() api.Handler {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+		name := muxVar(r, "name")
+		owner := muxVar(r, "owner")
+		repo := muxVar(r, "repo")
+
+		var event sdk.Event
+		if err := api.UnmarshalBody(r, &event); err != nil {
+			return sdk.WrapError(err, "VCS> postStatusHandler> Unable to read body")
+		}
+
+		accessToken, accessTokenSecret, ok := getAccessTokens(ctx)
+		if !ok {
+			return sdk.WrapError(sdk.ErrUnauthorized, "VCS> postStatusHandler> Unable to get access token headers")
+		}
+
+		consumer, err := s.getConsumer(name)
+		if err != nil {
+			return sdk.WrapError(err, "VCS> postStatusHandler> VCS server unavailable")
+		}
+
+		client, err := consumer.GetAuthorizedClient(accessToken, accessTokenSecret)
+		if err != nil {
+			return sdk.WrapError(err, "VCS> postStatusHandler> Unable to get authorized client")
+		}
+
+		if err := client.SetStatus(event); err != nil {
+			return sdk.WrapError(err, "VCS> postStatusHandler> Unable to set status on %s/%s", owner, repo)
+		}
+		return nil
+	}
+}
@@ -27,4 +27,8 @@ func (s *Service) initRouter(ctx context.Context) {
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}/branches/{branch}", r.GET(s.getBranchHandler))
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}/branches/{branch}/commits", r.GET(s.getCommitsHandler))
 	r.Handle("/vcs/{name}/repos/{owner}/{repo}/commits/{commit}", r.GET(s.getCommitHandler))
+	r.Handle("/vcs/{name}/repos/{owner}/{repo}/tags", r.GET(s.getTagsHandler))
+	r.Handle("/vcs/{name}/repos/{owner}/{repo}/pullrequests", r.GET(s.getPullRequestsHandler))
+	r.Handle("/vcs/{name}/repos/{owner}/{repo}/pullrequests/{id}/comments", r.POST(s.postPullRequestCommentHandler))
+	r.Handle("/vcs/{name}/repos/{owner}/{repo}/status", r.POST(s.postStatusHandler))
 }
@@ -22,6 +22,9 @@ func init() {
 	mapBuiltinActions[sdk.GitCloneAction] = runGitClone
 	mapBuiltinActions[sdk.GitTagAction] = runGitTag
 	mapBuiltinActions[sdk.ReleaseAction] = runRelease
+	mapBuiltinActions[sdk.CachePushAction] = runCachePush
+	mapBuiltinActions[sdk.CachePullAction] = runCachePull
+	mapBuiltinActions[sdk.CoverageAction] = runCoverage
 }
 
 // BuiltInAction defines builtin action signature
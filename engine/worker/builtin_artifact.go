@@ -2,7 +2,10 @@ package main
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"net/url"
 	"os"
 	"path"
@@ -237,7 +240,8 @@ func runArtifactDownload(w *currentWorker) BuiltInAction {
 				return res
 			}
 			sendLog(fmt.Sprintf("downloading artifact %s from workflow %s/%s on run %d...", destFile, project, workflow, n))
-			if err := w.client.WorkflowNodeRunArtifactDownload(project, workflow, a.ID, f); err != nil {
+			h := sha256.New()
+			if err := w.client.WorkflowNodeRunArtifactDownload(project, workflow, a.ID, io.MultiWriter(f, h)); err != nil {
 				res.Status = sdk.StatusFail.String()
 				res.Reason = err.Error()
 				log.Warning("Cannot download artifact %s: %s", destFile, err)
@@ -251,6 +255,14 @@ func runArtifactDownload(w *currentWorker) BuiltInAction {
 				sendLog(res.Reason)
 				return res
 			}
+			// Artifacts may be downloaded directly from the objectstore, check their integrity
+			if sum := hex.EncodeToString(h.Sum(nil)); a.SHA256sum != "" && sum != a.SHA256sum {
+				res.Status = sdk.StatusFail.String()
+				res.Reason = fmt.Sprintf("Artifact %s is corrupted: sha256 is %s, expected %s", destFile, sum, a.SHA256sum)
+				log.Warning("Cannot download artifact %s: %s", destFile, res.Reason)
+				sendLog(res.Reason)
+				return res
+			}
 		}
 
 		return res
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"archive/tar"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func runCachePush(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusSuccess.String()}
+
+		key := sdk.ParameterValue(a.Parameters, "key")
+		paths := cacheList(sdk.ParameterValue(a.Parameters, "path"))
+		if err := w.pushCache(buildID, key, ".", paths, sendLog); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Unable to push cache %s: %s", key, err)
+			sendLog(res.Reason)
+		}
+		return res
+	}
+}
+
+func runCachePull(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusSuccess.String()}
+
+		key := sdk.ParameterValue(a.Parameters, "key")
+		keys := append([]string{key}, cacheList(sdk.ParameterValue(a.Parameters, "fallback"))...)
+		dest := sdk.ParameterValue(a.Parameters, "path")
+		if dest == "" {
+			dest = "."
+		}
+		if err := w.pullCache(buildID, keys, dest, sendLog); err != nil {
+			res.Status = sdk.StatusFail.String()
+			res.Reason = fmt.Sprintf("Unable to pull cache %s: %s", key, err)
+			sendLog(res.Reason)
+		}
+		return res
+	}
+}
+
+// pushCache archives the paths, relative to a directory, and uploads the archive as the cache of the project with the given key
+func (w *currentWorker) pushCache(buildID int64, key, dir string, paths []string, sendLog LoggerFunc) error {
+	if w.currentJob.wJob == nil {
+		return fmt.Errorf("cache is only available in workflows")
+	}
+	if !sdk.IsValidWorkerCacheKey(key) {
+		return sdk.ErrInvalidWorkerCacheKey
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no path to archive")
+	}
+
+	tmp, err := ioutil.TempFile("", "cds-cache-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	errTar := tarPaths(tmp, dir, paths)
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if errTar != nil {
+		return errTar
+	}
+
+	stat, err := os.Stat(tmp.Name())
+	if err != nil {
+		return err
+	}
+	sendLog(fmt.Sprintf("Pushing cache %s (%d bytes): %s", key, stat.Size(), strings.Join(paths, ", ")))
+	return w.client.QueueCachePush(buildID, key, tmp.Name())
+}
+
+// pullCache downloads the cache of the project matching the first possible key and extracts it in a directory.
+// Nothing is done if no cache matches the keys
+func (w *currentWorker) pullCache(buildID int64, keys []string, dest string, sendLog LoggerFunc) error {
+	if w.currentJob.wJob == nil {
+		return fmt.Errorf("cache is only available in workflows")
+	}
+	for _, k := range keys {
+		if !sdk.IsValidWorkerCacheKey(k) {
+			return sdk.ErrInvalidWorkerCacheKey
+		}
+	}
+
+	cache, body, err := w.client.QueueCachePull(buildID, keys)
+	if err != nil {
+		return err
+	}
+	if cache == nil {
+		sendLog(fmt.Sprintf("No cache found for keys %s", strings.Join(keys, ", ")))
+		return nil
+	}
+	defer body.Close()
+
+	sendLog(fmt.Sprintf("Restoring cache %s (%d bytes) in %s", cache.Key, cache.Size, dest))
+	return untar(body, dest)
+}
+
+// cacheList returns the non empty lines of a parameter
+func cacheList(s string) []string {
+	var res []string
+	for _, l := range strings.Split(s, "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			res = append(res, l)
+		}
+	}
+	return res
+}
+
+// tarPaths writes a tar archive of files and directories. Paths must be relative to dir, they are kept in the archive
+func tarPaths(w io.Writer, dir string, paths []string) error {
+	tw := tar.NewWriter(w)
+	for _, p := range paths {
+		if filepath.IsAbs(p) || strings.HasPrefix(filepath.Clean(p), "..") {
+			return fmt.Errorf("path %s must be relative to the workspace", p)
+		}
+
+		errWalk := filepath.Walk(filepath.Join(dir, p), func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			name, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+
+			var link string
+			if info.Mode()&os.ModeSymlink != 0 {
+				if link, err = os.Readlink(path); err != nil {
+					return err
+				}
+			}
+			hdr, err := tar.FileInfoHeader(info, link)
+			if err != nil {
+				return err
+			}
+			hdr.Name = filepath.ToSlash(name)
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+
+			if !info.Mode().IsRegular() {
+				return nil
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(tw, f)
+			return err
+		})
+		if errWalk != nil {
+			return errWalk
+		}
+	}
+	return tw.Close()
+}
+
+// untar extracts a tar archive in a directory. Entries and symlinks outside of the directory are refused.
+// Symlinks are created last, so that no entry of the archive is written through them
+func untar(r io.Reader, dest string) error {
+	dest, err := filepath.Abs(dest)
+	if err != nil {
+		return err
+	}
+	realDest, err := resolvePath(dest)
+	if err != nil {
+		return err
+	}
+
+	var symlinks []*tar.Header
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, filepath.FromSlash(hdr.Name))
+		if !isInDir(dest, target) {
+			return fmt.Errorf("invalid path %s in archive", hdr.Name)
+		}
+		// The target can be outside of the directory through the symlinks which already exist
+		realTarget, err := resolvePath(target)
+		if err != nil {
+			return err
+		}
+		if !isInDir(realDest, realTarget) {
+			return fmt.Errorf("invalid path %s in archive", hdr.Name)
+		}
+
+		mode := hdr.FileInfo().Mode()
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, mode.Perm()); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			symlinks = append(symlinks, hdr)
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm())
+			if err != nil {
+				return err
+			}
+			_, errCopy := io.Copy(f, tr)
+			f.Close()
+			if errCopy != nil {
+				return errCopy
+			}
+		}
+	}
+
+	for _, hdr := range symlinks {
+		target := filepath.Join(dest, filepath.FromSlash(hdr.Name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		realDir, err := resolvePath(filepath.Dir(target))
+		if err != nil {
+			return err
+		}
+		if !isInDir(realDest, realDir) {
+			return fmt.Errorf("invalid path %s in archive", hdr.Name)
+		}
+		link := hdr.Linkname
+		if !filepath.IsAbs(link) {
+			link = filepath.Join(realDir, link)
+		}
+		realLink, err := resolvePath(link)
+		if err != nil {
+			return err
+		}
+		if !isInDir(realDest, realLink) {
+			return fmt.Errorf("invalid symlink %s -> %s in archive", hdr.Name, hdr.Linkname)
+		}
+		os.Remove(target)
+		if err := os.Symlink(hdr.Linkname, target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isInDir returns true if path is the directory dir or is inside it. Both paths must be clean
+func isInDir(dir, path string) bool {
+	return path == dir || strings.HasPrefix(path, dir+string(os.PathSeparator))
+}
+
+// resolvePath returns the path without symlinks. The last elements of the path may not exist yet
+func resolvePath(path string) (string, error) {
+	real, err := filepath.EvalSymlinks(path)
+	if err == nil {
+		return real, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", err
+	}
+	parent := filepath.Dir(path)
+	if parent == path {
+		return path, nil
+	}
+	realParent, err := resolvePath(parent)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(realParent, filepath.Base(path)), nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_tarPathsAndUntar(t *testing.T) {
+	src, err := ioutil.TempDir("", "cds-cache-src")
+	assert.NoError(t, err)
+	defer os.RemoveAll(src)
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(src, "deps", "lib"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(src, "deps", "lib", "a.txt"), []byte("a"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(src, "deps", "run.sh"), []byte("#!/bin/sh"), 0755))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(src, "other.txt"), []byte("other"), 0644))
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, tarPaths(buf, src, []string{"deps"}))
+	assert.Error(t, tarPaths(new(bytes.Buffer), src, []string{"/etc"}))
+	assert.Error(t, tarPaths(new(bytes.Buffer), src, []string{"../deps"}))
+
+	dest, err := ioutil.TempDir("", "cds-cache-dest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dest)
+
+	assert.NoError(t, untar(buf, dest))
+
+	content, err := ioutil.ReadFile(filepath.Join(dest, "deps", "lib", "a.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "a", string(content))
+
+	info, err := os.Stat(filepath.Join(dest, "deps", "run.sh"))
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+
+	_, err = os.Stat(filepath.Join(dest, "other.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func Test_untarRefusesPathsOutsideDestination(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "../evil.txt", Mode: 0644, Size: 4, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("evil"))
+	assert.NoError(t, err)
+	assert.NoError(t, tw.Close())
+
+	dest, err := ioutil.TempDir("", "cds-cache-dest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dest)
+
+	assert.Error(t, untar(buf, dest))
+	_, err = os.Stat(filepath.Join(filepath.Dir(dest), "evil.txt"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func Test_untarInWorkingDirectory(t *testing.T) {
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "deps/a.txt", Mode: 0644, Size: 1, Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte("a"))
+	assert.NoError(t, err)
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "deps/current", Linkname: "a.txt", Mode: 0777, Typeflag: tar.TypeSymlink}))
+	assert.NoError(t, tw.Close())
+
+	dest, err := ioutil.TempDir("", "cds-cache-dest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dest)
+
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(dest))
+	defer os.Chdir(wd)
+
+	assert.NoError(t, untar(buf, "."))
+
+	content, err := ioutil.ReadFile(filepath.Join(dest, "deps", "current"))
+	assert.NoError(t, err)
+	assert.Equal(t, "a", string(content))
+}
+
+func Test_untarRefusesSymlinksOutsideDestination(t *testing.T) {
+	outside, err := ioutil.TempDir("", "cds-cache-outside")
+	assert.NoError(t, err)
+	defer os.RemoveAll(outside)
+
+	for _, link := range []string{outside, "../" + filepath.Base(outside)} {
+		buf := new(bytes.Buffer)
+		tw := tar.NewWriter(buf)
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "a", Linkname: link, Mode: 0777, Typeflag: tar.TypeSymlink}))
+		assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "a/x", Mode: 0644, Size: 4, Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte("evil"))
+		assert.NoError(t, err)
+		assert.NoError(t, tw.Close())
+
+		dest, err := ioutil.TempDir("", "cds-cache-dest")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dest)
+
+		assert.Error(t, untar(buf, dest), link)
+		_, err = os.Stat(filepath.Join(outside, "x"))
+		assert.True(t, os.IsNotExist(err), link)
+	}
+}
+
+// A symlink of the workspace can't be used to write outside of the destination either
+func Test_untarRefusesPathsThroughExistingSymlinks(t *testing.T) {
+	outside, err := ioutil.TempDir("", "cds-cache-outside")
+	assert.NoError(t, err)
+	defer os.RemoveAll(outside)
+
+	dest, err := ioutil.TempDir("", "cds-cache-dest")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dest)
+	assert.NoError(t, os.Symlink(outside, filepath.Join(dest, "a")))
+
+	buf := new(bytes.Buffer)
+	tw := tar.NewWriter(buf)
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: "a/x", Mode: 0644, Size: 4, Typeflag: tar.TypeReg}))
+	_, err = tw.Write([]byte("evil"))
+	assert.NoError(t, err)
+	assert.NoError(t, tw.Close())
+
+	assert.Error(t, untar(buf, dest))
+	_, err = os.Stat(filepath.Join(outside, "x"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+func Test_cacheList(t *testing.T) {
+	assert.Equal(t, []string{"node_modules", ".m2/repository"}, cacheList("node_modules\n\n  .m2/repository \n"))
+	assert.Nil(t, cacheList(""))
+}
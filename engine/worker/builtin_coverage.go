@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func runCoverage(w *currentWorker) BuiltInAction {
+	return func(ctx context.Context, a *sdk.Action, buildID int64, params *[]sdk.Parameter, sendLog LoggerFunc) sdk.Result {
+		res := sdk.Result{Status: sdk.StatusFail.String()}
+
+		if w.currentJob.wJob == nil {
+			res.Reason = "Coverage parser: coverage is only available in workflows"
+			sendLog(res.Reason)
+			return res
+		}
+
+		var minimum float64
+		if m := sdk.ParameterValue(a.Parameters, "minimum"); m != "" {
+			var err error
+			minimum, err = strconv.ParseFloat(m, 64)
+			if err != nil || minimum < 0 || minimum > 100 {
+				res.Reason = fmt.Sprintf("Coverage parser: invalid minimum %s, it must be a percentage", m)
+				sendLog(res.Reason)
+				return res
+			}
+		}
+
+		format := sdk.ParameterValue(a.Parameters, "format")
+		report, files, err := parseCoverageFiles(format, sdk.ParameterValue(a.Parameters, "path"))
+		if err != nil {
+			res.Reason = fmt.Sprintf("Coverage parser: %s", err)
+			sendLog(res.Reason)
+			return res
+		}
+		report.Minimum = minimum
+
+		sendLog(fmt.Sprintf("Coverage parser: %d %s file(s) analyzed, %d source file(s)", files, format, len(report.Files)))
+		s := report.Summary
+		sendLog(fmt.Sprintf("Coverage parser: %.2f%% of lines covered (%d/%d)", s.Percent, s.LinesCovered, s.LinesTotal))
+		if s.BranchesTotal > 0 {
+			sendLog(fmt.Sprintf("Coverage parser: %.2f%% of branches covered (%d/%d)", float64(s.BranchesCovered)/float64(s.BranchesTotal)*100, s.BranchesCovered, s.BranchesTotal))
+		}
+
+		vcsStatus := sdk.ParameterValue(a.Parameters, "vcs_status") == "true"
+		if err := w.client.QueueSendCoverage(buildID, report, vcsStatus); err != nil {
+			res.Reason = fmt.Sprintf("Coverage parser: failed to send coverage: %s", err)
+			sendLog(res.Reason)
+			return res
+		}
+
+		if report.IsBelowMinimum() {
+			res.Reason = fmt.Sprintf("Coverage parser: coverage %.2f%% is below the minimum %.2f%%", s.Percent, minimum)
+			sendLog(res.Reason)
+			return res
+		}
+
+		res.Status = sdk.StatusSuccess.String()
+		return res
+	}
+}
+
+// parseCoverageFiles parses the files matching the glob pattern into a single report, it returns the number of files parsed
+func parseCoverageFiles(format, pattern string) (sdk.CoverageReport, int, error) {
+	if pattern == "" {
+		return sdk.CoverageReport{}, 0, fmt.Errorf("path not provided")
+	}
+
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return sdk.CoverageReport{}, 0, fmt.Errorf("cannot find requested files, invalid pattern")
+	}
+	if len(files) == 0 {
+		return sdk.CoverageReport{}, 0, fmt.Errorf("no file matches %s", pattern)
+	}
+
+	contents := make([][]byte, len(files))
+	for i, f := range files {
+		data, err := ioutil.ReadFile(f)
+		if err != nil {
+			return sdk.CoverageReport{}, 0, fmt.Errorf("cannot read file %s (%s)", f, err)
+		}
+		contents[i] = data
+	}
+
+	report, err := sdk.ParseCoverage(format, contents...)
+	if err != nil {
+		return sdk.CoverageReport{}, 0, err
+	}
+	return report, len(files), nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func Test_parseCoverageFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cds-coverage")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "a.out"), []byte("mode: set\na.go:1.1,2.2 3 1\n"), 0644))
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, "b.out"), []byte("mode: set\nb.go:1.1,2.2 1 0\n"), 0644))
+
+	report, n, err := parseCoverageFiles(sdk.CoverageFormatGo, filepath.Join(dir, "*.out"))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Len(t, report.Files, 2)
+	assert.Equal(t, 75.0, report.Summary.Percent)
+
+	_, _, err = parseCoverageFiles(sdk.CoverageFormatGo, "")
+	assert.Error(t, err)
+	_, _, err = parseCoverageFiles(sdk.CoverageFormatGo, filepath.Join(dir, "*.xml"))
+	assert.Error(t, err)
+	_, _, err = parseCoverageFiles(sdk.CoverageFormatCobertura, filepath.Join(dir, "*.out"))
+	assert.Error(t, err)
+}
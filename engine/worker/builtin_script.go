@@ -125,6 +125,7 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 
 			log.Info("runScriptAction> %s %s", shell, strings.Trim(fmt.Sprint(opts), "[]"))
 			cmd := exec.CommandContext(ctx, shell, opts...)
+			setProcessGroup(cmd)
 			res.Status = sdk.StatusUnknown.String()
 
 			env := os.Environ()
@@ -239,10 +240,24 @@ func runScriptAction(w *currentWorker) BuiltInAction {
 				chanRes <- res
 			}
 
+			// Kill the whole process tree when the step is cancelled or timed out
+			stopKiller := make(chan struct{})
+			go func() {
+				select {
+				case <-ctx.Done():
+					if err := killProcessTree(cmd); err != nil {
+						log.Warning("runScriptAction> cannot kill process tree: %s", err)
+					}
+				case <-stopKiller:
+				}
+			}()
+
 			<-outchan
 			<-errchan
-			if err := cmd.Wait(); err != nil {
-				res.Reason = fmt.Sprintf("%s\n", err)
+			errWait := cmd.Wait()
+			close(stopKiller)
+			if errWait != nil {
+				res.Reason = fmt.Sprintf("%s\n", errWait)
 				sendLog(res.Reason)
 				res.Status = sdk.StatusFail.String()
 				chanRes <- res
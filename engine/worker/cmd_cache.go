@@ -0,0 +1,165 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+// cacheRequest is sent by the worker cache commands to the worker
+type cacheRequest struct {
+	Keys      []string `json:"keys"`
+	Directory string   `json:"directory"`
+	Paths     []string `json:"paths,omitempty"`
+}
+
+var cmdCachePullDestination string
+
+func cmdCache(w *currentWorker) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "cache",
+		Short: "worker cache push|pull",
+	}
+
+	push := &cobra.Command{
+		Use:   "push",
+		Short: "worker cache push <key> <path>...",
+		Long:  "Archive files and directories, relative to the current directory, in the cache of the project with the given key",
+		Run:   cachePushCmd(w),
+	}
+
+	pull := &cobra.Command{
+		Use:   "pull",
+		Short: "worker cache pull <key> [<fallback key>...]",
+		Long:  "Restore the cache of the project matching the key. If no cache matches, the most recent cache whose key starts with a fallback key is restored",
+		Run:   cachePullCmd(w),
+	}
+	pull.Flags().StringVar(&cmdCachePullDestination, "destination", "", "Directory where the cache is extracted, current directory by default")
+
+	c.AddCommand(push, pull)
+	return c
+}
+
+func cachePushCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		if len(args) < 2 {
+			sdk.Exit("Wrong usage: Example : worker cache push deps-{{.git.hash}} node_modules .m2/repository\n")
+		}
+
+		dir, err := os.Getwd()
+		if err != nil {
+			sdk.Exit("cannot get current directory: %s\n", err)
+		}
+
+		sendCacheRequest("push", cacheRequest{Keys: args[:1], Directory: dir, Paths: args[1:]})
+	}
+}
+
+func cachePullCmd(w *currentWorker) func(cmd *cobra.Command, args []string) {
+	return func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			sdk.Exit("Wrong usage: Example : worker cache pull deps-{{.git.hash}} deps-\n")
+		}
+
+		dir := cmdCachePullDestination
+		if dir == "" {
+			var err error
+			if dir, err = os.Getwd(); err != nil {
+				sdk.Exit("cannot get current directory: %s\n", err)
+			}
+		}
+
+		sendCacheRequest("pull", cacheRequest{Keys: args, Directory: dir})
+	}
+}
+
+func sendCacheRequest(action string, c cacheRequest) {
+	portS := os.Getenv(WorkerServerPort)
+	if portS == "" {
+		sdk.Exit("%s not found, are you running inside a CDS worker job?\n", WorkerServerPort)
+	}
+
+	port, errPort := strconv.Atoi(portS)
+	if errPort != nil {
+		sdk.Exit("cannot parse '%s' as a port number", portS)
+	}
+
+	data, errMarshal := json.Marshal(c)
+	if errMarshal != nil {
+		sdk.Exit("internal error (%s)\n", errMarshal)
+	}
+
+	req, errRequest := http.NewRequest("POST", fmt.Sprintf("http://127.0.0.1:%d/cache/%s", port, action), bytes.NewReader(data))
+	if errRequest != nil {
+		sdk.Exit("cannot cache %s (Request): %s\n", action, errRequest)
+	}
+
+	client := http.DefaultClient
+	client.Timeout = 30 * time.Minute
+
+	resp, errDo := client.Do(req)
+	if errDo != nil {
+		sdk.Exit("cannot cache %s (Do): %s\n", action, errDo)
+	}
+
+	if resp.StatusCode >= 300 {
+		sdk.Exit("cannot cache %s: HTTP %d\n", action, resp.StatusCode)
+	}
+}
+
+func (wk *currentWorker) readCacheRequest(w http.ResponseWriter, r *http.Request) (*cacheRequest, bool) {
+	data, errRead := ioutil.ReadAll(r.Body)
+	if errRead != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return nil, false
+	}
+
+	var c cacheRequest
+	if err := json.Unmarshal(data, &c); err != nil || len(c.Keys) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		return nil, false
+	}
+
+	if wk.currentJob.wJob == nil {
+		log.Error("readCacheRequest> Cache is only available in workflows")
+		w.WriteHeader(http.StatusBadRequest)
+		return nil, false
+	}
+	return &c, true
+}
+
+func (wk *currentWorker) cachePushHandler(w http.ResponseWriter, r *http.Request) {
+	c, ok := wk.readCacheRequest(w, r)
+	if !ok {
+		return
+	}
+
+	sendLog := getLogger(wk, wk.currentJob.wJob.ID, wk.currentJob.currentStep)
+	if err := wk.pushCache(wk.currentJob.wJob.ID, c.Keys[0], c.Directory, c.Paths, sendLog); err != nil {
+		sendLog(fmt.Sprintf("Unable to push cache %s: %s", c.Keys[0], err))
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func (wk *currentWorker) cachePullHandler(w http.ResponseWriter, r *http.Request) {
+	c, ok := wk.readCacheRequest(w, r)
+	if !ok {
+		return
+	}
+
+	sendLog := getLogger(wk, wk.currentJob.wJob.ID, wk.currentJob.currentStep)
+	if err := wk.pullCache(wk.currentJob.wJob.ID, c.Keys, c.Directory, sendLog); err != nil {
+		sendLog(fmt.Sprintf("Unable to pull cache %s: %s", c.Keys[0], err))
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
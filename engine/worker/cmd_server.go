@@ -35,6 +35,8 @@ func (w *currentWorker) serve(c context.Context) (int, error) {
 	r.HandleFunc("/var", w.addBuildVarHandler)
 	r.HandleFunc("/upload", w.uploadHandler)
 	r.HandleFunc("/tmpl", w.tmplHandler)
+	r.HandleFunc("/cache/push", w.cachePushHandler)
+	r.HandleFunc("/cache/pull", w.cachePullHandler)
 
 	srv := &http.Server{
 		Handler:      r,
@@ -54,6 +54,7 @@ func main() {
 	cmd := cmdMain(w)
 	cmd.AddCommand(cmdExport)
 	cmd.AddCommand(cmdUpload(w))
+	cmd.AddCommand(cmdCache(w))
 	cmd.AddCommand(cmdTmpl(w))
 	cmd.AddCommand(cmdVersion)
 	cmd.AddCommand(cmdRegister(w))
@@ -0,0 +1,34 @@
+// +build !windows
+
+package main
+
+import (
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_killProcessTree(t *testing.T) {
+	// The child sleep keeps the output open: Wait returns only if the whole tree is killed
+	cmd := exec.Command("sh", "-c", "sleep 60 & sleep 60")
+	cmd.Stdout = &nopWriter{}
+	setProcessGroup(cmd)
+	assert.NoError(t, cmd.Start())
+
+	done := make(chan error)
+	go func() { done <- cmd.Wait() }()
+
+	assert.NoError(t, killProcessTree(cmd))
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("process tree not killed")
+	}
+}
+
+type nopWriter struct{}
+
+func (nopWriter) Write(p []byte) (int, error) { return len(p), nil }
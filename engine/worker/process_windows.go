@@ -0,0 +1,17 @@
+package main
+
+import (
+	"os/exec"
+	"strconv"
+)
+
+// setProcessGroup does nothing on windows, taskkill finds the children of the process
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessTree kills a command and all its children
+func killProcessTree(cmd *exec.Cmd) error {
+	if cmd.Process == nil {
+		return nil
+	}
+	return exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(cmd.Process.Pid)).Run()
+}
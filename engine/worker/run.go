@@ -18,6 +18,9 @@ import (
 	"github.com/ovh/cds/sdk/vcs"
 )
 
+// defaultJobTimeout is the timeout of the jobs without timeout
+const defaultJobTimeout = 6 * time.Hour
+
 func processJobParameter(params *[]sdk.Parameter, secrets []sdk.Variable) {
 	parameters := *params
 
@@ -213,7 +216,13 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 			}
 			w.sendLog(buildID, fmt.Sprintf("Starting step %s\n", childName), w.currentJob.currentStep, false)
 
-			r = w.startAction(ctx, &child, buildID, params, w.currentJob.currentStep, childName)
+			r = w.startStep(ctx, &child, buildID, params, childName)
+			for retry := 1; r.Status == sdk.StatusFail.String() && retry <= child.Retry && ctx.Err() == nil; retry++ {
+				if !w.waitStepRetry(ctx, buildID, childName, retry, child.Retry, sdk.RetryDelay(child.RetryDelay, retry)) {
+					break
+				}
+				r = w.startStep(ctx, &child, buildID, params, childName)
+			}
 			if r.Status != sdk.StatusSuccess.String() && !child.Optional {
 				criticalStepFailed = true
 			}
@@ -241,6 +250,66 @@ func (w *currentWorker) runSteps(ctx context.Context, steps []sdk.Action, a *sdk
 	return r, nbDisabledChildren
 }
 
+// startStep runs a step. The step is stopped and fails if it lasts more than its timeout
+func (w *currentWorker) startStep(ctx context.Context, step *sdk.Action, buildID int64, params *[]sdk.Parameter, stepName string) sdk.Result {
+	if step.Timeout <= 0 {
+		return w.startAction(ctx, step, buildID, params, w.currentJob.currentStep, stepName)
+	}
+
+	timeout := time.Duration(step.Timeout) * time.Second
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	r := w.startAction(stepCtx, step, buildID, params, w.currentJob.currentStep, stepName)
+	if stepCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		r.Status = sdk.StatusFail.String()
+		r.Reason = fmt.Sprintf("Step %s timed out after %s", stepName, timeout)
+		w.sendLog(buildID, r.Reason+"\n", w.currentJob.currentStep, false)
+	}
+	return r
+}
+
+// jobTimeout returns the timeout of a job, defaultJobTimeout if the job has no timeout
+func jobTimeout(j *sdk.Job) time.Duration {
+	if j.Timeout > 0 {
+		return time.Duration(j.Timeout) * time.Second
+	}
+	return defaultJobTimeout
+}
+
+// checkJobTimeout fails the result of a job stopped by its timeout
+func (w *currentWorker) checkJobTimeout(ctx context.Context, res *sdk.Result, buildID int64, timeout time.Duration) {
+	if ctx.Err() != context.DeadlineExceeded {
+		return
+	}
+	res.Status = sdk.StatusFail.String()
+	res.Reason = fmt.Sprintf("Job timed out after %s", timeout)
+	w.sendLog(buildID, res.Reason+"\n", w.currentJob.currentStep, false)
+}
+
+// waitStepRetry records a new attempt of a failed step in the logs and the spawn infos of the job,
+// then waits for the retry delay. It returns false if the job has been cancelled meanwhile
+func (w *currentWorker) waitStepRetry(ctx context.Context, buildID int64, stepName string, retry, maxRetry int, delay time.Duration) bool {
+	w.sendLog(buildID, fmt.Sprintf("Step %s failed, retry %d/%d in %s\n", stepName, retry, maxRetry, delay), w.currentJob.currentStep, false)
+
+	if w.currentJob.wJob != nil {
+		info := sdk.SpawnInfo{
+			RemoteTime: time.Now(),
+			Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoStepRetry.ID, Args: []interface{}{stepName, w.status.Name, fmt.Sprintf("%d", retry), fmt.Sprintf("%d", maxRetry), delay.String()}},
+		}
+		if err := w.client.QueueJobSendStepSpawnInfo(buildID, []sdk.SpawnInfo{info}); err != nil {
+			log.Warning("Cannot record retry of step %s for build %d: %s", stepName, buildID, err)
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(delay):
+		return true
+	}
+}
+
 func (w *currentWorker) updateStepStatus(pbJobID int64, stepOrder int, status string) error {
 	step := sdk.StepStatus{
 		StepOrder: stepOrder,
@@ -310,7 +379,8 @@ func workingDirectory(basedir, jobPath string) string {
 
 func (w *currentWorker) processJob(ctx context.Context, jobInfo *worker.WorkflowNodeJobRunInfo) sdk.Result {
 	t0 := time.Now()
-	ctx, cancel := context.WithTimeout(ctx, 6*time.Hour)
+	timeout := jobTimeout(&jobInfo.NodeJobRun.Job.Job)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 
 	log.Debug("processJob> Begin %p", ctx)
 	defer log.Debug("processJob> End %p", ctx)
@@ -401,6 +471,7 @@ func (w *currentWorker) processJob(ctx context.Context, jobInfo *worker.Workflow
 
 	logsecrets = jobInfo.Secrets
 	res := w.startAction(ctx, &jobInfo.NodeJobRun.Job.Action, jobInfo.NodeJobRun.ID, &jobInfo.NodeJobRun.Parameters, -1, "")
+	w.checkJobTimeout(ctx, &res, jobInfo.NodeJobRun.ID, timeout)
 	logsecrets = nil
 
 	if err := teardownBuildDirectory(wd); err != nil {
@@ -410,7 +481,8 @@ func (w *currentWorker) processJob(ctx context.Context, jobInfo *worker.Workflow
 }
 
 func (w *currentWorker) run(ctx context.Context, pbji *worker.PipelineBuildJobInfo) sdk.Result {
-	ctx, cancel := context.WithTimeout(ctx, 6*time.Hour)
+	timeout := jobTimeout(&pbji.PipelineBuildJob.Job.Job)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	log.Debug("run> Begin %p", ctx)
@@ -506,6 +578,7 @@ func (w *currentWorker) run(ctx context.Context, pbji *worker.PipelineBuildJobIn
 	logsecrets = pbji.Secrets
 
 	res := w.startAction(ctx, &pbji.PipelineBuildJob.Job.Action, pbji.PipelineBuildJob.ID, &pbji.PipelineBuildJob.Parameters, -1, "")
+	w.checkJobTimeout(ctx, &res, pbji.PipelineBuildJob.ID, timeout)
 	logsecrets = nil
 
 	if err := teardownBuildDirectory(wd); err != nil {
@@ -2,6 +2,7 @@ package main
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -50,3 +51,8 @@ func Test_processJobParameter(t *testing.T) {
 		assert.EqualValues(t, tt.want, tt.args.pbJob.Parameters)
 	}
 }
+
+func Test_jobTimeout(t *testing.T) {
+	assert.Equal(t, defaultJobTimeout, jobTimeout(&sdk.Job{}))
+	assert.Equal(t, 90*time.Second, jobTimeout(&sdk.Job{Timeout: 90}))
+}
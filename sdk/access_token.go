@@ -0,0 +1,64 @@
+package sdk
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Scopes of the personal access tokens. The admin scope is given on a project: admin:<project key>
+const (
+	AccessTokenScopeRead  = "read"
+	AccessTokenScopeRun   = "run"
+	AccessTokenScopeAdmin = "admin"
+)
+
+// AccessTokenPrefix starts all the personal access tokens, so that they can be found by secret scanners
+const AccessTokenPrefix = "cdspat_"
+
+// AccessToken is a personal access token used by automation to call the API as a user, with restricted permissions
+type AccessToken struct {
+	ID       int64      `json:"id" db:"id" cli:"id,key"`
+	UserID   int64      `json:"user_id" db:"user_id"`
+	Name     string     `json:"name" db:"name" cli:"name"`
+	Scopes   []string   `json:"scopes" db:"-" cli:"scopes"`
+	Hash     string     `json:"-" db:"hash"`
+	Created  time.Time  `json:"created" db:"created" cli:"created"`
+	Expire   time.Time  `json:"expire" db:"expire" cli:"expire"`
+	LastUsed *time.Time `json:"last_used,omitempty" db:"last_used" cli:"last_used"`
+	// Token is only returned when the token is created
+	Token string `json:"token,omitempty" db:"-" cli:"token"`
+}
+
+// IsExpired returns true if the token cannot be used anymore
+func (t AccessToken) IsExpired(now time.Time) bool {
+	return !now.Before(t.Expire)
+}
+
+// IsValidAccessTokenScope returns true for read, run and admin:<project key> scopes
+func IsValidAccessTokenScope(s string) bool {
+	if s == AccessTokenScopeRead || s == AccessTokenScopeRun {
+		return true
+	}
+	_, ok := AccessTokenScopeProject(s)
+	return ok
+}
+
+// AccessTokenScopeProject returns the project key of an admin scope
+func AccessTokenScopeProject(s string) (string, bool) {
+	if !strings.HasPrefix(s, AccessTokenScopeAdmin+":") {
+		return "", false
+	}
+	key := strings.TrimPrefix(s, AccessTokenScopeAdmin+":")
+	if !regexp.MustCompile(ProjectKeyPattern).MatchString(key) {
+		return "", false
+	}
+	return key, true
+}
+
+// AccessTokenRequest is sent to create a personal access token
+type AccessTokenRequest struct {
+	Name   string    `json:"name"`
+	Scopes []string  `json:"scopes"`
+	Expire time.Time `json:"expire"`
+}
@@ -0,0 +1,28 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidAccessTokenScope(t *testing.T) {
+	assert.True(t, IsValidAccessTokenScope("read"))
+	assert.True(t, IsValidAccessTokenScope("run"))
+	assert.True(t, IsValidAccessTokenScope("admin:PROJ1"))
+	assert.False(t, IsValidAccessTokenScope("admin"))
+	assert.False(t, IsValidAccessTokenScope("admin:proj"))
+	assert.False(t, IsValidAccessTokenScope("write"))
+
+	key, ok := AccessTokenScopeProject("admin:PROJ1")
+	assert.True(t, ok)
+	assert.Equal(t, "PROJ1", key)
+}
+
+func TestAccessTokenIsExpired(t *testing.T) {
+	now := time.Now()
+	assert.False(t, AccessToken{Expire: now.Add(time.Hour)}.IsExpired(now))
+	assert.True(t, AccessToken{Expire: now}.IsExpired(now))
+	assert.True(t, AccessToken{Expire: now.Add(-time.Hour)}.IsExpired(now))
+}
@@ -20,6 +20,9 @@ type Action struct {
 	Deprecated     bool          `json:"deprecated" yaml:"-"`
 	Optional       bool          `json:"optional" yaml:"-"`
 	AlwaysExecuted bool          `json:"always_executed" yaml:"-"`
+	Retry          int           `json:"retry,omitempty" yaml:"-"`
+	RetryDelay     int           `json:"retry_delay,omitempty" yaml:"-"`
+	Timeout        int           `json:"timeout,omitempty" yaml:"-"`
 	LastModified   int64         `json:"last_modified" cli:"modified"`
 }
 
@@ -67,11 +70,14 @@ const (
 
 // Builtin Action
 const (
-	ScriptAction   = "Script"
-	JUnitAction    = "JUnit"
-	GitCloneAction = "GitClone"
-	GitTagAction   = "GitTag"
-	ReleaseAction  = "Release"
+	ScriptAction    = "Script"
+	JUnitAction     = "JUnit"
+	GitCloneAction  = "GitClone"
+	GitTagAction    = "GitTag"
+	ReleaseAction   = "Release"
+	CachePushAction = "CachePush"
+	CachePullAction = "CachePull"
+	CoverageAction  = "Coverage"
 )
 
 // NewAction instanciate a new Action
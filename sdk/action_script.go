@@ -93,6 +93,36 @@ func NewStepRelease(v map[string]string) Action {
 	return newAction
 }
 
+// NewStepCachePush returns an action (basically used as a step of a job) of CachePush type
+func NewStepCachePush(v map[string]string) Action {
+	newAction := Action{
+		Name:       CachePushAction,
+		Type:       BuiltinAction,
+		Parameters: ParametersFromMap(v),
+	}
+	return newAction
+}
+
+// NewStepCachePull returns an action (basically used as a step of a job) of CachePull type
+func NewStepCachePull(v map[string]string) Action {
+	newAction := Action{
+		Name:       CachePullAction,
+		Type:       BuiltinAction,
+		Parameters: ParametersFromMap(v),
+	}
+	return newAction
+}
+
+// NewStepCoverage returns an action (basically used as a step of a job) of Coverage type
+func NewStepCoverage(v map[string]string) Action {
+	newAction := Action{
+		Name:       CoverageAction,
+		Type:       BuiltinAction,
+		Parameters: ParametersFromMap(v),
+	}
+	return newAction
+}
+
 // NewStepArtifactUpload returns an action (basically used as a step of a job) of artifact upload type
 func NewStepArtifactUpload(v map[string]string) Action {
 	newAction := Action{
@@ -3,6 +3,7 @@ package sdk
 import (
 	"bytes"
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -33,6 +34,7 @@ type Artifact struct {
 	Size         int64  `json:"size,omitempty" cli:"size"`
 	Perm         uint32 `json:"perm,omitempty"`
 	MD5sum       string `json:"md5sum,omitempty" cli:"md5sum"`
+	SHA256sum    string `json:"sha256sum,omitempty" cli:"sha256sum"`
 	ObjectPath   string `json:"object_path,omitempty"`
 }
 
@@ -100,15 +102,21 @@ func download(project, app, pip string, a Artifact, destdir string) error {
 			mode = os.FileMode(a.Perm)
 		}
 
-		f, err := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY, mode)
+		f, err := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
 		if err != nil {
 			lasterr = err
 			continue
 		}
 
-		_, err = io.Copy(f, reader)
+		// Artifacts may be downloaded directly from the objectstore, check their integrity
+		h := sha256.New()
+		_, err = io.Copy(io.MultiWriter(f, h), reader)
+		reader.Close()
 		if err != nil {
 			lasterr = err
+		} else if sum := hex.EncodeToString(h.Sum(nil)); a.SHA256sum != "" && sum != a.SHA256sum {
+			err = fmt.Errorf("artifact %s is corrupted: sha256 is %s, expected %s", a.Name, sum, a.SHA256sum)
+			lasterr = err
 		}
 
 		f.Close()
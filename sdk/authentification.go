@@ -28,3 +28,30 @@ func NewAuth(hashedToken string) *Auth {
 	}
 	return a
 }
+
+// OIDCLoginResponse gives the URL of the OpenID Connect provider where the user has to log in
+type OIDCLoginResponse struct {
+	URL string `json:"url"`
+}
+
+// OIDCCallbackRequest is sent by the UI when the OpenID Connect provider redirects the user after login
+type OIDCCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
+
+// OIDCDeviceAuthorization is returned by the OpenID Connect provider at the beginning of the device flow.
+// The user has to open the verification URI and enter the user code, meanwhile the device code is used to poll the token
+type OIDCDeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval,omitempty"`
+}
+
+// OIDCDeviceTokenRequest is sent by the CLI to poll the token of a device flow
+type OIDCDeviceTokenRequest struct {
+	DeviceCode string `json:"device_code"`
+}
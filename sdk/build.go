@@ -44,6 +44,8 @@ type ExecutedJob struct {
 	Reason     string       `json:"reason" db:"-"`
 	WorkerName string       `json:"worker_name" db:"-"`
 	WorkerID   string       `json:"worker_id" db:"-"`
+	// MatrixValues are the values of the matrix variables of the job run, if the job has a matrix
+	MatrixValues map[string]string `json:"matrix_values,omitempty" db:"-"`
 }
 
 // StepStatus Represent a step and his status
@@ -82,6 +84,8 @@ func StatusFromString(in string) Status {
 		return StatusDisabled
 	case StatusSkipped.String():
 		return StatusSkipped
+	case StatusStopped.String():
+		return StatusStopped
 	default:
 		return StatusUnknown
 	}
@@ -91,6 +95,15 @@ func (t Status) String() string {
 	return string(t)
 }
 
+// StatusIsTerminated returns true if a status is final: the build will not change anymore
+func StatusIsTerminated(status string) bool {
+	switch status {
+	case StatusSuccess.String(), StatusFail.String(), StatusStopped.String(), StatusSkipped.String(), StatusDisabled.String(), StatusNeverBuilt.String():
+		return true
+	}
+	return false
+}
+
 // Action status in queue
 const (
 	StatusWaiting    Status = "Waiting"
@@ -25,7 +25,8 @@ func New(c Config) Interface {
 	cli := new(client)
 	cli.config = c
 	cli.HTTPClient = &http.Client{
-		Timeout: time.Second * 10,
+		Timeout:       time.Second * 10,
+		CheckRedirect: sdk.CheckRedirect,
 	}
 	cli.init()
 	return cli
@@ -40,7 +41,8 @@ func NewService(endpoint string) Interface {
 	cli := new(client)
 	cli.config = conf
 	cli.HTTPClient = &http.Client{
-		Timeout: time.Second * 10,
+		Timeout:       time.Second * 10,
+		CheckRedirect: sdk.CheckRedirect,
 	}
 	cli.isService = true
 	cli.init()
@@ -56,7 +58,8 @@ func NewWorker(endpoint string, name string) Interface {
 	cli := new(client)
 	cli.config = conf
 	cli.HTTPClient = &http.Client{
-		Timeout: time.Second * 10,
+		Timeout:       time.Second * 10,
+		CheckRedirect: sdk.CheckRedirect,
 	}
 	cli.isWorker = true
 	cli.name = name
@@ -79,6 +82,7 @@ func NewHatchery(endpoint string, token string, requestSecondsTimeout int, insec
 			MaxTries:        5,
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: insecureSkipVerifyTLS},
 		},
+		CheckRedirect: sdk.CheckRedirect,
 	}
 
 	// hatchery don't need to make a request without timeout on API
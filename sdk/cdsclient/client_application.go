@@ -53,3 +53,45 @@ func (c *client) ApplicationList(key string) ([]sdk.Application, error) {
 	}
 	return apps, nil
 }
+
+func (c *client) ApplicationVCSBranches(key string, appName string) ([]sdk.VCSBranch, error) {
+	branches := []sdk.VCSBranch{}
+	code, err := c.GetJSON("/project/"+key+"/application/"+appName+"/vcs/branches", &branches)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return branches, nil
+}
+
+func (c *client) ApplicationVCSCommit(key string, appName string, hash string) (*sdk.VCSCommit, error) {
+	commit := &sdk.VCSCommit{}
+	code, err := c.GetJSON("/project/"+key+"/application/"+appName+"/vcs/commits/"+hash, commit)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return commit, nil
+}
+
+func (c *client) ApplicationVCSTags(key string, appName string) ([]sdk.VCSTag, error) {
+	tags := []sdk.VCSTag{}
+	code, err := c.GetJSON("/project/"+key+"/application/"+appName+"/vcs/tags", &tags)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
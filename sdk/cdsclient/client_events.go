@@ -0,0 +1,96 @@
+package cdsclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// EventsListen connects to the events stream of the API and sends every received event in chanEvents.
+// Events can be filtered by projects and workflows. It blocks until the context is done or the stream is closed
+func (c *client) EventsListen(ctx context.Context, projects, workflows []string, chanEvents chan<- sdk.Event) error {
+	query := url.Values{}
+	for _, p := range projects {
+		query.Add("project", p)
+	}
+	for _, w := range workflows {
+		query.Add("workflow", w)
+	}
+	path := "/events"
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	body, code, err := c.Stream(http.MethodGet, path, nil, true, SetHeader("Accept", "text/event-stream"))
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if code >= 400 {
+		b, _ := ioutil.ReadAll(body)
+		if cdserr := sdk.DecodeError(b); cdserr != nil {
+			return cdserr
+		}
+		return fmt.Errorf("HTTP Code %d", code)
+	}
+
+	return readEventStream(ctx, body, func(data string) error {
+		var e sdk.Event
+		if err := json.Unmarshal([]byte(data), &e); err != nil {
+			return sdk.WrapError(err, "Unable to read event %s", data)
+		}
+		select {
+		case chanEvents <- e:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// readEventStream reads a server-sent events stream and calls f with the data of every event.
+// It blocks until the context is done, the stream is closed or f returns an error
+func readEventStream(ctx context.Context, body io.ReadCloser, f func(data string) error) error {
+	// Closing the body stops the reading loop
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			body.Close()
+		case <-done:
+		}
+	}()
+
+	reader := bufio.NewReader(body)
+	var data string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " ")
+		case line == "" && data != "":
+			if err := f(data); err != nil {
+				return err
+			}
+			data = ""
+		}
+	}
+}
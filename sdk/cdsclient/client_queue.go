@@ -44,7 +44,7 @@ func (c *client) QueuePolling(ctx context.Context, jobs chan<- sdk.WorkflowNodeJ
 
 			if jobs != nil {
 				queue := []sdk.WorkflowNodeJobRun{}
-				if _, err := c.GetJSON("/queue/workflows", &queue); err != nil {
+				if _, err := c.GetJSON("/queue/workflows?skipUnserviceable=true", &queue); err != nil {
 					errs <- sdk.WrapError(err, "Unable to load old jobs")
 				}
 				for _, j := range queue {
@@ -58,7 +58,7 @@ func (c *client) QueuePolling(ctx context.Context, jobs chan<- sdk.WorkflowNodeJ
 
 			if jobs != nil {
 				queue := []sdk.WorkflowNodeJobRun{}
-				if _, err := c.GetJSON("/queue/workflows", &queue, SetHeader("If-Modified-Since", t0.Format(time.RFC1123))); err != nil {
+				if _, err := c.GetJSON("/queue/workflows?skipUnserviceable=true", &queue, SetHeader("If-Modified-Since", t0.Format(time.RFC1123))); err != nil {
 					errs <- sdk.WrapError(err, "Unable to load jobs")
 				}
 				// Gracetime to remove, see https://github.com/ovh/cds/issues/1214
@@ -155,6 +155,17 @@ func (c *client) QueueJobSendSpawnInfo(isWorkflowJob bool, id int64, in []sdk.Sp
 	return nil
 }
 
+// QueueJobSendStepSpawnInfo sends a spawn info on a job taken by the worker, such as the retry of a step
+func (c *client) QueueJobSendStepSpawnInfo(id int64, in []sdk.SpawnInfo) error {
+	path := fmt.Sprintf("/queue/workflows/%d/step/spawn/infos", id)
+	if code, err := c.PostJSON(path, &in, nil); err != nil {
+		return err
+	} else if code != http.StatusOK {
+		return fmt.Errorf("HTTP Error: %d", code)
+	}
+	return nil
+}
+
 // QueueJobBook books a job for a Hatchery
 func (c *client) QueueJobBook(isWorkflowJob bool, id int64) error {
 	path := fmt.Sprintf("/queue/workflows/%d/book", id)
@@ -181,6 +192,21 @@ func (c *client) QueueSendResult(id int64, res sdk.Result) error {
 	return nil
 }
 
+// QueueSendCoverage sends the coverage of a job, and asks the API to send it as commit status through the VCS µservice
+func (c *client) QueueSendCoverage(id int64, report sdk.CoverageReport, vcsStatus bool) error {
+	path := fmt.Sprintf("/queue/workflows/%d/coverage", id)
+	if vcsStatus {
+		path += "?vcs_status=true"
+	}
+
+	if code, err := c.PostJSON(path, report, nil); err != nil {
+		return err
+	} else if code != http.StatusOK {
+		return fmt.Errorf("HTTP Error: %d", code)
+	}
+	return nil
+}
+
 func (c *client) QueueArtifactUpload(id int64, tag, filePath string) error {
 	fileForMD5, errop := os.Open(filePath)
 	if errop != nil {
@@ -0,0 +1,80 @@
+package cdsclient
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// QueueCachePush uploads a tar archive as the cache of the project of a job, with the given key
+func (c *client) QueueCachePush(id int64, key, tarPath string) error {
+	f, err := os.Open(tarPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	// The archive is sent again from its beginning on each retry
+	setBody := func(req *http.Request) {
+		f.Seek(0, io.SeekStart)
+		req.Body = ioutil.NopCloser(f)
+		req.ContentLength = stat.Size()
+	}
+
+	uri := fmt.Sprintf("/queue/workflows/%d/cache/%s", id, url.PathEscape(key))
+	body, code, err := c.Stream("POST", uri, nil, true, SetHeader("Content-Type", "application/x-tar"), setBody)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	res, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if err := sdk.DecodeError(res); err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("HTTP %d", code)
+	}
+	return nil
+}
+
+// QueueCachePull returns the cache of the project of a job matching the first possible key, and its tar archive.
+// It returns a nil cache if no cache matches the keys
+func (c *client) QueueCachePull(id int64, keys []string) (*sdk.WorkerCache, io.ReadCloser, error) {
+	var cache sdk.WorkerCache
+	q := url.Values{"key": keys}
+	code, err := c.GetJSON(fmt.Sprintf("/queue/workflows/%d/cache?%s", id, q.Encode()), &cache)
+	if code == http.StatusNotFound {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body, code, err := c.Stream("GET", fmt.Sprintf("/queue/workflows/%d/cache/%s", id, url.PathEscape(cache.Key)), nil, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	if code >= 300 {
+		res, _ := ioutil.ReadAll(body)
+		body.Close()
+		if err := sdk.DecodeError(res); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("HTTP %d", code)
+	}
+	return &cache, body, nil
+}
@@ -0,0 +1,67 @@
+package cdsclient
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func quotaPath(scope, name string) string {
+	return fmt.Sprintf("/quota/%s/%s", url.PathEscape(scope), url.PathEscape(name))
+}
+
+func (c *client) QuotaDelete(scope, name string) error {
+	code, err := c.DeleteJSON(quotaPath(scope, name), nil)
+	if code != 200 {
+		if err == nil {
+			return fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (c *client) QuotaGet(scope, name string) (*sdk.Quota, error) {
+	q := &sdk.Quota{}
+	code, err := c.GetJSON(quotaPath(scope, name), q)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func (c *client) QuotaList() ([]sdk.Quota, error) {
+	qs := []sdk.Quota{}
+	code, err := c.GetJSON("/quota", &qs)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return qs, nil
+}
+
+func (c *client) QuotaSet(scope, name string, maxJobs int64) (*sdk.Quota, error) {
+	q := &sdk.Quota{}
+	code, err := c.PutJSON(quotaPath(scope, name), sdk.Quota{MaxJobs: maxJobs}, q)
+	if code != 200 {
+		if err == nil {
+			return nil, fmt.Errorf("HTTP Code %d", code)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return q, nil
+}
@@ -38,6 +38,30 @@ func (c *client) UserLogin(username, password string) (bool, string, error) {
 	return true, response.Password, nil
 }
 
+func (c *client) UserLoginOIDCDevice() (*sdk.OIDCDeviceAuthorization, error) {
+	res := sdk.OIDCDeviceAuthorization{}
+	code, err := c.PostJSON("/login/oidc/device", nil, &res)
+	if err != nil {
+		return nil, err
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("Error %d", code)
+	}
+	return &res, nil
+}
+
+func (c *client) UserLoginOIDCDeviceToken(deviceCode string) (*sdk.UserAPIResponse, error) {
+	res := sdk.UserAPIResponse{}
+	code, err := c.PostJSON("/login/oidc/device/token", sdk.OIDCDeviceTokenRequest{DeviceCode: deviceCode}, &res)
+	if err != nil {
+		return nil, err
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("Error %d", code)
+	}
+	return &res, nil
+}
+
 func (c *client) UserList() ([]sdk.User, error) {
 	res := []sdk.User{}
 	code, err := c.GetJSON("/user", &res)
@@ -0,0 +1,44 @@
+package cdsclient
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) UserAccessTokenList(username string) ([]sdk.AccessToken, error) {
+	res := []sdk.AccessToken{}
+	code, err := c.GetJSON("/user/"+url.QueryEscape(username)+"/accesstoken", &res)
+	if err != nil {
+		return nil, err
+	}
+	if code != http.StatusOK {
+		return nil, fmt.Errorf("HTTP Code %d", code)
+	}
+	return res, nil
+}
+
+func (c *client) UserAccessTokenCreate(username string, req sdk.AccessTokenRequest) (*sdk.AccessToken, error) {
+	res := &sdk.AccessToken{}
+	code, err := c.PostJSON("/user/"+url.QueryEscape(username)+"/accesstoken", req, res)
+	if err != nil {
+		return nil, err
+	}
+	if code != http.StatusCreated {
+		return nil, fmt.Errorf("HTTP Code %d", code)
+	}
+	return res, nil
+}
+
+func (c *client) UserAccessTokenDelete(username string, id int64) error {
+	code, err := c.DeleteJSON(fmt.Sprintf("/user/%s/accesstoken/%d", url.QueryEscape(username), id), nil)
+	if err != nil {
+		return err
+	}
+	if code != http.StatusOK {
+		return fmt.Errorf("HTTP Code %d", code)
+	}
+	return nil
+}
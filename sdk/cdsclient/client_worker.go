@@ -22,6 +22,17 @@ func (c *client) WorkerList() ([]sdk.Worker, error) {
 	return p, nil
 }
 
+func (c *client) WorkerDisable(id string) error {
+	code, err := c.PostJSON(fmt.Sprintf("/worker/%s/disable", id), nil, nil)
+	if err != nil {
+		return err
+	}
+	if code >= 300 {
+		return fmt.Errorf("HTTP Code %d", code)
+	}
+	return nil
+}
+
 func (c *client) WorkerRegister(r worker.RegistrationForm) (*sdk.Worker, bool, error) {
 	var w sdk.Worker
 	code, err := c.PostJSON("/worker", r, &w)
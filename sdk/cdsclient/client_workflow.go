@@ -1,8 +1,11 @@
 package cdsclient
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 
 	"github.com/ovh/cds/sdk"
@@ -73,11 +76,14 @@ func (c *client) WorkflowNodeRunArtifacts(projectKey string, workflowName string
 
 func (c *client) WorkflowNodeRunArtifactDownload(projectKey string, workflowName string, artifactID int64, w io.Writer) error {
 	url := fmt.Sprintf("/project/%s/workflows/%s/artifact/%d", projectKey, workflowName, artifactID)
-	reader, _, err := c.Stream("GET", url, nil, true)
+	reader, code, err := c.Stream("GET", url, nil, true)
 	if err != nil {
 		return err
 	}
 	defer reader.Close()
+	if code >= 300 {
+		return fmt.Errorf("Cannot download artifact %d. HTTP code error : %d", artifactID, code)
+	}
 	if _, err := io.Copy(w, reader); err != nil {
 		return err
 	}
@@ -138,3 +144,97 @@ func (c *client) WorkflowRunFromManual(projectKey string, workflowName string, m
 
 	return run, nil
 }
+
+func (c *client) WorkflowExport(projectKey, workflowName, exportFormat string) ([]byte, error) {
+	url := fmt.Sprintf("/project/%s/export/workflows/%s?format=%s", projectKey, workflowName, exportFormat)
+	btes, code, err := c.Request("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("Cannot export workflow. HTTP code error: %d", code)
+	}
+	return btes, nil
+}
+
+func (c *client) WorkflowImport(projectKey string, content []byte, format string, force, dryRun bool) ([]string, error) {
+	url := fmt.Sprintf("/project/%s/import/workflows?format=%s", projectKey, format)
+	if force {
+		url += "&forceUpdate=true"
+	}
+	if dryRun {
+		url += "&dryRun=true"
+	}
+
+	btes, code, err := c.Request("POST", url, content)
+	if err != nil {
+		return nil, err
+	}
+	if code >= 300 {
+		return nil, fmt.Errorf("Cannot import workflow. HTTP code error: %d", code)
+	}
+
+	var msgs []string
+	if err := json.Unmarshal(btes, &msgs); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+// WorkflowRunLogsStream sends the logs of a workflow run in chanLogs, as parts of the logs of its steps.
+// If follow is true, it streams the new logs until the run is terminated, otherwise it returns once the current logs are sent
+func (c *client) WorkflowRunLogsStream(ctx context.Context, projectKey string, workflowName string, number int64, follow bool, chanLogs chan<- sdk.WorkflowRunLog) error {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/logs", projectKey, workflowName, number)
+	if follow {
+		url += "?follow=true"
+	}
+
+	body, code, err := c.Stream("GET", url, nil, true, SetHeader("Accept", "text/event-stream"))
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if code >= 400 {
+		b, _ := ioutil.ReadAll(body)
+		if cdserr := sdk.DecodeError(b); cdserr != nil {
+			return cdserr
+		}
+		return fmt.Errorf("Cannot get logs of workflow run %d. HTTP code error: %d", number, code)
+	}
+
+	err = readEventStream(ctx, body, func(data string) error {
+		var l sdk.WorkflowRunLog
+		if err := json.Unmarshal([]byte(data), &l); err != nil {
+			return sdk.WrapError(err, "Unable to read logs %s", data)
+		}
+		select {
+		case chanLogs <- l:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	// The stream is closed by the API at the end of the logs
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// WorkflowRunLogsDownload writes the logs of all the steps of a workflow run in w, as a tar.gz archive
+func (c *client) WorkflowRunLogsDownload(projectKey string, workflowName string, number int64, w io.Writer) error {
+	url := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/logs/download", projectKey, workflowName, number)
+	reader, code, err := c.Stream("GET", url, nil, true)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if code >= 300 {
+		return fmt.Errorf("Cannot download logs of workflow run %d. HTTP code error: %d", number, code)
+	}
+	if _, err := io.Copy(w, reader); err != nil {
+		return err
+	}
+	return nil
+}
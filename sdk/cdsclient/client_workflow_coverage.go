@@ -0,0 +1,16 @@
+package cdsclient
+
+import (
+	"fmt"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) WorkflowRunCoverage(projectKey, workflowName string, number int64) ([]sdk.WorkflowNodeRunCoverage, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/coverage", projectKey, workflowName, number)
+	res := []sdk.WorkflowNodeRunCoverage{}
+	if _, err := c.GetJSON(path, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,45 @@
+package cdsclient
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func (c *client) WorkflowTestCaseHistory(projectKey, workflowName, nodeName, testSuite, name string, limit int) (*sdk.TestCaseHistory, error) {
+	q := url.Values{}
+	q.Set("node", nodeName)
+	q.Set("testsuite", testSuite)
+	q.Set("name", name)
+	if limit > 0 {
+		q.Set("limit", fmt.Sprintf("%d", limit))
+	}
+	path := fmt.Sprintf("/project/%s/workflows/%s/tests/history?%s", projectKey, workflowName, q.Encode())
+	h := sdk.TestCaseHistory{}
+	if _, err := c.GetJSON(path, &h); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+func (c *client) WorkflowFlakyTestCases(projectKey, workflowName string, limit int) ([]sdk.FlakyTestCase, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/tests/flaky", projectKey, workflowName)
+	if limit > 0 {
+		path += fmt.Sprintf("?limit=%d", limit)
+	}
+	tests := []sdk.FlakyTestCase{}
+	if _, err := c.GetJSON(path, &tests); err != nil {
+		return nil, err
+	}
+	return tests, nil
+}
+
+func (c *client) WorkflowRunTestsComparison(projectKey, workflowName string, number int64) (*sdk.TestsComparison, error) {
+	path := fmt.Sprintf("/project/%s/workflows/%s/runs/%d/tests/compare", projectKey, workflowName, number)
+	comp := sdk.TestsComparison{}
+	if _, err := c.GetJSON(path, &comp); err != nil {
+		return nil, err
+	}
+	return &comp, nil
+}
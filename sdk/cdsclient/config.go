@@ -2,11 +2,12 @@ package cdsclient
 
 //Config is the configuration data used by the cdsclient interface implementation
 type Config struct {
-	Host      string
-	User      string
-	Token     string
-	Hash      string
-	userAgent string
-	Verbose   bool
-	Retry     int
+	Host        string
+	User        string
+	Token       string
+	Hash        string
+	AccessToken string // Personal access token, used instead of User and Token if set
+	userAgent   string
+	Verbose     bool
+	Retry       int
 }
@@ -35,11 +35,13 @@ type HTTPClient interface {
 	Do(*http.Request) (*http.Response, error)
 }
 
-// NoTimeout returns a http.DefaultClient from a HTTPClient
+// NoTimeout returns a http client without timeout from a HTTPClient
 func NoTimeout(c HTTPClient) HTTPClient {
-	return http.DefaultClient
+	return noTimeoutClient
 }
 
+var noTimeoutClient = &http.Client{CheckRedirect: sdk.CheckRedirect}
+
 // SetHeader modify headers of http.Request
 func SetHeader(key, value string) RequestModifier {
 	return func(req *http.Request) {
@@ -165,7 +167,9 @@ func (c *client) Stream(method string, path string, args []byte, noTimeout bool,
 				basedHash := base64.StdEncoding.EncodeToString([]byte(c.config.Hash))
 				req.Header.Set(AuthHeader, basedHash)
 			}
-			if c.config.User != "" && c.config.Token != "" {
+			if c.config.AccessToken != "" {
+				req.Header.Set("Authorization", "Bearer "+c.config.AccessToken)
+			} else if c.config.User != "" && c.config.Token != "" {
 				req.Header.Add(SessionTokenHeader, c.config.Token)
 				req.SetBasicAuth(c.config.User, c.config.Token)
 			}
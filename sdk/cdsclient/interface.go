@@ -26,7 +26,11 @@ type Interface interface {
 	ApplicationVariableCreate(projectKey string, appName string, variable *sdk.Variable) error
 	ApplicationVariableDelete(projectKey string, appName string, variable string) error
 	ApplicationVariableUpdate(projectKey string, appName string, variable *sdk.Variable) error
+	ApplicationVCSBranches(key string, appName string) ([]sdk.VCSBranch, error)
+	ApplicationVCSCommit(key string, appName string, hash string) (*sdk.VCSCommit, error)
+	ApplicationVCSTags(key string, appName string) ([]sdk.VCSTag, error)
 	ConfigUser() (map[string]string, error)
+	EventsListen(ctx context.Context, projects, workflows []string, chanEvents chan<- sdk.Event) error
 	EnvironmentCreate(string, *sdk.Environment) error
 	EnvironmentDelete(string, string) error
 	EnvironmentGet(string, string, ...RequestModifier) (*sdk.Environment, error)
@@ -65,28 +69,42 @@ type Interface interface {
 	ProjectVariableCreate(projectKey string, variable *sdk.Variable) error
 	ProjectVariableDelete(projectKey string, variable string) error
 	ProjectVariableUpdate(projectKey string, variable *sdk.Variable) error
+	QuotaDelete(scope, name string) error
+	QuotaGet(scope, name string) (*sdk.Quota, error)
+	QuotaList() ([]sdk.Quota, error)
+	QuotaSet(scope, name string, maxJobs int64) (*sdk.Quota, error)
 	Queue() ([]sdk.WorkflowNodeJobRun, []sdk.PipelineBuildJob, error)
 	QueuePolling(context.Context, chan<- sdk.WorkflowNodeJobRun, chan<- sdk.PipelineBuildJob, chan<- error, time.Duration, int) error
 	QueueTakeJob(sdk.WorkflowNodeJobRun, bool) (*worker.WorkflowNodeJobRunInfo, error)
 	QueueJobBook(isWorkflowJob bool, id int64) error
 	QueueJobInfo(id int64) (*sdk.WorkflowNodeJobRun, error)
 	QueueJobSendSpawnInfo(isWorkflowJob bool, id int64, in []sdk.SpawnInfo) error
+	QueueJobSendStepSpawnInfo(id int64, in []sdk.SpawnInfo) error
 	QueueSendResult(int64, sdk.Result) error
+	QueueSendCoverage(id int64, report sdk.CoverageReport, vcsStatus bool) error
 	QueueArtifactUpload(id int64, tag, filePath string) error
+	QueueCachePush(id int64, key, tarPath string) error
+	QueueCachePull(id int64, keys []string) (*sdk.WorkerCache, io.ReadCloser, error)
 	Requirements() ([]sdk.Requirement, error)
 	ServiceRegister(sdk.Service) (string, error)
 	TemplateList() ([]sdk.Template, error)
 	TemplateGet(name string) (*sdk.Template, error)
 	TemplateApplicationCreate(projectKey, name string, template *sdk.Template) error
 	UserLogin(username, password string) (bool, string, error)
+	UserLoginOIDCDevice() (*sdk.OIDCDeviceAuthorization, error)
+	UserLoginOIDCDeviceToken(deviceCode string) (*sdk.UserAPIResponse, error)
 	UserList() ([]sdk.User, error)
 	UserSignup(username, fullname, email, callback string) error
 	UserGet(username string) (*sdk.User, error)
 	UserGetGroups(username string) (map[string][]sdk.Group, error)
 	UserReset(username, email, callback string) error
 	UserConfirm(username, token string) (bool, string, error)
+	UserAccessTokenList(username string) ([]sdk.AccessToken, error)
+	UserAccessTokenCreate(username string, req sdk.AccessTokenRequest) (*sdk.AccessToken, error)
+	UserAccessTokenDelete(username string, id int64) error
 	Version() (*sdk.Version, error)
 	WorkerList() ([]sdk.Worker, error)
+	WorkerDisable(id string) error
 	WorkerModelSpawnError(id int64, info string) error
 	WorkerModelsEnabled() ([]sdk.Model, error)
 	WorkerModels() ([]sdk.Model, error)
@@ -94,8 +112,16 @@ type Interface interface {
 	WorkerSetStatus(sdk.Status) error
 	WorkflowList(projectKey string) ([]sdk.Workflow, error)
 	WorkflowGet(projectKey, name string) (*sdk.Workflow, error)
+	WorkflowExport(projectKey, name, exportFormat string) ([]byte, error)
+	WorkflowImport(projectKey string, content []byte, format string, force, dryRun bool) ([]string, error)
 	WorkflowRunGet(projectKey string, name string, number int64) (*sdk.WorkflowRun, error)
 	WorkflowRunArtifacts(projectKey string, name string, number int64) ([]sdk.Artifact, error)
+	WorkflowRunLogsStream(ctx context.Context, projectKey string, name string, number int64, follow bool, chanLogs chan<- sdk.WorkflowRunLog) error
+	WorkflowRunLogsDownload(projectKey string, name string, number int64, w io.Writer) error
+	WorkflowRunTestsComparison(projectKey string, name string, number int64) (*sdk.TestsComparison, error)
+	WorkflowRunCoverage(projectKey string, name string, number int64) ([]sdk.WorkflowNodeRunCoverage, error)
+	WorkflowTestCaseHistory(projectKey, name, nodeName, testSuite, testName string, limit int) (*sdk.TestCaseHistory, error)
+	WorkflowFlakyTestCases(projectKey, name string, limit int) ([]sdk.FlakyTestCase, error)
 	WorkflowRunFromHook(projectKey string, workflowName string, hook sdk.WorkflowNodeRunHookEvent) (*sdk.WorkflowRun, error)
 	WorkflowRunFromManual(projectKey string, workflowName string, manual sdk.WorkflowNodeRunManual, number, fromNodeID int64) (*sdk.WorkflowRun, error)
 	WorkflowNodeRun(projectKey string, name string, number int64, nodeRunID int64) (*sdk.WorkflowNodeRun, error)
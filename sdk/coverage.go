@@ -0,0 +1,363 @@
+package sdk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Coverage report formats
+const (
+	CoverageFormatCobertura = "cobertura"
+	CoverageFormatLCOV      = "lcov"
+	CoverageFormatGo        = "go"
+)
+
+// CoverageFormats is the list of the coverage report formats handled by the Coverage action
+var CoverageFormats = []string{CoverageFormatCobertura, CoverageFormatLCOV, CoverageFormatGo}
+
+// CoverageSummary counts the covered lines and branches.
+// Go coverprofiles count statements instead of lines and have no branches.
+type CoverageSummary struct {
+	LinesCovered    int64 `json:"lines_covered" cli:"lines_covered"`
+	LinesTotal      int64 `json:"lines_total" cli:"lines_total"`
+	BranchesCovered int64 `json:"branches_covered" cli:"branches_covered"`
+	BranchesTotal   int64 `json:"branches_total" cli:"branches_total"`
+	// Percent is the percentage of covered lines
+	Percent float64 `json:"percent" cli:"percent"`
+}
+
+func (s *CoverageSummary) add(o CoverageSummary) {
+	s.LinesCovered += o.LinesCovered
+	s.LinesTotal += o.LinesTotal
+	s.BranchesCovered += o.BranchesCovered
+	s.BranchesTotal += o.BranchesTotal
+	s.computePercent()
+}
+
+func (s *CoverageSummary) computePercent() {
+	s.Percent = 0
+	if s.LinesTotal > 0 {
+		s.Percent = float64(s.LinesCovered) / float64(s.LinesTotal) * 100
+	}
+}
+
+// CoverageFile is the coverage of a source file
+type CoverageFile struct {
+	Path string `json:"path" cli:"path"`
+	CoverageSummary
+}
+
+// CoverageReport is the coverage of the source files of a workflow node run
+type CoverageReport struct {
+	Summary CoverageSummary `json:"summary"`
+	Files   []CoverageFile  `json:"files"`
+	// Minimum is the percentage of covered lines required by the Coverage action, 0 if there is none
+	Minimum float64 `json:"minimum,omitempty"`
+}
+
+// IsBelowMinimum returns true if the report does not reach its minimum percentage of covered lines
+func (r CoverageReport) IsBelowMinimum() bool {
+	return r.Minimum > 0 && r.Summary.Percent < r.Minimum
+}
+
+// Merge adds the files of another report, the files of the other report replace the files with the same path
+func (r *CoverageReport) Merge(o CoverageReport) {
+	files := make(map[string]CoverageFile, len(r.Files)+len(o.Files))
+	for _, f := range r.Files {
+		files[f.Path] = f
+	}
+	for _, f := range o.Files {
+		files[f.Path] = f
+	}
+	if o.Minimum > 0 {
+		r.Minimum = o.Minimum
+	}
+
+	r.Files = make([]CoverageFile, 0, len(files))
+	for _, f := range files {
+		r.Files = append(r.Files, f)
+	}
+	r.compute()
+}
+
+// compute sorts the files and computes the summary
+func (r *CoverageReport) compute() {
+	sort.Slice(r.Files, func(i, j int) bool { return r.Files[i].Path < r.Files[j].Path })
+	r.Summary = CoverageSummary{}
+	for _, f := range r.Files {
+		r.Summary.add(f.CoverageSummary)
+	}
+}
+
+// coverageUnit is a line, a statement block or a branch, with the number of lines it counts for
+type coverageUnit struct {
+	weight int64
+	hits   int64
+}
+
+type coverageFileUnits struct {
+	lines    map[string]coverageUnit
+	branches map[string]coverageUnit
+}
+
+// coverageBuilder gathers the units of the source files, a unit found several times is covered if it is covered once
+type coverageBuilder map[string]*coverageFileUnits
+
+func (b coverageBuilder) file(path string) *coverageFileUnits {
+	f, ok := b[path]
+	if !ok {
+		f = &coverageFileUnits{lines: map[string]coverageUnit{}, branches: map[string]coverageUnit{}}
+		b[path] = f
+	}
+	return f
+}
+
+func addCoverageUnit(units map[string]coverageUnit, key string, weight, hits int64) {
+	u, ok := units[key]
+	if !ok || hits > u.hits {
+		units[key] = coverageUnit{weight: weight, hits: hits}
+	}
+}
+
+func (b coverageBuilder) addLine(path, key string, weight, hits int64) {
+	addCoverageUnit(b.file(path).lines, key, weight, hits)
+}
+
+func (b coverageBuilder) addBranch(path, key string, hits int64) {
+	addCoverageUnit(b.file(path).branches, key, 1, hits)
+}
+
+func (b coverageBuilder) report() CoverageReport {
+	r := CoverageReport{Files: make([]CoverageFile, 0, len(b))}
+	for path, units := range b {
+		f := CoverageFile{Path: path}
+		for _, u := range units.lines {
+			f.LinesTotal += u.weight
+			if u.hits > 0 {
+				f.LinesCovered += u.weight
+			}
+		}
+		for _, u := range units.branches {
+			f.BranchesTotal += u.weight
+			if u.hits > 0 {
+				f.BranchesCovered += u.weight
+			}
+		}
+		f.computePercent()
+		r.Files = append(r.Files, f)
+	}
+	r.compute()
+	return r
+}
+
+// ParseCoverage parses coverage files of the given format into a single report
+func ParseCoverage(format string, contents ...[]byte) (CoverageReport, error) {
+	var parse func(coverageBuilder, []byte) error
+	switch format {
+	case CoverageFormatCobertura:
+		parse = parseCobertura
+	case CoverageFormatLCOV:
+		parse = parseLCOV
+	case CoverageFormatGo:
+		parse = parseGoCoverProfile
+	default:
+		return CoverageReport{}, fmt.Errorf("unsupported coverage format %s, expected one of %s", format, strings.Join(CoverageFormats, ", "))
+	}
+
+	b := coverageBuilder{}
+	for _, c := range contents {
+		if err := parse(b, c); err != nil {
+			return CoverageReport{}, err
+		}
+	}
+	return b.report(), nil
+}
+
+type coberturaCoverage struct {
+	Packages []struct {
+		Classes []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Number            int64  `xml:"number,attr"`
+				Hits              int64  `xml:"hits,attr"`
+				Branch            bool   `xml:"branch,attr"`
+				ConditionCoverage string `xml:"condition-coverage,attr"`
+			} `xml:"lines>line"`
+		} `xml:"classes>class"`
+	} `xml:"packages>package"`
+}
+
+// parseCobertura parses a Cobertura XML report, the branches are read from the condition coverage of the lines: 50% (1/2)
+func parseCobertura(b coverageBuilder, data []byte) error {
+	var c coberturaCoverage
+	if err := xml.Unmarshal(data, &c); err != nil {
+		return fmt.Errorf("invalid cobertura report: %v", err)
+	}
+	for _, p := range c.Packages {
+		for _, cl := range p.Classes {
+			for _, l := range cl.Lines {
+				line := strconv.FormatInt(l.Number, 10)
+				b.addLine(cl.Filename, line, 1, l.Hits)
+				if !l.Branch || l.ConditionCoverage == "" {
+					continue
+				}
+				var percent string
+				var covered, total int64
+				if _, err := fmt.Sscanf(l.ConditionCoverage, "%s (%d/%d)", &percent, &covered, &total); err != nil {
+					return fmt.Errorf("invalid cobertura condition coverage %s on %s:%d", l.ConditionCoverage, cl.Filename, l.Number)
+				}
+				for i := int64(0); i < total; i++ {
+					var hits int64
+					if i < covered {
+						hits = 1
+					}
+					b.addBranch(cl.Filename, line+"/"+strconv.FormatInt(i, 10), hits)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// parseLCOV parses a LCOV tracefile, from the DA and BRDA records of each SF section
+func parseLCOV(b coverageBuilder, data []byte) error {
+	var file string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		i := strings.Index(line, ":")
+		if i < 0 {
+			if line == "end_of_record" {
+				file = ""
+			}
+			continue
+		}
+
+		record, value := line[:i], line[i+1:]
+		switch record {
+		case "SF":
+			file = value
+		case "DA", "BRDA":
+			if file == "" {
+				return fmt.Errorf("invalid lcov report: %s record outside of a source file at line %d", record, n)
+			}
+			fields := strings.Split(value, ",")
+			if record == "DA" {
+				if len(fields) < 2 {
+					return fmt.Errorf("invalid lcov report: invalid DA record at line %d", n)
+				}
+				hits, err := strconv.ParseInt(fields[1], 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid lcov report: invalid DA record at line %d", n)
+				}
+				b.addLine(file, fields[0], 1, hits)
+				continue
+			}
+			if len(fields) != 4 {
+				return fmt.Errorf("invalid lcov report: invalid BRDA record at line %d", n)
+			}
+			// taken is "-" when the block was never executed
+			var hits int64
+			if fields[3] != "-" {
+				h, err := strconv.ParseInt(fields[3], 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid lcov report: invalid BRDA record at line %d", n)
+				}
+				hits = h
+			}
+			b.addBranch(file, strings.Join(fields[:3], ","), hits)
+		}
+	}
+	return scanner.Err()
+}
+
+// parseGoCoverProfile parses a profile written by go test -coverprofile: file:startLine.startCol,endLine.endCol statements count
+func parseGoCoverProfile(b coverageBuilder, data []byte) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "mode:") {
+			continue
+		}
+
+		i := strings.LastIndex(line, ":")
+		fields := strings.Fields(line[i+1:])
+		if i < 0 || len(fields) != 3 {
+			return fmt.Errorf("invalid go coverprofile at line %d", n)
+		}
+		statements, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid go coverprofile at line %d", n)
+		}
+		count, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid go coverprofile at line %d", n)
+		}
+		b.addLine(line[:i], fields[0], statements, count)
+	}
+	return scanner.Err()
+}
+
+// CoverageFileDelta is the variation of the coverage of a source file
+type CoverageFileDelta struct {
+	Path    string  `json:"path" cli:"path"`
+	Percent float64 `json:"percent" cli:"percent"`
+	Delta   float64 `json:"delta" cli:"delta"`
+	// New is true if the file is not in the reference report
+	New bool `json:"new" cli:"new"`
+}
+
+// CoverageDelta is the variation of the coverage compared to a reference report
+type CoverageDelta struct {
+	Percent float64             `json:"percent"`
+	Files   []CoverageFileDelta `json:"files"`
+}
+
+// CompareCoverage returns the variation of the percentage of covered lines, for the whole report and for the new and changed files.
+// The files are sorted from the biggest decrease.
+func CompareCoverage(current, reference CoverageReport) CoverageDelta {
+	d := CoverageDelta{
+		Percent: current.Summary.Percent - reference.Summary.Percent,
+		Files:   []CoverageFileDelta{},
+	}
+
+	ref := make(map[string]CoverageFile, len(reference.Files))
+	for _, f := range reference.Files {
+		ref[f.Path] = f
+	}
+	for _, f := range current.Files {
+		r, ok := ref[f.Path]
+		switch {
+		case !ok:
+			d.Files = append(d.Files, CoverageFileDelta{Path: f.Path, Percent: f.Percent, Delta: f.Percent, New: true})
+		case r.Percent != f.Percent:
+			d.Files = append(d.Files, CoverageFileDelta{Path: f.Path, Percent: f.Percent, Delta: f.Percent - r.Percent})
+		}
+	}
+	sort.SliceStable(d.Files, func(i, j int) bool {
+		if d.Files[i].Delta != d.Files[j].Delta {
+			return d.Files[i].Delta < d.Files[j].Delta
+		}
+		return d.Files[i].Path < d.Files[j].Path
+	})
+	return d
+}
+
+// WorkflowNodeRunCoverage is the coverage of a workflow node run compared to the last run on the default branch
+type WorkflowNodeRunCoverage struct {
+	WorkflowNodeRunID int64          `json:"workflow_node_run_id"`
+	NodeName          string         `json:"node_name"`
+	Number            int64          `json:"num"`
+	SubNumber         int64          `json:"subnumber"`
+	Branch            string         `json:"branch"`
+	Report            CoverageReport `json:"report"`
+	DefaultBranch     string         `json:"default_branch"`
+	// ReferenceNumber is the number of the run of the default branch, 0 if there is none
+	ReferenceNumber int64          `json:"reference_num"`
+	Delta           *CoverageDelta `json:"delta,omitempty"`
+}
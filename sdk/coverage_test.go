@@ -0,0 +1,148 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCoverageCobertura(t *testing.T) {
+	report := `<?xml version="1.0" ?>
+<coverage line-rate="0.75" branch-rate="0.5" version="4.5">
+	<packages>
+		<package name="app">
+			<classes>
+				<class name="main.py" filename="app/main.py" line-rate="0.75">
+					<methods>
+						<method name="run">
+							<lines><line number="2" hits="0"/></lines>
+						</method>
+					</methods>
+					<lines>
+						<line number="1" hits="3"/>
+						<line number="2" hits="0"/>
+						<line number="3" hits="1" branch="true" condition-coverage="50% (1/2)"/>
+						<line number="4" hits="2"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>`
+
+	r, err := ParseCoverage(CoverageFormatCobertura, []byte(report))
+	assert.NoError(t, err)
+	assert.Len(t, r.Files, 1)
+	assert.Equal(t, "app/main.py", r.Files[0].Path)
+	assert.Equal(t, int64(3), r.Summary.LinesCovered)
+	assert.Equal(t, int64(4), r.Summary.LinesTotal)
+	assert.Equal(t, int64(1), r.Summary.BranchesCovered)
+	assert.Equal(t, int64(2), r.Summary.BranchesTotal)
+	assert.Equal(t, 75.0, r.Summary.Percent)
+
+	_, err = ParseCoverage(CoverageFormatCobertura, []byte("not xml"))
+	assert.Error(t, err)
+}
+
+func TestParseCoverageLCOV(t *testing.T) {
+	report := `TN:
+SF:src/a.js
+FN:1,a
+DA:1,1
+DA:2,0
+BRDA:2,0,0,1
+BRDA:2,0,1,-
+LF:2
+LH:1
+end_of_record
+SF:src/b.js
+DA:1,4
+end_of_record
+`
+	r, err := ParseCoverage(CoverageFormatLCOV, []byte(report))
+	assert.NoError(t, err)
+	assert.Len(t, r.Files, 2)
+	assert.Equal(t, "src/a.js", r.Files[0].Path)
+	assert.Equal(t, 50.0, r.Files[0].Percent)
+	assert.Equal(t, int64(1), r.Files[0].BranchesCovered)
+	assert.Equal(t, int64(2), r.Files[0].BranchesTotal)
+	assert.Equal(t, "src/b.js", r.Files[1].Path)
+	assert.Equal(t, 100.0, r.Files[1].Percent)
+	assert.Equal(t, int64(2), r.Summary.LinesCovered)
+	assert.Equal(t, int64(3), r.Summary.LinesTotal)
+
+	_, err = ParseCoverage(CoverageFormatLCOV, []byte("DA:1,1\n"))
+	assert.Error(t, err)
+}
+
+func TestParseCoverageGo(t *testing.T) {
+	profile1 := `mode: set
+github.com/ovh/cds/sdk/a.go:10.2,12.3 2 1
+github.com/ovh/cds/sdk/a.go:14.2,16.3 3 0
+`
+	// the same block covered in another profile is covered
+	profile2 := `mode: set
+github.com/ovh/cds/sdk/a.go:14.2,16.3 3 1
+github.com/ovh/cds/sdk/b.go:1.1,2.2 5 0
+`
+	r, err := ParseCoverage(CoverageFormatGo, []byte(profile1), []byte(profile2))
+	assert.NoError(t, err)
+	assert.Len(t, r.Files, 2)
+	assert.Equal(t, int64(5), r.Files[0].LinesCovered)
+	assert.Equal(t, int64(5), r.Files[0].LinesTotal)
+	assert.Equal(t, int64(0), r.Files[1].LinesCovered)
+	assert.Equal(t, 50.0, r.Summary.Percent)
+
+	_, err = ParseCoverage(CoverageFormatGo, []byte("a.go:1.1,2.2 x 1"))
+	assert.Error(t, err)
+
+	_, err = ParseCoverage("jacoco", []byte(profile1))
+	assert.Error(t, err)
+}
+
+func TestCoverageReportMerge(t *testing.T) {
+	r := CoverageReport{Files: []CoverageFile{
+		{Path: "a", CoverageSummary: CoverageSummary{LinesCovered: 1, LinesTotal: 2, Percent: 50}},
+		{Path: "b", CoverageSummary: CoverageSummary{LinesCovered: 0, LinesTotal: 2, Percent: 0}},
+	}}
+	r.Merge(CoverageReport{
+		Minimum: 60,
+		Files: []CoverageFile{
+			{Path: "b", CoverageSummary: CoverageSummary{LinesCovered: 2, LinesTotal: 2, Percent: 100}},
+			{Path: "c", CoverageSummary: CoverageSummary{LinesCovered: 0, LinesTotal: 4, Percent: 0}},
+		},
+	})
+
+	assert.Len(t, r.Files, 3)
+	assert.Equal(t, int64(3), r.Summary.LinesCovered)
+	assert.Equal(t, int64(8), r.Summary.LinesTotal)
+	assert.Equal(t, 37.5, r.Summary.Percent)
+	assert.True(t, r.IsBelowMinimum())
+}
+
+func TestCompareCoverage(t *testing.T) {
+	reference := CoverageReport{
+		Summary: CoverageSummary{Percent: 60},
+		Files: []CoverageFile{
+			{Path: "a", CoverageSummary: CoverageSummary{Percent: 50}},
+			{Path: "b", CoverageSummary: CoverageSummary{Percent: 80}},
+			{Path: "removed", CoverageSummary: CoverageSummary{Percent: 10}},
+		},
+	}
+	current := CoverageReport{
+		Summary: CoverageSummary{Percent: 65},
+		Files: []CoverageFile{
+			{Path: "a", CoverageSummary: CoverageSummary{Percent: 50}},
+			{Path: "b", CoverageSummary: CoverageSummary{Percent: 70}},
+			{Path: "c", CoverageSummary: CoverageSummary{Percent: 90}},
+		},
+	}
+
+	d := CompareCoverage(current, reference)
+	assert.Equal(t, 5.0, d.Percent)
+	assert.Len(t, d.Files, 2)
+	assert.Equal(t, "b", d.Files[0].Path)
+	assert.Equal(t, -10.0, d.Files[0].Delta)
+	assert.Equal(t, "c", d.Files[1].Path)
+	assert.True(t, d.Files[1].New)
+}
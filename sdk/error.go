@@ -123,6 +123,16 @@ var (
 	ErrWorkflowNodeParentNotRun              = Error{ID: 107, Status: http.StatusForbidden}
 	ErrHookNotFound                          = Error{ID: 108, Status: http.StatusNotFound}
 	ErrDefaultGroupPermission                = Error{ID: 109, Status: http.StatusBadRequest}
+	ErrWorkflowAlreadyExists                 = &Error{ID: 110, Status: http.StatusConflict}
+	ErrQuotaExceeded                         = &Error{ID: 111, Status: http.StatusTooManyRequests}
+	ErrInvalidQuota                          = &Error{ID: 112, Status: http.StatusBadRequest}
+	ErrInvalidWorkerCacheKey                 = &Error{ID: 113, Status: http.StatusBadRequest}
+	ErrWorkerCacheTooLarge                   = &Error{ID: 114, Status: http.StatusRequestEntityTooLarge}
+	ErrOIDCNotEnabled                        = &Error{ID: 115, Status: http.StatusBadRequest}
+	ErrOIDCAuthorizationPending              = &Error{ID: 116, Status: http.StatusBadRequest}
+	ErrOIDCInvalidState                      = &Error{ID: 117, Status: http.StatusBadRequest}
+	ErrInvalidAccessTokenScope               = &Error{ID: 118, Status: http.StatusBadRequest}
+	ErrAccessTokenScopeForbidden             = &Error{ID: 119, Status: http.StatusForbidden}
 )
 
 var errorsAmericanEnglish = map[int]string{
@@ -234,6 +244,16 @@ var errorsAmericanEnglish = map[int]string{
 	ErrInvalidNodeNamePattern.ID:                "Node name must respect the following pattern: '^[a-zA-Z0-9.-_-]{1,}$'",
 	ErrWorkflowNodeParentNotRun.ID:              "Cannot run a node if their parents have never been launched",
 	ErrDefaultGroupPermission.ID:                "Only read permission is allowed to default group",
+	ErrWorkflowAlreadyExists.ID:                 "Workflow already exists",
+	ErrQuotaExceeded.ID:                         "Quota of concurrent jobs exceeded",
+	ErrInvalidQuota.ID:                          "Invalid quota",
+	ErrInvalidWorkerCacheKey.ID:                 "Invalid cache key, it must contain only alphanumerical characters, dots, dashes and underscores",
+	ErrWorkerCacheTooLarge.ID:                   "Cache exceeds the maximum size",
+	ErrOIDCNotEnabled.ID:                        "OpenID Connect authentication is not enabled",
+	ErrOIDCAuthorizationPending.ID:              "OpenID Connect authorization is pending",
+	ErrOIDCInvalidState.ID:                      "Invalid or expired OpenID Connect login request",
+	ErrInvalidAccessTokenScope.ID:               "Invalid access token scope, expected read, run or admin:<project key>",
+	ErrAccessTokenScopeForbidden.ID:             "The scopes of the access token don't allow this request",
 }
 
 var errorsFrench = map[int]string{
@@ -345,6 +365,16 @@ var errorsFrench = map[int]string{
 	ErrInvalidNodeNamePattern.ID:                "Le nom du noeud du workflow doit respecter le pattern suivant; '^[a-zA-Z0-9.-_-]{1,}$'",
 	ErrWorkflowNodeParentNotRun.ID:              "Il est interdit de lancer un noeuds si ses parents n'ont jamais été lancés",
 	ErrDefaultGroupPermission.ID:                "Le groupe par défaut ne peut être utilisé qu'en lecture seule",
+	ErrWorkflowAlreadyExists.ID:                 "Le workflow existe déjà",
+	ErrQuotaExceeded.ID:                         "Le quota de jobs simultanés est atteint",
+	ErrInvalidQuota.ID:                          "Quota invalide",
+	ErrInvalidWorkerCacheKey.ID:                 "Clé de cache invalide, elle ne doit contenir que des caractères alphanumériques, des points, des tirets et des underscores",
+	ErrWorkerCacheTooLarge.ID:                   "Le cache dépasse la taille maximale",
+	ErrOIDCNotEnabled.ID:                        "L'authentification OpenID Connect n'est pas activée",
+	ErrOIDCAuthorizationPending.ID:              "L'autorisation OpenID Connect est en attente",
+	ErrOIDCInvalidState.ID:                      "Demande de connexion OpenID Connect invalide ou expirée",
+	ErrInvalidAccessTokenScope.ID:               "Portée de jeton d'accès invalide, read, run ou admin:<clé de projet> attendu",
+	ErrAccessTokenScopeForbidden.ID:             "Les portées du jeton d'accès ne permettent pas cette requête",
 }
 
 var errorsLanguages = []map[int]string{
@@ -65,3 +65,71 @@ type EventNotif struct {
 	Subject    string   `json:"subject,omitempty"`
 	Body       string   `json:"body,omitempty"`
 }
+
+// EventWorkflowRun contains event data for a workflow run
+type EventWorkflowRun struct {
+	ID            int64  `json:"id,omitempty"`
+	Number        int64  `json:"number,omitempty"`
+	LastSubNumber int64  `json:"lastSubNumber,omitempty"`
+	Status        Status `json:"status,omitempty"`
+	Start         int64  `json:"start,omitempty"`
+	LastModified  int64  `json:"lastModified,omitempty"`
+	ProjectKey    string `json:"projectKey,omitempty"`
+	WorkflowName  string `json:"workflowName,omitempty"`
+	BranchName    string `json:"branchName,omitempty"`
+	Hash          string `json:"hash,omitempty"`
+}
+
+// EventWorkflowNodeRun contains event data for a workflow node run
+type EventWorkflowNodeRun struct {
+	ID                    int64  `json:"id,omitempty"`
+	WorkflowRunID         int64  `json:"workflowRunID,omitempty"`
+	WorkflowNodeID        int64  `json:"workflowNodeID,omitempty"`
+	Number                int64  `json:"number,omitempty"`
+	SubNumber             int64  `json:"subNumber,omitempty"`
+	Status                Status `json:"status,omitempty"`
+	Start                 int64  `json:"start,omitempty"`
+	Done                  int64  `json:"done,omitempty"`
+	ProjectKey            string `json:"projectKey,omitempty"`
+	WorkflowName          string `json:"workflowName,omitempty"`
+	NodeName              string `json:"nodeName,omitempty"`
+	PipelineName          string `json:"pipelineName,omitempty"`
+	ApplicationName       string `json:"applicationName,omitempty"`
+	EnvironmentName       string `json:"environmentName,omitempty"`
+	BranchName            string `json:"branchName,omitempty"`
+	Hash                  string `json:"hash,omitempty"`
+	RepositoryManagerName string `json:"repositoryManagerName,omitempty"`
+	RepositoryFullname    string `json:"repositoryFullname,omitempty"`
+}
+
+// EventWorkflowNodeJobRun contains event data for a job of a workflow node run
+type EventWorkflowNodeJobRun struct {
+	ID                int64  `json:"id,omitempty"`
+	WorkflowNodeRunID int64  `json:"workflowNodeRunID,omitempty"`
+	Number            int64  `json:"number,omitempty"`
+	SubNumber         int64  `json:"subNumber,omitempty"`
+	JobName           string `json:"jobName,omitempty"`
+	Status            Status `json:"status,omitempty"`
+	Queued            int64  `json:"queued,omitempty"`
+	Start             int64  `json:"start,omitempty"`
+	Done              int64  `json:"done,omitempty"`
+	ModelName         string `json:"modelName,omitempty"`
+	ProjectKey        string `json:"projectKey,omitempty"`
+	WorkflowName      string `json:"workflowName,omitempty"`
+	PipelineName      string `json:"pipelineName,omitempty"`
+	ApplicationName   string `json:"applicationName,omitempty"`
+	EnvironmentName   string `json:"environmentName,omitempty"`
+	BranchName        string `json:"branchName,omitempty"`
+	Hash              string `json:"hash,omitempty"`
+}
+
+// EventCommitStatus contains data for a commit status which is not related to a pipeline build, like the coverage of a workflow node run
+type EventCommitStatus struct {
+	RepositoryFullname string `json:"repositoryFullname,omitempty"`
+	Hash               string `json:"hash,omitempty"`
+	BranchName         string `json:"branchName,omitempty"`
+	Context            string `json:"context,omitempty"`
+	Status             Status `json:"status,omitempty"`
+	Description        string `json:"description,omitempty"`
+	URL                string `json:"url,omitempty"`
+}
@@ -16,6 +16,7 @@ import (
 type Pipeline struct {
 	Name         string                    `json:"name,omitempty" yaml:"name,omitempty"`
 	Type         string                    `json:"type,omitempty" yaml:"type,omitempty"`
+	Priority     int64                     `json:"priority,omitempty" yaml:"priority,omitempty"`
 	Permissions  map[string]int            `json:"permissions,omitempty" yaml:"permissions,omitempty"`
 	Parameters   map[string]ParameterValue `json:"parameters,omitempty" yaml:"parameters,omitempty"`
 	Stages       map[string]Stage          `json:"stages,omitempty" yaml:"stages,omitempty"`
@@ -39,16 +40,29 @@ type Job struct {
 	Requirements   []Requirement `json:"requirements,omitempty" yaml:"requirements,omitempty" hcl:"requirement,omitempty"`
 	Optional       *bool         `json:"optional,omitempty" yaml:"optional,omitempty" hcl:"optional,omitempty"`
 	AlwaysExecuted *bool         `json:"always_executed,omitempty" yaml:"always_executed,omitempty" hcl:"always_executed,omitempty"`
+	Retry          int           `json:"retry,omitempty" yaml:"retry,omitempty" hcl:"retry,omitempty"`
+	RetryDelay     int           `json:"retry_delay,omitempty" yaml:"retry_delay,omitempty" hcl:"retry_delay,omitempty"`
+	Timeout        int           `json:"timeout,omitempty" yaml:"timeout,omitempty" hcl:"timeout,omitempty"`
+	Matrix         sdk.JobMatrix `json:"matrix,omitempty" yaml:"matrix,omitempty" hcl:"matrix,omitempty"`
 }
 
 // Step represents exported step used in a job
 type Step map[string]interface{}
 
+// isStepOption returns true if the key of a step is an option and not the action of the step
+func isStepOption(k string) bool {
+	switch k {
+	case "enabled", "optional", "always_executed", "retry", "retry_delay", "timeout":
+		return true
+	}
+	return false
+}
+
 // IsValid returns true is the step is valid
 func (s Step) IsValid() bool {
 	keys := []string{}
 	for k := range s {
-		if k != "enabled" && k != "optional" && k != "always_executed" {
+		if !isStepOption(k) {
 			keys = append(keys, k)
 		}
 	}
@@ -58,7 +72,7 @@ func (s Step) IsValid() bool {
 func (s Step) key() string {
 	keys := []string{}
 	for k := range s {
-		if k != "enabled" && k != "optional" && k != "always_executed" {
+		if !isStepOption(k) {
 			keys = append(keys, k)
 		}
 	}
@@ -255,6 +269,123 @@ func (s Step) AsArtifactUpload() (*sdk.Action, bool, error) {
 	return &a, true, nil
 }
 
+//AsCachePush returns the step a sdk.Action
+func (s Step) AsCachePush() (*sdk.Action, bool, error) {
+	if !s.IsValid() {
+		return nil, false, fmt.Errorf("Malformatted Step")
+	}
+
+	bI, ok := s["cachePush"]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if reflect.ValueOf(bI).Kind() != reflect.Map {
+		return nil, false, nil
+	}
+
+	argss := map[string]string{}
+	if err := mapstructure.Decode(bI, &argss); err != nil {
+		return nil, true, sdk.WrapError(err, "Malformatted Step")
+	}
+
+	a := sdk.NewStepCachePush(argss)
+
+	var err error
+	a.Enabled, err = s.IsFlagged("enabled")
+	if err != nil {
+		return nil, true, err
+	}
+	a.Optional, err = s.IsFlagged("optional")
+	if err != nil {
+		return nil, true, err
+	}
+	a.AlwaysExecuted, err = s.IsFlagged("always_executed")
+	if err != nil {
+		return nil, true, err
+	}
+
+	return &a, true, nil
+}
+
+//AsCachePull returns the step a sdk.Action
+func (s Step) AsCachePull() (*sdk.Action, bool, error) {
+	if !s.IsValid() {
+		return nil, false, fmt.Errorf("Malformatted Step")
+	}
+
+	bI, ok := s["cachePull"]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if reflect.ValueOf(bI).Kind() != reflect.Map {
+		return nil, false, nil
+	}
+
+	argss := map[string]string{}
+	if err := mapstructure.Decode(bI, &argss); err != nil {
+		return nil, true, sdk.WrapError(err, "Malformatted Step")
+	}
+
+	a := sdk.NewStepCachePull(argss)
+
+	var err error
+	a.Enabled, err = s.IsFlagged("enabled")
+	if err != nil {
+		return nil, true, err
+	}
+	a.Optional, err = s.IsFlagged("optional")
+	if err != nil {
+		return nil, true, err
+	}
+	a.AlwaysExecuted, err = s.IsFlagged("always_executed")
+	if err != nil {
+		return nil, true, err
+	}
+
+	return &a, true, nil
+}
+
+//AsCoverage returns the step a sdk.Action
+func (s Step) AsCoverage() (*sdk.Action, bool, error) {
+	if !s.IsValid() {
+		return nil, false, fmt.Errorf("Malformatted Step")
+	}
+
+	bI, ok := s["coverage"]
+	if !ok {
+		return nil, false, nil
+	}
+
+	if reflect.ValueOf(bI).Kind() != reflect.Map {
+		return nil, false, nil
+	}
+
+	argss := map[string]string{}
+	if err := mapstructure.Decode(bI, &argss); err != nil {
+		return nil, true, sdk.WrapError(err, "Malformatted Step")
+	}
+
+	a := sdk.NewStepCoverage(argss)
+
+	var err error
+	a.Enabled, err = s.IsFlagged("enabled")
+	if err != nil {
+		return nil, true, err
+	}
+	a.Optional, err = s.IsFlagged("optional")
+	if err != nil {
+		return nil, true, err
+	}
+	a.AlwaysExecuted, err = s.IsFlagged("always_executed")
+	if err != nil {
+		return nil, true, err
+	}
+
+	return &a, true, nil
+}
+
 //AsArtifactDownload returns the step a sdk.Action
 func (s Step) AsArtifactDownload() (*sdk.Action, bool, error) {
 	if !s.IsValid() {
@@ -303,6 +434,32 @@ func (s Step) IsFlagged(flag string) (bool, error) {
 	return bS, nil
 }
 
+// IntOption returns the value of an integer option of the step, 0 if it is not set
+func (s Step) IntOption(option string) (int, error) {
+	vI, ok := s[option]
+	if !ok {
+		return 0, nil
+	}
+	var v int
+	switch vt := vI.(type) {
+	case int:
+		v = vt
+	case int64:
+		v = int(vt)
+	case float64:
+		if vt != float64(int(vt)) {
+			return 0, fmt.Errorf("Malformatted Step : %s attribute must be an integer", option)
+		}
+		v = int(vt)
+	default:
+		return 0, fmt.Errorf("Malformatted Step : %s attribute must be an integer", option)
+	}
+	if v < 0 {
+		return 0, fmt.Errorf("Malformatted Step : %s attribute must be positive", option)
+	}
+	return v, nil
+}
+
 // Requirement represents an exported sdk.Requirement
 type Requirement struct {
 	Binary   string             `json:"binary,omitempty" yaml:"binary,omitempty"`
@@ -334,6 +491,8 @@ func NewPipeline(pip *sdk.Pipeline) (p *Pipeline) {
 		p.Type = pip.Type
 	}
 
+	p.Priority = pip.Priority
+
 	if len(pip.GroupPermission) > 0 {
 		p.Permissions = make(map[string]int, len(pip.GroupPermission))
 		for _, perm := range pip.GroupPermission {
@@ -360,9 +519,13 @@ func NewPipeline(pip *sdk.Pipeline) (p *Pipeline) {
 			case 0:
 				return
 			case 1:
-				p.Steps = newSteps(pip.Stages[0].Jobs[0].Action)
-				p.Requirements = newRequirements(pip.Stages[0].Jobs[0].Action.Requirements)
-				return
+				// The retry policy, the timeout and the matrix of the job can't be exported without the job
+				if j := pip.Stages[0].Jobs[0]; j.Retry == 0 && j.RetryDelay == 0 && j.Timeout == 0 && len(j.Matrix) == 0 {
+					p.Steps = newSteps(j.Action)
+					p.Requirements = newRequirements(j.Action.Requirements)
+					return
+				}
+				p.Jobs = newJobs(pip.Stages[0].Jobs)
 			default:
 				p.Jobs = newJobs(pip.Stages[0].Jobs)
 			}
@@ -441,6 +604,10 @@ func newJobs(jobs []sdk.Job) map[string]Job {
 		jo.Steps = newSteps(j.Action)
 		jo.Description = j.Action.Description
 		jo.Requirements = newRequirements(j.Action.Requirements)
+		jo.Retry = j.Retry
+		jo.RetryDelay = j.RetryDelay
+		jo.Timeout = j.Timeout
+		jo.Matrix = j.Matrix
 		res[j.Action.Name] = jo
 	}
 	return res
@@ -460,6 +627,15 @@ func newSteps(a sdk.Action) []Step {
 		if act.AlwaysExecuted {
 			s["always_executed"] = act.AlwaysExecuted
 		}
+		if act.Retry > 0 {
+			s["retry"] = act.Retry
+		}
+		if act.RetryDelay > 0 {
+			s["retry_delay"] = act.RetryDelay
+		}
+		if act.Timeout > 0 {
+			s["timeout"] = act.Timeout
+		}
 
 		switch act.Type {
 		case sdk.BuiltinAction:
@@ -531,6 +707,26 @@ func newSteps(a sdk.Action) []Step {
 				}
 
 				s["gitClone"] = gitCloneArgs
+			case sdk.CachePushAction, sdk.CachePullAction:
+				cacheArgs := map[string]string{}
+				for _, p := range act.Parameters {
+					if p.Value != "" {
+						cacheArgs[p.Name] = p.Value
+					}
+				}
+				if act.Name == sdk.CachePushAction {
+					s["cachePush"] = cacheArgs
+				} else {
+					s["cachePull"] = cacheArgs
+				}
+			case sdk.CoverageAction:
+				coverageArgs := map[string]string{}
+				for _, p := range act.Parameters {
+					if p.Value != "" {
+						coverageArgs[p.Name] = p.Value
+					}
+				}
+				s["coverage"] = coverageArgs
 			case sdk.JUnitAction:
 				path := sdk.ParameterFind(act.Parameters, "path")
 				if path != nil {
@@ -566,6 +762,7 @@ func (p *Pipeline) Pipeline() (*sdk.Pipeline, error) {
 
 	pip.Name = p.Name
 	pip.Type = p.Type
+	pip.Priority = p.Priority
 
 	//Compute permissions
 	for g, p := range p.Permissions {
@@ -696,7 +893,25 @@ func computeSteps(steps []Step) ([]sdk.Action, error) {
 	return res, nil
 }
 
-func computeStep(s Step) (a *sdk.Action, e error) {
+func computeStep(s Step) (*sdk.Action, error) {
+	a, err := computeStepAction(s)
+	if err != nil || a == nil {
+		return a, err
+	}
+
+	if a.Retry, err = s.IntOption("retry"); err != nil {
+		return nil, err
+	}
+	if a.RetryDelay, err = s.IntOption("retry_delay"); err != nil {
+		return nil, err
+	}
+	if a.Timeout, err = s.IntOption("timeout"); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func computeStepAction(s Step) (a *sdk.Action, e error) {
 	if !s.IsValid() {
 		e = fmt.Errorf("Malformatted step")
 		return
@@ -723,6 +938,21 @@ func computeStep(s Step) (a *sdk.Action, e error) {
 		return
 	}
 
+	a, ok, e = s.AsCachePush()
+	if ok {
+		return
+	}
+
+	a, ok, e = s.AsCachePull()
+	if ok {
+		return
+	}
+
+	a, ok, e = s.AsCoverage()
+	if ok {
+		return
+	}
+
 	a, ok, e = s.AsScript()
 	if ok {
 		return
@@ -794,6 +1024,18 @@ func computeJob(name string, j Job) (*sdk.Job, error) {
 	job.Action.Enabled = job.Enabled
 	job.Action.Requirements = computeJobRequirements(j.Requirements)
 
+	if j.Retry < 0 || j.RetryDelay < 0 || j.Timeout < 0 {
+		return nil, fmt.Errorf("Malformatted job %s : retry, retry_delay and timeout must be positive", name)
+	}
+	job.Retry = j.Retry
+	job.RetryDelay = j.RetryDelay
+	job.Timeout = j.Timeout
+
+	if err := j.Matrix.Check(); err != nil {
+		return nil, fmt.Errorf("Malformatted job %s : %s", name, err)
+	}
+	job.Matrix = j.Matrix
+
 	//Compute steps for the jobs
 	children, err := computeSteps(j.Steps)
 	if err != nil {
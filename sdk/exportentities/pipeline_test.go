@@ -591,3 +591,167 @@ func Test_IsFlagged(t *testing.T) {
 	}
 
 }
+
+func Test_ImportPipelineWithRetry(t *testing.T) {
+	in := `name: deploy
+jobs:
+  deploy:
+    retry: 2
+    retry_delay: 30
+    steps:
+    - script: ./deploy.sh
+      retry: 3
+      retry_delay: 10
+    - script: ./check.sh
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	job := p.Stages[0].Jobs[0]
+	assert.Equal(t, 2, job.Retry)
+	assert.Equal(t, 30, job.RetryDelay)
+	assert.Len(t, job.Action.Actions, 2)
+	assert.Equal(t, 3, job.Action.Actions[0].Retry)
+	assert.Equal(t, 10, job.Action.Actions[0].RetryDelay)
+	assert.Equal(t, 0, job.Action.Actions[1].Retry)
+
+	// The job is exported with its retry policy
+	exported := NewPipeline(p)
+	assert.Nil(t, exported.Steps)
+	assert.Equal(t, 2, exported.Jobs["deploy"].Retry)
+	assert.Equal(t, 30, exported.Jobs["deploy"].RetryDelay)
+	assert.Equal(t, 3, exported.Jobs["deploy"].Steps[0]["retry"])
+
+	// Values decoded from JSON are float64
+	s := Step{"script": "./deploy.sh", "retry": float64(3)}
+	a, err := computeStep(s)
+	test.NoError(t, err)
+	assert.Equal(t, 3, a.Retry)
+
+	_, err = computeStep(Step{"script": "./deploy.sh", "retry": -1})
+	assert.Error(t, err)
+	_, err = computeStep(Step{"script": "./deploy.sh", "retry": "often"})
+	assert.Error(t, err)
+}
+
+func Test_ImportPipelineWithTimeout(t *testing.T) {
+	in := `name: build
+steps:
+- script: make
+  timeout: 600
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	job := p.Stages[0].Jobs[0]
+	assert.Equal(t, 600, job.Action.Actions[0].Timeout)
+
+	// The job is exported with its timeout
+	p.Stages[0].Jobs[0].Timeout = 3600
+	exported := NewPipeline(p)
+	assert.Nil(t, exported.Steps)
+	assert.Equal(t, 3600, exported.Jobs[job.Action.Name].Timeout)
+	assert.Equal(t, 600, exported.Jobs[job.Action.Name].Steps[0]["timeout"])
+}
+
+func Test_ImportPipelineWithMatrix(t *testing.T) {
+	in := `name: build
+jobs:
+  build:
+    matrix:
+      go: ["1.8", "1.9"]
+      os: [linux, windows]
+    requirements:
+    - model: golang-{{.cds.matrix.go}}
+    steps:
+    - script: GOOS={{.cds.matrix.os}} go build
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	job := p.Stages[0].Jobs[0]
+	assert.Equal(t, sdk.JobMatrix{"go": {"1.8", "1.9"}, "os": {"linux", "windows"}}, job.Matrix)
+
+	// The job is exported with its matrix
+	exported := NewPipeline(p)
+	assert.Nil(t, exported.Steps)
+	assert.Equal(t, job.Matrix, exported.Jobs["build"].Matrix)
+
+	// A variable without value
+	payload.Jobs["build"] = Job{Matrix: sdk.JobMatrix{"go": {}}, Steps: payload.Jobs["build"].Steps}
+	_, err = payload.Pipeline()
+	assert.Error(t, err)
+}
+
+func Test_ImportPipelineWithCache(t *testing.T) {
+	in := `name: build
+steps:
+- cachePull:
+    key: deps-{{.git.hash}}
+    fallback: deps-
+- script: npm install
+- cachePush:
+    key: deps-{{.git.hash}}
+    path: node_modules
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	actions := p.Stages[0].Jobs[0].Action.Actions
+	assert.Len(t, actions, 3)
+	assert.Equal(t, sdk.CachePullAction, actions[0].Name)
+	assert.Equal(t, "deps-", sdk.ParameterValue(actions[0].Parameters, "fallback"))
+	assert.Equal(t, sdk.CachePushAction, actions[2].Name)
+	assert.Equal(t, "node_modules", sdk.ParameterValue(actions[2].Parameters, "path"))
+
+	// The steps are exported with their arguments
+	exported := NewPipeline(p)
+	assert.Equal(t, map[string]string{"key": "deps-{{.git.hash}}", "path": "node_modules"}, exported.Steps[2]["cachePush"])
+}
+
+func Test_ImportPipelineWithCoverage(t *testing.T) {
+	in := `name: build
+steps:
+- script: go test -coverprofile=coverage.out ./...
+- coverage:
+    path: coverage.out
+    format: go
+    minimum: "80"
+    vcs_status: "true"
+  always_executed: true
+`
+
+	payload := &Pipeline{}
+	test.NoError(t, yaml.Unmarshal([]byte(in), payload))
+
+	p, err := payload.Pipeline()
+	test.NoError(t, err)
+
+	actions := p.Stages[0].Jobs[0].Action.Actions
+	assert.Len(t, actions, 2)
+	assert.Equal(t, sdk.CoverageAction, actions[1].Name)
+	assert.Equal(t, sdk.BuiltinAction, actions[1].Type)
+	assert.Equal(t, "go", sdk.ParameterValue(actions[1].Parameters, "format"))
+	assert.Equal(t, "80", sdk.ParameterValue(actions[1].Parameters, "minimum"))
+	assert.True(t, actions[1].AlwaysExecuted)
+
+	// The step is exported with its arguments
+	exported := NewPipeline(p)
+	assert.Equal(t, map[string]string{"path": "coverage.out", "format": "go", "minimum": "80", "vcs_status": "true"}, exported.Steps[1]["coverage"])
+}
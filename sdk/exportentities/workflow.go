@@ -0,0 +1,498 @@
+package exportentities
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/sdk"
+)
+
+// Workflow is a struct to export sdk.Workflow. Nodes are indexed by their name:
+// a node without dependency is the root of the workflow, a node depending on one node is triggered by this node
+// and a node depending on several nodes is triggered by a join on these nodes
+type Workflow struct {
+	Name        string               `json:"name" yaml:"name"`
+	Description string               `json:"description,omitempty" yaml:"description,omitempty"`
+	Workflow    map[string]NodeEntry `json:"workflow" yaml:"workflow"`
+}
+
+// NodeEntry is a struct to export a sdk.WorkflowNode with the trigger which leads to it
+type NodeEntry struct {
+	DependsOn       []string               `json:"depends_on,omitempty" yaml:"depends_on,omitempty"`
+	Conditions      *ConditionsEntry       `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+	Manual          bool                   `json:"manual,omitempty" yaml:"manual,omitempty"`
+	ContinueOnError bool                   `json:"continue_on_error,omitempty" yaml:"continue_on_error,omitempty"`
+	PipelineName    string                 `json:"pipeline" yaml:"pipeline"`
+	ApplicationName string                 `json:"application,omitempty" yaml:"application,omitempty"`
+	EnvironmentName string                 `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Payload         map[string]interface{} `json:"payload,omitempty" yaml:"payload,omitempty"`
+	Parameters      map[string]string      `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Priority        int64                  `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Hooks           []HookEntry            `json:"hooks,omitempty" yaml:"hooks,omitempty"`
+	Notifications   []NotificationEntry    `json:"notifications,omitempty" yaml:"notifications,omitempty"`
+}
+
+// HookEntry is a struct to export a sdk.WorkflowNodeHook
+type HookEntry struct {
+	Model      string            `json:"type" yaml:"type"`
+	Config     map[string]string `json:"config,omitempty" yaml:"config,omitempty"`
+	Conditions *ConditionsEntry  `json:"conditions,omitempty" yaml:"conditions,omitempty"`
+}
+
+// NotificationEntry is a struct to export a sdk.WorkflowNodeNotification
+type NotificationEntry struct {
+	Type    string `json:"type" yaml:"type"`
+	When    string `json:"when" yaml:"when"`
+	URL     string `json:"url" yaml:"url"`
+	Channel string `json:"channel,omitempty" yaml:"channel,omitempty"`
+	Subject string `json:"subject,omitempty" yaml:"subject,omitempty"`
+	Body    string `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// ConditionsEntry is a struct to export sdk.WorkflowTriggerConditions
+type ConditionsEntry struct {
+	Check  []ConditionEntry `json:"check,omitempty" yaml:"check,omitempty"`
+	Script string           `json:"script,omitempty" yaml:"script,omitempty"`
+}
+
+// ConditionEntry is a struct to export a sdk.WorkflowTriggerCondition
+type ConditionEntry struct {
+	Variable string `json:"variable" yaml:"variable"`
+	Operator string `json:"operator" yaml:"operator"`
+	Value    string `json:"value" yaml:"value"`
+}
+
+// hookConfigReservedKeys are set by the API on each hook, they are not exported. The secrets are not exported either
+var hookConfigReservedKeys = map[string]bool{"project": true, "workflow": true}
+
+//NewWorkflow returns a Workflow from a sdk.Workflow
+func NewWorkflow(w sdk.Workflow) (Workflow, error) {
+	exportedWorkflow := Workflow{
+		Name:        w.Name,
+		Description: w.Description,
+		Workflow:    map[string]NodeEntry{},
+	}
+
+	if w.Root == nil {
+		return exportedWorkflow, sdk.ErrWorkflowInvalidRoot
+	}
+
+	if err := exportedWorkflow.addNode(w.Root, nil, sdk.WorkflowTriggerConditions{}, false, false); err != nil {
+		return exportedWorkflow, err
+	}
+
+	for _, j := range w.Joins {
+		dependsOn := make([]string, 0, len(j.SourceNodeIDs))
+		for _, id := range j.SourceNodeIDs {
+			src := w.GetNode(id)
+			if src == nil {
+				return exportedWorkflow, sdk.WrapError(sdk.ErrWorkflowNodeRef, "NewWorkflow> Unable to find source node %d of join %d", id, j.ID)
+			}
+			dependsOn = append(dependsOn, src.Name)
+		}
+		sort.Strings(dependsOn)
+
+		for i := range j.Triggers {
+			t := &j.Triggers[i]
+			if err := exportedWorkflow.addNode(&t.WorkflowDestNode, dependsOn, t.Conditions, t.Manual, t.ContinueOnError); err != nil {
+				return exportedWorkflow, err
+			}
+		}
+	}
+
+	return exportedWorkflow, nil
+}
+
+// addNode adds the node and its children to the exported workflow
+func (w *Workflow) addNode(n *sdk.WorkflowNode, dependsOn []string, conditions sdk.WorkflowTriggerConditions, manual, continueOnError bool) error {
+	if _, ok := w.Workflow[n.Name]; ok {
+		return sdk.NewError(sdk.ErrWorkflowInvalid, fmt.Errorf("Duplicate node name %s", n.Name))
+	}
+
+	entry := NodeEntry{
+		DependsOn:       dependsOn,
+		Conditions:      newConditionsEntry(conditions),
+		Manual:          manual,
+		ContinueOnError: continueOnError,
+		PipelineName:    n.Pipeline.Name,
+	}
+
+	if n.Context != nil {
+		if n.Context.Application != nil {
+			entry.ApplicationName = n.Context.Application.Name
+		}
+		if n.Context.Environment != nil {
+			entry.EnvironmentName = n.Context.Environment.Name
+		}
+
+		if n.Context.DefaultPayload != nil {
+			// The payload is stored as JSON, it is always a JSON object
+			btes, err := json.Marshal(n.Context.DefaultPayload)
+			if err != nil {
+				return sdk.WrapError(err, "NewWorkflow> Unable to marshal default payload of node %s", n.Name)
+			}
+			if err := json.Unmarshal(btes, &entry.Payload); err != nil {
+				return sdk.WrapError(err, "NewWorkflow> Default payload of node %s is not an object", n.Name)
+			}
+		}
+
+		if len(n.Context.DefaultPipelineParameters) > 0 {
+			entry.Parameters = make(map[string]string, len(n.Context.DefaultPipelineParameters))
+			for _, p := range n.Context.DefaultPipelineParameters {
+				entry.Parameters[p.Name] = p.Value
+			}
+		}
+
+		entry.Priority = n.Context.Priority
+	}
+
+	for _, h := range n.Hooks {
+		config := make(map[string]string, len(h.Config))
+		for k, v := range h.Config.WithoutSecrets() {
+			if !hookConfigReservedKeys[k] {
+				config[k] = v
+			}
+		}
+		entry.Hooks = append(entry.Hooks, HookEntry{
+			Model:      h.WorkflowHookModel.Name,
+			Config:     config,
+			Conditions: newConditionsEntry(h.Conditions),
+		})
+	}
+
+	for _, notif := range n.Notifications {
+		entry.Notifications = append(entry.Notifications, NotificationEntry{
+			Type:    string(notif.Type),
+			When:    string(notif.Settings.When),
+			URL:     notif.Settings.URL,
+			Channel: notif.Settings.Channel,
+			Subject: notif.Settings.Template.Subject,
+			Body:    notif.Settings.Template.Body,
+		})
+	}
+
+	w.Workflow[n.Name] = entry
+
+	for i := range n.Triggers {
+		t := &n.Triggers[i]
+		if err := w.addNode(&t.WorkflowDestNode, []string{n.Name}, t.Conditions, t.Manual, t.ContinueOnError); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newConditionsEntry(c sdk.WorkflowTriggerConditions) *ConditionsEntry {
+	if len(c.PlainConditions) == 0 && c.LuaScript == "" {
+		return nil
+	}
+	entry := &ConditionsEntry{Script: c.LuaScript}
+	for _, cond := range c.PlainConditions {
+		entry.Check = append(entry.Check, ConditionEntry{
+			Variable: cond.Variable,
+			Operator: cond.Operator,
+			Value:    cond.Value,
+		})
+	}
+	return entry
+}
+
+func (c *ConditionsEntry) conditions() sdk.WorkflowTriggerConditions {
+	res := sdk.WorkflowTriggerConditions{}
+	if c == nil {
+		return res
+	}
+	res.LuaScript = c.Script
+	for _, cond := range c.Check {
+		res.PlainConditions = append(res.PlainConditions, sdk.WorkflowTriggerCondition{
+			Variable: cond.Variable,
+			Operator: cond.Operator,
+			Value:    cond.Value,
+		})
+	}
+	return res
+}
+
+// GetWorkflow returns a sdk.Workflow. Pipelines, applications and environments are resolved by their names in the project
+func (w Workflow) GetWorkflow(proj *sdk.Project) (*sdk.Workflow, error) {
+	wf := &sdk.Workflow{
+		Name:        w.Name,
+		Description: w.Description,
+		ProjectID:   proj.ID,
+		ProjectKey:  proj.Key,
+	}
+
+	names := make([]string, 0, len(w.Workflow))
+	for name := range w.Workflow {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	errs := sdk.MultiError{}
+	nodes := make(map[string]*sdk.WorkflowNode, len(w.Workflow))
+	for _, name := range names {
+		n, err := w.Workflow[name].getNode(name, proj)
+		if err != nil {
+			errs.Append(err)
+			continue
+		}
+		nodes[name] = n
+	}
+
+	// Children of nodes with only one dependency, and joins indexed by their sources
+	var roots []string
+	children := map[string][]string{}
+	joins := map[string][]string{}
+	for _, name := range names {
+		dependsOn := w.Workflow[name].DependsOn
+		for _, d := range dependsOn {
+			if _, ok := w.Workflow[d]; !ok || d == name {
+				errs.Append(fmt.Errorf("Invalid dependency %s on node %s", d, name))
+			}
+		}
+		switch len(dependsOn) {
+		case 0:
+			roots = append(roots, name)
+		case 1:
+			children[dependsOn[0]] = append(children[dependsOn[0]], name)
+		default:
+			sources := append([]string{}, dependsOn...)
+			sort.Strings(sources)
+			key := strings.Join(sources, ",")
+			joins[key] = append(joins[key], name)
+		}
+	}
+	if len(roots) != 1 {
+		errs.Append(fmt.Errorf("Workflow must have exactly one node without dependency, got %d", len(roots)))
+	}
+	if !errs.IsEmpty() {
+		return nil, sdk.NewError(sdk.ErrWorkflowInvalid, &errs)
+	}
+
+	attached := map[string]bool{}
+	var attach func(name string) sdk.WorkflowNode
+	attach = func(name string) sdk.WorkflowNode {
+		attached[name] = true
+		n := nodes[name]
+		for _, child := range children[name] {
+			e := w.Workflow[child]
+			n.Triggers = append(n.Triggers, sdk.WorkflowNodeTrigger{
+				WorkflowDestNode: attach(child),
+				Conditions:       e.Conditions.conditions(),
+				Manual:           e.Manual,
+				ContinueOnError:  e.ContinueOnError,
+			})
+		}
+		return *n
+	}
+
+	root := attach(roots[0])
+	wf.Root = &root
+
+	// A join can be inserted only once all its sources are in the workflow
+	joinKeys := make([]string, 0, len(joins))
+	for k := range joins {
+		joinKeys = append(joinKeys, k)
+	}
+	sort.Strings(joinKeys)
+	for len(joinKeys) > 0 {
+		var pending []string
+		for _, k := range joinKeys {
+			sources := strings.Split(k, ",")
+			ready := true
+			for _, s := range sources {
+				if !attached[s] {
+					ready = false
+					break
+				}
+			}
+			if !ready {
+				pending = append(pending, k)
+				continue
+			}
+
+			j := sdk.WorkflowNodeJoin{SourceNodeRefs: sources}
+			for _, dest := range joins[k] {
+				e := w.Workflow[dest]
+				j.Triggers = append(j.Triggers, sdk.WorkflowNodeJoinTrigger{
+					WorkflowDestNode: attach(dest),
+					Conditions:       e.Conditions.conditions(),
+					Manual:           e.Manual,
+					ContinueOnError:  e.ContinueOnError,
+				})
+			}
+			wf.Joins = append(wf.Joins, j)
+		}
+		if len(pending) == len(joinKeys) {
+			break
+		}
+		joinKeys = pending
+	}
+
+	for _, name := range names {
+		if !attached[name] {
+			errs.Append(fmt.Errorf("Node %s is not reachable from node %s", name, roots[0]))
+		}
+	}
+	if !errs.IsEmpty() {
+		return nil, sdk.NewError(sdk.ErrWorkflowInvalid, &errs)
+	}
+
+	return wf, nil
+}
+
+// getNode returns the sdk.WorkflowNode of the entry without its triggers
+func (e NodeEntry) getNode(name string, proj *sdk.Project) (*sdk.WorkflowNode, error) {
+	n := &sdk.WorkflowNode{
+		Name:    name,
+		Ref:     name,
+		Context: &sdk.WorkflowNodeContext{},
+	}
+
+	var pip *sdk.Pipeline
+	for i := range proj.Pipelines {
+		if proj.Pipelines[i].Name == e.PipelineName {
+			pip = &proj.Pipelines[i]
+			break
+		}
+	}
+	if pip == nil {
+		return nil, fmt.Errorf("Pipeline %s of node %s not found", e.PipelineName, name)
+	}
+	n.Pipeline = *pip
+	n.PipelineID = pip.ID
+
+	if e.ApplicationName != "" {
+		for i := range proj.Applications {
+			if proj.Applications[i].Name == e.ApplicationName {
+				app := proj.Applications[i]
+				n.Context.Application = &app
+				n.Context.ApplicationID = app.ID
+				break
+			}
+		}
+		if n.Context.Application == nil {
+			return nil, fmt.Errorf("Application %s of node %s not found", e.ApplicationName, name)
+		}
+	}
+
+	if e.EnvironmentName != "" {
+		for i := range proj.Environments {
+			if proj.Environments[i].Name == e.EnvironmentName {
+				env := proj.Environments[i]
+				n.Context.Environment = &env
+				n.Context.EnvironmentID = env.ID
+				break
+			}
+		}
+		if n.Context.Environment == nil {
+			return nil, fmt.Errorf("Environment %s of node %s not found", e.EnvironmentName, name)
+		}
+	}
+
+	if len(e.Payload) > 0 {
+		n.Context.DefaultPayload = jsonValue(e.Payload)
+	}
+
+	params := make([]string, 0, len(e.Parameters))
+	for k := range e.Parameters {
+		params = append(params, k)
+	}
+	sort.Strings(params)
+	for _, k := range params {
+		p := sdk.Parameter{Name: k, Type: sdk.StringParameter, Value: e.Parameters[k]}
+		for _, pp := range pip.Parameter {
+			if pp.Name == k {
+				p.Type = pp.Type
+				break
+			}
+		}
+		n.Context.DefaultPipelineParameters = append(n.Context.DefaultPipelineParameters, p)
+	}
+
+	n.Context.Priority = e.Priority
+
+	for _, h := range e.Hooks {
+		config := make(sdk.WorkflowNodeHookConfig, len(h.Config))
+		for k, v := range h.Config {
+			config[k] = v
+		}
+		n.Hooks = append(n.Hooks, sdk.WorkflowNodeHook{
+			WorkflowHookModel: sdk.WorkflowHookModel{Name: h.Model},
+			Config:            config,
+			Conditions:        h.Conditions.conditions(),
+		})
+	}
+
+	for _, notif := range e.Notifications {
+		n.Notifications = append(n.Notifications, sdk.WorkflowNodeNotification{
+			Type: sdk.UserNotificationSettingsType(notif.Type),
+			Settings: sdk.WorkflowNodeNotificationSettings{
+				When:    sdk.UserNotificationEventType(notif.When),
+				URL:     notif.URL,
+				Channel: notif.Channel,
+				Template: sdk.UserNotificationTemplate{
+					Subject: notif.Subject,
+					Body:    notif.Body,
+				},
+			},
+		})
+	}
+
+	return n, nil
+}
+
+// jsonValue converts the maps decoded from yaml to maps which can be marshalled in JSON
+func jsonValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			m[fmt.Sprintf("%v", k)] = jsonValue(v)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, v := range t {
+			m[k] = jsonValue(v)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(t))
+		for i := range t {
+			s[i] = jsonValue(t[i])
+		}
+		return s
+	}
+	return v
+}
+
+// WorkflowDiff returns the unified diff between the yaml exports of two workflows. from can be nil if the workflow doesn't exist
+func WorkflowDiff(from *Workflow, to Workflow) (string, error) {
+	var a string
+	if from != nil {
+		btes, err := yaml.Marshal(from)
+		if err != nil {
+			return "", err
+		}
+		a = string(btes)
+	}
+
+	b, err := yaml.Marshal(to)
+	if err != nil {
+		return "", err
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(a),
+		B:        difflib.SplitLines(string(b)),
+		FromFile: "current",
+		ToFile:   "imported",
+		Context:  3,
+	})
+}
@@ -0,0 +1,223 @@
+package exportentities
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+
+	"github.com/ovh/cds/sdk"
+)
+
+var workflowTestProject = &sdk.Project{
+	ID:  1,
+	Key: "PROJ",
+	Pipelines: []sdk.Pipeline{
+		{ID: 1, Name: "build", Parameter: []sdk.Parameter{{Name: "target", Type: sdk.ListParameter, Value: "linux;darwin"}}},
+		{ID: 2, Name: "test"},
+		{ID: 3, Name: "deploy"},
+	},
+	Applications: []sdk.Application{
+		{ID: 1, Name: "app"},
+	},
+	Environments: []sdk.Environment{
+		{ID: 1, Name: "production"},
+	},
+}
+
+const workflowTestYAML = `name: my-workflow
+description: build, test and deploy
+workflow:
+  build:
+    pipeline: build
+    application: app
+    payload:
+      git.branch: master
+      nested:
+        key: value
+    parameters:
+      target: linux
+    hooks:
+    - type: WebHook
+      config:
+        method: POST
+  test-unit:
+    depends_on:
+    - build
+    pipeline: test
+  test-it:
+    depends_on:
+    - build
+    pipeline: test
+    conditions:
+      check:
+      - variable: cds.status
+        operator: eq
+        value: Success
+  deploy:
+    depends_on:
+    - test-it
+    - test-unit
+    pipeline: deploy
+    application: app
+    environment: production
+    manual: true
+    notifications:
+    - type: slack
+      when: failure
+      url: https://hooks.slack.com/services/T00/B00/XXX
+      channel: '#deploy'
+      subject: '{{.cds.node}} failed'
+`
+
+func TestWorkflowImportExport(t *testing.T) {
+	var payload Workflow
+	assert.NoError(t, yaml.Unmarshal([]byte(workflowTestYAML), &payload))
+
+	w, err := payload.GetWorkflow(workflowTestProject)
+	assert.NoError(t, err)
+	if w == nil {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "my-workflow", w.Name)
+	assert.Equal(t, "PROJ", w.ProjectKey)
+	assert.Equal(t, "build", w.Root.Name)
+	assert.Equal(t, int64(1), w.Root.PipelineID)
+	assert.Equal(t, int64(1), w.Root.Context.ApplicationID)
+	assert.Equal(t, map[string]interface{}{"git.branch": "master", "nested": map[string]interface{}{"key": "value"}}, w.Root.Context.DefaultPayload)
+	assert.Equal(t, []sdk.Parameter{{Name: "target", Type: sdk.ListParameter, Value: "linux"}}, w.Root.Context.DefaultPipelineParameters)
+	assert.Len(t, w.Root.Hooks, 1)
+	assert.Equal(t, "WebHook", w.Root.Hooks[0].WorkflowHookModel.Name)
+
+	assert.Len(t, w.Root.Triggers, 2)
+	assert.Equal(t, "test-it", w.Root.Triggers[0].WorkflowDestNode.Name)
+	assert.Equal(t, "cds.status", w.Root.Triggers[0].Conditions.PlainConditions[0].Variable)
+	assert.Equal(t, "test-unit", w.Root.Triggers[1].WorkflowDestNode.Name)
+
+	assert.Len(t, w.Joins, 1)
+	assert.Equal(t, []string{"test-it", "test-unit"}, w.Joins[0].SourceNodeRefs)
+	assert.Len(t, w.Joins[0].Triggers, 1)
+	assert.True(t, w.Joins[0].Triggers[0].Manual)
+	assert.Equal(t, "deploy", w.Joins[0].Triggers[0].WorkflowDestNode.Name)
+	assert.Equal(t, int64(1), w.Joins[0].Triggers[0].WorkflowDestNode.Context.EnvironmentID)
+	notifs := w.Joins[0].Triggers[0].WorkflowDestNode.Notifications
+	assert.Len(t, notifs, 1)
+	assert.Equal(t, sdk.SlackUserNotification, notifs[0].Type)
+	assert.Equal(t, sdk.UserNotificationFailure, notifs[0].Settings.When)
+	assert.Equal(t, "#deploy", notifs[0].Settings.Channel)
+	assert.Equal(t, "{{.cds.node}} failed", notifs[0].Settings.Template.Subject)
+
+	// Simulate the node IDs set by the database, used to reference the sources of the joins
+	w.Root.ID = 1
+	w.Root.Triggers[0].WorkflowDestNode.ID = 2
+	w.Root.Triggers[1].WorkflowDestNode.ID = 3
+	w.Joins[0].SourceNodeIDs = []int64{2, 3}
+	w.Root.Hooks[0].Config["project"] = "PROJ"
+	w.Root.Hooks[0].Config["workflow"] = "my-workflow"
+	w.Root.Hooks[0].Config["secret"] = "s3cr3t"
+
+	exported, err := NewWorkflow(*w)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test-it", "test-unit"}, exported.Workflow["deploy"].DependsOn)
+	assert.Equal(t, map[string]string{"method": "POST"}, exported.Workflow["build"].Hooks[0].Config)
+
+	diff, err := WorkflowDiff(&payload, exported)
+	assert.NoError(t, err)
+	assert.Empty(t, diff)
+}
+
+func TestWorkflowImportErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		yml  string
+	}{
+		{
+			name: "unknown pipeline",
+			yml: `name: w
+workflow:
+  root:
+    pipeline: unknown`,
+		},
+		{
+			name: "unknown application",
+			yml: `name: w
+workflow:
+  root:
+    pipeline: build
+    application: unknown`,
+		},
+		{
+			name: "two roots",
+			yml: `name: w
+workflow:
+  a:
+    pipeline: build
+  b:
+    pipeline: test`,
+		},
+		{
+			name: "unknown dependency",
+			yml: `name: w
+workflow:
+  a:
+    pipeline: build
+  b:
+    pipeline: test
+    depends_on: [c]`,
+		},
+		{
+			name: "cycle",
+			yml: `name: w
+workflow:
+  a:
+    pipeline: build
+  b:
+    pipeline: test
+    depends_on: [c]
+  c:
+    pipeline: test
+    depends_on: [b]`,
+		},
+		{
+			name: "join cycle",
+			yml: `name: w
+workflow:
+  a:
+    pipeline: build
+  b:
+    pipeline: test
+    depends_on: [a, c]
+  c:
+    pipeline: test
+    depends_on: [b]`,
+		},
+	}
+
+	for _, tt := range tests {
+		var payload Workflow
+		assert.NoError(t, yaml.Unmarshal([]byte(tt.yml), &payload), tt.name)
+		_, err := payload.GetWorkflow(workflowTestProject)
+		assert.Error(t, err, tt.name)
+	}
+}
+
+func TestWorkflowDiff(t *testing.T) {
+	from := Workflow{
+		Name:     "w",
+		Workflow: map[string]NodeEntry{"root": {PipelineName: "build"}},
+	}
+	to := Workflow{
+		Name:     "w",
+		Workflow: map[string]NodeEntry{"root": {PipelineName: "test"}},
+	}
+
+	diff, err := WorkflowDiff(&from, to)
+	assert.NoError(t, err)
+	assert.Contains(t, diff, "-    pipeline: build")
+	assert.Contains(t, diff, "+    pipeline: test")
+
+	diff, err = WorkflowDiff(nil, to)
+	assert.NoError(t, err)
+	assert.Contains(t, diff, "+name: w")
+}
@@ -36,6 +36,10 @@ type CommonConfiguration struct {
 		MaxWorker         int  `toml:"maxWorker" default:"10" comment:"Maximum allowed simultaneous workers"`
 		GraceTimeQueued   int  `toml:"graceTimeQueued" default:"4" comment:"if worker is queued less than this value (seconds), hatchery does not take care of it"`
 		RegisterFrequency int  `toml:"registerFrequency" default:"60" comment:"Check if some worker model have to be registered each n Seconds"`
+		Pool              struct {
+			MaxIdle int `toml:"maxIdle" default:"0" comment:"Maximum number of idle workers kept for each worker model. If lower than the provision of the worker model, the provision is used"`
+			IdleTTL int `toml:"idleTTL" default:"300" comment:"When the queue is empty, an idle worker above the provision of its worker model is disabled after this delay (seconds)"`
+		} `toml:"pool" comment:"Pool of idle workers waiting for jobs. The minimum size of the pool of a worker model is its provision"`
 		WorkerLogsOptions struct {
 			Graylog struct {
 				Host       string `toml:"host"`
@@ -57,6 +61,9 @@ type CommonConfiguration struct {
 			ThresholdWarning  int `toml:"thresholdWarning" default:"360" comment:"log warning if spawn take more than this value (in seconds)"`
 		} `toml:"spawnOptions"`
 	} `toml:"logOptions" comment:"Hatchery Log Configuration"`
+	Metrics struct {
+		Addr string `toml:"addr" default:"" commented:"true" comment:"Listen address of the HTTP server exposing the metrics of the hatchery on /mon/metrics, like :8086. Disabled if empty"`
+	} `toml:"metrics"`
 }
 
 // Interface describe an interface for each hatchery mode (mesos, local)
@@ -105,7 +112,7 @@ func CheckRequirement(r sdk.Requirement) (bool, error) {
 	}
 }
 
-func receiveJob(h Interface, isWorkflowJob bool, execGroups []sdk.Group, jobID int64, jobQueuedSeconds int64, jobBookedBy sdk.Hatchery, requirements []sdk.Requirement, models []sdk.Model, nRoutines *int64, spawnIDs *cache.Cache, hostname string) bool {
+func receiveJob(h Interface, pool *workerPool, isWorkflowJob bool, execGroups []sdk.Group, jobID int64, jobQueuedSeconds int64, jobBookedBy sdk.Hatchery, requirements []sdk.Requirement, models []sdk.Model, nRoutines *int64, spawnIDs *cache.Cache, hostname string) bool {
 	if jobID == 0 {
 		return false
 	}
@@ -138,7 +145,7 @@ func receiveJob(h Interface, isWorkflowJob bool, execGroups []sdk.Group, jobID i
 
 	atomic.AddInt64(nRoutines, 1)
 	defer atomic.AddInt64(nRoutines, -1)
-	isSpawned, errR := routine(h, isWorkflowJob, models, execGroups, jobID, requirements, hostname, time.Now().Unix())
+	isSpawned, errR := routine(h, pool, isWorkflowJob, models, execGroups, jobID, requirements, hostname, time.Now().Unix())
 	if errR != nil {
 		log.Warning("Error on routine: %s", errR)
 		return false
@@ -146,7 +153,7 @@ func receiveJob(h Interface, isWorkflowJob bool, execGroups []sdk.Group, jobID i
 	return isSpawned
 }
 
-func routine(h Interface, isWorkflowJob bool, models []sdk.Model, execGroups []sdk.Group, jobID int64, requirements []sdk.Requirement, hostname string, timestamp int64) (bool, error) {
+func routine(h Interface, pool *workerPool, isWorkflowJob bool, models []sdk.Model, execGroups []sdk.Group, jobID int64, requirements []sdk.Requirement, hostname string, timestamp int64) (bool, error) {
 	defer logTime(h, fmt.Sprintf("routine> %d", timestamp), time.Now())
 	log.Debug("routine> %d enter", timestamp)
 
@@ -160,6 +167,12 @@ func routine(h Interface, isWorkflowJob bool, models []sdk.Model, execGroups []s
 	}
 	log.Debug("routine> %d - models received: %d", timestamp, len(models))
 
+	if !h.Configuration().Provision.Disabled {
+		if bookForIdleWorker(h, pool, isWorkflowJob, models, execGroups, jobID, requirements, hostname, timestamp) {
+			return true, nil
+		}
+	}
+
 	for _, model := range models {
 		if canRunJob(h, timestamp, execGroups, jobID, requirements, &model, hostname) {
 			if err := h.Client().QueueJobBook(isWorkflowJob, jobID); err != nil {
@@ -206,6 +219,8 @@ func routine(h Interface, isWorkflowJob bool, models []sdk.Model, execGroups []s
 			if err := h.Client().QueueJobSendSpawnInfo(isWorkflowJob, jobID, infos); err != nil {
 				log.Warning("routine> %d - cannot client.QueueJobSendSpawnInfo for job %d: %s", timestamp, jobID, err)
 			}
+			// The job waited for a new worker: keep one more idle worker of this model
+			pool.grow(h, &model)
 			return true, nil // ok for this job
 		}
 	}
@@ -213,30 +228,63 @@ func routine(h Interface, isWorkflowJob bool, models []sdk.Model, execGroups []s
 	return false, nil
 }
 
-func provisioning(h Interface, provisionDisabled bool, models []sdk.Model) {
+// bookForIdleWorker books the job for an idle worker of the pool which can run it
+func bookForIdleWorker(h Interface, pool *workerPool, isWorkflowJob bool, models []sdk.Model, execGroups []sdk.Group, jobID int64, requirements []sdk.Requirement, hostname string, timestamp int64) bool {
+	model, workerName, ok := pool.reserve(h, models, func(m *sdk.Model) bool {
+		return checkRequirements(h, timestamp, execGroups, jobID, requirements, m, hostname)
+	})
+	if !ok {
+		return false
+	}
+
+	if err := h.Client().QueueJobBook(isWorkflowJob, jobID); err != nil {
+		// perhaps already booked by another hatchery
+		pool.release(workerName)
+		log.Debug("routine> %d - cannot book job %d for idle worker %s: %s", timestamp, jobID, workerName, err)
+		return false
+	}
+	log.Debug("routine> %d - job %d booked for idle worker %s by hatchery %d", timestamp, jobID, workerName, h.Hatchery().ID)
+
+	infos := []sdk.SpawnInfo{
+		{
+			RemoteTime: time.Now(),
+			Message:    sdk.SpawnMsg{ID: sdk.MsgSpawnInfoHatcheryWaitingWorker.ID, Args: []interface{}{fmt.Sprintf("%s", h.Hatchery().Name), fmt.Sprintf("%d", h.Hatchery().ID), model.Name}},
+		},
+	}
+	if err := h.Client().QueueJobSendSpawnInfo(isWorkflowJob, jobID, infos); err != nil {
+		log.Warning("routine> %d - cannot client.QueueJobSendSpawnInfo for job %d: %s", timestamp, jobID, err)
+	}
+
+	// Replace the worker in the pool
+	pool.provision(h, *model)
+	return true
+}
+
+func provisioning(h Interface, provisionDisabled bool, pool *workerPool, models []sdk.Model) {
 	if provisionDisabled {
 		log.Debug("provisioning> disabled on this hatchery")
 		return
 	}
 
+	if err := pool.refresh(h); err != nil {
+		log.Warning("provisioning> %s", err)
+		return
+	}
+
 	for k := range models {
 		if models[k].Type == h.ModelType() {
-			existing := h.WorkersStartedByModel(&models[k])
-			for i := existing; i < int(models[k].Provision); i++ {
-				go func(m sdk.Model) {
-					if name, errSpawn := h.SpawnWorker(&m, 0, nil, false, "spawn for provision"); errSpawn != nil {
-						log.Warning("provisioning> cannot spawn worker %s with model %s for provisioning: %s", name, m.Name, errSpawn)
-						if err := h.Client().WorkerModelSpawnError(m.ID, fmt.Sprintf("routine> cannot spawn worker %s for provisioning: %s", m.Name, errSpawn)); err != nil {
-							log.Error("provisioning> cannot client.WorkerModelSpawnError for worker %s with model %s for provisioning: %s", name, m.Name, errSpawn)
-						}
-					}
-				}(models[k])
-			}
+			pool.shrink(h, models[k])
+			pool.provision(h, models[k])
 		}
 	}
 }
 
 func canRunJob(h Interface, timestamp int64, execGroups []sdk.Group, jobID int64, requirements []sdk.Requirement, model *sdk.Model, hostname string) bool {
+	return checkRequirements(h, timestamp, execGroups, jobID, requirements, model, hostname) && h.CanSpawn(model, jobID, requirements)
+}
+
+// checkRequirements checks if a worker of the model can run the job
+func checkRequirements(h Interface, timestamp int64, execGroups []sdk.Group, jobID int64, requirements []sdk.Requirement, model *sdk.Model, hostname string) bool {
 	if model.Type != h.ModelType() {
 		return false
 	}
@@ -321,7 +369,7 @@ func canRunJob(h Interface, timestamp int64, execGroups []sdk.Group, jobID int64
 		}
 	}
 
-	return h.CanSpawn(model, jobID, requirements)
+	return true
 }
 
 func logTime(h Interface, name string, then time.Time) {
@@ -0,0 +1,327 @@
+package hatchery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	// A reserved worker which did not take its job after this delay can be reserved again
+	poolReservationTTL = 2 * time.Minute
+	// The idle workers are listed again before a reservation if the list is older than this delay
+	poolRefreshDelay = 5 * time.Second
+)
+
+var (
+	registry = prometheus.NewRegistry()
+
+	poolIdleWorkers     = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "hatchery_pool_idle_workers", Help: "Number of idle workers waiting for a job"}, []string{"model"})
+	poolStartingWorkers = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "hatchery_pool_starting_workers", Help: "Number of workers started but not registered yet"}, []string{"model"})
+	poolReservedWorkers = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "hatchery_pool_reserved_workers", Help: "Number of idle workers counted for the jobs booked and not taken yet"}, []string{"model"})
+	poolSize            = prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "hatchery_pool_size", Help: "Number of idle workers the pool is provisioned to"}, []string{"model"})
+	poolJobs            = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "hatchery_pool_jobs", Help: "Number of jobs booked for an idle worker"}, []string{"model"})
+	poolDisabledWorkers = prometheus.NewCounterVec(prometheus.CounterOpts{Name: "hatchery_pool_disabled_workers", Help: "Number of idle workers disabled to shrink the pool"}, []string{"model"})
+)
+
+func init() {
+	registry.MustRegister(poolIdleWorkers)
+	registry.MustRegister(poolStartingWorkers)
+	registry.MustRegister(poolReservedWorkers)
+	registry.MustRegister(poolSize)
+	registry.MustRegister(poolJobs)
+	registry.MustRegister(poolDisabledWorkers)
+}
+
+// GetGatherer returns the gatherer of the metrics of the pool of idle workers
+func GetGatherer() prometheus.Gatherer {
+	return registry
+}
+
+// metricsHandler writes the metrics of the hatchery in the format negotiated with the client
+func metricsHandler(w http.ResponseWriter, r *http.Request) {
+	mfs, err := GetGatherer().Gather()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("An error has occurred during metrics gathering: %s", err), http.StatusInternalServerError)
+		return
+	}
+	contentType := expfmt.Negotiate(r.Header)
+	writer := &bytes.Buffer{}
+	enc := expfmt.NewEncoder(writer, contentType)
+	for _, mf := range mfs {
+		if err := enc.Encode(mf); err != nil {
+			http.Error(w, fmt.Sprintf("An error has occurred during metrics encoding: %s", err), http.StatusInternalServerError)
+			return
+		}
+	}
+	header := w.Header()
+	header.Set("Content-Type", string(contentType))
+	header.Set("Content-Length", fmt.Sprint(writer.Len()))
+	w.Write(writer.Bytes())
+}
+
+// serveMetrics serves the metrics of the hatchery on /mon/metrics until the context is done
+func serveMetrics(ctx context.Context, addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mon/metrics", metricsHandler)
+	srv := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		srv.Close()
+	}()
+
+	log.Info("serveMetrics> Metrics served on %s/mon/metrics", addr)
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Error("serveMetrics> Cannot serve metrics on %s: %s", addr, err)
+	}
+}
+
+// workerPool keeps idle workers waiting for jobs for each worker model. The minimum size
+// of the pool of a worker model is its provision, the maximum size is Provision.Pool.MaxIdle.
+// A job which can be run by an idle worker is booked instead of spawning a new worker, the
+// first idle worker of the model takes it. An idle worker is counted as reserved until then, so
+// that it is not counted for another job. The pool grows each time a job needs a new worker.
+type workerPool struct {
+	mutex       sync.Mutex
+	idle        map[int64][]sdk.Worker // idle workers by model ID, the oldest first
+	registered  map[int64]int          // number of registered workers by model ID
+	size        map[int64]int          // number of idle workers to keep by model ID
+	idleSince   map[string]time.Time   // by worker name
+	reserved    map[string]time.Time   // reservation date by worker name
+	lastRefresh time.Time
+	lastJob     time.Time
+	now         func() time.Time
+}
+
+func newWorkerPool() *workerPool {
+	return &workerPool{
+		idle:       map[int64][]sdk.Worker{},
+		registered: map[int64]int{},
+		size:       map[int64]int{},
+		idleSince:  map[string]time.Time{},
+		reserved:   map[string]time.Time{},
+		now:        time.Now,
+	}
+}
+
+// jobReceived is called for each job received from the queue
+func (p *workerPool) jobReceived() {
+	p.mutex.Lock()
+	p.lastJob = p.now()
+	p.mutex.Unlock()
+}
+
+// refresh loads the workers registered by the hatchery
+func (p *workerPool) refresh(h Interface) error {
+	workers, err := h.Client().WorkerList()
+	if err != nil {
+		return sdk.WrapError(err, "workerPool.refresh> Cannot list workers")
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := p.now()
+	idle := map[int64][]sdk.Worker{}
+	registered := map[int64]int{}
+	idleSince := map[string]time.Time{}
+	for _, w := range workers {
+		if w.HatcheryID != h.Hatchery().ID || w.Status == sdk.StatusDisabled {
+			continue
+		}
+		registered[w.ModelID]++
+		if w.Status != sdk.StatusWaiting {
+			continue
+		}
+		idle[w.ModelID] = append(idle[w.ModelID], w)
+		since, ok := p.idleSince[w.Name]
+		if !ok {
+			since = now
+		}
+		idleSince[w.Name] = since
+	}
+
+	for _, ws := range idle {
+		sort.SliceStable(ws, func(i, j int) bool {
+			return idleSince[ws[i].Name].Before(idleSince[ws[j].Name])
+		})
+	}
+
+	// Forget the reservations of workers which took a job, or which did not take it in time
+	for name, t := range p.reserved {
+		if _, ok := idleSince[name]; !ok || now.Sub(t) > poolReservationTTL {
+			delete(p.reserved, name)
+		}
+	}
+
+	p.idle = idle
+	p.registered = registered
+	p.idleSince = idleSince
+	p.lastRefresh = now
+	return nil
+}
+
+// available returns the idle workers of a model which are not reserved
+func (p *workerPool) available(modelID int64) []sdk.Worker {
+	ws := []sdk.Worker{}
+	for _, w := range p.idle[modelID] {
+		if _, ok := p.reserved[w.Name]; !ok {
+			ws = append(ws, w)
+		}
+	}
+	return ws
+}
+
+// reserve looks for an idle worker able to run a job. canRun checks if a model can run the job
+func (p *workerPool) reserve(h Interface, models []sdk.Model, canRun func(m *sdk.Model) bool) (*sdk.Model, string, bool) {
+	p.mutex.Lock()
+	stale := p.now().Sub(p.lastRefresh) > poolRefreshDelay
+	p.mutex.Unlock()
+
+	if stale {
+		if err := p.refresh(h); err != nil {
+			log.Warning("workerPool.reserve> %s", err)
+			return nil, "", false
+		}
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for i := range models {
+		m := &models[i]
+		ws := p.available(m.ID)
+		if len(ws) == 0 || !canRun(m) {
+			continue
+		}
+		p.reserved[ws[0].Name] = p.now()
+		poolJobs.WithLabelValues(m.Name).Inc()
+		return m, ws[0].Name, true
+	}
+	return nil, "", false
+}
+
+// release cancels the reservation of a worker
+func (p *workerPool) release(workerName string) {
+	p.mutex.Lock()
+	delete(p.reserved, workerName)
+	p.mutex.Unlock()
+}
+
+// maxIdle returns the maximum number of idle workers of a model
+func (p *workerPool) maxIdle(h Interface, m *sdk.Model) int {
+	max := h.Configuration().Provision.Pool.MaxIdle
+	if max < int(m.Provision) {
+		return int(m.Provision)
+	}
+	return max
+}
+
+// poolSize returns the number of idle workers to keep for a model, between its provision and the maximum size of the pool
+func (p *workerPool) poolSize(h Interface, m *sdk.Model) int {
+	size := p.size[m.ID]
+	if size < int(m.Provision) {
+		return int(m.Provision)
+	}
+	if max := p.maxIdle(h, m); size > max {
+		return max
+	}
+	return size
+}
+
+// grow adds one idle worker to the pool of a model, it is called when a job had to wait for a new worker
+func (p *workerPool) grow(h Interface, m *sdk.Model) {
+	p.mutex.Lock()
+	p.size[m.ID] = p.poolSize(h, m) + 1
+	p.mutex.Unlock()
+}
+
+// provision spawns workers to keep the pool of a model at its size
+func (p *workerPool) provision(h Interface, m sdk.Model) {
+	p.mutex.Lock()
+	idle := len(p.available(m.ID))
+	reserved := len(p.idle[m.ID]) - idle
+	registered := p.registered[m.ID]
+	size := p.poolSize(h, &m)
+	p.mutex.Unlock()
+
+	// Workers spawned but not registered yet will join the pool
+	starting := h.WorkersStartedByModel(&m) - registered
+	if starting < 0 {
+		starting = 0
+	}
+
+	poolIdleWorkers.WithLabelValues(m.Name).Set(float64(idle))
+	poolStartingWorkers.WithLabelValues(m.Name).Set(float64(starting))
+	poolReservedWorkers.WithLabelValues(m.Name).Set(float64(reserved))
+	poolSize.WithLabelValues(m.Name).Set(float64(size))
+
+	n := size - idle - starting
+	if free := h.Configuration().Provision.MaxWorker - h.WorkersStarted(); n > free {
+		n = free
+	}
+	if n <= 0 {
+		return
+	}
+
+	log.Info("provisioning> model %s: %d idle, %d starting, spawn %d workers", m.Name, idle, starting, n)
+	for i := 0; i < n; i++ {
+		go func(m sdk.Model) {
+			if name, errSpawn := h.SpawnWorker(&m, 0, nil, false, "spawn for provision"); errSpawn != nil {
+				log.Warning("provisioning> cannot spawn worker %s with model %s for provisioning: %s", name, m.Name, errSpawn)
+				if err := h.Client().WorkerModelSpawnError(m.ID, fmt.Sprintf("routine> cannot spawn worker %s for provisioning: %s", m.Name, errSpawn)); err != nil {
+					log.Error("provisioning> cannot client.WorkerModelSpawnError for worker %s with model %s for provisioning: %s", name, m.Name, errSpawn)
+				}
+			}
+		}(m)
+	}
+}
+
+// shrink disables idle workers of a model when the queue is empty: the workers above the
+// maximum size of the pool, then one worker above the minimum size idle for more than Provision.Pool.IdleTTL.
+// The size of the pool decreases with the disabled workers
+func (p *workerPool) shrink(h Interface, m sdk.Model) {
+	p.mutex.Lock()
+	now := p.now()
+	if now.Sub(p.lastJob) <= time.Duration(h.Configuration().Provision.Frequency)*time.Second {
+		p.mutex.Unlock()
+		return
+	}
+
+	ws := p.available(m.ID)
+	var toDisable []sdk.Worker
+	if max := p.maxIdle(h, &m); len(ws) > max {
+		toDisable = ws[:len(ws)-max]
+	} else if len(ws) > int(m.Provision) && now.Sub(p.idleSince[ws[0].Name]) > time.Duration(h.Configuration().Provision.Pool.IdleTTL)*time.Second {
+		toDisable = ws[:1]
+	}
+
+	if len(toDisable) > 0 {
+		p.size[m.ID] = len(ws) - len(toDisable)
+	}
+	// Disabled workers must not be reserved until the next refresh
+	for _, w := range toDisable {
+		p.reserved[w.Name] = now
+	}
+	p.mutex.Unlock()
+
+	for _, w := range toDisable {
+		log.Info("workerPool.shrink> disable idle worker %s of model %s", w.Name, m.Name)
+		if err := h.Client().WorkerDisable(w.ID); err != nil {
+			log.Warning("workerPool.shrink> cannot disable worker %s: %s", w.Name, err)
+			continue
+		}
+		poolDisabledWorkers.WithLabelValues(m.Name).Inc()
+	}
+}
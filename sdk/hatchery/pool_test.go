@@ -0,0 +1,258 @@
+package hatchery
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/cdsclient"
+)
+
+type fakeClient struct {
+	cdsclient.Interface
+	mutex    sync.Mutex
+	workers  []sdk.Worker
+	disabled []string
+	booked   []int64
+	infos    []sdk.SpawnInfo
+}
+
+func (c *fakeClient) WorkerList() ([]sdk.Worker, error) {
+	return c.workers, nil
+}
+
+func (c *fakeClient) WorkerDisable(id string) error {
+	c.disabled = append(c.disabled, id)
+	return nil
+}
+
+func (c *fakeClient) QueueJobBook(isWorkflowJob bool, id int64) error {
+	c.booked = append(c.booked, id)
+	return nil
+}
+
+func (c *fakeClient) QueueJobSendSpawnInfo(isWorkflowJob bool, id int64, in []sdk.SpawnInfo) error {
+	c.infos = append(c.infos, in...)
+	return nil
+}
+
+type fakeHatchery struct {
+	mutex   sync.Mutex
+	config  CommonConfiguration
+	client  *fakeClient
+	hatch   *sdk.Hatchery
+	started map[int64]int
+	spawned chan int64
+}
+
+func newFakeHatchery() *fakeHatchery {
+	h := &fakeHatchery{
+		client:  &fakeClient{},
+		hatch:   &sdk.Hatchery{ID: 1, Name: "my-hatchery"},
+		started: map[int64]int{},
+		spawned: make(chan int64, 10),
+	}
+	h.config.Provision.Frequency = 30
+	h.config.Provision.MaxWorker = 10
+	h.config.Provision.Pool.IdleTTL = 300
+	return h
+}
+
+func (h *fakeHatchery) Init() error { return nil }
+func (h *fakeHatchery) SpawnWorker(model *sdk.Model, jobID int64, requirements []sdk.Requirement, registerOnly bool, logInfo string) (string, error) {
+	h.spawned <- model.ID
+	return "worker", nil
+}
+func (h *fakeHatchery) CanSpawn(model *sdk.Model, jobID int64, requirements []sdk.Requirement) bool {
+	return true
+}
+func (h *fakeHatchery) WorkersStartedByModel(model *sdk.Model) int {
+	return h.started[model.ID]
+}
+func (h *fakeHatchery) WorkersStarted() int {
+	var n int
+	for _, s := range h.started {
+		n += s
+	}
+	return n
+}
+func (h *fakeHatchery) Hatchery() *sdk.Hatchery                { return h.hatch }
+func (h *fakeHatchery) Client() cdsclient.Interface            { return h.client }
+func (h *fakeHatchery) Configuration() CommonConfiguration     { return h.config }
+func (h *fakeHatchery) ModelType() string                      { return sdk.Docker }
+func (h *fakeHatchery) NeedRegistration(model *sdk.Model) bool { return false }
+func (h *fakeHatchery) ID() int64                              { return h.hatch.ID }
+func (h *fakeHatchery) Serve(ctx context.Context) error        { return nil }
+
+// spawnedWorkers waits for the workers spawned in goroutines
+func spawnedWorkers(h *fakeHatchery, n int) []int64 {
+	ids := []int64{}
+	for i := 0; i < n; i++ {
+		select {
+		case id := <-h.spawned:
+			ids = append(ids, id)
+		case <-time.After(time.Second):
+			return ids
+		}
+	}
+	return ids
+}
+
+func TestWorkerPoolProvision(t *testing.T) {
+	h := newFakeHatchery()
+	models := []sdk.Model{
+		{ID: 1, Name: "go", Type: sdk.Docker, Provision: 3},
+		{ID: 2, Name: "openstack", Type: sdk.Openstack, Provision: 3},
+	}
+
+	// 1 idle worker, 1 building worker, 1 worker of another hatchery and 1 worker starting
+	h.client.workers = []sdk.Worker{
+		{ID: "1", Name: "idle", ModelID: 1, HatcheryID: 1, Status: sdk.StatusWaiting},
+		{ID: "2", Name: "building", ModelID: 1, HatcheryID: 1, Status: sdk.StatusBuilding},
+		{ID: "3", Name: "other", ModelID: 1, HatcheryID: 2, Status: sdk.StatusWaiting},
+	}
+	h.started[1] = 3
+
+	pool := newWorkerPool()
+	provisioning(h, false, pool, models)
+	assert.Equal(t, []int64{1}, spawnedWorkers(h, 2))
+
+	// The pool is full
+	h.started[1] = 4
+	provisioning(h, false, pool, models)
+	assert.Empty(t, spawnedWorkers(h, 1))
+
+	// Do not exceed max workers
+	h.client.workers = nil
+	h.started[1] = 0
+	h.started[3] = 9
+	provisioning(h, false, pool, models)
+	assert.Equal(t, []int64{1}, spawnedWorkers(h, 3))
+
+	provisioning(h, true, pool, models)
+	assert.Empty(t, spawnedWorkers(h, 1))
+}
+
+func TestWorkerPoolBookForIdleWorker(t *testing.T) {
+	h := newFakeHatchery()
+	models := []sdk.Model{
+		{ID: 1, Name: "go", Type: sdk.Docker, Provision: 1},
+		{ID: 2, Name: "node", Type: sdk.Docker, Provision: 1},
+	}
+	h.client.workers = []sdk.Worker{
+		{ID: "1", Name: "node-1", ModelID: 2, HatcheryID: 1, Status: sdk.StatusWaiting},
+	}
+	h.started[2] = 1
+	pool := newWorkerPool()
+
+	// No idle worker of model go: spawn a new one
+	requirements := []sdk.Requirement{{Type: sdk.ModelRequirement, Value: "go"}}
+	isRun, err := routine(h, pool, true, models, nil, 42, requirements, "localhost", 0)
+	assert.NoError(t, err)
+	assert.True(t, isRun)
+	assert.Equal(t, []int64{1}, spawnedWorkers(h, 1))
+	assert.Equal(t, []int64{42}, h.client.booked)
+
+	// The job is booked for the idle worker of model node, and the pool is refilled
+	requirements = []sdk.Requirement{{Type: sdk.ModelRequirement, Value: "node"}}
+	isRun, err = routine(h, pool, true, models, nil, 43, requirements, "localhost", 0)
+	assert.NoError(t, err)
+	assert.True(t, isRun)
+	assert.Equal(t, []int64{42, 43}, h.client.booked)
+	assert.Equal(t, sdk.MsgSpawnInfoHatcheryWaitingWorker.ID, h.client.infos[len(h.client.infos)-1].Message.ID)
+	assert.Equal(t, []int64{2}, spawnedWorkers(h, 1))
+
+	// The idle worker is reserved: a new worker is spawned for the next job
+	isRun, err = routine(h, pool, true, models, nil, 44, requirements, "localhost", 0)
+	assert.NoError(t, err)
+	assert.True(t, isRun)
+	assert.Equal(t, []int64{2}, spawnedWorkers(h, 1))
+	assert.Equal(t, sdk.MsgSpawnInfoHatcheryStartsSuccessfully.ID, h.client.infos[len(h.client.infos)-1].Message.ID)
+}
+
+func TestWorkerPoolShrink(t *testing.T) {
+	h := newFakeHatchery()
+	h.config.Provision.Pool.MaxIdle = 3
+	models := []sdk.Model{{ID: 1, Name: "go", Type: sdk.Docker, Provision: 1}}
+
+	now := time.Now()
+	pool := newWorkerPool()
+	pool.now = func() time.Time { return now }
+
+	h.client.workers = []sdk.Worker{
+		{ID: "1", Name: "w1", ModelID: 1, HatcheryID: 1, Status: sdk.StatusWaiting},
+		{ID: "2", Name: "w2", ModelID: 1, HatcheryID: 1, Status: sdk.StatusWaiting},
+		{ID: "3", Name: "w3", ModelID: 1, HatcheryID: 1, Status: sdk.StatusWaiting},
+		{ID: "4", Name: "w4", ModelID: 1, HatcheryID: 1, Status: sdk.StatusWaiting},
+	}
+	h.started[1] = 4
+
+	// The pool does not shrink while jobs are received
+	pool.jobReceived()
+	provisioning(h, false, pool, models)
+	assert.Empty(t, h.client.disabled)
+
+	// The queue is empty: the worker above the max size is disabled
+	now = now.Add(time.Minute)
+	provisioning(h, false, pool, models)
+	assert.Equal(t, []string{"1"}, h.client.disabled)
+
+	// Then the idle workers above the provision, one by one after the idle TTL
+	h.client.workers = h.client.workers[1:]
+	h.started[1] = 3
+	provisioning(h, false, pool, models)
+	assert.Equal(t, []string{"1"}, h.client.disabled)
+
+	now = now.Add(10 * time.Minute)
+	provisioning(h, false, pool, models)
+	assert.Equal(t, []string{"1", "2"}, h.client.disabled)
+
+	h.client.workers = h.client.workers[1:]
+	h.started[1] = 2
+	provisioning(h, false, pool, models)
+	assert.Equal(t, []string{"1", "2", "3"}, h.client.disabled)
+
+	// The provision of the model is kept
+	h.client.workers = h.client.workers[1:]
+	h.started[1] = 1
+	now = now.Add(time.Hour)
+	provisioning(h, false, pool, models)
+	assert.Equal(t, []string{"1", "2", "3"}, h.client.disabled)
+	assert.Empty(t, spawnedWorkers(h, 1))
+}
+
+func TestWorkerPoolGrow(t *testing.T) {
+	h := newFakeHatchery()
+	h.config.Provision.Pool.MaxIdle = 2
+	models := []sdk.Model{{ID: 1, Name: "go", Type: sdk.Docker, Provision: 0}}
+	requirements := []sdk.Requirement{{Type: sdk.ModelRequirement, Value: "go"}}
+	pool := newWorkerPool()
+
+	// No idle worker: the job waits for a new worker and the pool grows
+	for i := int64(0); i < 3; i++ {
+		isRun, err := routine(h, pool, true, models, nil, 42+i, requirements, "localhost", 0)
+		assert.NoError(t, err)
+		assert.True(t, isRun)
+		assert.Equal(t, []int64{1}, spawnedWorkers(h, 1))
+	}
+	assert.Equal(t, sdk.MsgSpawnInfoHatcheryStartsSuccessfully.ID, h.client.infos[len(h.client.infos)-1].Message.ID)
+
+	// The pool is provisioned up to its maximum size
+	provisioning(h, false, pool, models)
+	assert.Equal(t, []int64{1, 1}, spawnedWorkers(h, 3))
+}
+
+func TestMetricsHandler(t *testing.T) {
+	poolIdleWorkers.WithLabelValues("go").Set(2)
+
+	w := httptest.NewRecorder()
+	metricsHandler(w, httptest.NewRequest("GET", "/mon/metrics", nil))
+	assert.Equal(t, 200, w.Code)
+	assert.True(t, strings.Contains(w.Body.String(), `hatchery_pool_idle_workers{model="go"} 2`))
+}
@@ -67,6 +67,13 @@ func Create(h Interface) {
 	// purges expired items every minute
 	spawnIDs := cache.New(3*time.Second, 60*time.Second)
 
+	// Idle workers waiting for jobs
+	pool := newWorkerPool()
+
+	if addr := h.Configuration().Metrics.Addr; addr != "" {
+		go serveMetrics(ctx, addr)
+	}
+
 	tickerProvision := time.NewTicker(time.Duration(h.Configuration().Provision.Frequency) * time.Second)
 	tickerRegister := time.NewTicker(time.Duration(h.Configuration().Provision.RegisterFrequency) * time.Second)
 	tickerCountWorkersStarted := time.NewTicker(time.Duration(2 * time.Second))
@@ -104,19 +111,21 @@ func Create(h Interface) {
 				log.Error("error on h.Client().WorkerModelsEnabled(): %v", errwm)
 			}
 		case j := <-pbjobs:
+			pool.jobReceived()
 			if workersStarted > int64(h.Configuration().Provision.MaxWorker) {
 				log.Debug("maxWorkersReached:%d", workersStarted)
 				continue
 			}
 			go func(job sdk.PipelineBuildJob) {
 				atomic.AddInt64(&workersStarted, 1)
-				if isRun := receiveJob(h, false, job.ExecGroups, job.ID, job.QueuedSeconds, job.BookedBy, job.Job.Action.Requirements, models, &nRoutines, spawnIDs, hostname); isRun {
+				if isRun := receiveJob(h, pool, false, job.ExecGroups, job.ID, job.QueuedSeconds, job.BookedBy, job.Job.Action.Requirements, models, &nRoutines, spawnIDs, hostname); isRun {
 					spawnIDs.SetDefault(string(job.ID), job.ID)
 				} else {
 					atomic.AddInt64(&workersStarted, -1)
 				}
 			}(j)
 		case j := <-wjobs:
+			pool.jobReceived()
 			if workersStarted > int64(h.Configuration().Provision.MaxWorker) {
 				log.Debug("maxWorkersReached:%d", workersStarted)
 				continue
@@ -125,7 +134,7 @@ func Create(h Interface) {
 				// count + 1 here, and remove -1 if worker is not started
 				// this avoid to spawn to many workers compare
 				atomic.AddInt64(&workersStarted, 1)
-				if isRun := receiveJob(h, true, nil, job.ID, job.QueuedSeconds, job.BookedBy, job.Job.Action.Requirements, models, &nRoutines, spawnIDs, hostname); isRun {
+				if isRun := receiveJob(h, pool, true, nil, job.ID, job.QueuedSeconds, job.BookedBy, job.Job.Action.Requirements, models, &nRoutines, spawnIDs, hostname); isRun {
 					atomic.AddInt64(&workersStarted, 1)
 					spawnIDs.SetDefault(string(job.ID), job.ID)
 				} else {
@@ -135,7 +144,7 @@ func Create(h Interface) {
 		case err := <-errs:
 			log.Error("%v", err)
 		case <-tickerProvision.C:
-			provisioning(h, h.Configuration().Provision.Disabled, models)
+			provisioning(h, h.Configuration().Provision.Disabled, pool, models)
 		case <-tickerRegister.C:
 			if err := workerRegister(h, models); err != nil {
 				log.Warning("Error on workerRegister: %s", err)
@@ -0,0 +1,100 @@
+package hatchery
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ovh/cds/sdk"
+	"github.com/ovh/cds/sdk/log"
+)
+
+const (
+	// DefaultServiceMemory is the memory in MB of a service container without CDS_SERVICE_MEMORY option
+	DefaultServiceMemory = 1024
+	// serviceMemoryOption sets the memory of a service container, in MB
+	serviceMemoryOption = "CDS_SERVICE_MEMORY"
+)
+
+// Service is a container started next to a worker for a service requirement of its job
+type Service struct {
+	// Name is the hostname of the service for the worker
+	Name   string
+	Image  string
+	Env    []string
+	Memory int64
+}
+
+// ParseServiceRequirement computes the service of a requirement. The name of the requirement is the hostname
+// of the service, its value is the image followed by environment variables: "postgres:9.6 POSTGRES_PASSWORD=cds".
+// The memory of the service can be set with the variable CDS_SERVICE_MEMORY=512
+func ParseServiceRequirement(r sdk.Requirement) (Service, error) {
+	s := Service{
+		Name:   r.Name,
+		Memory: DefaultServiceMemory,
+	}
+	if r.Type != sdk.ServiceRequirement {
+		return s, fmt.Errorf("requirement %s is not a service requirement", r.Name)
+	}
+
+	tuple := strings.Fields(r.Value)
+	if r.Name == "" || len(tuple) == 0 {
+		return s, fmt.Errorf("service requirement %s must have a name and an image", r.Name)
+	}
+	s.Image = tuple[0]
+
+	for _, e := range tuple[1:] {
+		kv := strings.SplitN(e, "=", 2)
+		// The hatcheries have always ignored the options without value, keep doing so
+		if len(kv) != 2 {
+			log.Warning("ParseServiceRequirement> Ignoring option %s of service %s, expected KEY=value", e, r.Name)
+			continue
+		}
+		if kv[0] == serviceMemoryOption {
+			m, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil || m <= 0 {
+				return s, fmt.Errorf("invalid memory %s of service %s", kv[1], r.Name)
+			}
+			s.Memory = m
+			continue
+		}
+		s.Env = append(s.Env, e)
+	}
+	return s, nil
+}
+
+// ServicesFromRequirements returns the services of the service requirements of a job
+func ServicesFromRequirements(requirements []sdk.Requirement) ([]Service, error) {
+	var services []Service
+	for _, r := range requirements {
+		if r.Type != sdk.ServiceRequirement {
+			continue
+		}
+		s, err := ParseServiceRequirement(r)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, s)
+	}
+	return services, nil
+}
+
+// ServiceReady returns true if a service container is ready given its docker status and health status,
+// and an error if it will never be. A service is ready when it is running, and healthy if its image has a healthcheck
+func ServiceReady(status, health string) (bool, error) {
+	switch status {
+	case "running":
+	case "exited", "dead":
+		return false, fmt.Errorf("service is %s", status)
+	default:
+		return false, nil
+	}
+
+	switch health {
+	case "", "healthy":
+		return true, nil
+	case "unhealthy":
+		return false, fmt.Errorf("service is unhealthy")
+	}
+	return false, nil
+}
@@ -0,0 +1,60 @@
+package hatchery
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/cds/sdk"
+)
+
+func TestParseServiceRequirement(t *testing.T) {
+	s, err := ParseServiceRequirement(sdk.Requirement{Name: "pg", Type: sdk.ServiceRequirement, Value: "postgres:9.6 POSTGRES_PASSWORD=cds CDS_SERVICE_MEMORY=512"})
+	assert.NoError(t, err)
+	assert.Equal(t, Service{Name: "pg", Image: "postgres:9.6", Env: []string{"POSTGRES_PASSWORD=cds"}, Memory: 512}, s)
+
+	s, err = ParseServiceRequirement(sdk.Requirement{Name: "redis", Type: sdk.ServiceRequirement, Value: "redis"})
+	assert.NoError(t, err)
+	assert.Equal(t, Service{Name: "redis", Image: "redis", Memory: DefaultServiceMemory}, s)
+
+	_, err = ParseServiceRequirement(sdk.Requirement{Name: "pg", Type: sdk.ServiceRequirement, Value: ""})
+	assert.Error(t, err)
+	_, err = ParseServiceRequirement(sdk.Requirement{Name: "pg", Type: sdk.ServiceRequirement, Value: "postgres CDS_SERVICE_MEMORY=lots"})
+	assert.Error(t, err)
+
+	// Options without value are ignored
+	s, err = ParseServiceRequirement(sdk.Requirement{Name: "pg", Type: sdk.ServiceRequirement, Value: "postgres debug POSTGRES_USER=cds"})
+	assert.NoError(t, err)
+	assert.Equal(t, Service{Name: "pg", Image: "postgres", Env: []string{"POSTGRES_USER=cds"}, Memory: DefaultServiceMemory}, s)
+
+	services, err := ServicesFromRequirements([]sdk.Requirement{
+		{Name: "go", Type: sdk.ModelRequirement, Value: "golang"},
+		{Name: "pg", Type: sdk.ServiceRequirement, Value: "postgres"},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, services, 1)
+}
+
+func TestServiceReady(t *testing.T) {
+	ready, err := ServiceReady("created", "")
+	assert.False(t, ready)
+	assert.NoError(t, err)
+
+	// Without healthcheck, a running service is ready
+	ready, err = ServiceReady("running", "")
+	assert.True(t, ready)
+	assert.NoError(t, err)
+
+	ready, err = ServiceReady("running", "starting")
+	assert.False(t, ready)
+	assert.NoError(t, err)
+
+	ready, err = ServiceReady("running", "healthy")
+	assert.True(t, ready)
+	assert.NoError(t, err)
+
+	_, err = ServiceReady("running", "unhealthy")
+	assert.Error(t, err)
+	_, err = ServiceReady("exited", "")
+	assert.Error(t, err)
+}
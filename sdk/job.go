@@ -1,11 +1,127 @@
 package sdk
 
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	// MaxRetryDelay is the maximum delay between two attempts of a job or a step
+	MaxRetryDelay = time.Hour
+	// MaxJobMatrixSize is the maximum number of job runs a job matrix can be expanded into
+	MaxJobMatrixSize = 64
+	// JobMatrixVariablePrefix prefixes the names of the parameters injected in the job runs of a matrix
+	JobMatrixVariablePrefix = "cds.matrix."
+)
+
 // Job is the element of a stage
 type Job struct {
 	PipelineActionID int64                  `json:"pipeline_action_id"`
 	PipelineStageID  int64                  `json:"pipeline_stage_id"`
 	Enabled          bool                   `json:"enabled"`
+	Retry            int                    `json:"retry,omitempty"`
+	RetryDelay       int                    `json:"retry_delay,omitempty"`
+	Timeout          int                    `json:"timeout,omitempty"`
+	Matrix           JobMatrix              `json:"matrix,omitempty"`
 	LastModified     int64                  `json:"last_modified"`
 	Action           Action                 `json:"action"`
 	Warnings         []PipelineBuildWarning `json:"warnings"`
 }
+
+// RetryDelay returns the delay before the given retry of a job or a step. The delay
+// in seconds doubles at each retry: delay, 2*delay, 4*delay... up to MaxRetryDelay
+func RetryDelay(delay, retry int) time.Duration {
+	if delay <= 0 {
+		return 0
+	}
+	d := time.Duration(delay) * time.Second
+	for i := 1; i < retry && d < MaxRetryDelay; i++ {
+		d *= 2
+	}
+	if d > MaxRetryDelay {
+		return MaxRetryDelay
+	}
+	return d
+}
+
+// JobMatrix defines variables and their values. A job with a matrix is expanded into
+// one job run for each combination of the values
+type JobMatrix map[string][]string
+
+// Size returns the number of combinations of the matrix, 0 if the matrix is empty
+func (m JobMatrix) Size() int {
+	if len(m) == 0 {
+		return 0
+	}
+	size := 1
+	for _, values := range m {
+		size *= len(values)
+	}
+	return size
+}
+
+// Check returns an error if a variable of the matrix has no value or if the matrix has too many combinations
+func (m JobMatrix) Check() error {
+	for name, values := range m {
+		if name == "" {
+			return fmt.Errorf("matrix variable names must not be empty")
+		}
+		if len(values) == 0 {
+			return fmt.Errorf("matrix variable %s has no value", name)
+		}
+	}
+	if m.Size() > MaxJobMatrixSize {
+		return fmt.Errorf("matrix has %d combinations, the maximum is %d", m.Size(), MaxJobMatrixSize)
+	}
+	return nil
+}
+
+// Combinations returns all the combinations of the values of the matrix. The combinations are
+// sorted by variable name, then in the order of the values
+func (m JobMatrix) Combinations() []map[string]string {
+	if m.Size() == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	res := []map[string]string{{}}
+	for _, name := range names {
+		next := make([]map[string]string, 0, len(res)*len(m[name]))
+		for _, c := range res {
+			for _, value := range m[name] {
+				combination := make(map[string]string, len(c)+1)
+				for k, v := range c {
+					combination[k] = v
+				}
+				combination[name] = value
+				next = append(next, combination)
+			}
+		}
+		res = next
+	}
+	return res
+}
+
+// JobMatrixCombinationString returns a readable and stable representation of a combination of a matrix: go=1.9, os=linux
+func JobMatrixCombinationString(combination map[string]string) string {
+	names := make([]string, 0, len(combination))
+	for name := range combination {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var s string
+	for i, name := range names {
+		if i > 0 {
+			s += ", "
+		}
+		s += name + "=" + combination[name]
+	}
+	return s
+}
@@ -0,0 +1,39 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, time.Duration(0), RetryDelay(0, 1))
+	assert.Equal(t, 10*time.Second, RetryDelay(10, 1))
+	assert.Equal(t, 20*time.Second, RetryDelay(10, 2))
+	assert.Equal(t, 40*time.Second, RetryDelay(10, 3))
+	assert.Equal(t, MaxRetryDelay, RetryDelay(1800, 3))
+	assert.Equal(t, MaxRetryDelay, RetryDelay(10, 100))
+}
+
+func TestJobMatrix(t *testing.T) {
+	var m JobMatrix
+	assert.Equal(t, 0, m.Size())
+	assert.Nil(t, m.Combinations())
+	assert.NoError(t, m.Check())
+
+	m = JobMatrix{"os": {"linux", "windows"}, "go": {"1.8", "1.9", "1.10"}}
+	assert.Equal(t, 6, m.Size())
+	assert.NoError(t, m.Check())
+	c := m.Combinations()
+	assert.Len(t, c, 6)
+	assert.Equal(t, "go=1.8, os=linux", JobMatrixCombinationString(c[0]))
+	assert.Equal(t, "go=1.8, os=windows", JobMatrixCombinationString(c[1]))
+	assert.Equal(t, "go=1.10, os=windows", JobMatrixCombinationString(c[5]))
+
+	m["arch"] = []string{}
+	assert.Error(t, m.Check())
+
+	m = JobMatrix{"a": make([]string, 8), "b": make([]string, 9)}
+	assert.Error(t, m.Check())
+}
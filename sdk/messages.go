@@ -58,11 +58,21 @@ var (
 	MsgSpawnInfoHatcheryStarts             = &Message{"MsgSpawnInfoHatcheryStarts", trad{FR: "La Hatchery %s (%s) a démarré le lancement du worker avec le model %s", EN: "Hatchery %s (%s) starts spawn worker with model %s"}, nil}
 	MsgSpawnInfoHatcheryErrorSpawn         = &Message{"MsgSpawnInfoHatcheryErrorSpawn", trad{FR: "Une erreur est survenue lorsque la Hatchery %s (%s) a démarré un worker avec le model %s après %s, err:%s", EN: "Error while Hatchery %s (%s) spawn worker with model %s after %s, err:%s"}, nil}
 	MsgSpawnInfoHatcheryStartsSuccessfully = &Message{"MsgSpawnInfoHatcheryStartsSuccessfully", trad{FR: "La Hatchery %s (%s) a démarré le worker %s avec succès en %s", EN: "Hatchery %s (%s) spawn worker %s successfully in %s"}, nil}
+	MsgSpawnInfoHatcheryWaitingWorker      = &Message{"MsgSpawnInfoHatcheryWaitingWorker", trad{FR: "La Hatchery %s (%s) a réservé ce job pour les workers en attente du modèle %s", EN: "Hatchery %s (%s) booked this job for the waiting workers of model %s"}, nil}
 	MsgSpawnInfoWorkerEnd                  = &Message{"MsgSpawnInfoWorkerEnd", trad{FR: "Le worker %s a terminé et a passé %s à travailler sur les étapes", EN: "Worker %s finished working on this job and took %s to work on the steps"}, nil}
 	MsgSpawnInfoJobTaken                   = &Message{"MsgSpawnInfoJobTaken", trad{FR: "Le job a été pris par le worker %s", EN: "Job was taken by worker %s"}, nil}
 	MsgSpawnInfoWorkerForJob               = &Message{"MsgSpawnInfoWorkerForJob", trad{FR: "Ce worker %s a été créé pour lancer ce job", EN: "This worker %s was created to take this action"}, nil}
 	MsgSpawnInfoWorkerForJobError          = &Message{"MsgSpawnInfoWorkerForJobError", trad{FR: "Ce worker %s a été créé pour lancer ce job, mais ne possède pas tous les pré-requis. Vérifiez que les prérequis suivants:%s", EN: "This worker %s was created to take this action, but does not have all prerequisites. Please verify the following prerequisites:%s"}, nil}
+	MsgSpawnInfoJobWaitingQuota            = &Message{"MsgSpawnInfoJobWaitingQuota", trad{FR: "Le job attend le quota (%s %s): %s/%s jobs simultanés", EN: "Job is waiting for quota of %s %s: %s/%s concurrent jobs"}, nil}
 	MsgSpawnInfoJobError                   = &Message{"MsgSpawnInfoJobError", trad{FR: "Impossible de lancer ce job : %s", EN: "Unable to run this job: %s"}, nil}
+	MsgSpawnInfoJobRetry                   = &Message{"MsgSpawnInfoJobRetry", trad{FR: "Le job a échoué sur le worker %s, la tentative %s/%s démarrera dans %s", EN: "Job failed on worker %s, attempt %s/%s will start in %s"}, nil}
+	MsgSpawnInfoJobRequeueWorkerLost       = &Message{"MsgSpawnInfoJobRequeueWorkerLost", trad{FR: "Le worker %s a disparu pendant le job, la tentative %s/%s démarrera dans %s", EN: "Worker %s vanished while building the job, attempt %s/%s will start in %s"}, nil}
+	MsgSpawnInfoJobRequeueSpawnError       = &Message{"MsgSpawnInfoJobRequeueSpawnError", trad{FR: "Aucun worker n'a pu être démarré pour le job (%s/%s), il sera réservé à nouveau dans %s", EN: "No worker could be spawned for the job (%s/%s), it will be booked again in %s"}, nil}
+	MsgSpawnInfoJobSpawnError              = &Message{"MsgSpawnInfoJobSpawnError", trad{FR: "Aucun worker n'a pu être démarré pour le job après %s tentatives", EN: "No worker could be spawned for the job after %s attempts"}, nil}
+	MsgSpawnInfoJobWorkerLost              = &Message{"MsgSpawnInfoJobWorkerLost", trad{FR: "Le worker %s a disparu pendant le job, aucune tentative restante", EN: "Worker %s vanished while building the job, no attempt left"}, nil}
+	MsgSpawnInfoJobTimeout                 = &Message{"MsgSpawnInfoJobTimeout", trad{FR: "Le job a dépassé son timeout de %s et a été arrêté", EN: "Job exceeded its timeout of %s and has been stopped"}, nil}
+	MsgSpawnInfoJobMatrix                  = &Message{"MsgSpawnInfoJobMatrix", trad{FR: "Valeurs de la matrice : %s", EN: "Matrix values: %s"}, nil}
+	MsgSpawnInfoStepRetry                  = &Message{"MsgSpawnInfoStepRetry", trad{FR: "L'étape %s a échoué sur le worker %s, nouvelle tentative %s/%s dans %s", EN: "Step %s failed on worker %s, retry %s/%s in %s"}, nil}
 	MsgWorkflowStarting                    = &Message{"MsgWorkflowStarting", trad{FR: "Le workflow %s#%s a été démarré", EN: "Workflow %s#%s has been started"}, nil}
 	MsgWorkflowError                       = &Message{"MsgWorkflowError", trad{FR: "Une erreur est survenue: %v", EN: "An error has occured: %v"}, nil}
 	MsgWorkflowNodeStop                    = &Message{"MsgWorkflowNodeStop", trad{FR: "Le pipeline a été arrété par %s", EN: "The pipeline has been stopped by %s"}, nil}
@@ -109,11 +119,21 @@ var Messages = map[string]*Message{
 	MsgSpawnInfoHatcheryStarts.ID:             MsgSpawnInfoHatcheryStarts,
 	MsgSpawnInfoHatcheryErrorSpawn.ID:         MsgSpawnInfoHatcheryErrorSpawn,
 	MsgSpawnInfoHatcheryStartsSuccessfully.ID: MsgSpawnInfoHatcheryStartsSuccessfully,
+	MsgSpawnInfoHatcheryWaitingWorker.ID:      MsgSpawnInfoHatcheryWaitingWorker,
 	MsgSpawnInfoWorkerEnd.ID:                  MsgSpawnInfoWorkerEnd,
 	MsgSpawnInfoJobTaken.ID:                   MsgSpawnInfoJobTaken,
 	MsgSpawnInfoWorkerForJob.ID:               MsgSpawnInfoWorkerForJob,
 	MsgSpawnInfoWorkerForJobError.ID:          MsgSpawnInfoWorkerForJobError,
+	MsgSpawnInfoJobWaitingQuota.ID:            MsgSpawnInfoJobWaitingQuota,
 	MsgSpawnInfoJobError.ID:                   MsgSpawnInfoJobError,
+	MsgSpawnInfoJobRetry.ID:                   MsgSpawnInfoJobRetry,
+	MsgSpawnInfoJobRequeueWorkerLost.ID:       MsgSpawnInfoJobRequeueWorkerLost,
+	MsgSpawnInfoJobRequeueSpawnError.ID:       MsgSpawnInfoJobRequeueSpawnError,
+	MsgSpawnInfoJobSpawnError.ID:              MsgSpawnInfoJobSpawnError,
+	MsgSpawnInfoJobWorkerLost.ID:              MsgSpawnInfoJobWorkerLost,
+	MsgSpawnInfoJobTimeout.ID:                 MsgSpawnInfoJobTimeout,
+	MsgSpawnInfoJobMatrix.ID:                  MsgSpawnInfoJobMatrix,
+	MsgSpawnInfoStepRetry.ID:                  MsgSpawnInfoStepRetry,
 	MsgWorkflowStarting.ID:                    MsgWorkflowStarting,
 	MsgWorkflowError.ID:                       MsgWorkflowError,
 	MsgWorkflowNodeStop.ID:                    MsgWorkflowNodeStop,
@@ -7,18 +7,23 @@ type UserNotificationSettingsType string
 
 //const
 const (
-	EmailUserNotification  UserNotificationSettingsType = "email"
-	JabberUserNotification UserNotificationSettingsType = "jabber"
+	EmailUserNotification      UserNotificationSettingsType = "email"
+	JabberUserNotification     UserNotificationSettingsType = "jabber"
+	SlackUserNotification      UserNotificationSettingsType = "slack"
+	MattermostUserNotification UserNotificationSettingsType = "mattermost"
+	TeamsUserNotification      UserNotificationSettingsType = "teams"
+	WebhookUserNotification    UserNotificationSettingsType = "webhook"
 )
 
-//UserNotificationEventType always/never/change
+//UserNotificationEventType always/never/change/failure
 type UserNotificationEventType string
 
 //const
 const (
-	UserNotificationAlways UserNotificationEventType = "always"
-	UserNotificationNever  UserNotificationEventType = "never"
-	UserNotificationChange UserNotificationEventType = "change"
+	UserNotificationAlways  UserNotificationEventType = "always"
+	UserNotificationNever   UserNotificationEventType = "never"
+	UserNotificationChange  UserNotificationEventType = "change"
+	UserNotificationFailure UserNotificationEventType = "failure"
 )
 
 // UserNotification is a settings on application_pipeline/env
@@ -28,6 +28,7 @@ type Pipeline struct {
 	Usage             *Usage            `json:"usage,omitempty"`
 	Permission        int               `json:"permission"`
 	LastModified      int64             `json:"last_modified" cli:"modified"`
+	Priority          int64             `json:"priority,omitempty" cli:"priority"`
 }
 
 // PipelineAudit represents pipeline audit
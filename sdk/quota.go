@@ -0,0 +1,40 @@
+package sdk
+
+// Scopes of quotas
+const (
+	QuotaScopeGroup   = "group"
+	QuotaScopeProject = "project"
+	QuotaScopeModel   = "model"
+)
+
+// QuotaScopes is the list of the scopes of quotas
+var QuotaScopes = []string{QuotaScopeGroup, QuotaScopeProject, QuotaScopeModel}
+
+// Quota is the maximum number of concurrent jobs of a group, a project or a worker model.
+// The jobs of a group are the jobs of the projects in which the group has the read-write-execute permission.
+type Quota struct {
+	ID       int64  `json:"id" db:"id" cli:"-"`
+	Scope    string `json:"scope" db:"scope" cli:"scope"`
+	TargetID int64  `json:"target_id" db:"target_id" cli:"-"`
+	Name     string `json:"name" db:"-" cli:"name"`
+	MaxJobs  int64  `json:"max_jobs" db:"max_jobs" cli:"max_jobs"`
+	Current  int64  `json:"current" db:"-" cli:"current"`
+}
+
+// IsValid checks the scope and the maximum number of jobs of the quota
+func (q Quota) IsValid() bool {
+	if q.MaxJobs < 0 {
+		return false
+	}
+	for _, s := range QuotaScopes {
+		if s == q.Scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Exceeded returns true if no more job can be started
+func (q Quota) Exceeded() bool {
+	return q.Current >= q.MaxJobs
+}
@@ -0,0 +1,20 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaIsValid(t *testing.T) {
+	assert.True(t, Quota{Scope: QuotaScopeGroup, MaxJobs: 10}.IsValid())
+	assert.True(t, Quota{Scope: QuotaScopeModel, MaxJobs: 0}.IsValid())
+	assert.False(t, Quota{Scope: QuotaScopeProject, MaxJobs: -1}.IsValid())
+	assert.False(t, Quota{Scope: "pipeline", MaxJobs: 1}.IsValid())
+}
+
+func TestQuotaExceeded(t *testing.T) {
+	assert.False(t, Quota{MaxJobs: 2, Current: 1}.Exceeded())
+	assert.True(t, Quota{MaxJobs: 2, Current: 2}.Exceeded())
+	assert.True(t, Quota{MaxJobs: 0}.Exceeded())
+}
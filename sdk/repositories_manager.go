@@ -369,8 +369,15 @@ type VCSBranch struct {
 	Parents      []string `json:"parents"`
 }
 
+//VCSTag represents a tag and the commit it points to
+type VCSTag struct {
+	Tag  string `json:"tag"`
+	Hash string `json:"hash"`
+}
+
 //VCSPullRequest represents a pull request
 type VCSPullRequest struct {
+	ID     int          `json:"id"`
 	URL    string       `json:"url"`
 	User   VCSAuthor    `json:"user"`
 	Head   VCSPushEvent `json:"head"`
@@ -378,6 +385,13 @@ type VCSPullRequest struct {
 	Branch VCSBranch    `json:"branch"`
 }
 
+//VCSPullRequestComment represents a comment on a pull request.
+//If Marker is set, the comment of the pull request containing it is updated instead of adding a new comment
+type VCSPullRequestComment struct {
+	Message string `json:"message"`
+	Marker  string `json:"marker,omitempty"`
+}
+
 //VCSPushEvent represents a push events for polling
 type VCSPushEvent struct {
 	Repo     string    `json:"repo"`
@@ -87,11 +87,25 @@ func init() {
 		tr := &http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 		}
-		client = &http.Client{Transport: tr}
+		client = &http.Client{Transport: tr, CheckRedirect: CheckRedirect}
 		return
 	}
 
-	client = http.DefaultClient
+	client = &http.Client{CheckRedirect: CheckRedirect}
+}
+
+// CheckRedirect is the redirect policy of CDS http clients. Redirects are followed, for instance
+// to download artifacts from signed urls, but CDS authentication headers are never sent to another host
+func CheckRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return fmt.Errorf("stopped after 10 redirects")
+	}
+	if req.URL.Host != via[0].URL.Host {
+		req.Header.Del(AuthHeader)
+		req.Header.Del(SessionTokenHeader)
+		req.Header.Del("Authorization")
+	}
+	return nil
 }
 
 func initRequest(req *http.Request) {
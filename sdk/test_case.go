@@ -0,0 +1,202 @@
+package sdk
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ovh/venom"
+)
+
+// WorkflowNodeRunTestCase is the result of a test case in a workflow node run
+type WorkflowNodeRunTestCase struct {
+	ID                int64     `json:"id" db:"id"`
+	WorkflowID        int64     `json:"workflow_id" db:"workflow_id"`
+	WorkflowRunID     int64     `json:"workflow_run_id" db:"workflow_run_id"`
+	WorkflowNodeRunID int64     `json:"workflow_node_run_id" db:"workflow_node_run_id"`
+	Number            int64     `json:"num" db:"num" cli:"num"`
+	SubNumber         int64     `json:"subnumber" db:"sub_num" cli:"subnumber"`
+	NodeName          string    `json:"node_name" db:"node_name" cli:"node"`
+	TestSuite         string    `json:"testsuite" db:"testsuite" cli:"testsuite"`
+	Name              string    `json:"name" db:"name" cli:"name"`
+	Status            string    `json:"status" db:"status" cli:"status"`
+	Duration          float64   `json:"duration" db:"duration" cli:"duration"`
+	Branch            string    `json:"branch" db:"branch" cli:"branch"`
+	Hash              string    `json:"hash" db:"hash" cli:"hash"`
+	Created           time.Time `json:"created" db:"created" cli:"created"`
+}
+
+// Key identifies a test case in the runs of a workflow
+func (t WorkflowNodeRunTestCase) Key() string {
+	return t.NodeName + "/" + t.TestSuite + "/" + t.Name
+}
+
+// NewWorkflowNodeRunTestCases returns the results of all the test cases of the test suites.
+// The name of a test case is prefixed by its class name, if any.
+func NewWorkflowNodeRunTestCases(tests venom.Tests) []WorkflowNodeRunTestCase {
+	res := []WorkflowNodeRunTestCase{}
+	for _, ts := range tests.TestSuites {
+		for _, tc := range ts.TestCases {
+			t := WorkflowNodeRunTestCase{
+				TestSuite: ts.Name,
+				Name:      tc.Name,
+				Status:    StatusSuccess.String(),
+			}
+			if tc.Classname != "" {
+				t.Name = tc.Classname + "." + tc.Name
+			}
+			switch {
+			case len(tc.Failures) > 0 || len(tc.Errors) > 0:
+				t.Status = StatusFail.String()
+			case len(tc.Skipped) > 0:
+				t.Status = StatusSkipped.String()
+			}
+			t.Duration, _ = strconv.ParseFloat(tc.Time, 64)
+			res = append(res, t)
+		}
+	}
+	return res
+}
+
+// TestCaseHistory is the history of a test case across the runs of a workflow, the most recent run first
+type TestCaseHistory struct {
+	NodeName  string                    `json:"node_name" cli:"node"`
+	TestSuite string                    `json:"testsuite" cli:"testsuite"`
+	Name      string                    `json:"name" cli:"name"`
+	Runs      []WorkflowNodeRunTestCase `json:"runs" cli:"-"`
+	Total     int                       `json:"total" cli:"total"`
+	Failures  int                       `json:"failures" cli:"failures"`
+	// Flaky is true if the test case succeeded and failed on the same commit
+	Flaky       bool     `json:"flaky" cli:"flaky"`
+	FlakyHashes []string `json:"flaky_hashes,omitempty" cli:"-"`
+	// AverageDuration is the average duration of the successful runs, in seconds
+	AverageDuration float64 `json:"average_duration" cli:"average_duration"`
+	// DurationTrend is the variation in percent of the average duration of the recent half of the successful runs compared to the older half
+	DurationTrend float64 `json:"duration_trend" cli:"duration_trend"`
+}
+
+// NewTestCaseHistory computes the statistics of the runs of a test case, sorted from the most recent one
+func NewTestCaseHistory(nodeName, testSuite, name string, runs []WorkflowNodeRunTestCase) TestCaseHistory {
+	h := TestCaseHistory{
+		NodeName:  nodeName,
+		TestSuite: testSuite,
+		Name:      name,
+		Runs:      runs,
+		Total:     len(runs),
+	}
+
+	statusByHash := map[string]map[string]bool{}
+	durations := []float64{}
+	for _, r := range runs {
+		switch r.Status {
+		case StatusFail.String():
+			h.Failures++
+		case StatusSuccess.String():
+			durations = append(durations, r.Duration)
+		default:
+			continue
+		}
+		if r.Hash == "" {
+			continue
+		}
+		if statusByHash[r.Hash] == nil {
+			statusByHash[r.Hash] = map[string]bool{}
+		}
+		statusByHash[r.Hash][r.Status] = true
+	}
+
+	for hash, status := range statusByHash {
+		if status[StatusSuccess.String()] && status[StatusFail.String()] {
+			h.Flaky = true
+			h.FlakyHashes = append(h.FlakyHashes, hash)
+		}
+	}
+	sort.Strings(h.FlakyHashes)
+
+	h.AverageDuration = average(durations)
+	if len(durations) >= 2 {
+		// durations are sorted from the most recent run
+		recent, older := average(durations[:len(durations)/2]), average(durations[len(durations)/2:])
+		if older > 0 {
+			h.DurationTrend = (recent - older) / older * 100
+		}
+	}
+	return h
+}
+
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// FlakyTestCase is a test case which succeeded and failed on the same commits
+type FlakyTestCase struct {
+	NodeName    string    `json:"node_name" db:"node_name" cli:"node"`
+	TestSuite   string    `json:"testsuite" db:"testsuite" cli:"testsuite"`
+	Name        string    `json:"name" db:"name" cli:"name"`
+	FlakyHashes int64     `json:"flaky_hashes" db:"flaky_hashes" cli:"flaky_commits"`
+	LastSeen    time.Time `json:"last_seen" db:"last_seen" cli:"last_seen"`
+}
+
+// TestsComparison compares the test cases of a workflow run with the test cases of the previous run on the same branch
+type TestsComparison struct {
+	Number         int64                     `json:"num"`
+	PreviousNumber int64                     `json:"previous_num"`
+	Branch         string                    `json:"branch"`
+	NewFailures    []WorkflowNodeRunTestCase `json:"new_failures"`
+	StillFailing   []WorkflowNodeRunTestCase `json:"still_failing"`
+	Fixed          []WorkflowNodeRunTestCase `json:"fixed"`
+	NewTests       []WorkflowNodeRunTestCase `json:"new_tests"`
+}
+
+// latestTestCases keeps the result of the last sub number of each test case
+func latestTestCases(tests []WorkflowNodeRunTestCase) map[string]WorkflowNodeRunTestCase {
+	res := make(map[string]WorkflowNodeRunTestCase, len(tests))
+	for _, t := range tests {
+		if r, ok := res[t.Key()]; !ok || t.SubNumber > r.SubNumber {
+			res[t.Key()] = t
+		}
+	}
+	return res
+}
+
+// CompareTestCases returns the new failures, the test cases still failing, the fixed and the new test cases of a run compared to the previous one
+func CompareTestCases(current, previous []WorkflowNodeRunTestCase) TestsComparison {
+	c := TestsComparison{
+		NewFailures:  []WorkflowNodeRunTestCase{},
+		StillFailing: []WorkflowNodeRunTestCase{},
+		Fixed:        []WorkflowNodeRunTestCase{},
+		NewTests:     []WorkflowNodeRunTestCase{},
+	}
+
+	prev := latestTestCases(previous)
+	cur := latestTestCases(current)
+	keys := make([]string, 0, len(cur))
+	for k := range cur {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		t := cur[k]
+		p, ok := prev[k]
+		if !ok {
+			c.NewTests = append(c.NewTests, t)
+		}
+		switch {
+		case t.Status == StatusFail.String() && ok && p.Status == StatusFail.String():
+			c.StillFailing = append(c.StillFailing, t)
+		case t.Status == StatusFail.String():
+			c.NewFailures = append(c.NewFailures, t)
+		case t.Status == StatusSuccess.String() && ok && p.Status == StatusFail.String():
+			c.Fixed = append(c.Fixed, t)
+		}
+	}
+	return c
+}
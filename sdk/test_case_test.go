@@ -0,0 +1,98 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/ovh/venom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWorkflowNodeRunTestCases(t *testing.T) {
+	tests := venom.Tests{TestSuites: []venom.TestSuite{
+		{
+			Name: "api",
+			TestCases: []venom.TestCase{
+				{Classname: "github.com/ovh/cds/engine/api", Name: "TestOK", Time: "1.5"},
+				{Name: "TestFailure", Failures: []venom.Failure{{Value: "boom"}}},
+				{Name: "TestError", Errors: []venom.Failure{{Value: "panic"}}},
+				{Name: "TestSkipped", Skipped: []venom.Skipped{{Value: "short"}}},
+			},
+		},
+	}}
+
+	res := NewWorkflowNodeRunTestCases(tests)
+	assert.Len(t, res, 4)
+	assert.Equal(t, "api", res[0].TestSuite)
+	assert.Equal(t, "github.com/ovh/cds/engine/api.TestOK", res[0].Name)
+	assert.Equal(t, StatusSuccess.String(), res[0].Status)
+	assert.Equal(t, 1.5, res[0].Duration)
+	assert.Equal(t, StatusFail.String(), res[1].Status)
+	assert.Equal(t, StatusFail.String(), res[2].Status)
+	assert.Equal(t, StatusSkipped.String(), res[3].Status)
+}
+
+func TestNewTestCaseHistory(t *testing.T) {
+	// Most recent first
+	runs := []WorkflowNodeRunTestCase{
+		{Number: 5, Status: StatusSuccess.String(), Duration: 3, Hash: "ccc"},
+		{Number: 4, Status: StatusSuccess.String(), Duration: 3, Hash: "bbb"},
+		{Number: 3, Status: StatusFail.String(), Duration: 9, Hash: "bbb"},
+		{Number: 2, Status: StatusSuccess.String(), Duration: 1, Hash: "aaa"},
+		{Number: 1, Status: StatusSuccess.String(), Duration: 1, Hash: "aaa"},
+		{Number: 1, SubNumber: 1, Status: StatusSkipped.String(), Hash: "aaa"},
+	}
+
+	h := NewTestCaseHistory("build", "api", "TestOK", runs)
+	assert.Equal(t, 6, h.Total)
+	assert.Equal(t, 1, h.Failures)
+	assert.True(t, h.Flaky)
+	assert.Equal(t, []string{"bbb"}, h.FlakyHashes)
+	assert.Equal(t, 2.0, h.AverageDuration)
+	// 3s on the 2 recent successful runs, 1s on the 2 older ones
+	assert.Equal(t, 200.0, h.DurationTrend)
+
+	h = NewTestCaseHistory("build", "api", "TestOK", runs[:2])
+	assert.False(t, h.Flaky)
+	assert.Equal(t, 0.0, h.DurationTrend)
+
+	h = NewTestCaseHistory("build", "api", "TestOK", nil)
+	assert.Equal(t, 0, h.Total)
+	assert.Equal(t, 0.0, h.AverageDuration)
+}
+
+func TestCompareTestCases(t *testing.T) {
+	previous := []WorkflowNodeRunTestCase{
+		{NodeName: "build", TestSuite: "api", Name: "TestStillOK", Status: StatusSuccess.String()},
+		{NodeName: "build", TestSuite: "api", Name: "TestBroken", Status: StatusSuccess.String()},
+		{NodeName: "build", TestSuite: "api", Name: "TestStillKO", Status: StatusFail.String()},
+		{NodeName: "build", TestSuite: "api", Name: "TestFixed", Status: StatusFail.String()},
+		{NodeName: "build", TestSuite: "api", Name: "TestRemoved", Status: StatusFail.String()},
+	}
+	current := []WorkflowNodeRunTestCase{
+		{NodeName: "build", TestSuite: "api", Name: "TestStillOK", Status: StatusSuccess.String()},
+		{NodeName: "build", TestSuite: "api", Name: "TestBroken", Status: StatusFail.String()},
+		{NodeName: "build", TestSuite: "api", Name: "TestStillKO", Status: StatusFail.String()},
+		{NodeName: "build", TestSuite: "api", Name: "TestFixed", Status: StatusFail.String()},
+		{NodeName: "build", TestSuite: "api", Name: "TestFixed", SubNumber: 1, Status: StatusSuccess.String()},
+		{NodeName: "build", TestSuite: "api", Name: "TestNewKO", Status: StatusFail.String()},
+		{NodeName: "build", TestSuite: "api", Name: "TestNewOK", Status: StatusSuccess.String()},
+	}
+
+	names := func(tests []WorkflowNodeRunTestCase) []string {
+		res := []string{}
+		for _, t := range tests {
+			res = append(res, t.Name)
+		}
+		return res
+	}
+
+	c := CompareTestCases(current, previous)
+	assert.Equal(t, []string{"TestBroken", "TestNewKO"}, names(c.NewFailures))
+	assert.Equal(t, []string{"TestStillKO"}, names(c.StillFailing))
+	assert.Equal(t, []string{"TestFixed"}, names(c.Fixed))
+	assert.Equal(t, []string{"TestNewKO", "TestNewOK"}, names(c.NewTests))
+
+	c = CompareTestCases(current, nil)
+	assert.Len(t, c.NewTests, 6)
+	assert.Empty(t, c.Fixed)
+}
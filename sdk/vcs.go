@@ -5,6 +5,12 @@ import (
 	"time"
 )
 
+// HTTP Headers used by the VCS µservice to get the tokens of the authorized client
+const (
+	HeaderXAccessToken       = "X-CDS-ACCESS-TOKEN"
+	HeaderXAccessTokenSecret = "X-CDS-ACCESS-TOKEN-SECRET"
+)
+
 type VCSServer interface {
 	AuthorizeRedirect() (string, string, error)
 	AuthorizeToken(string, string) (string, string, error)
@@ -24,8 +30,12 @@ type VCSAuthorizedClient interface {
 	Commits(repo, branch, since, until string) ([]VCSCommit, error)
 	Commit(repo, hash string) (VCSCommit, error)
 
+	//Tags
+	Tags(repo string) ([]VCSTag, error)
+
 	// PullRequests
 	PullRequests(string) ([]VCSPullRequest, error)
+	PullRequestComment(repo string, id int, comment VCSPullRequestComment) error
 
 	//Hooks
 	CreateHook(repo string, hook VCSHook) error
@@ -0,0 +1,65 @@
+package sdk
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MaxWorkerCacheKeyLength is the maximum length of the key of a cache
+const MaxWorkerCacheKeyLength = 256
+
+var workerCacheKeyPattern = regexp.MustCompile("^[a-zA-Z0-9._-]+$")
+
+// WorkerCache is a tar archive of build dependencies, pushed by a job and pulled by the next jobs of the project.
+// It is stored in the objectstore and identified by its project and its key.
+// Each push is stored as a new object, so that a failed push never alters the previous cache
+type WorkerCache struct {
+	ID         int64     `json:"id" db:"id" cli:"-"`
+	ProjectID  int64     `json:"project_id" db:"project_id" cli:"-"`
+	Key        string    `json:"key" db:"cache_key" cli:"key"`
+	ObjectName string    `json:"-" db:"object_name" cli:"-"`
+	Size       int64     `json:"size" db:"size" cli:"size"`
+	Created    time.Time `json:"created" db:"created" cli:"created"`
+	Expire     time.Time `json:"expire" db:"expire" cli:"expire"`
+}
+
+// GetName returns the name of the cache in the objectstore
+func (c *WorkerCache) GetName() string {
+	if c.ObjectName != "" {
+		return c.ObjectName
+	}
+	return c.Key
+}
+
+// GetPath returns the path of the cache in the objectstore
+func (c *WorkerCache) GetPath() string {
+	return fmt.Sprintf("cache/%d", c.ProjectID)
+}
+
+// IsValidWorkerCacheKey returns true if a cache key can be used as a file name in the objectstore
+func IsValidWorkerCacheKey(key string) bool {
+	return len(key) <= MaxWorkerCacheKeyLength && key != "." && key != ".." && workerCacheKeyPattern.MatchString(key)
+}
+
+// FindWorkerCache returns the cache to restore given a list of keys. The keys are tried in order: a cache matching
+// exactly the key is returned, otherwise the most recent cache whose key starts with it. It returns nil if no cache matches
+func FindWorkerCache(caches []WorkerCache, keys []string) *WorkerCache {
+	for _, k := range keys {
+		var found *WorkerCache
+		for i := range caches {
+			c := &caches[i]
+			if c.Key == k {
+				return c
+			}
+			if strings.HasPrefix(c.Key, k) && (found == nil || c.Created.After(found.Created)) {
+				found = c
+			}
+		}
+		if found != nil {
+			return found
+		}
+	}
+	return nil
+}
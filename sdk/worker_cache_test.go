@@ -0,0 +1,43 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidWorkerCacheKey(t *testing.T) {
+	assert.True(t, IsValidWorkerCacheKey("go-deps-1.9_linux.v2"))
+	assert.False(t, IsValidWorkerCacheKey(""))
+	assert.False(t, IsValidWorkerCacheKey("../other"))
+	assert.False(t, IsValidWorkerCacheKey("."))
+	assert.False(t, IsValidWorkerCacheKey(".."))
+	assert.True(t, IsValidWorkerCacheKey(".deps"))
+	assert.False(t, IsValidWorkerCacheKey("key with spaces"))
+	assert.False(t, IsValidWorkerCacheKey(string(make([]byte, MaxWorkerCacheKeyLength+1))))
+}
+
+func TestFindWorkerCache(t *testing.T) {
+	now := time.Now()
+	caches := []WorkerCache{
+		{Key: "deps-master-abc", Created: now.Add(-2 * time.Hour)},
+		{Key: "deps-master-def", Created: now.Add(-1 * time.Hour)},
+		{Key: "deps-feature-123", Created: now},
+	}
+
+	c := FindWorkerCache(caches, []string{"deps-master-abc", "deps-"})
+	assert.NotNil(t, c)
+	assert.Equal(t, "deps-master-abc", c.Key)
+
+	c = FindWorkerCache(caches, []string{"deps-master-xyz", "deps-master-", "deps-"})
+	assert.NotNil(t, c)
+	assert.Equal(t, "deps-master-def", c.Key)
+
+	c = FindWorkerCache(caches, []string{"deps-fix-", "deps-"})
+	assert.NotNil(t, c)
+	assert.Equal(t, "deps-feature-123", c.Key)
+
+	assert.Nil(t, FindWorkerCache(caches, []string{"other"}))
+	assert.Nil(t, FindWorkerCache(nil, []string{"deps-"}))
+}
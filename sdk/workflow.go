@@ -3,6 +3,8 @@ package sdk
 import (
 	"encoding/json"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 )
 
@@ -228,17 +230,18 @@ type WorkflowNodeJoinTrigger struct {
 
 //WorkflowNode represents a node in w workflow tree
 type WorkflowNode struct {
-	ID               int64                 `json:"id" db:"id"`
-	Name             string                `json:"name" db:"name"`
-	Ref              string                `json:"ref,omitempty" db:"-"`
-	WorkflowID       int64                 `json:"workflow_id" db:"workflow_id"`
-	PipelineID       int64                 `json:"pipeline_id" db:"pipeline_id"`
-	Pipeline         Pipeline              `json:"pipeline" db:"-"`
-	Context          *WorkflowNodeContext  `json:"context" db:"-"`
-	TriggerSrcID     int64                 `json:"-" db:"-"`
-	TriggerJoinSrcID int64                 `json:"-" db:"-"`
-	Hooks            []WorkflowNodeHook    `json:"hooks,omitempty" db:"-"`
-	Triggers         []WorkflowNodeTrigger `json:"triggers,omitempty" db:"-"`
+	ID               int64                      `json:"id" db:"id"`
+	Name             string                     `json:"name" db:"name"`
+	Ref              string                     `json:"ref,omitempty" db:"-"`
+	WorkflowID       int64                      `json:"workflow_id" db:"workflow_id"`
+	PipelineID       int64                      `json:"pipeline_id" db:"pipeline_id"`
+	Pipeline         Pipeline                   `json:"pipeline" db:"-"`
+	Context          *WorkflowNodeContext       `json:"context" db:"-"`
+	TriggerSrcID     int64                      `json:"-" db:"-"`
+	TriggerJoinSrcID int64                      `json:"-" db:"-"`
+	Hooks            []WorkflowNodeHook         `json:"hooks,omitempty" db:"-"`
+	Triggers         []WorkflowNodeTrigger      `json:"triggers,omitempty" db:"-"`
+	Notifications    []WorkflowNodeNotification `json:"notifications,omitempty" db:"-"`
 }
 
 // FilterHooksConfig filter all hooks configuration and remove somme configuration key
@@ -512,6 +515,7 @@ type WorkflowNodeContext struct {
 	EnvironmentID             int64        `json:"environment_id" db:"environment_id"`
 	DefaultPayload            interface{}  `json:"default_payload,omitempty" db:"-"`
 	DefaultPipelineParameters []Parameter  `json:"default_pipeline_parameters,omitempty" db:"-"`
+	Priority                  int64        `json:"priority,omitempty" db:"priority"`
 }
 
 //WorkflowNodeHook represents a hook which cann trigger the workflow from a given node
@@ -530,6 +534,65 @@ var WorkflowHookModelBuiltin = "builtin"
 //WorkflowNodeHookConfig represents the configguration for a WorkflowNodeHook
 type WorkflowNodeHookConfig map[string]string
 
+//WorkflowNodeHookSecretKeys are the keys of the configurations of hooks whose value is a secret
+var WorkflowNodeHookSecretKeys = []string{"secret", "password"}
+
+//IsSecret returns true if the value of a key is a secret: the key is a secret key, or the value is an url with a password
+func (c WorkflowNodeHookConfig) IsSecret(k string) bool {
+	for _, s := range WorkflowNodeHookSecretKeys {
+		if k == s {
+			return true
+		}
+	}
+	_, ok := maskURLPassword(c[k])
+	return ok
+}
+
+//WithoutSecrets returns a copy of the configuration without the secrets. The passwords of the urls are replaced by PasswordPlaceholder
+func (c WorkflowNodeHookConfig) WithoutSecrets() WorkflowNodeHookConfig {
+	res := make(WorkflowNodeHookConfig, len(c))
+	for k, v := range c {
+		if !c.IsSecret(k) {
+			res[k] = v
+		} else if masked, ok := maskURLPassword(v); ok {
+			res[k] = masked
+		}
+	}
+	return res
+}
+
+//RestoreSecrets sets the secrets of a previous configuration which are missing, or which are masked by WithoutSecrets
+func (c WorkflowNodeHookConfig) RestoreSecrets(previous WorkflowNodeHookConfig) {
+	for k, v := range previous {
+		if !previous.IsSecret(k) {
+			continue
+		}
+		current, ok := c[k]
+		if !ok {
+			c[k] = v
+		} else if masked, isURL := maskURLPassword(v); isURL && current == masked {
+			c[k] = v
+		}
+	}
+}
+
+// maskURLPassword replaces the password of an url by PasswordPlaceholder. It returns false if the value is not an url with a password
+func maskURLPassword(v string) (string, bool) {
+	u, err := url.Parse(v)
+	if err != nil || u.User == nil {
+		return "", false
+	}
+	if _, ok := u.User.Password(); !ok {
+		return "", false
+	}
+	//The placeholder is not escaped, so that it is readable
+	user := url.User(u.User.Username()).String()
+	u.User = nil
+	res := u.String()
+	i := strings.Index(res, "//") + 2
+	return res[:i] + user + ":" + PasswordPlaceholder + "@" + res[i:], true
+}
+
 //WorkflowHookModel represents a hook which can be used in workflows.
 type WorkflowHookModel struct {
 	ID            int64                  `json:"id" db:"id" cli:"-"`
@@ -0,0 +1,62 @@
+package sdk
+
+import (
+	"net/url"
+)
+
+// WorkflowNodeNotification is a settings on a workflow node to post a message when a run of the node is over
+type WorkflowNodeNotification struct {
+	ID             int64                            `json:"id" db:"id"`
+	WorkflowNodeID int64                            `json:"workflow_node_id" db:"workflow_node_id"`
+	Type           UserNotificationSettingsType     `json:"type" db:"type"`
+	Settings       WorkflowNodeNotificationSettings `json:"settings" db:"-"`
+}
+
+// WorkflowNodeNotificationSettings are the settings of a slack, mattermost, teams or webhook notification
+type WorkflowNodeNotificationSettings struct {
+	When     UserNotificationEventType `json:"when"`
+	URL      string                    `json:"url"`
+	Channel  string                    `json:"channel,omitempty"`
+	Template UserNotificationTemplate  `json:"template"`
+}
+
+// IsValid checks the type, the trigger and the url of the notification
+func (n WorkflowNodeNotification) IsValid() error {
+	switch n.Type {
+	case SlackUserNotification, MattermostUserNotification, TeamsUserNotification, WebhookUserNotification:
+	default:
+		return ErrNotSupportedUserNotification
+	}
+
+	switch n.Settings.When {
+	case UserNotificationAlways, UserNotificationNever, UserNotificationChange, UserNotificationFailure:
+	default:
+		return ErrParseUserNotification
+	}
+
+	u, err := url.Parse(n.Settings.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrParseUserNotification
+	}
+	return nil
+}
+
+// ShouldNotify returns true if the notification has to be sent for a node run which is over.
+// The previous status is the status of the previous run of the node, empty for the first run.
+func (n WorkflowNodeNotification) ShouldNotify(status, previousStatus string) bool {
+	switch status {
+	case StatusSuccess.String(), StatusFail.String(), StatusStopped.String():
+	default:
+		return false
+	}
+
+	switch n.Settings.When {
+	case UserNotificationAlways:
+		return true
+	case UserNotificationChange:
+		return previousStatus == "" || status != previousStatus
+	case UserNotificationFailure:
+		return status == StatusFail.String()
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowNodeNotificationIsValid(t *testing.T) {
+	n := WorkflowNodeNotification{
+		Type: SlackUserNotification,
+		Settings: WorkflowNodeNotificationSettings{
+			When: UserNotificationChange,
+			URL:  "https://hooks.slack.com/services/T00/B00/XXX",
+		},
+	}
+	assert.NoError(t, n.IsValid())
+
+	n.Type = EmailUserNotification
+	assert.Equal(t, ErrNotSupportedUserNotification, n.IsValid())
+
+	n.Type = TeamsUserNotification
+	n.Settings.When = "sometimes"
+	assert.Equal(t, ErrParseUserNotification, n.IsValid())
+
+	n.Settings.When = UserNotificationAlways
+	for _, u := range []string{"", "hooks.slack.com/services", "ftp://hooks.slack.com", "https://"} {
+		n.Settings.URL = u
+		assert.Equal(t, ErrParseUserNotification, n.IsValid(), u)
+	}
+}
+
+func TestWorkflowNodeNotificationShouldNotify(t *testing.T) {
+	tests := []struct {
+		when     UserNotificationEventType
+		status   Status
+		previous Status
+		notify   bool
+	}{
+		{UserNotificationAlways, StatusSuccess, StatusSuccess, true},
+		{UserNotificationAlways, StatusBuilding, "", false},
+		{UserNotificationNever, StatusFail, StatusSuccess, false},
+		{UserNotificationChange, StatusSuccess, "", true},
+		{UserNotificationChange, StatusSuccess, StatusSuccess, false},
+		{UserNotificationChange, StatusSuccess, StatusFail, true},
+		{UserNotificationChange, StatusStopped, StatusSuccess, true},
+		{UserNotificationFailure, StatusFail, StatusFail, true},
+		{UserNotificationFailure, StatusSuccess, StatusFail, false},
+		{UserNotificationFailure, StatusStopped, "", false},
+	}
+	for _, tt := range tests {
+		n := WorkflowNodeNotification{Settings: WorkflowNodeNotificationSettings{When: tt.when}}
+		assert.Equal(t, tt.notify, n.ShouldNotify(tt.status.String(), tt.previous.String()), "%s %s after %s", tt.when, tt.status, tt.previous)
+	}
+}
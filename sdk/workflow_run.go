@@ -106,7 +106,9 @@ type WorkflowNodeRun struct {
 	BuildParameters    []Parameter               `json:"build_parameters" db:"-"`
 	Artifacts          []WorkflowNodeRunArtifact `json:"artifacts,omitempty" db:"-"`
 	Tests              *venom.Tests              `json:"tests,omitempty" db:"-"`
+	Coverage           *CoverageReport           `json:"coverage,omitempty" db:"-"`
 	Commits            []VCSCommit               `json:"commits,omitempty" db:"-"`
+	Priority           int64                     `json:"priority" db:"priority"`
 }
 
 // Translate translates messages in WorkflowNodeRun
@@ -129,6 +131,7 @@ type WorkflowNodeRunArtifact struct {
 	Size              int64     `json:"size,omitempty" db:"size"`
 	Perm              uint32    `json:"perm,omitempty" db:"perm"`
 	MD5sum            string    `json:"md5sum,omitempty" db:"md5sum"`
+	SHA256sum         string    `json:"sha256sum,omitempty" db:"sha256sum"`
 	ObjectPath        string    `json:"object_path,omitempty" db:"object_path"`
 	Created           time.Time `json:"created,omitempty" db:"created"`
 }
@@ -145,6 +148,10 @@ type WorkflowNodeJobRun struct {
 	Start             time.Time   `json:"start,omitempty" db:"start"`
 	Done              time.Time   `json:"done,omitempty" db:"done"`
 	Model             string      `json:"model,omitempty" db:"model"`
+	ProjectID         int64       `json:"project_id" db:"project_id"`
+	Priority          int64       `json:"priority" db:"priority"`
+	Retry             int         `json:"retry" db:"retry"`
+	RetryAfter        time.Time   `json:"retry_after,omitempty" db:"retry_after"`
 	BookedBy          Hatchery    `json:"bookedby" db:"-"`
 	SpawnInfos        []SpawnInfo `json:"spawninfos" db:"-"`
 }
@@ -169,6 +176,7 @@ type WorkflowNodeRunManual struct {
 	Payload            interface{} `json:"payload" db:"-"`
 	PipelineParameters []Parameter `json:"pipeline_parameter" db:"-"`
 	User               User        `json:"user" db:"-"`
+	Priority           *int64      `json:"priority,omitempty" db:"-"`
 }
 
 //GetName returns the name the artifact
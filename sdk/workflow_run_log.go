@@ -0,0 +1,42 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WorkflowRunLog is a part of the logs of a step of a workflow run. The logs of a running step are sent
+// as successive parts, Offset is the position of Value in the logs of the step
+type WorkflowRunLog struct {
+	NodeName  string `json:"node_name"`
+	SubNumber int64  `json:"subnumber"`
+	StageName string `json:"stage_name"`
+	JobID     int64  `json:"job_id"`
+	JobName   string `json:"job_name"`
+	Retry     int    `json:"retry"`
+	StepOrder int    `json:"step_order"`
+	StepName  string `json:"step_name"`
+	Status    string `json:"status"`
+	Offset    int    `json:"offset"`
+	Value     string `json:"value"`
+}
+
+// StepKey identifies the step of the logs in a workflow run
+func (l WorkflowRunLog) StepKey() string {
+	return fmt.Sprintf("%d/%d", l.JobID, l.StepOrder)
+}
+
+// Title returns the header of the logs of the step: node/stage/job/step
+func (l WorkflowRunLog) Title() string {
+	return fmt.Sprintf("%s/%s/%s/%s", l.NodeName, l.StageName, l.JobName, l.StepName)
+}
+
+// FileName returns the path of the logs of the step in a logs archive of the workflow run
+func (l WorkflowRunLog) FileName() string {
+	clean := strings.NewReplacer("/", "_", "\\", "_", " ", "_")
+	return fmt.Sprintf("%s.%d/%s/%s.%d/%02d-%s.log",
+		clean.Replace(l.NodeName), l.SubNumber,
+		clean.Replace(l.StageName),
+		clean.Replace(l.JobName), l.JobID,
+		l.StepOrder, clean.Replace(l.StepName))
+}
@@ -0,0 +1,29 @@
+package sdk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowRunLogFileName(t *testing.T) {
+	l := WorkflowRunLog{
+		NodeName:  "build",
+		SubNumber: 1,
+		StageName: "Compile and test",
+		JobID:     42,
+		JobName:   "go/build",
+		StepOrder: 3,
+		StepName:  "Script",
+	}
+	assert.Equal(t, "build.1/Compile_and_test/go_build.42/03-Script.log", l.FileName())
+	assert.Equal(t, "build/Compile and test/go/build/Script", l.Title())
+	assert.Equal(t, "42/3", l.StepKey())
+}
+
+func TestStatusIsTerminated(t *testing.T) {
+	assert.True(t, StatusIsTerminated(StatusSuccess.String()))
+	assert.True(t, StatusIsTerminated(StatusStopped.String()))
+	assert.False(t, StatusIsTerminated(StatusBuilding.String()))
+	assert.False(t, StatusIsTerminated(StatusWaiting.String()))
+}
@@ -45,6 +45,37 @@ export class UserService {
         });
     }
 
+    /**
+     * Get the authentication mode of CDS: local, ldap or oidc
+     * @returns {Observable<string>}
+     */
+    getAuthMode(): Observable<string> {
+        return this._http.get<any>('/auth/mode').map(res => res.auth_mode);
+    }
+
+    /**
+     * Get the URL of the OpenID Connect provider where the user logs in
+     * @returns {Observable<string>}
+     */
+    getOIDCLoginURL(): Observable<string> {
+        return this._http.get<any>('/login/oidc').map(res => res.url);
+    }
+
+    /**
+     * Login with the code given by the OpenID Connect provider
+     * @param code Code given by the provider
+     * @param state State given by CDS before the redirection on the provider
+     * @returns {Observable<User>}
+     */
+    loginOIDC(code: string, state: string): Observable<User> {
+        return this._http.post<any>('/login/oidc/callback', {code: code, state: state}).map(res => {
+            let u = res.user;
+            u.token = res.token;
+            this._authStore.addUser(u, true);
+            return u;
+        });
+    }
+
     resetPassword(user: User, href: string) {
         let request = {
             user: user,
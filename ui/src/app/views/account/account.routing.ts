@@ -4,6 +4,7 @@ import {ModuleWithProviders} from '@angular/core';
 import {PasswordComponent} from './password/password.component';
 import {SignUpComponent} from './signup/signup.component';
 import {VerifyComponent} from './verify/verify.component';
+import {OIDCComponent} from './oidc/oidc.component';
 
 const routes: Routes = [
     {
@@ -13,7 +14,8 @@ const routes: Routes = [
             { path: 'login', component: LoginComponent },
             { path: 'password', component: PasswordComponent },
             { path: 'signup', component: SignUpComponent },
-            { path: 'verify/:username/:token', component: VerifyComponent }
+            { path: 'verify/:username/:token', component: VerifyComponent },
+            { path: 'oidc', component: OIDCComponent }
         ]
     }
 ];
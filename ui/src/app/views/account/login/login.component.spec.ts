@@ -58,6 +58,11 @@ describe('CDS: LoginComponent', () => {
         fixture.detectChanges();
         tick(50);
 
+        http.expectOne(((req: HttpRequest<any>) => {
+            return req.url === 'foo.bar/auth/mode';
+        })).flush({'auth_mode': 'local'});
+        expect(component.oidc).toBeFalsy();
+
         // Simulate user typing
         let inputUsername = compiled.querySelector('input[name="username"]');
         inputUsername.value = 'foo';
@@ -4,6 +4,7 @@ import {UserService} from '../../../service/user/user.service';
 import {Router, ActivatedRoute} from '@angular/router';
 import {AuthentificationStore} from '../../../service/auth/authentification.store';
 import {AccountComponent} from '../account.component';
+import {OIDCComponent} from '../oidc/oidc.component';
 
 @Component({
     selector: 'app-account-login',
@@ -14,6 +15,7 @@ export class LoginComponent extends AccountComponent {
 
     user: User;
     redirect: string;
+    oidc = false;
 
     constructor(private _userService: UserService, private _router: Router,
         private _authStore: AuthentificationStore, private _route: ActivatedRoute) {
@@ -23,6 +25,20 @@ export class LoginComponent extends AccountComponent {
         this._route.queryParams.subscribe(queryParams => {
            this.redirect = queryParams.redirect;
         });
+
+        this._userService.getAuthMode().subscribe(mode => {
+            this.oidc = mode === 'oidc';
+        });
+    }
+
+    signInOIDC() {
+        this._userService.getOIDCLoginURL().subscribe(url => {
+            // The redirection is restored when the provider redirects the user on CDS
+            if (this.redirect) {
+                sessionStorage.setItem(OIDCComponent.redirectKey, this.redirect);
+            }
+            window.location.href = url;
+        });
     }
 
     signIn() {
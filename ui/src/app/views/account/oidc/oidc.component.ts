@@ -0,0 +1,45 @@
+import {Component, OnInit} from '@angular/core';
+import {ActivatedRoute, Params, Router} from '@angular/router';
+import {UserService} from '../../../service/user/user.service';
+import {AuthentificationStore} from '../../../service/auth/authentification.store';
+import {AccountComponent} from '../account.component';
+
+@Component({
+    selector: 'app-account-oidc',
+    templateUrl: './oidc.html',
+    styleUrls: ['./oidc.scss']
+})
+export class OIDCComponent extends AccountComponent implements OnInit {
+
+    static redirectKey = 'CDS-OIDC-REDIRECT';
+
+    showErrorMessage = false;
+
+    constructor(private _userService: UserService, private _router: Router,
+        private _activatedRoute: ActivatedRoute, private _authStore: AuthentificationStore) {
+        super(_authStore);
+    }
+
+    ngOnInit(): void {
+        let params: Params = this._activatedRoute.snapshot.queryParams;
+        if (!params['code'] || !params['state']) {
+            this.showErrorMessage = true;
+            return;
+        }
+        this._userService.loginOIDC(params['code'], params['state']).subscribe(() => {
+            let redirect = sessionStorage.getItem(OIDCComponent.redirectKey);
+            sessionStorage.removeItem(OIDCComponent.redirectKey);
+            if (redirect) {
+                this._router.navigateByUrl(decodeURIComponent(redirect));
+            } else {
+                this._router.navigate(['home']);
+            }
+        }, () => {
+            this.showErrorMessage = true;
+        });
+    }
+
+    navigateToLogin() {
+        this._router.navigate(['/account/login']);
+    }
+}